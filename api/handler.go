@@ -4,8 +4,10 @@ import (
 	"net/http"
 
 	"github.com/containous/mux"
+	"github.com/containous/traefik/healthcheck"
 	"github.com/containous/traefik/log"
 	"github.com/containous/traefik/middlewares"
+	"github.com/containous/traefik/middlewares/cache"
 	"github.com/containous/traefik/safe"
 	"github.com/containous/traefik/types"
 	"github.com/containous/traefik/version"
@@ -15,13 +17,16 @@ import (
 
 // Handler expose api routes
 type Handler struct {
-	EntryPoint            string `description:"EntryPoint" export:"true"`
-	Dashboard             bool   `description:"Activate dashboard" export:"true"`
-	Debug                 bool   `export:"true"`
-	CurrentConfigurations *safe.Safe
-	Statistics            *types.Statistics          `description:"Enable more detailed statistics" export:"true"`
-	Stats                 *thoas_stats.Stats         `json:"-"`
-	StatsRecorder         *middlewares.StatsRecorder `json:"-"`
+	EntryPoint                string `description:"EntryPoint" export:"true"`
+	Dashboard                 bool   `description:"Activate dashboard" export:"true"`
+	Debug                     bool   `export:"true"`
+	CurrentConfigurations     *safe.Safe
+	LastRejectedConfiguration *safe.Safe                 `json:"-"`
+	Statistics                *types.Statistics          `description:"Enable more detailed statistics" export:"true"`
+	Stats                     *thoas_stats.Stats         `json:"-"`
+	StatsRecorder             *middlewares.StatsRecorder `json:"-"`
+	HealthCheck               *healthcheck.HealthCheck   `json:"-"`
+	Caches                    *cache.Manager             `json:"-"`
 }
 
 var (
@@ -47,9 +52,15 @@ func (p Handler) AddRoutes(router *mux.Router) {
 	router.Methods(http.MethodGet).Path("/api/providers/{provider}/frontends/{frontend}").HandlerFunc(p.getFrontendHandler)
 	router.Methods(http.MethodGet).Path("/api/providers/{provider}/frontends/{frontend}/routes").HandlerFunc(p.getRoutesHandler)
 	router.Methods(http.MethodGet).Path("/api/providers/{provider}/frontends/{frontend}/routes/{route}").HandlerFunc(p.getRouteHandler)
+	router.Methods(http.MethodGet).Path("/api/rejected").HandlerFunc(p.getRejectedConfigurationHandler)
 
 	// health route
 	router.Methods(http.MethodGet).Path("/health").HandlerFunc(p.getHealthHandler)
+	router.Methods(http.MethodGet).Path("/health/outliers").HandlerFunc(p.getOutliersHandler)
+
+	// cache routes
+	router.Methods(http.MethodDelete).Path("/api/cache").HandlerFunc(p.purgeCacheHandler)
+	router.Methods(http.MethodDelete).Path("/api/cache/{frontend}").HandlerFunc(p.purgeCacheHandler)
 
 	version.Handler{}.AddRoutes(router)
 
@@ -231,6 +242,24 @@ func (p Handler) getRouteHandler(response http.ResponseWriter, request *http.Req
 	http.NotFound(response, request)
 }
 
+func (p Handler) getRejectedConfigurationHandler(response http.ResponseWriter, request *http.Request) {
+	if p.LastRejectedConfiguration == nil {
+		http.NotFound(response, request)
+		return
+	}
+
+	rejected, ok := p.LastRejectedConfiguration.Get().(*types.RejectedConfiguration)
+	if !ok || rejected == nil {
+		http.NotFound(response, request)
+		return
+	}
+
+	err := templatesRenderer.JSON(response, http.StatusOK, rejected)
+	if err != nil {
+		log.Error(err)
+	}
+}
+
 // healthResponse combines data returned by thoas/stats with statistics (if
 // they are enabled).
 type healthResponse struct {
@@ -248,3 +277,42 @@ func (p *Handler) getHealthHandler(response http.ResponseWriter, request *http.R
 		log.Error(err)
 	}
 }
+
+func (p *Handler) getOutliersHandler(response http.ResponseWriter, request *http.Request) {
+	ejected := []healthcheck.EjectedServer{}
+	if p.HealthCheck != nil {
+		ejected = p.HealthCheck.EjectedServers()
+	}
+	err := templatesRenderer.JSON(response, http.StatusOK, ejected)
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// purgeResponse is the result of a cache purge.
+type purgeResponse struct {
+	Purged int `json:"purged"`
+}
+
+func (p *Handler) purgeCacheHandler(response http.ResponseWriter, request *http.Request) {
+	caches := p.Caches
+	if caches == nil {
+		caches = cache.NewManager()
+	}
+
+	purged, err := caches.Purge(mux.Vars(request)["frontend"], request.URL.Query().Get("path"))
+	if err == cache.ErrUnknownCache {
+		http.NotFound(response, request)
+		return
+	}
+	if err != nil {
+		log.Errorf("Error purging the cache: %v", err)
+		http.Error(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = templatesRenderer.JSON(response, http.StatusOK, purgeResponse{Purged: purged})
+	if err != nil {
+		log.Error(err)
+	}
+}
@@ -262,6 +262,11 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
       [backends."backend-{{ $backendName }}".loadBalancer.stickiness]
         cookieName = "{{ $loadBalancer.Stickiness.CookieName }}"
       {{end}}
+      {{if $loadBalancer.ConsistentHash }}
+      [backends."backend-{{ $backendName }}".loadBalancer.consistentHash]
+        extractorFunc = "{{ $loadBalancer.ConsistentHash.ExtractorFunc }}"
+        loadFactor = {{ printf "%f" $loadBalancer.ConsistentHash.LoadFactor }}
+      {{end}}
   {{end}}
 
   {{ $maxConn := getMaxConn $backend }}
@@ -274,9 +279,27 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
   {{ $healthCheck := getHealthCheck $backend }}
   {{if $healthCheck }}
   [backends.backend-{{ $backendName }}.healthCheck]
+    mode = "{{ $healthCheck.Mode }}"
+    scheme = "{{ $healthCheck.Scheme }}"
     path = "{{ $healthCheck.Path }}"
     port = {{ $healthCheck.Port }}
     interval = "{{ $healthCheck.Interval }}"
+    timeout = "{{ $healthCheck.Timeout }}"
+    hostname = "{{ $healthCheck.Hostname }}"
+    {{if $healthCheck.Status }}
+    status = [{{range $healthCheck.Status }}
+      "{{.}}",
+      {{end}}]
+    {{end}}
+    bodyRegex = {{ quote $healthCheck.BodyRegex }}
+    healthyThreshold = {{ $healthCheck.HealthyThreshold }}
+    unhealthyThreshold = {{ $healthCheck.UnhealthyThreshold }}
+    {{if $healthCheck.Headers }}
+    [backends.backend-{{ $backendName }}.healthCheck.headers]
+      {{range $k, $v := $healthCheck.Headers }}
+      {{$k}} = "{{$v}}"
+      {{end}}
+    {{end}}
   {{end}}
 
   {{ $buffering := getBuffering $backend }}
@@ -364,6 +387,7 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
     {{if $rateLimit }}
     [frontends."frontend-{{ $ServiceFrontendName }}".rateLimit]
       extractorFunc = "{{ $rateLimit.ExtractorFunc }}"
+      distributed = {{ $rateLimit.Distributed }}
       [frontends."frontend-{{ $ServiceFrontendName }}".rateLimit.rateSet]
         {{range $limitName, $limit := $rateLimit.RateSet }}
         [frontends."frontend-{{ $ServiceFrontendName }}".rateLimit.rateSet.{{ $limitName }}]
@@ -463,6 +487,138 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
       permanent = {{ $redirect.Permanent }}
     {{end}}
 
+    {{ $weightedBackends := getWeightedBackends $container }}
+    {{if $weightedBackends }}
+    [frontends."frontend-{{ $frontendName }}".weightedBackends]
+      [frontends."frontend-{{ $frontendName }}".weightedBackends.backends]
+        {{range $backendName, $weight := $weightedBackends.Backends }}
+        "{{ $backendName }}" = {{ $weight }}
+        {{end}}
+      {{if $weightedBackends.Stickiness }}
+      [frontends."frontend-{{ $frontendName }}".weightedBackends.stickiness]
+        cookieName = "{{ $weightedBackends.Stickiness.CookieName }}"
+      {{end}}
+    {{end}}
+
+    {{ $accessLog := getAccessLog $container }}
+    {{if $accessLog }}
+    [frontends."frontend-{{ $frontendName }}".accessLog]
+      {{if $accessLog.Filters }}
+      [frontends."frontend-{{ $frontendName }}".accessLog.filters]
+        statusCodes = [{{range $accessLog.Filters.StatusCodes }}
+          "{{.}}",
+          {{end}}]
+        retryAttempts = {{ $accessLog.Filters.RetryAttempts }}
+        minDuration = "{{ $accessLog.Filters.MinDuration }}"
+      {{end}}
+      {{if $accessLog.Fields }}
+      [frontends."frontend-{{ $frontendName }}".accessLog.fields]
+        defaultMode = "{{ $accessLog.Fields.DefaultMode }}"
+        {{if $accessLog.Fields.Names }}
+        [frontends."frontend-{{ $frontendName }}".accessLog.fields.names]
+          {{range $name, $mode := $accessLog.Fields.Names }}
+          "{{ $name }}" = "{{ $mode }}"
+          {{end}}
+        {{end}}
+        {{if $accessLog.Fields.Headers }}
+        [frontends."frontend-{{ $frontendName }}".accessLog.fields.headers]
+          defaultMode = "{{ $accessLog.Fields.Headers.DefaultMode }}"
+          {{if $accessLog.Fields.Headers.Names }}
+          [frontends."frontend-{{ $frontendName }}".accessLog.fields.headers.names]
+            {{range $name, $mode := $accessLog.Fields.Headers.Names }}
+            "{{ $name }}" = "{{ $mode }}"
+            {{end}}
+          {{end}}
+        {{end}}
+      {{end}}
+    {{end}}
+
+    {{ $compression := getCompression $container }}
+    {{if $compression }}
+    [frontends."frontend-{{ $frontendName }}".compression]
+      encodings = [{{range $compression.Encodings }}
+        "{{.}}",
+        {{end}}]
+      minSize = {{ $compression.MinSize }}
+      gzipLevel = {{ $compression.GzipLevel }}
+      brotliLevel = {{ $compression.BrotliLevel }}
+      includedContentTypes = [{{range $compression.IncludedContentTypes }}
+        "{{.}}",
+        {{end}}]
+      excludedContentTypes = [{{range $compression.ExcludedContentTypes }}
+        "{{.}}",
+        {{end}}]
+    {{end}}
+
+    {{ $auth := getAuth $container }}
+    {{if $auth }}
+    [frontends."frontend-{{ $frontendName }}".auth]
+      headerField = "{{ $auth.HeaderField }}"
+
+      {{if $auth.JWT }}
+      [frontends."frontend-{{ $frontendName }}".auth.jwt]
+        secret = {{ quote $auth.JWT.Secret }}
+        publicKeys = [{{range $auth.JWT.PublicKeys }}
+          {{ quote . }},
+          {{end}}]
+        jwks = "{{ $auth.JWT.JWKS }}"
+        jwksRefreshInterval = "{{ $auth.JWT.JWKSRefreshInterval }}"
+        issuer = {{ quote $auth.JWT.Issuer }}
+        audiences = [{{range $auth.JWT.Audiences }}
+          "{{.}}",
+          {{end}}]
+        clockSkew = "{{ $auth.JWT.ClockSkew }}"
+        allowNoExpiration = {{ $auth.JWT.AllowNoExpiration }}
+
+        {{if $auth.JWT.ClaimHeaders }}
+        [frontends."frontend-{{ $frontendName }}".auth.jwt.claimHeaders]
+          {{range $header, $claim := $auth.JWT.ClaimHeaders }}
+          {{$header}} = "{{$claim}}"
+          {{end}}
+        {{end}}
+      {{end}}
+    {{end}}
+
+    {{ $tlsClientCert := getTLSClientCert $container }}
+    {{if $tlsClientCert }}
+    [frontends."frontend-{{ $frontendName }}".tlsClientCert]
+      pem = {{ $tlsClientCert.PEM }}
+      fields = [{{range $tlsClientCert.Fields }}
+        "{{.}}",
+        {{end}}]
+      allowedSubjects = [{{range $tlsClientCert.AllowedSubjects }}
+        '{{.}}',
+        {{end}}]
+      allowedSANs = [{{range $tlsClientCert.AllowedSANs }}
+        '{{.}}',
+        {{end}}]
+    {{end}}
+
+    {{ $cache := getCache $container }}
+    {{if $cache }}
+    [frontends."frontend-{{ $frontendName }}".cache]
+      storage = "{{ $cache.Storage }}"
+      path = "{{ $cache.Path }}"
+      maxEntries = {{ $cache.MaxEntries }}
+      maxBodySize = {{ $cache.MaxBodySize }}
+      defaultTTL = "{{ $cache.DefaultTTL }}"
+    {{end}}
+
+    {{ $retry := getRetry $container }}
+    {{if $retry }}
+    [frontends."frontend-{{ $frontendName }}".retry]
+      attempts = {{ $retry.Attempts }}
+      statusCodes = [{{range $retry.StatusCodes }}
+        "{{.}}",
+        {{end}}]
+      methods = [{{range $retry.Methods }}
+        "{{.}}",
+        {{end}}]
+      initialInterval = "{{ $retry.InitialInterval }}"
+      maxInterval = "{{ $retry.MaxInterval }}"
+      budget = {{ printf "%f" $retry.Budget }}
+    {{end}}
+
     {{ $errorPages := getErrorPages $container }}
     {{if $errorPages }}
     [frontends."frontend-{{ $frontendName }}".errors]
@@ -480,6 +636,7 @@ var _templatesDockerTmpl = []byte(`{{$backendServers := .Servers}}
     {{if $rateLimit }}
     [frontends."frontend-{{ $frontendName }}".rateLimit]
       extractorFunc = "{{ $rateLimit.ExtractorFunc }}"
+      distributed = {{ $rateLimit.Distributed }}
       [frontends."frontend-{{ $frontendName }}".rateLimit.rateSet]
         {{ range $limitName, $limit := $rateLimit.RateSet }}
         [frontends."frontend-{{ $frontendName }}".rateLimit.rateSet.{{ $limitName }}]
@@ -819,6 +976,31 @@ var _templatesKubernetesTmpl = []byte(`[backends]
       retryExpression = "{{ $backend.Buffering.RetryExpression }}"
     {{end}}
 
+    {{if $backend.HealthCheck }}
+    [backends."{{ $backendName }}".healthCheck]
+      mode = "{{ $backend.HealthCheck.Mode }}"
+      scheme = "{{ $backend.HealthCheck.Scheme }}"
+      path = "{{ $backend.HealthCheck.Path }}"
+      port = {{ $backend.HealthCheck.Port }}
+      interval = "{{ $backend.HealthCheck.Interval }}"
+      timeout = "{{ $backend.HealthCheck.Timeout }}"
+      hostname = "{{ $backend.HealthCheck.Hostname }}"
+      {{if $backend.HealthCheck.Status }}
+      status = [{{range $backend.HealthCheck.Status }}
+        "{{.}}",
+        {{end}}]
+      {{end}}
+      bodyRegex = {{ quote $backend.HealthCheck.BodyRegex }}
+      healthyThreshold = {{ $backend.HealthCheck.HealthyThreshold }}
+      unhealthyThreshold = {{ $backend.HealthCheck.UnhealthyThreshold }}
+      {{if $backend.HealthCheck.Headers }}
+      [backends."{{ $backendName }}".healthCheck.headers]
+        {{range $k, $v := $backend.HealthCheck.Headers }}
+        {{$k}} = "{{$v}}"
+        {{end}}
+      {{end}}
+    {{end}}
+
     {{range $serverName, $server := $backend.Servers }}
     [backends."{{ $backendName }}".servers."{{ $serverName }}"]
       url = "{{ $server.URL }}"
@@ -855,6 +1037,18 @@ var _templatesKubernetesTmpl = []byte(`[backends]
       replacement = "{{ $frontend.Redirect.Replacement }}"
     {{end}}
 
+    {{if $frontend.WeightedBackends }}
+    [frontends."{{ $frontendName }}".weightedBackends]
+      [frontends."{{ $frontendName }}".weightedBackends.backends]
+        {{range $backendName, $weight := $frontend.WeightedBackends.Backends }}
+        "{{ $backendName }}" = {{ $weight }}
+        {{end}}
+      {{if $frontend.WeightedBackends.Stickiness }}
+      [frontends."{{ $frontendName }}".weightedBackends.stickiness]
+        cookieName = "{{ $frontend.WeightedBackends.Stickiness.CookieName }}"
+      {{end}}
+    {{end}}
+
     {{if $frontend.Errors }}
     [frontends."frontend-{{ $frontendName }}".errors]
       {{range $pageName, $page := $frontend.Errors }}
@@ -870,6 +1064,7 @@ var _templatesKubernetesTmpl = []byte(`[backends]
     {{if $frontend.RateLimit }}
     [frontends."frontend-{{ $frontendName }}".rateLimit]
       extractorFunc = "{{ $frontend.RateLimit.ExtractorFunc }}"
+      distributed = {{ $frontend.RateLimit.Distributed }}
       [frontends."frontend-{{ $frontendName }}".rateLimit.rateSet]
         {{range $limitName, $limit := $frontend.RateLimit.RateSet }}
         [frontends."frontend-{{ $frontendName }}".rateLimit.rateSet.{{ $limitName }}]
@@ -879,6 +1074,20 @@ var _templatesKubernetesTmpl = []byte(`[backends]
         {{end}}
     {{end}}
 
+    {{if $frontend.Retry }}
+    [frontends."{{ $frontendName }}".retry]
+      attempts = {{ $frontend.Retry.Attempts }}
+      statusCodes = [{{range $frontend.Retry.StatusCodes }}
+        "{{.}}",
+        {{end}}]
+      methods = [{{range $frontend.Retry.Methods }}
+        "{{.}}",
+        {{end}}]
+      initialInterval = "{{ $frontend.Retry.InitialInterval }}"
+      maxInterval = "{{ $frontend.Retry.MaxInterval }}"
+      budget = {{ printf "%f" $frontend.Retry.Budget }}
+    {{end}}
+
   {{if $frontend.Headers }}
   [frontends."{{ $frontendName }}".headers]
     SSLRedirect = {{ $frontend.Headers.SSLRedirect }}
@@ -978,6 +1187,11 @@ var _templatesKvTmpl = []byte(`[backends]
       [backends."{{ $backendName }}".loadBalancer.stickiness]
         cookieName = "{{ $loadBalancer.Stickiness.CookieName }}"
       {{end}}
+      {{if $loadBalancer.ConsistentHash }}
+      [backends."{{ $backendName }}".loadBalancer.consistentHash]
+        extractorFunc = "{{ $loadBalancer.ConsistentHash.ExtractorFunc }}"
+        loadFactor = {{ printf "%f" $loadBalancer.ConsistentHash.LoadFactor }}
+      {{end}}
   {{end}}
 
   {{ $maxConn := getMaxConn $backend }}
@@ -990,9 +1204,27 @@ var _templatesKvTmpl = []byte(`[backends]
   {{ $healthCheck := getHealthCheck $backend }}
   {{if $healthCheck }}
   [backends.{{ $backendName }}.healthCheck]
+    mode = "{{ $healthCheck.Mode }}"
+    scheme = "{{ $healthCheck.Scheme }}"
     path = "{{ $healthCheck.Path }}"
     port = {{ $healthCheck.Port }}
     interval = "{{ $healthCheck.Interval }}"
+    timeout = "{{ $healthCheck.Timeout }}"
+    hostname = "{{ $healthCheck.Hostname }}"
+    {{if $healthCheck.Status }}
+    status = [{{range $healthCheck.Status }}
+      "{{.}}",
+      {{end}}]
+    {{end}}
+    bodyRegex = {{ quote $healthCheck.BodyRegex }}
+    healthyThreshold = {{ $healthCheck.HealthyThreshold }}
+    unhealthyThreshold = {{ $healthCheck.UnhealthyThreshold }}
+    {{if $healthCheck.Headers }}
+    [backends.{{ $backendName }}.healthCheck.headers]
+      {{range $k, $v := $healthCheck.Headers }}
+      {{$k}} = "{{$v}}"
+      {{end}}
+    {{end}}
   {{end}}
 
   {{ $buffering := getBuffering $backend }}
@@ -1038,6 +1270,125 @@ var _templatesKvTmpl = []byte(`[backends]
       "{{.}}",
       {{end}}]
 
+    {{ $accessLog := getAccessLog $frontend }}
+    {{if $accessLog }}
+    [frontends."{{ $frontendName }}".accessLog]
+      {{if $accessLog.Filters }}
+      [frontends."{{ $frontendName }}".accessLog.filters]
+        statusCodes = [{{range $accessLog.Filters.StatusCodes }}
+          "{{.}}",
+          {{end}}]
+        retryAttempts = {{ $accessLog.Filters.RetryAttempts }}
+        minDuration = "{{ $accessLog.Filters.MinDuration }}"
+      {{end}}
+      {{if $accessLog.Fields }}
+      [frontends."{{ $frontendName }}".accessLog.fields]
+        defaultMode = "{{ $accessLog.Fields.DefaultMode }}"
+        {{if $accessLog.Fields.Names }}
+        [frontends."{{ $frontendName }}".accessLog.fields.names]
+          {{range $name, $mode := $accessLog.Fields.Names }}
+          "{{ $name }}" = "{{ $mode }}"
+          {{end}}
+        {{end}}
+        {{if $accessLog.Fields.Headers }}
+        [frontends."{{ $frontendName }}".accessLog.fields.headers]
+          defaultMode = "{{ $accessLog.Fields.Headers.DefaultMode }}"
+          {{if $accessLog.Fields.Headers.Names }}
+          [frontends."{{ $frontendName }}".accessLog.fields.headers.names]
+            {{range $name, $mode := $accessLog.Fields.Headers.Names }}
+            "{{ $name }}" = "{{ $mode }}"
+            {{end}}
+          {{end}}
+        {{end}}
+      {{end}}
+    {{end}}
+
+    {{ $compression := getCompression $frontend }}
+    {{if $compression }}
+    [frontends."{{ $frontendName }}".compression]
+      encodings = [{{range $compression.Encodings }}
+        "{{.}}",
+        {{end}}]
+      minSize = {{ $compression.MinSize }}
+      gzipLevel = {{ $compression.GzipLevel }}
+      brotliLevel = {{ $compression.BrotliLevel }}
+      includedContentTypes = [{{range $compression.IncludedContentTypes }}
+        "{{.}}",
+        {{end}}]
+      excludedContentTypes = [{{range $compression.ExcludedContentTypes }}
+        "{{.}}",
+        {{end}}]
+    {{end}}
+
+    {{ $auth := getAuth $frontend }}
+    {{if $auth }}
+    [frontends."{{ $frontendName }}".auth]
+      headerField = "{{ $auth.HeaderField }}"
+
+      {{if $auth.JWT }}
+      [frontends."{{ $frontendName }}".auth.jwt]
+        secret = {{ quote $auth.JWT.Secret }}
+        publicKeys = [{{range $auth.JWT.PublicKeys }}
+          {{ quote . }},
+          {{end}}]
+        jwks = "{{ $auth.JWT.JWKS }}"
+        jwksRefreshInterval = "{{ $auth.JWT.JWKSRefreshInterval }}"
+        issuer = {{ quote $auth.JWT.Issuer }}
+        audiences = [{{range $auth.JWT.Audiences }}
+          "{{.}}",
+          {{end}}]
+        clockSkew = "{{ $auth.JWT.ClockSkew }}"
+        allowNoExpiration = {{ $auth.JWT.AllowNoExpiration }}
+
+        {{if $auth.JWT.ClaimHeaders }}
+        [frontends."{{ $frontendName }}".auth.jwt.claimHeaders]
+          {{range $header, $claim := $auth.JWT.ClaimHeaders }}
+          {{$header}} = "{{$claim}}"
+          {{end}}
+        {{end}}
+      {{end}}
+    {{end}}
+
+    {{ $tlsClientCert := getTLSClientCert $frontend }}
+    {{if $tlsClientCert }}
+    [frontends."{{ $frontendName }}".tlsClientCert]
+      pem = {{ $tlsClientCert.PEM }}
+      fields = [{{range $tlsClientCert.Fields }}
+        "{{.}}",
+        {{end}}]
+      allowedSubjects = [{{range $tlsClientCert.AllowedSubjects }}
+        '{{.}}',
+        {{end}}]
+      allowedSANs = [{{range $tlsClientCert.AllowedSANs }}
+        '{{.}}',
+        {{end}}]
+    {{end}}
+
+    {{ $cache := getCache $frontend }}
+    {{if $cache }}
+    [frontends."{{ $frontendName }}".cache]
+      storage = "{{ $cache.Storage }}"
+      path = "{{ $cache.Path }}"
+      maxEntries = {{ $cache.MaxEntries }}
+      maxBodySize = {{ $cache.MaxBodySize }}
+      defaultTTL = "{{ $cache.DefaultTTL }}"
+    {{end}}
+
+    {{ $retry := getRetry $frontend }}
+    {{if $retry }}
+    [frontends."{{ $frontendName }}".retry]
+      attempts = {{ $retry.Attempts }}
+      statusCodes = [{{range $retry.StatusCodes }}
+        "{{.}}",
+        {{end}}]
+      methods = [{{range $retry.Methods }}
+        "{{.}}",
+        {{end}}]
+      initialInterval = "{{ $retry.InitialInterval }}"
+      maxInterval = "{{ $retry.MaxInterval }}"
+      budget = {{ printf "%f" $retry.Budget }}
+    {{end}}
+
     {{ $redirect := getRedirect $frontend }}
     {{if $redirect }}
     [frontends."{{ $frontendName }}".redirect]
@@ -1064,6 +1415,7 @@ var _templatesKvTmpl = []byte(`[backends]
     {{if $rateLimit }}
     [frontends."{{ $frontendName }}".rateLimit]
       extractorFunc = "{{ $rateLimit.ExtractorFunc }}"
+      distributed = {{ $rateLimit.Distributed }}
       [frontends."{{ $frontendName }}".rateLimit.rateSet]
         {{range $limitName, $rateLimit := $rateLimit.RateSet }}
         [frontends."{{ $frontendName }}".rateLimit.rateSet.{{ $limitName }}]
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/containous/flaeg"
+	"github.com/containous/staert"
 	"github.com/containous/traefik-extra-service-fabric"
 	"github.com/containous/traefik/api"
 	"github.com/containous/traefik/configuration"
@@ -188,6 +190,29 @@ func NewTraefikDefaultPointersConfiguration() *TraefikConfiguration {
 	defaultAccessLog := types.AccessLog{
 		Format:   accesslog.CommonFormat,
 		FilePath: "",
+		Filters:  &types.AccessLogFilters{},
+		Fields: &types.AccessLogFields{
+			DefaultMode: types.AccessLogKeep,
+			Headers: &types.FieldHeaders{
+				DefaultMode: types.AccessLogKeep,
+			},
+		},
+		Buffer: &types.AccessLogBuffer{
+			Size:          accesslog.DefaultBufferSize,
+			FlushInterval: flaeg.Duration(accesslog.DefaultBufferFlushInterval),
+			OnFull:        accesslog.BlockOnFull,
+		},
+		Syslog: &types.AccessLogSyslog{
+			Network:  "udp",
+			Address:  "localhost:514",
+			Facility: "local0",
+			AppName:  "traefik",
+			Retries:  3,
+		},
+		HTTP: &types.AccessLogHTTP{
+			Timeout: flaeg.Duration(5 * time.Second),
+			Retries: 3,
+		},
 	}
 
 	// default HealthCheckConfig
@@ -313,3 +338,25 @@ func NewTraefikConfiguration() *TraefikConfiguration {
 		ConfigFile: "",
 	}
 }
+
+// loadTraefikConfiguration reads the TOML configuration file, searched in the default locations if empty,
+// for the commands not parsing the configuration of the traefik command
+func loadTraefikConfiguration(configFile string) (*TraefikConfiguration, error) {
+	traefikConfiguration := NewTraefikConfiguration()
+	traefikCmd := &flaeg.Command{
+		Name:                  "traefik",
+		Config:                traefikConfiguration,
+		DefaultPointersConfig: NewTraefikDefaultPointersConfiguration(),
+	}
+
+	s := staert.NewStaert(traefikCmd)
+	toml := staert.NewTomlSource("traefik", []string{configFile, "/etc/traefik/", "$HOME/.traefik/", "."})
+	s.AddSource(toml)
+	if _, err := s.LoadConfig(); err != nil {
+		return nil, fmt.Errorf("error reading TOML config file %s: %v", toml.ConfigFileUsed(), err)
+	}
+
+	traefikConfiguration.ConfigFile = toml.ConfigFileUsed()
+	traefikConfiguration.GlobalConfiguration.SetEffectiveConfiguration(traefikConfiguration.ConfigFile)
+	return traefikConfiguration, nil
+}
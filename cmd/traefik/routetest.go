@@ -0,0 +1,152 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/server"
+)
+
+// RouteTestConfiguration holds the request tested by the route-test command
+type RouteTestConfiguration struct {
+	ConfigFile string         `short:"c" description:"Configuration file to use (TOML)."`
+	EntryPoint string         `description:"Entrypoint receiving the request, the first default entrypoint if empty"`
+	Method     string         `description:"Method of the request"`
+	Host       string         `description:"Host of the request"`
+	Path       string         `description:"Path of the request, with its query"`
+	Header     RequestHeaders `description:"Header of the request, as Name:value (can be repeated)"`
+}
+
+// RequestHeaders holds the headers of the request tested by the route-test command
+type RequestHeaders []string
+
+// Set adds a header to the request
+func (h *RequestHeaders) Set(value string) error {
+	*h = append(*h, value)
+	return nil
+}
+
+// Get return the headers
+func (h *RequestHeaders) Get() interface{} { return *h }
+
+// String return the headers in a readable format
+func (h *RequestHeaders) String() string { return fmt.Sprintf("%v", *h) }
+
+// SetValue sets the headers
+func (h *RequestHeaders) SetValue(val interface{}) {
+	*h = val.(RequestHeaders)
+}
+
+func newRouteTestCmd() *flaeg.Command {
+	routeTestConfiguration := &RouteTestConfiguration{
+		Method: http.MethodGet,
+		Host:   "localhost",
+		Path:   "/",
+	}
+	return &flaeg.Command{
+		Name:                  "route-test",
+		Description:           `Prints the frontend, backend and middlewares matching a request, from the file provider configuration. Traefik will not start.`,
+		Config:                routeTestConfiguration,
+		DefaultPointersConfig: &RouteTestConfiguration{},
+		Run: func() error {
+			// the routing table is built as the server does, keeping its logs out of the output
+			log.SetOutput(os.Stderr)
+			matched, err := runRouteTest(routeTestConfiguration, os.Stdout)
+			if err != nil {
+				fmt.Printf("Error testing route: %s\n", err)
+				os.Exit(1)
+			}
+			if !matched {
+				os.Exit(1)
+			}
+			os.Exit(0)
+			return nil
+		},
+	}
+}
+
+// runRouteTest writes the frontend matching the request to w, and tells whether a frontend matches the request
+func runRouteTest(routeTestConfiguration *RouteTestConfiguration, w io.Writer) (bool, error) {
+	traefikConfiguration, err := loadTraefikConfiguration(routeTestConfiguration.ConfigFile)
+	if err != nil {
+		return false, err
+	}
+
+	globalConfiguration := traefikConfiguration.GlobalConfiguration
+	if globalConfiguration.File == nil {
+		return false, errors.New("the file provider must be enabled")
+	}
+
+	config, err := globalConfiguration.File.BuildConfiguration()
+	if err != nil {
+		return false, fmt.Errorf("error loading the file provider configuration: %v", err)
+	}
+
+	entryPointName := routeTestConfiguration.EntryPoint
+	if len(entryPointName) == 0 && len(globalConfiguration.DefaultEntryPoints) > 0 {
+		entryPointName = globalConfiguration.DefaultEntryPoints[0]
+	}
+
+	req, err := newRouteTestRequest(routeTestConfiguration, globalConfiguration.EntryPoints[entryPointName] != nil && globalConfiguration.EntryPoints[entryPointName].TLS != nil)
+	if err != nil {
+		return false, err
+	}
+
+	matchedRoute, err := server.MatchRoute(globalConfiguration, config, entryPointName, req)
+	if err != nil {
+		return false, err
+	}
+	if matchedRoute == nil {
+		fmt.Fprintf(w, "No frontend of entrypoint %s matches %s %s%s\n", entryPointName, req.Method, req.Host, req.URL.RequestURI())
+		return false, nil
+	}
+
+	fmt.Fprintf(w, "Entrypoint:  %s\n", entryPointName)
+	fmt.Fprintf(w, "Frontend:    %s\n", matchedRoute.Frontend)
+	fmt.Fprintf(w, "Rules:       %s\n", strings.Join(matchedRoute.Rules, ", "))
+	fmt.Fprintf(w, "Priority:    %d\n", matchedRoute.Priority)
+	fmt.Fprintf(w, "Backend:     %s\n", strings.Join(matchedRoute.Backends, ", "))
+	fmt.Fprintf(w, "Middlewares: %s\n", strings.Join(matchedRoute.Middlewares, " -> "))
+	return true, nil
+}
+
+func newRouteTestRequest(routeTestConfiguration *RouteTestConfiguration, secure bool) (*http.Request, error) {
+	scheme := "http"
+	if secure {
+		scheme = "https"
+	}
+
+	path := routeTestConfiguration.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	req, err := http.NewRequest(routeTestConfiguration.Method, scheme+"://"+routeTestConfiguration.Host+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	if secure {
+		req.TLS = &tls.ConnectionState{}
+	}
+
+	for _, header := range routeTestConfiguration.Header {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("bad header format %q, expected Name:value", header)
+		}
+		name, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Add(name, value)
+	}
+	return req, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const routeTestConfig = `
+defaultEntryPoints = ["http"]
+
+[entryPoints]
+  [entryPoints.http]
+  address = ":80"
+
+[file]
+
+[backends]
+  [backends.backend1]
+    [backends.backend1.servers.server1]
+    url = "http://127.0.0.1:8080"
+
+[frontends]
+  [frontends.api]
+  backend = "backend1"
+    [frontends.api.compression]
+    [frontends.api.routes.api]
+    rule = "Host:foo.bar;PathPrefixStrip:/api"
+  [frontends.test]
+  backend = "backend1"
+    [frontends.test.routes.test]
+    rule = "Host:foo.bar && Headers:X-Test,1"
+`
+
+func TestRunRouteTest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traefik-route-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "traefik.toml")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(routeTestConfig), 0644))
+
+	testCases := []struct {
+		desc            string
+		config          RouteTestConfiguration
+		expectedMatched bool
+		expectedOutput  string
+		expectErr       bool
+	}{
+		{
+			desc:            "path prefix",
+			config:          RouteTestConfiguration{Method: "GET", Host: "foo.bar", Path: "/api/users"},
+			expectedMatched: true,
+			expectedOutput: `Entrypoint:  http
+Frontend:    api
+Rules:       Host:foo.bar;PathPrefixStrip:/api
+Priority:    33
+Backend:     backend1
+Middlewares: Strip prefix -> gRPC errors -> Compress
+`,
+		},
+		{
+			desc:            "header",
+			config:          RouteTestConfiguration{Method: "GET", Host: "foo.bar", Path: "/users", Header: RequestHeaders{"X-Test: 1"}},
+			expectedMatched: true,
+			expectedOutput: `Entrypoint:  http
+Frontend:    test
+Rules:       Host:foo.bar && Headers:X-Test,1
+Priority:    32
+Backend:     backend1
+Middlewares: gRPC errors
+`,
+		},
+		{
+			desc:           "no matching frontend",
+			config:         RouteTestConfiguration{Method: "GET", Host: "foo.bar", Path: "/users"},
+			expectedOutput: "No frontend of entrypoint http matches GET foo.bar/users\n",
+		},
+		{
+			desc:      "undefined entrypoint",
+			config:    RouteTestConfiguration{EntryPoint: "https", Method: "GET", Host: "foo.bar", Path: "/"},
+			expectErr: true,
+		},
+		{
+			desc:      "bad header",
+			config:    RouteTestConfiguration{Method: "GET", Host: "foo.bar", Path: "/", Header: RequestHeaders{"X-Test"}},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			test.config.ConfigFile = configFile
+
+			output := &bytes.Buffer{}
+			matched, err := runRouteTest(&test.config, output)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedMatched, matched)
+			assert.Equal(t, test.expectedOutput, output.String())
+		})
+	}
+}
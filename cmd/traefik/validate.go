@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/provider/file"
+	"github.com/containous/traefik/provider/kv"
+	"github.com/containous/traefik/server"
+	"github.com/containous/traefik/types"
+)
+
+// ValidateConfiguration holds the configuration sources checked by the validate command
+type ValidateConfiguration struct {
+	ConfigFile string `short:"c" description:"Configuration file to use (TOML)."`
+	File       string `description:"File holding the dynamic configuration, instead of the one of the file provider"`
+	Directory  string `description:"Directory holding the dynamic configuration files, instead of the one of the file provider"`
+	KVSnapshot string `description:"JSON file holding the key/value pairs of a KV store snapshot, to validate instead of the file provider configuration"`
+	KVPrefix   string `description:"Prefix of the keys of the KV store snapshot"`
+}
+
+// ValidationReport is the output of the validate command
+type ValidationReport struct {
+	ConfigFile  string              `json:"configFile,omitempty"`
+	Valid       bool                `json:"valid"`
+	Diagnostics []server.Diagnostic `json:"diagnostics"`
+}
+
+func newValidateCmd() *flaeg.Command {
+	validateConfiguration := &ValidateConfiguration{
+		KVPrefix: "traefik",
+	}
+	return &flaeg.Command{
+		Name:                  "validate",
+		Description:           `Checks the static and dynamic configurations, printing the problems found as JSON. Traefik will not start.`,
+		Config:                validateConfiguration,
+		DefaultPointersConfig: &ValidateConfiguration{},
+		Run: func() error {
+			// the configurations are built as the server does, keeping its logs out of the report
+			log.SetOutput(os.Stderr)
+			report := runValidate(validateConfiguration)
+			if err := writeValidationReport(report, os.Stdout); err != nil {
+				return err
+			}
+			if !report.Valid {
+				os.Exit(1)
+			}
+			os.Exit(0)
+			return nil
+		},
+	}
+}
+
+func runValidate(validateConfiguration *ValidateConfiguration) *ValidationReport {
+	report := &ValidationReport{Diagnostics: []server.Diagnostic{}}
+
+	traefikConfiguration, err := loadTraefikConfiguration(validateConfiguration.ConfigFile)
+	if err != nil {
+		report.Diagnostics = append(report.Diagnostics, server.Diagnostic{Level: server.DiagnosticError, Section: "configuration", Message: err.Error()})
+		return report
+	}
+	report.ConfigFile = traefikConfiguration.ConfigFile
+
+	globalConfiguration := traefikConfiguration.GlobalConfiguration
+	report.Diagnostics = append(report.Diagnostics, server.ValidateGlobalConfiguration(globalConfiguration)...)
+
+	config, diag := loadDynamicConfiguration(validateConfiguration, traefikConfiguration)
+	if diag != nil {
+		report.Diagnostics = append(report.Diagnostics, *diag)
+	}
+	if config != nil {
+		report.Diagnostics = append(report.Diagnostics, server.ValidateConfiguration(globalConfiguration, config)...)
+	}
+
+	report.Valid = !server.HasErrors(report.Diagnostics)
+	return report
+}
+
+// loadDynamicConfiguration loads the dynamic configuration to validate,
+// returning a diagnostic instead if it cannot be loaded
+func loadDynamicConfiguration(validateConfiguration *ValidateConfiguration, traefikConfiguration *TraefikConfiguration) (*types.Configuration, *server.Diagnostic) {
+	var source string
+	var config *types.Configuration
+	var err error
+
+	switch {
+	case len(validateConfiguration.KVSnapshot) > 0:
+		source = "kv"
+		var snapshot kv.SnapshotStore
+		snapshot, err = kv.LoadSnapshotStore(validateConfiguration.KVSnapshot)
+		if err == nil {
+			provider := &kv.Provider{Prefix: validateConfiguration.KVPrefix}
+			provider.SetKVClient(snapshot)
+			config, err = provider.BuildConfiguration()
+		}
+
+	case len(validateConfiguration.File) > 0 || len(validateConfiguration.Directory) > 0:
+		source = "file"
+		provider := &file.Provider{Directory: validateConfiguration.Directory}
+		provider.Filename = validateConfiguration.File
+		config, err = provider.BuildConfiguration()
+
+	case traefikConfiguration.File != nil:
+		source = "file"
+		config, err = traefikConfiguration.File.BuildConfiguration()
+
+	default:
+		return nil, &server.Diagnostic{
+			Level:   server.DiagnosticWarning,
+			Section: "configuration",
+			Message: "no dynamic configuration to validate, the file provider is disabled",
+		}
+	}
+
+	if err != nil {
+		return nil, &server.Diagnostic{
+			Level:   server.DiagnosticError,
+			Section: "configuration",
+			Name:    source,
+			Message: fmt.Sprintf("error loading the dynamic configuration: %v", err),
+		}
+	}
+	return config, nil
+}
+
+func writeValidationReport(report *ValidationReport, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
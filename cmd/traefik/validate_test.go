@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containous/traefik/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validateConfig = `
+defaultEntryPoints = ["http"]
+
+[entryPoints]
+  [entryPoints.http]
+  address = ":80"
+
+[file]
+
+[backends]
+  [backends.backend1]
+    [backends.backend1.servers.server1]
+    url = "http://127.0.0.1:8080"
+
+[frontends]
+  [frontends.api]
+  backend = "backend1"
+    [frontends.api.routes.api]
+    rule = "Host:foo.bar"
+`
+
+const invalidValidateConfig = `
+defaultEntryPoints = ["http", "https"]
+
+[entryPoints]
+  [entryPoints.http]
+  address = ":80"
+
+[file]
+
+[backends]
+  [backends.backend1]
+    [backends.backend1.servers.server1]
+    url = "http://127.0.0.1:8080"
+
+[frontends]
+  [frontends.api]
+  backend = "backend2"
+    [frontends.api.routes.api]
+    rule = "Host:foo.bar"
+`
+
+func TestRunValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traefik-validate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		desc                string
+		config              string
+		expectedValid       bool
+		expectedDiagnostics []server.Diagnostic
+	}{
+		{
+			desc:                "valid configuration",
+			config:              validateConfig,
+			expectedValid:       true,
+			expectedDiagnostics: []server.Diagnostic{},
+		},
+		{
+			desc:          "invalid configuration",
+			config:        invalidValidateConfig,
+			expectedValid: false,
+			expectedDiagnostics: []server.Diagnostic{
+				{Level: server.DiagnosticError, Section: "defaultEntryPoints", Name: "https", Message: "undefined entrypoint 'https'"},
+				{Level: server.DiagnosticError, Section: "frontends", Name: "api", Message: "undefined entrypoint 'https'"},
+				{Level: server.DiagnosticError, Section: "frontends", Name: "api", Message: "error creating backend backend2: undefined backend 'backend2'"},
+			},
+		},
+	}
+
+	for i, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			configFile := filepath.Join(dir, fmt.Sprintf("traefik%d.toml", i))
+			require.NoError(t, ioutil.WriteFile(configFile, []byte(test.config), 0644))
+
+			report := runValidate(&ValidateConfiguration{ConfigFile: configFile, KVPrefix: "traefik"})
+
+			assert.Equal(t, configFile, report.ConfigFile)
+			assert.Equal(t, test.expectedValid, report.Valid)
+			assert.Equal(t, test.expectedDiagnostics, report.Diagnostics)
+		})
+	}
+}
@@ -47,6 +47,9 @@ const (
 	// DefaultGraceTimeout controls how long Traefik serves pending requests
 	// prior to shutting down.
 	DefaultGraceTimeout = 10 * time.Second
+
+	// DefaultUDPSessionTimeout before closing an idle UDP session.
+	DefaultUDPSessionTimeout = 30 * time.Second
 )
 
 // GlobalConfiguration holds global configuration (with providers, etc.).
@@ -69,6 +72,7 @@ type GlobalConfiguration struct {
 	ACME                      *acme.ACME              `description:"Enable ACME (Let's Encrypt): automatic SSL" export:"true"`
 	DefaultEntryPoints        DefaultEntryPoints      `description:"Entrypoints to be used by frontends that do not specify any entrypoint" export:"true"`
 	ProvidersThrottleDuration flaeg.Duration          `description:"Backends throttle duration: minimum duration between 2 events from providers before applying a new configuration. It avoids unnecessary reloads if multiples events are sent in a short amount of time." export:"true"`
+	StrictReload              bool                    `description:"Reject a whole configuration reload, keeping the current configuration, if any frontend or backend fails to build" export:"true"`
 	MaxIdleConnsPerHost       int                     `description:"If non-zero, controls the maximum idle (keep-alive) to keep per-host.  If zero, DefaultMaxIdleConnsPerHost is used" export:"true"`
 	IdleTimeout               flaeg.Duration          `description:"(Deprecated) maximum amount of time an idle (keep-alive) connection will remain idle before closing itself." export:"true"` // Deprecated
 	InsecureSkipVerify        bool                    `description:"Disable SSL certificate verification" export:"true"`
@@ -264,6 +268,22 @@ func (gc *GlobalConfiguration) SetEffectiveConfiguration(configFile string) {
 
 // ValidateConfiguration validate that configuration is coherent
 func (gc *GlobalConfiguration) ValidateConfiguration() {
+	for entryPointName, entryPoint := range gc.EntryPoints {
+		switch strings.ToLower(entryPoint.Protocol) {
+		case "", ProtocolHTTP:
+		case ProtocolTCP:
+			if entryPoint.TLS != nil {
+				log.Fatalf("TLS is not supported on TCP entrypoint %q: TLS connections are passed through to the backends", entryPointName)
+			}
+		case ProtocolUDP:
+			if entryPoint.TLS != nil {
+				log.Fatalf("TLS is not supported on UDP entrypoint %q", entryPointName)
+			}
+		default:
+			log.Fatalf("Unknown protocol %q for entrypoint %q", entryPoint.Protocol, entryPointName)
+		}
+	}
+
 	if gc.ACME != nil {
 		if _, ok := gc.EntryPoints[gc.ACME.EntryPoint]; !ok {
 			log.Fatalf("Unknown entrypoint %q for ACME configuration", gc.ACME.EntryPoint)
@@ -2,23 +2,52 @@ package configuration
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/containous/flaeg"
 	"github.com/containous/traefik/log"
 	"github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
 )
 
+const (
+	// ProtocolHTTP is the default protocol of an entry point: HTTP and HTTPS requests are routed to the frontends
+	ProtocolHTTP = "http"
+	// ProtocolTCP makes an entry point forward raw TCP connections to the frontends, according to their HostSNI rule
+	ProtocolTCP = "tcp"
+	// ProtocolUDP makes an entry point forward UDP datagrams to the backend of its frontend
+	ProtocolUDP = "udp"
+)
+
 // EntryPoint holds an entry point configuration of the reverse proxy (ip, port, TLS...)
 type EntryPoint struct {
 	Address              string
+	Protocol             string          `export:"true"`
 	TLS                  *tls.TLS        `export:"true"`
 	Redirect             *types.Redirect `export:"true"`
 	Auth                 *types.Auth     `export:"true"`
 	WhitelistSourceRange []string
-	Compress             bool              `export:"true"`
-	ProxyProtocol        *ProxyProtocol    `export:"true"`
-	ForwardedHeaders     *ForwardedHeaders `export:"true"`
+	Compress             bool               `export:"true"`
+	Compression          *types.Compression `export:"true"`
+	ProxyProtocol        *ProxyProtocol     `export:"true"`
+	ForwardedHeaders     *ForwardedHeaders  `export:"true"`
+	UDP                  *UDP               `export:"true"`
+}
+
+// IsTCP returns true if the entry point forwards raw TCP connections
+func (ep *EntryPoint) IsTCP() bool {
+	return strings.EqualFold(ep.Protocol, ProtocolTCP)
+}
+
+// IsUDP returns true if the entry point forwards UDP datagrams
+func (ep *EntryPoint) IsUDP() bool {
+	return strings.EqualFold(ep.Protocol, ProtocolUDP)
+}
+
+// UDP holds the options of the entry points using the udp protocol
+type UDP struct {
+	SessionTimeout flaeg.Duration `description:"Duration after which an idle UDP session is closed" export:"true"`
 }
 
 // EntryPoints holds entry points configuration of the reverse proxy (ip, port, TLS...)
@@ -63,21 +92,33 @@ func (ep *EntryPoints) Set(value string) error {
 		return err
 	}
 
+	configUDP, err := makeEntryPointUDP(result)
+	if err != nil {
+		return err
+	}
+
+	auth, err := makeEntryPointAuth(result)
+	if err != nil {
+		return err
+	}
+
 	(*ep)[result["name"]] = &EntryPoint{
 		Address:              result["address"],
+		Protocol:             result["protocol"],
 		TLS:                  configTLS,
-		Auth:                 makeEntryPointAuth(result),
+		Auth:                 auth,
 		Redirect:             makeEntryPointRedirect(result),
 		Compress:             compress,
 		WhitelistSourceRange: whiteListSourceRange,
 		ProxyProtocol:        makeEntryPointProxyProtocol(result),
 		ForwardedHeaders:     makeEntryPointForwardedHeaders(result),
+		UDP:                  configUDP,
 	}
 
 	return nil
 }
 
-func makeEntryPointAuth(result map[string]string) *types.Auth {
+func makeEntryPointAuth(result map[string]string) (*types.Auth, error) {
 	var basic *types.Basic
 	if v, ok := result["auth_basic_users"]; ok {
 		basic = &types.Basic{
@@ -92,42 +133,191 @@ func makeEntryPointAuth(result map[string]string) *types.Auth {
 		}
 	}
 
-	var forward *types.Forward
-	if address, ok := result["auth_forward_address"]; ok {
-		var clientTLS *types.ClientTLS
-
-		cert := result["auth_forward_tls_cert"]
-		key := result["auth_forward_tls_key"]
-		insecureSkipVerify := toBool(result, "auth_forward_tls_insecureskipverify")
+	forward, err := makeEntryPointForward(result)
+	if err != nil {
+		return nil, err
+	}
 
-		if len(cert) > 0 && len(key) > 0 || insecureSkipVerify {
-			clientTLS = &types.ClientTLS{
-				CA:                 result["auth_forward_tls_ca"],
-				CAOptional:         toBool(result, "auth_forward_tls_caoptional"),
-				Cert:               cert,
-				Key:                key,
-				InsecureSkipVerify: insecureSkipVerify,
-			}
-		}
+	jwt, err := makeEntryPointJWT(result)
+	if err != nil {
+		return nil, err
+	}
 
-		forward = &types.Forward{
-			Address:            address,
-			TLS:                clientTLS,
-			TrustForwardHeader: toBool(result, "auth_forward_trustforwardheader"),
-		}
+	oidc, err := makeEntryPointOIDC(result)
+	if err != nil {
+		return nil, err
 	}
 
 	var auth *types.Auth
-	if basic != nil || digest != nil || forward != nil {
+	if basic != nil || digest != nil || forward != nil || jwt != nil || oidc != nil {
 		auth = &types.Auth{
 			Basic:       basic,
 			Digest:      digest,
 			Forward:     forward,
+			JWT:         jwt,
+			OIDC:        oidc,
 			HeaderField: result["auth_headerfield"],
 		}
 	}
 
-	return auth
+	return auth, nil
+}
+
+func makeEntryPointForward(result map[string]string) (*types.Forward, error) {
+	address, ok := result["auth_forward_address"]
+	if !ok {
+		return nil, nil
+	}
+
+	var clientTLS *types.ClientTLS
+
+	cert := result["auth_forward_tls_cert"]
+	key := result["auth_forward_tls_key"]
+	insecureSkipVerify := toBool(result, "auth_forward_tls_insecureskipverify")
+
+	if len(cert) > 0 && len(key) > 0 || insecureSkipVerify {
+		clientTLS = &types.ClientTLS{
+			CA:                 result["auth_forward_tls_ca"],
+			CAOptional:         toBool(result, "auth_forward_tls_caoptional"),
+			Cert:               cert,
+			Key:                key,
+			InsecureSkipVerify: insecureSkipVerify,
+		}
+	}
+
+	forward := &types.Forward{
+		Address:            address,
+		TLS:                clientTLS,
+		TrustForwardHeader: toBool(result, "auth_forward_trustforwardheader"),
+		ForwardBody:        toBool(result, "auth_forward_forwardbody"),
+	}
+
+	if len(result["auth_forward_authresponseheaders"]) > 0 {
+		forward.AuthResponseHeaders = strings.Split(result["auth_forward_authresponseheaders"], ",")
+	}
+
+	if len(result["auth_forward_maxbodysize"]) > 0 {
+		maxBodySize, err := strconv.ParseInt(result["auth_forward_maxbodysize"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid forward auth max body size %q: %v", result["auth_forward_maxbodysize"], err)
+		}
+		forward.MaxBodySize = maxBodySize
+	}
+
+	if len(result["auth_forward_cache_ttl"]) > 0 {
+		forward.Cache = &types.ForwardCache{}
+		if err := forward.Cache.TTL.Set(result["auth_forward_cache_ttl"]); err != nil {
+			return nil, err
+		}
+
+		if len(result["auth_forward_cache_key"]) > 0 {
+			forward.Cache.Key = strings.Split(result["auth_forward_cache_key"], ",")
+		}
+
+		if len(result["auth_forward_cache_maxentries"]) > 0 {
+			maxEntries, err := strconv.Atoi(result["auth_forward_cache_maxentries"])
+			if err != nil {
+				return nil, fmt.Errorf("invalid forward auth cache max entries %q: %v", result["auth_forward_cache_maxentries"], err)
+			}
+			forward.Cache.MaxEntries = maxEntries
+		}
+	}
+
+	return forward, nil
+}
+
+func makeEntryPointJWT(result map[string]string) (*types.JWT, error) {
+	secret := result["auth_jwt_secret"]
+	publicKeys := result["auth_jwt_publickeys"]
+	jwks := result["auth_jwt_jwks"]
+	if len(secret) == 0 && len(publicKeys) == 0 && len(jwks) == 0 {
+		return nil, nil
+	}
+
+	jwt := &types.JWT{
+		Secret: secret,
+		JWKS:   jwks,
+		Issuer: result["auth_jwt_issuer"],
+	}
+
+	if len(publicKeys) > 0 {
+		jwt.PublicKeys = strings.Split(publicKeys, ",")
+	}
+
+	if len(result["auth_jwt_audiences"]) > 0 {
+		jwt.Audiences = strings.Split(result["auth_jwt_audiences"], ",")
+	}
+
+	if len(result["auth_jwt_jwksrefreshinterval"]) > 0 {
+		if err := jwt.JWKSRefreshInterval.Set(result["auth_jwt_jwksrefreshinterval"]); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(result["auth_jwt_clockskew"]) > 0 {
+		if err := jwt.ClockSkew.Set(result["auth_jwt_clockskew"]); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(result["auth_jwt_allownoexpiration"]) > 0 {
+		allowNoExpiration, err := strconv.ParseBool(result["auth_jwt_allownoexpiration"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT allow no expiration %q: %v", result["auth_jwt_allownoexpiration"], err)
+		}
+		jwt.AllowNoExpiration = allowNoExpiration
+	}
+
+	if len(result["auth_jwt_claimheaders"]) > 0 {
+		jwt.ClaimHeaders = make(map[string]string)
+		for _, claimHeader := range strings.Split(result["auth_jwt_claimheaders"], ",") {
+			parts := strings.SplitN(claimHeader, ":", 2)
+			if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+				return nil, fmt.Errorf("invalid JWT claim header %q, expected Header:claim", claimHeader)
+			}
+			jwt.ClaimHeaders[parts[0]] = parts[1]
+		}
+	}
+
+	return jwt, nil
+}
+
+func makeEntryPointOIDC(result map[string]string) (*types.OIDC, error) {
+	issuer := result["auth_oidc_issuer"]
+	if len(issuer) == 0 {
+		return nil, nil
+	}
+
+	oidc := &types.OIDC{
+		Issuer:        issuer,
+		ClientID:      result["auth_oidc_clientid"],
+		ClientSecret:  result["auth_oidc_clientsecret"],
+		CallbackPath:  result["auth_oidc_callbackpath"],
+		SessionSecret: result["auth_oidc_sessionsecret"],
+		CookieName:    result["auth_oidc_cookiename"],
+		CookieDomain:  result["auth_oidc_cookiedomain"],
+		GroupsClaim:   result["auth_oidc_groupsclaim"],
+	}
+
+	if len(result["auth_oidc_scopes"]) > 0 {
+		oidc.Scopes = strings.Split(result["auth_oidc_scopes"], ",")
+	}
+
+	if len(result["auth_oidc_alloweddomains"]) > 0 {
+		oidc.AllowedDomains = strings.Split(result["auth_oidc_alloweddomains"], ",")
+	}
+
+	if len(result["auth_oidc_allowedgroups"]) > 0 {
+		oidc.AllowedGroups = strings.Split(result["auth_oidc_allowedgroups"], ",")
+	}
+
+	if len(result["auth_oidc_sessionlifetime"]) > 0 {
+		if err := oidc.SessionLifetime.Set(result["auth_oidc_sessionlifetime"]); err != nil {
+			return nil, err
+		}
+	}
+
+	return oidc, nil
 }
 
 func makeEntryPointProxyProtocol(result map[string]string) *ProxyProtocol {
@@ -182,6 +372,19 @@ func makeEntryPointRedirect(result map[string]string) *types.Redirect {
 	return redirect
 }
 
+func makeEntryPointUDP(result map[string]string) (*UDP, error) {
+	var configUDP *UDP
+
+	if len(result["udp_sessiontimeout"]) > 0 {
+		configUDP = &UDP{}
+		if err := configUDP.SessionTimeout.Set(result["udp_sessiontimeout"]); err != nil {
+			return nil, err
+		}
+	}
+
+	return configUDP, nil
+}
+
 func makeEntryPointTLS(result map[string]string) (*tls.TLS, error) {
 	var configTLS *tls.TLS
 
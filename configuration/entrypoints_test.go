@@ -2,7 +2,9 @@ package configuration
 
 import (
 	"testing"
+	"time"
 
+	"github.com/containous/flaeg"
 	"github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
 	"github.com/stretchr/testify/assert"
@@ -409,6 +411,125 @@ func TestEntryPoints_Set(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:                   "TCP protocol",
+			expression:             "Name:foo Address::5432 Protocol:tcp",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				Address:          ":5432",
+				Protocol:         "tcp",
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+		{
+			name:                   "UDP protocol",
+			expression:             "Name:foo Address::53 Protocol:udp UDP.SessionTimeout:10s",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				Address:          ":53",
+				Protocol:         "udp",
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+				UDP:              &UDP{SessionTimeout: flaeg.Duration(10 * time.Second)},
+			},
+		},
+		{
+			name: "JWT auth",
+			expression: "Name:foo " +
+				"Auth.JWT.PublicKeys:path/to/rsa.pem,path/to/ec.pem " +
+				"Auth.JWT.JWKS:https://auth.example.com/jwks.json " +
+				"Auth.JWT.JWKSRefreshInterval:30m " +
+				"Auth.JWT.Issuer:https://auth.example.com " +
+				"Auth.JWT.Audiences:api,admin " +
+				"Auth.JWT.ClockSkew:30s " +
+				"Auth.JWT.AllowNoExpiration:true " +
+				"Auth.JWT.ClaimHeaders:X-User:sub,X-Roles:roles " +
+				"Auth.HeaderField:X-WebAuth-User",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				Auth: &types.Auth{
+					JWT: &types.JWT{
+						PublicKeys:          []string{"path/to/rsa.pem", "path/to/ec.pem"},
+						JWKS:                "https://auth.example.com/jwks.json",
+						JWKSRefreshInterval: flaeg.Duration(30 * time.Minute),
+						Issuer:              "https://auth.example.com",
+						Audiences:           []string{"api", "admin"},
+						ClockSkew:           flaeg.Duration(30 * time.Second),
+						AllowNoExpiration:   true,
+						ClaimHeaders:        map[string]string{"X-User": "sub", "X-Roles": "roles"},
+					},
+					HeaderField: "X-WebAuth-User",
+				},
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+		{
+			name: "Forward auth",
+			expression: "Name:foo " +
+				"Auth.Forward.Address:https://auth.example.com/verify " +
+				"Auth.Forward.TrustForwardHeader:true " +
+				"Auth.Forward.AuthResponseHeaders:X-Auth-User,X-Auth-Groups " +
+				"Auth.Forward.ForwardBody:true " +
+				"Auth.Forward.MaxBodySize:4096 " +
+				"Auth.Forward.Cache.TTL:30s " +
+				"Auth.Forward.Cache.Key:host,header:Authorization " +
+				"Auth.Forward.Cache.MaxEntries:100",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				Auth: &types.Auth{
+					Forward: &types.Forward{
+						Address:             "https://auth.example.com/verify",
+						TrustForwardHeader:  true,
+						AuthResponseHeaders: []string{"X-Auth-User", "X-Auth-Groups"},
+						ForwardBody:         true,
+						MaxBodySize:         4096,
+						Cache: &types.ForwardCache{
+							TTL:        flaeg.Duration(30 * time.Second),
+							Key:        []string{"host", "header:Authorization"},
+							MaxEntries: 100,
+						},
+					},
+				},
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
+		{
+			name: "OIDC auth",
+			expression: "Name:foo " +
+				"Auth.OIDC.Issuer:https://auth.example.com " +
+				"Auth.OIDC.ClientID:dashboard " +
+				"Auth.OIDC.ClientSecret:clientsecret " +
+				"Auth.OIDC.Scopes:openid,email,groups " +
+				"Auth.OIDC.CallbackPath:/oauth2/callback " +
+				"Auth.OIDC.SessionSecret:sessionsecret " +
+				"Auth.OIDC.SessionLifetime:12h " +
+				"Auth.OIDC.CookieName:_dashboard " +
+				"Auth.OIDC.CookieDomain:example.com " +
+				"Auth.OIDC.AllowedDomains:example.com,example.org " +
+				"Auth.OIDC.AllowedGroups:admins " +
+				"Auth.OIDC.GroupsClaim:roles " +
+				"Auth.HeaderField:X-WebAuth-User",
+			expectedEntryPointName: "foo",
+			expectedEntryPoint: &EntryPoint{
+				Auth: &types.Auth{
+					OIDC: &types.OIDC{
+						Issuer:          "https://auth.example.com",
+						ClientID:        "dashboard",
+						ClientSecret:    "clientsecret",
+						Scopes:          []string{"openid", "email", "groups"},
+						CallbackPath:    "/oauth2/callback",
+						SessionSecret:   "sessionsecret",
+						SessionLifetime: flaeg.Duration(12 * time.Hour),
+						CookieName:      "_dashboard",
+						CookieDomain:    "example.com",
+						AllowedDomains:  []string{"example.com", "example.org"},
+						AllowedGroups:   []string{"admins"},
+						GroupsClaim:     "roles",
+					},
+					HeaderField: "X-WebAuth-User",
+				},
+				ForwardedHeaders: &ForwardedHeaders{Insecure: true},
+			},
+		},
 		{
 			name:                   "compress on",
 			expression:             "Name:foo Compress:on",
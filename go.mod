@@ -0,0 +1,15 @@
+module github.com/golang/dep
+
+go 1.27.1
+
+require (
+	github.com/BurntSushi/toml v1.3.2 // indirect
+	github.com/Masterminds/vcs v1.11.1 // indirect
+	github.com/armon/go-radix v0.0.0-20160115234725-4239b77079c7 // indirect
+	github.com/pelletier/go-toml v1.0.2-0.20171218135716-b8b5e7696574 // indirect
+	github.com/pkg/errors v0.8.0 // indirect
+	github.com/sdboyer/constext v0.0.0-20170321163424-836a14457353 // indirect
+	golang.org/x/net v0.0.0-20170828231752-66aacef3dd8a // indirect
+	golang.org/x/sync v0.0.0-20170517211232-f52d1811a629 // indirect
+	gopkg.in/yaml.v2 v2.0.0-20170407172122-cd8b52f8269e // indirect
+)
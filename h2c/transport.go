@@ -0,0 +1,56 @@
+package h2c
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"golang.org/x/net/http2"
+)
+
+// Scheme is the URL scheme of the servers speaking HTTP/2 over cleartext TCP (h2c), such as plaintext gRPC servers.
+const Scheme = "h2c"
+
+// Transport is a round tripper forwarding the requests with the h2c scheme with HTTP/2 over cleartext TCP,
+// and the other requests to the wrapped round tripper.
+type Transport struct {
+	next http.RoundTripper
+	h2c  *http2.Transport
+}
+
+// NewTransport creates a new Transport wrapping next, and dialing the h2c servers with the given dialer.
+func NewTransport(next http.RoundTripper, dialer *net.Dialer) *Transport {
+	return &Transport{
+		next: next,
+		h2c: &http2.Transport{
+			AllowHTTP: true,
+			// The h2c connections are plain TCP connections, without any TLS handshake.
+			DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+				return dialer.Dial(network, addr)
+			},
+		},
+	}
+}
+
+// TLSClientConfig returns the TLS configuration of the wrapped round tripper, if it is an *http.Transport.
+func (t *Transport) TLSClientConfig() *tls.Config {
+	if transport, ok := t.next.(*http.Transport); ok {
+		return transport.TLSClientConfig
+	}
+	return nil
+}
+
+// RoundTrip executes a single HTTP transaction.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != Scheme {
+		return t.next.RoundTrip(req)
+	}
+
+	// make shallow copy of request before changing anything to avoid side effects
+	outReq := *req
+	u := *req.URL
+	u.Scheme = "http"
+	outReq.URL = &u
+
+	return t.h2c.RoundTrip(&outReq)
+}
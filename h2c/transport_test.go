@@ -0,0 +1,47 @@
+package h2c
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportH2C(t *testing.T) {
+	listener := testhelpers.NewH2CServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(req.Proto + " " + req.URL.Path))
+	}))
+	defer listener.Close()
+
+	transport := NewTransport(failingRoundTripper{}, &net.Dialer{})
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "h2c://"+listener.Addr().String()+"/foo", nil)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/2.0 /foo", string(body))
+
+	// the original request is left untouched
+	assert.Equal(t, Scheme, req.URL.Scheme)
+}
+
+func TestTransportOtherSchemes(t *testing.T) {
+	transport := NewTransport(failingRoundTripper{}, &net.Dialer{})
+
+	_, err := transport.RoundTrip(testhelpers.MustNewRequest(http.MethodGet, "http://localhost/foo", nil))
+	assert.EqualError(t, err, "next round tripper")
+}
+
+type failingRoundTripper struct{}
+
+func (failingRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("next round tripper")
+}
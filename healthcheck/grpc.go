@@ -0,0 +1,189 @@
+package healthcheck
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/containous/traefik/h2c"
+)
+
+// grpcHealthCheckPath is the path of the Check method of the gRPC health checking protocol,
+// see https://github.com/grpc/grpc/blob/master/doc/health-checking.md
+const grpcHealthCheckPath = "/grpc.health.v1.Health/Check"
+
+// grpcServingStatuses are the names of the values of the status of the HealthCheckResponse message.
+var grpcServingStatuses = map[uint64]string{
+	0: "UNKNOWN",
+	1: "SERVING",
+	2: "NOT_SERVING",
+	3: "SERVICE_UNKNOWN",
+}
+
+const grpcServing = 1
+
+// grpcService returns the name of the service checked by the gRPC health checks.
+// The path "/" stands for the overall health of the server.
+func (backend *BackendHealthCheck) grpcService() string {
+	return strings.Trim(backend.Path, "/")
+}
+
+func (backend *BackendHealthCheck) newGRPCRequest(serverURL *url.URL) (*http.Request, error) {
+	u := backend.targetURL(serverURL)
+	// gRPC requires HTTP/2, which is spoken over cleartext TCP with the plaintext servers.
+	if u.Scheme == "http" {
+		u.Scheme = h2c.Scheme
+	}
+	u.Path = grpcHealthCheckPath
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(encodeGRPCHealthCheckRequest(backend.grpcService())))
+	if err != nil {
+		return nil, err
+	}
+	backend.setHeaders(req)
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("Te", "trailers")
+
+	return req, nil
+}
+
+// checkGRPCHealth returns a nil error if the server answers SERVING to a gRPC health check request.
+func checkGRPCHealth(serverURL *url.URL, backend *BackendHealthCheck) error {
+	transport := backend.Options.Transport
+	if transport == nil {
+		transport = h2c.NewTransport(http.DefaultTransport, &net.Dialer{Timeout: backend.requestTimeout})
+	}
+	client := http.Client{
+		Timeout:   backend.requestTimeout,
+		Transport: transport,
+	}
+
+	req, err := backend.newGRPCRequest(serverURL)
+	if err != nil {
+		return fmt.Errorf("failed to create gRPC request: %s", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("gRPC request failed: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("received unexpected status code: %v", resp.StatusCode)
+	}
+
+	// the body is read before the gRPC status, as the trailers are only available once the body is consumed
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %s", err)
+	}
+
+	// the status of the Trailers-Only responses is sent in the headers
+	grpcStatus := resp.Trailer.Get("Grpc-Status")
+	grpcMessage := resp.Trailer.Get("Grpc-Message")
+	if len(grpcStatus) == 0 {
+		grpcStatus = resp.Header.Get("Grpc-Status")
+		grpcMessage = resp.Header.Get("Grpc-Message")
+	}
+	if grpcStatus != "0" {
+		return fmt.Errorf("received unexpected gRPC status: %q %s", grpcStatus, grpcMessage)
+	}
+
+	status, err := decodeGRPCHealthCheckResponse(body)
+	if err != nil {
+		return fmt.Errorf("invalid gRPC health check response: %s", err)
+	}
+	if status != grpcServing {
+		name, ok := grpcServingStatuses[status]
+		if !ok {
+			name = fmt.Sprintf("%d", status)
+		}
+		return fmt.Errorf("received unexpected serving status: %s", name)
+	}
+	return nil
+}
+
+// encodeGRPCHealthCheckRequest returns the gRPC message of a HealthCheckRequest for the given service.
+func encodeGRPCHealthCheckRequest(service string) []byte {
+	var message []byte
+	if len(service) > 0 {
+		// field 1, length-delimited
+		message = append(message, 0x0a)
+		message = appendUvarint(message, uint64(len(service)))
+		message = append(message, service...)
+	}
+
+	frame := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	return append(frame, message...)
+}
+
+// decodeGRPCHealthCheckResponse returns the status of the HealthCheckResponse gRPC message.
+func decodeGRPCHealthCheckResponse(body []byte) (uint64, error) {
+	if len(body) < 5 {
+		return 0, errors.New("missing message")
+	}
+	if body[0] != 0 {
+		return 0, errors.New("compressed messages are not supported")
+	}
+	length := binary.BigEndian.Uint32(body[1:5])
+	message := body[5:]
+	if uint32(len(message)) < length {
+		return 0, errors.New("truncated message")
+	}
+	message = message[:length]
+
+	var status uint64
+	for len(message) > 0 {
+		key, n := binary.Uvarint(message)
+		if n <= 0 {
+			return 0, errors.New("invalid field key")
+		}
+		message = message[n:]
+
+		var size int
+		switch wireType := key & 0x7; wireType {
+		case 0:
+			value, n := binary.Uvarint(message)
+			if n <= 0 {
+				return 0, errors.New("invalid varint")
+			}
+			if key>>3 == 1 {
+				status = value
+			}
+			size = n
+		case 1:
+			size = 8
+		case 2:
+			length, n := binary.Uvarint(message)
+			if n <= 0 {
+				return 0, errors.New("invalid length")
+			}
+			size = n + int(length)
+		case 5:
+			size = 4
+		default:
+			return 0, fmt.Errorf("unsupported wire type %d", wireType)
+		}
+
+		if size < 0 || size > len(message) {
+			return 0, errors.New("truncated field")
+		}
+		message = message[size:]
+	}
+	return status, nil
+}
+
+func appendUvarint(buf []byte, value uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], value)
+	return append(buf, tmp[:n]...)
+}
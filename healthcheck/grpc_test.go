@@ -0,0 +1,159 @@
+package healthcheck
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckGRPCHealth(t *testing.T) {
+	// statuses of the services of the test server, the empty name being the overall server
+	services := map[string]byte{
+		"":        1,
+		"serving": 1,
+		"stopped": 2,
+	}
+
+	listener := testhelpers.NewH2CServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != grpcHealthCheckPath || r.Header.Get("Content-Type") != "application/grpc" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil || len(body) < 5 {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// the request only holds the service name: 0x0a, length, name
+		var service string
+		if len(body) > 7 {
+			service = string(body[7:])
+		}
+
+		rw.Header().Set("Content-Type", "application/grpc")
+		status, ok := services[service]
+		if !ok {
+			// Trailers-Only response
+			rw.Header().Set("Grpc-Status", "5")
+			rw.Header().Set("Grpc-Message", "unknown service")
+			rw.WriteHeader(http.StatusOK)
+			return
+		}
+
+		rw.Header().Set("Trailer", "Grpc-Status")
+		rw.WriteHeader(http.StatusOK)
+		rw.Write([]byte{0, 0, 0, 0, 2, 0x08, status})
+		rw.Header().Set("Grpc-Status", "0")
+	}))
+	defer listener.Close()
+
+	tests := []struct {
+		desc      string
+		scheme    string
+		path      string
+		wantError bool
+	}{
+		{
+			desc:   "server serving",
+			scheme: "h2c",
+			path:   "/",
+		},
+		{
+			desc:   "service serving",
+			scheme: "h2c",
+			path:   "/serving",
+		},
+		{
+			desc:   "plaintext server",
+			scheme: "http",
+			path:   "serving",
+		},
+		{
+			desc:      "service not serving",
+			scheme:    "h2c",
+			path:      "/stopped",
+			wantError: true,
+		},
+		{
+			desc:      "unknown service",
+			scheme:    "h2c",
+			path:      "/unknown",
+			wantError: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			backend := NewBackendHealthCheck(Options{Mode: ModeGRPC, Path: test.path}, "backendName")
+
+			err := checkHealth(&url.URL{Scheme: test.scheme, Host: listener.Addr().String()}, backend)
+			if test.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDecodeGRPCHealthCheckResponse(t *testing.T) {
+	tests := []struct {
+		desc       string
+		body       []byte
+		wantStatus uint64
+		wantError  bool
+	}{
+		{
+			desc:       "serving",
+			body:       []byte{0, 0, 0, 0, 2, 0x08, 1},
+			wantStatus: 1,
+		},
+		{
+			desc:       "empty message",
+			body:       []byte{0, 0, 0, 0, 0},
+			wantStatus: 0,
+		},
+		{
+			desc:       "unknown fields",
+			body:       []byte{0, 0, 0, 0, 11, 0x12, 2, 'o', 'k', 0x08, 2, 0x1d, 1, 2, 3, 4},
+			wantStatus: 2,
+		},
+		{
+			desc:      "truncated message",
+			body:      []byte{0, 0, 0, 0, 4, 0x08, 1},
+			wantError: true,
+		},
+		{
+			desc:      "compressed message",
+			body:      []byte{1, 0, 0, 0, 2, 0x08, 1},
+			wantError: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			status, err := decodeGRPCHealthCheckResponse(test.body)
+			if test.wantError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantStatus, status)
+		})
+	}
+}
+
+func TestEncodeGRPCHealthCheckRequest(t *testing.T) {
+	assert.Equal(t, []byte{0, 0, 0, 0, 0}, encodeGRPCHealthCheckRequest(""))
+	assert.Equal(t, []byte{0, 0, 0, 0, 5, 0x0a, 3, 'f', 'o', 'o'}, encodeGRPCHealthCheckRequest("foo"))
+}
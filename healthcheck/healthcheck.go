@@ -3,15 +3,21 @@ package healthcheck
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/containous/traefik/log"
 	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/types"
 	"github.com/go-kit/kit/metrics"
 	"github.com/vulcand/oxy/roundrobin"
 )
@@ -27,25 +33,62 @@ func GetHealthCheck(metrics metricsRegistry) *HealthCheck {
 	return singleton
 }
 
+const (
+	// ModeHTTP checks the servers with an HTTP GET request on the health check path
+	ModeHTTP = "http"
+	// ModeTCP checks that a TCP connection can be opened to the servers
+	ModeTCP = "tcp"
+	// ModeGRPC checks the servers with the gRPC health checking protocol, the health check path being the service name
+	ModeGRPC = "grpc"
+)
+
+// DefaultTimeout is the default timeout of a health check request.
+const DefaultTimeout = 5 * time.Second
+
+// maxBodySize is the maximum size of the response body read to be matched against the body regular expression.
+const maxBodySize = 1 << 20
+
 // Options are the public health check options.
 type Options struct {
-	Path      string
-	Port      int
-	Transport http.RoundTripper
-	Interval  time.Duration
-	LB        LoadBalancer
+	Mode               string
+	Scheme             string
+	Path               string
+	Port               int
+	Hostname           string
+	Headers            map[string]string
+	Transport          http.RoundTripper
+	Interval           time.Duration
+	Timeout            time.Duration
+	Status             []types.StatusRange
+	Body               *regexp.Regexp
+	HealthyThreshold   int
+	UnhealthyThreshold int
+	LB                 LoadBalancer
 }
 
 func (opt Options) String() string {
-	return fmt.Sprintf("[Path: %s Port: %d Interval: %s]", opt.Path, opt.Port, opt.Interval)
+	if opt.Mode == ModeTCP {
+		return fmt.Sprintf("[Mode: %s Port: %d Interval: %s Timeout: %s]", opt.Mode, opt.Port, opt.Interval, opt.Timeout)
+	}
+	if opt.Mode == ModeGRPC {
+		return fmt.Sprintf("[Mode: %s Service: %s Port: %d Interval: %s Timeout: %s]", opt.Mode, opt.Path, opt.Port, opt.Interval, opt.Timeout)
+	}
+	return fmt.Sprintf("[Path: %s Port: %d Interval: %s Timeout: %s]", opt.Path, opt.Port, opt.Interval, opt.Timeout)
 }
 
 // BackendHealthCheck HealthCheck configuration for a backend
 type BackendHealthCheck struct {
 	Options
 	name           string
-	disabledURLs   []*url.URL
 	requestTimeout time.Duration
+	// disabledLock guards the disabled servers, shared with the outlier detector of the backend.
+	disabledLock sync.Mutex
+	disabledURLs []*url.URL
+	// handedOverURLs are the servers handed over by the outlier detector, disabled at the next check.
+	handedOverURLs []*url.URL
+	// failures and successes count the consecutive results of the health checks, per server URL.
+	failures  map[string]int
+	successes map[string]int
 }
 
 //HealthCheck struct
@@ -53,6 +96,9 @@ type HealthCheck struct {
 	Backends map[string]*BackendHealthCheck
 	metrics  metricsRegistry
 	cancel   context.CancelFunc
+
+	outliersLock sync.RWMutex
+	outliers     map[string]*OutlierDetector
 }
 
 // LoadBalancer includes functionality for load-balancing management.
@@ -77,10 +123,24 @@ type metricsRegistry interface {
 
 // NewBackendHealthCheck Instantiate a new BackendHealthCheck
 func NewBackendHealthCheck(options Options, backendName string) *BackendHealthCheck {
+	if options.HealthyThreshold <= 0 {
+		options.HealthyThreshold = 1
+	}
+	if options.UnhealthyThreshold <= 0 {
+		options.UnhealthyThreshold = 1
+	}
+
+	requestTimeout := DefaultTimeout
+	if options.Timeout > 0 {
+		requestTimeout = options.Timeout
+	}
+
 	return &BackendHealthCheck{
 		Options:        options,
 		name:           backendName,
-		requestTimeout: 5 * time.Second,
+		requestTimeout: requestTimeout,
+		failures:       make(map[string]int),
+		successes:      make(map[string]int),
 	}
 }
 
@@ -101,6 +161,31 @@ func (hc *HealthCheck) SetBackendsConfiguration(parentCtx context.Context, backe
 	}
 }
 
+// SetOutlierDetectors set the outlier detectors of the backends
+func (hc *HealthCheck) SetOutlierDetectors(detectors map[string]*OutlierDetector) {
+	hc.outliersLock.Lock()
+	defer hc.outliersLock.Unlock()
+	hc.outliers = detectors
+}
+
+// EjectedServers returns the servers currently ejected by the outlier detectors
+func (hc *HealthCheck) EjectedServers() []EjectedServer {
+	hc.outliersLock.RLock()
+	defer hc.outliersLock.RUnlock()
+
+	ejected := []EjectedServer{}
+	for _, detector := range hc.outliers {
+		ejected = append(ejected, detector.EjectedServers()...)
+	}
+	sort.Slice(ejected, func(i, j int) bool {
+		if ejected[i].Backend != ejected[j].Backend {
+			return ejected[i].Backend < ejected[j].Backend
+		}
+		return ejected[i].URL < ejected[j].URL
+	})
+	return ejected
+}
+
 func (hc *HealthCheck) execute(ctx context.Context, backend *BackendHealthCheck) {
 	log.Debugf("Initial health check for backend: %q", backend.name)
 	hc.checkBackend(backend)
@@ -120,71 +205,200 @@ func (hc *HealthCheck) execute(ctx context.Context, backend *BackendHealthCheck)
 
 func (hc *HealthCheck) checkBackend(backend *BackendHealthCheck) {
 	enabledURLs := backend.LB.Servers()
+
+	backend.disabledLock.Lock()
+	backend.disabledURLs = append(backend.disabledURLs, backend.handedOverURLs...)
+	backend.handedOverURLs = nil
+	disabledURLs := backend.disabledURLs
+	backend.disabledLock.Unlock()
+
 	var newDisabledURLs []*url.URL
-	for _, url := range backend.disabledURLs {
+	for _, url := range disabledURLs {
 		serverUpMetricValue := float64(0)
 		if err := checkHealth(url, backend); err == nil {
-			log.Warnf("Health check up: Returning to server list. Backend: %q URL: %q", backend.name, url.String())
-			backend.LB.UpsertServer(url, roundrobin.Weight(1))
-			serverUpMetricValue = 1
+			backend.successes[url.String()]++
+			if backend.successes[url.String()] >= backend.HealthyThreshold {
+				log.Warnf("Health check up: Returning to server list. Backend: %q URL: %q", backend.name, url.String())
+				delete(backend.successes, url.String())
+				backend.LB.UpsertServer(url, roundrobin.Weight(1))
+				serverUpMetricValue = 1
+			} else {
+				log.Warnf("Health check up (%d/%d). Backend: %q URL: %q", backend.successes[url.String()], backend.HealthyThreshold, backend.name, url.String())
+				newDisabledURLs = append(newDisabledURLs, url)
+			}
 		} else {
 			log.Warnf("Health check still failing. Backend: %q URL: %q Reason: %s", backend.name, url.String(), err)
+			delete(backend.successes, url.String())
 			newDisabledURLs = append(newDisabledURLs, url)
 		}
 		labelValues := []string{"backend", backend.name, "url", url.String()}
 		hc.metrics.BackendServerUpGauge().With(labelValues...).Set(serverUpMetricValue)
 	}
+	backend.disabledLock.Lock()
 	backend.disabledURLs = newDisabledURLs
+	backend.disabledLock.Unlock()
 
 	for _, url := range enabledURLs {
 		serverUpMetricValue := float64(1)
 		if err := checkHealth(url, backend); err != nil {
-			log.Warnf("Health check failed: Remove from server list. Backend: %q URL: %q Reason: %s", backend.name, url.String(), err)
-			backend.LB.RemoveServer(url)
-			backend.disabledURLs = append(backend.disabledURLs, url)
-			serverUpMetricValue = 0
+			backend.failures[url.String()]++
+			if backend.failures[url.String()] >= backend.UnhealthyThreshold {
+				log.Warnf("Health check failed: Remove from server list. Backend: %q URL: %q Reason: %s", backend.name, url.String(), err)
+				delete(backend.failures, url.String())
+				backend.LB.RemoveServer(url)
+				backend.disabledLock.Lock()
+				backend.disabledURLs = append(backend.disabledURLs, url)
+				backend.disabledLock.Unlock()
+				serverUpMetricValue = 0
+			} else {
+				log.Warnf("Health check failed (%d/%d). Backend: %q URL: %q Reason: %s", backend.failures[url.String()], backend.UnhealthyThreshold, backend.name, url.String(), err)
+			}
+		} else {
+			delete(backend.failures, url.String())
 		}
 		labelValues := []string{"backend", backend.name, "url", url.String()}
 		hc.metrics.BackendServerUpGauge().With(labelValues...).Set(serverUpMetricValue)
 	}
 }
 
+// isDisabled returns whether the server is removed from the load balancer by the health check.
+func (backend *BackendHealthCheck) isDisabled(serverURL *url.URL) bool {
+	backend.disabledLock.Lock()
+	defer backend.disabledLock.Unlock()
+
+	for _, urls := range [][]*url.URL{backend.disabledURLs, backend.handedOverURLs} {
+		for _, u := range urls {
+			if u.String() == serverURL.String() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handOver hands over a server missing from the load balancer, returned once its health checks succeed.
+func (backend *BackendHealthCheck) handOver(serverURL *url.URL) {
+	backend.disabledLock.Lock()
+	defer backend.disabledLock.Unlock()
+	backend.handedOverURLs = append(backend.handedOverURLs, serverURL)
+}
+
 func (backend *BackendHealthCheck) newRequest(serverURL *url.URL) (*http.Request, error) {
-	if backend.Port == 0 {
-		return http.NewRequest(http.MethodGet, serverURL.String()+backend.Path, nil)
+	u := backend.targetURL(serverURL)
+
+	req, err := http.NewRequest(http.MethodGet, u.String()+backend.Path, nil)
+	if err != nil {
+		return nil, err
 	}
+	backend.setHeaders(req)
+
+	return req, nil
+}
 
-	// copy the url and add the port to the host
+// targetURL returns a copy of the server URL, with the scheme and the port overridden by the health check ones.
+func (backend *BackendHealthCheck) targetURL(serverURL *url.URL) *url.URL {
 	u := &url.URL{}
 	*u = *serverURL
-	u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(backend.Port))
-	u.Path = u.Path + backend.Path
+	if backend.Scheme != "" {
+		u.Scheme = backend.Scheme
+	}
+	if backend.Port != 0 {
+		u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(backend.Port))
+	}
+	return u
+}
+
+// setHeaders sets the hostname and the headers of the health check on the request.
+func (backend *BackendHealthCheck) setHeaders(req *http.Request) {
+	if backend.Hostname != "" {
+		req.Host = backend.Hostname
+	}
+	for k, v := range backend.Headers {
+		if strings.EqualFold(k, "Host") {
+			req.Host = v
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+}
+
+// checkResponse checks the status code of the response, and its body when a body regular expression is set.
+func (backend *BackendHealthCheck) checkResponse(resp *http.Response) error {
+	if !backend.isExpectedStatus(resp.StatusCode) {
+		return fmt.Errorf("received unexpected status code: %v", resp.StatusCode)
+	}
+
+	if backend.Body != nil {
+		body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %s", err)
+		}
+		if !backend.Body.Match(body) {
+			return fmt.Errorf("response body does not match %q", backend.Body.String())
+		}
+	}
+	return nil
+}
+
+// isExpectedStatus returns true if the status code is in one of the status ranges, or is 200 when none is set.
+func (backend *BackendHealthCheck) isExpectedStatus(code int) bool {
+	if len(backend.Status) == 0 {
+		return code == http.StatusOK
+	}
 
-	return http.NewRequest(http.MethodGet, u.String(), nil)
+	for _, statusRange := range backend.Status {
+		if statusRange.Contains(code) {
+			return true
+		}
+	}
+	return false
 }
 
 // checkHealth returns a nil error in case it was successful and otherwise
 // a non-nil error with a meaningful description why the health check failed.
 func checkHealth(serverURL *url.URL, backend *BackendHealthCheck) error {
+	switch backend.Mode {
+	case ModeTCP:
+		return checkTCPHealth(serverURL, backend)
+	case ModeGRPC:
+		return checkGRPCHealth(serverURL, backend)
+	}
+
 	client := http.Client{
 		Timeout:   backend.requestTimeout,
 		Transport: backend.Options.Transport,
 	}
+	if len(backend.Status) > 0 {
+		// the redirections are not followed, so that the expected status codes can include 3xx codes
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
 	req, err := backend.newRequest(serverURL)
 	if err != nil {
 		return fmt.Errorf("failed to create HTTP request: %s", err)
 	}
 
 	resp, err := client.Do(req)
-	if err == nil {
-		defer resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %s", err)
 	}
+	defer resp.Body.Close()
 
-	switch {
-	case err != nil:
-		return fmt.Errorf("HTTP request failed: %s", err)
-	case resp.StatusCode != http.StatusOK:
-		return fmt.Errorf("received non-200 status code: %v", resp.StatusCode)
+	return backend.checkResponse(resp)
+}
+
+// checkTCPHealth returns a nil error if a TCP connection can be opened to the server.
+func checkTCPHealth(serverURL *url.URL, backend *BackendHealthCheck) error {
+	address := serverURL.Host
+	if backend.Port != 0 {
+		address = net.JoinHostPort(serverURL.Hostname(), strconv.Itoa(backend.Port))
 	}
-	return nil
+
+	conn, err := net.DialTimeout("tcp", address, backend.requestTimeout)
+	if err != nil {
+		return fmt.Errorf("TCP connection failed: %s", err)
+	}
+	return conn.Close()
 }
@@ -2,14 +2,20 @@ package healthcheck
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"regexp"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/vulcand/oxy/roundrobin"
 )
 
@@ -200,6 +206,228 @@ func TestNewRequest(t *testing.T) {
 	}
 }
 
+func TestNewRequestOptions(t *testing.T) {
+	tests := []struct {
+		desc         string
+		options      Options
+		expectedURL  string
+		expectedHost string
+		expectedFoo  string
+	}{
+		{
+			desc:         "scheme override",
+			options:      Options{Scheme: "https", Path: "/health"},
+			expectedURL:  "https://backend1:80/health",
+			expectedHost: "backend1:80",
+		},
+		{
+			desc:         "hostname override",
+			options:      Options{Path: "/health", Hostname: "foo.bar"},
+			expectedURL:  "http://backend1:80/health",
+			expectedHost: "foo.bar",
+		},
+		{
+			desc:         "custom headers",
+			options:      Options{Path: "/health", Headers: map[string]string{"X-Foo": "bar", "Host": "foo.bar"}},
+			expectedURL:  "http://backend1:80/health",
+			expectedHost: "foo.bar",
+			expectedFoo:  "bar",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			backend := NewBackendHealthCheck(test.options, "backendName")
+
+			req, err := backend.newRequest(&url.URL{Scheme: "http", Host: "backend1:80"})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedURL, req.URL.String())
+			assert.Equal(t, test.expectedHost, req.Host)
+			assert.Equal(t, test.expectedFoo, req.Header.Get("X-Foo"))
+		})
+	}
+}
+
+func TestCheckHealthResponse(t *testing.T) {
+	tests := []struct {
+		desc      string
+		code      int
+		body      string
+		status    []types.StatusRange
+		bodyRegex string
+		wantError bool
+	}{
+		{
+			desc: "default status",
+			code: http.StatusOK,
+		},
+		{
+			desc:      "non-200 status without status ranges",
+			code:      http.StatusNoContent,
+			wantError: true,
+		},
+		{
+			desc:   "status in range",
+			code:   http.StatusNoContent,
+			status: []types.StatusRange{{Low: 200, High: 299}},
+		},
+		{
+			desc:   "redirection status accepted",
+			code:   http.StatusFound,
+			status: []types.StatusRange{{Low: 200, High: 299}, {Low: 302, High: 302}},
+		},
+		{
+			desc:      "status out of range",
+			code:      http.StatusServiceUnavailable,
+			status:    []types.StatusRange{{Low: 200, High: 399}},
+			wantError: true,
+		},
+		{
+			desc:      "body matching",
+			code:      http.StatusOK,
+			body:      `{"status": "up"}`,
+			bodyRegex: `"status":\s*"up"`,
+		},
+		{
+			desc:      "body not matching",
+			code:      http.StatusOK,
+			body:      `{"status": "down"}`,
+			bodyRegex: `"status":\s*"up"`,
+			wantError: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				if test.code == http.StatusFound {
+					rw.Header().Set("Location", "/elsewhere")
+				}
+				rw.WriteHeader(test.code)
+				rw.Write([]byte(test.body))
+			}))
+			defer ts.Close()
+
+			options := Options{Path: "/health", Status: test.status}
+			if test.bodyRegex != "" {
+				options.Body = regexp.MustCompile(test.bodyRegex)
+			}
+			backend := NewBackendHealthCheck(options, "backendName")
+
+			err := checkHealth(testhelpers.MustParseURL(ts.URL), backend)
+			if test.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckBackendThresholds(t *testing.T) {
+	healthy := true
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+	serverURL := testhelpers.MustParseURL(ts.URL)
+	lb.servers = append(lb.servers, serverURL)
+
+	backend := NewBackendHealthCheck(Options{
+		Path:               "/health",
+		HealthyThreshold:   3,
+		UnhealthyThreshold: 2,
+		LB:                 lb,
+	}, "backendName")
+	check := HealthCheck{metrics: testhelpers.NewCollectingHealthCheckMetrics()}
+
+	healthy = false
+	check.checkBackend(backend)
+	assert.Len(t, lb.Servers(), 1, "the server should stay enabled below the unhealthy threshold")
+	check.checkBackend(backend)
+	assert.Empty(t, lb.Servers(), "the server should be disabled once the unhealthy threshold is reached")
+
+	healthy = true
+	check.checkBackend(backend)
+	check.checkBackend(backend)
+	assert.Empty(t, lb.Servers(), "the server should stay disabled below the healthy threshold")
+	check.checkBackend(backend)
+	assert.Len(t, lb.Servers(), 1, "the server should be enabled once the healthy threshold is reached")
+}
+
+func TestCheckTCPHealth(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer listener.Close()
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to split listener address: %s", err)
+	}
+	listenerPort, _ := strconv.Atoi(port)
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	tests := []struct {
+		desc      string
+		host      string
+		port      int
+		wantError bool
+	}{
+		{
+			desc: "server accepting connections",
+			host: listener.Addr().String(),
+		},
+		{
+			desc:      "server refusing connections",
+			host:      closedAddr,
+			wantError: true,
+		},
+		{
+			desc: "port override",
+			host: closedAddr,
+			port: listenerPort,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			backend := NewBackendHealthCheck(
+				Options{
+					Mode: ModeTCP,
+					Port: test.port,
+				}, "backendName")
+
+			err := checkHealth(&url.URL{Scheme: "tcp", Host: test.host}, backend)
+			if test.wantError && err == nil {
+				t.Fatal("expected an error, got none")
+			}
+			if !test.wantError && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
 type testLoadBalancer struct {
 	// RWMutex needed due to parallel test execution: Both the system-under-test
 	// and the test assertions reference the counters.
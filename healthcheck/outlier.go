@@ -0,0 +1,254 @@
+package healthcheck
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+const (
+	// DefaultConsecutiveErrors is the number of consecutive errors ejecting a server, when none is configured.
+	DefaultConsecutiveErrors = 5
+	// DefaultBaseEjectionTime is the duration of the first ejection of a server, when none is configured.
+	DefaultBaseEjectionTime = 30 * time.Second
+	// DefaultMaxEjectionTime is the maximum duration of an ejection, when none is configured.
+	DefaultMaxEjectionTime = 300 * time.Second
+	// DefaultMaxEjectionPercent is the maximum percentage of ejected servers of a backend, when none is configured.
+	DefaultMaxEjectionPercent = 50
+)
+
+// OutlierOptions are the passive health check options.
+type OutlierOptions struct {
+	ConsecutiveErrors  int
+	BaseEjectionTime   time.Duration
+	MaxEjectionTime    time.Duration
+	MaxEjectionPercent int
+	LB                 LoadBalancer
+	// HealthCheck is the active health check of the backend, if any.
+	HealthCheck *BackendHealthCheck
+}
+
+func (opt OutlierOptions) String() string {
+	return fmt.Sprintf("[ConsecutiveErrors: %d BaseEjectionTime: %s MaxEjectionTime: %s MaxEjectionPercent: %d]",
+		opt.ConsecutiveErrors, opt.BaseEjectionTime, opt.MaxEjectionTime, opt.MaxEjectionPercent)
+}
+
+// OutlierDetector ejects from the load balancer the servers failing on real traffic.
+// A server is ejected after ConsecutiveErrors consecutive errors, for a duration
+// doubling with each consecutive ejection, and is then returned to the load balancer.
+type OutlierDetector struct {
+	OutlierOptions
+	name    string
+	metrics metricsRegistry
+
+	lock    sync.Mutex
+	servers map[string]*outlierServer
+}
+
+type outlierServer struct {
+	url               *url.URL
+	weight            int
+	consecutiveErrors int
+	ejections         int
+	ejected           bool
+	ejectedUntil      time.Time
+	returnedAt        time.Time
+}
+
+// EjectedServer describes a server currently ejected by an OutlierDetector.
+type EjectedServer struct {
+	Backend      string    `json:"backend"`
+	URL          string    `json:"url"`
+	Ejections    int       `json:"ejections"`
+	EjectedUntil time.Time `json:"ejectedUntil"`
+}
+
+// serverWeighter is implemented by the load balancers able to report the weight of a server.
+type serverWeighter interface {
+	ServerWeight(u *url.URL) (int, bool)
+}
+
+// NewOutlierDetector Instantiate a new OutlierDetector.
+// Zero options are replaced by their default values.
+func NewOutlierDetector(options OutlierOptions, backendName string, metrics metricsRegistry) *OutlierDetector {
+	if options.ConsecutiveErrors <= 0 {
+		options.ConsecutiveErrors = DefaultConsecutiveErrors
+	}
+	if options.BaseEjectionTime <= 0 {
+		options.BaseEjectionTime = DefaultBaseEjectionTime
+	}
+	if options.MaxEjectionTime <= 0 {
+		options.MaxEjectionTime = DefaultMaxEjectionTime
+	}
+	if options.MaxEjectionTime < options.BaseEjectionTime {
+		options.MaxEjectionTime = options.BaseEjectionTime
+	}
+	if options.MaxEjectionPercent <= 0 {
+		options.MaxEjectionPercent = DefaultMaxEjectionPercent
+	}
+	if options.MaxEjectionPercent > 100 {
+		options.MaxEjectionPercent = 100
+	}
+
+	return &OutlierDetector{
+		OutlierOptions: options,
+		name:           backendName,
+		metrics:        metrics,
+		servers:        make(map[string]*outlierServer),
+	}
+}
+
+// Record records the result of a request forwarded to a server.
+// Responses with a 5xx status code, including the ones reporting a connection error, are errors.
+func (d *OutlierDetector) Record(serverURL *url.URL, statusCode int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	key := serverURL.String()
+	server, ok := d.servers[key]
+	if !ok {
+		server = &outlierServer{url: serverURL}
+		d.servers[key] = server
+	}
+	if server.ejected {
+		return
+	}
+
+	if statusCode < 500 {
+		server.consecutiveErrors = 0
+		if server.ejections > 0 && time.Now().After(server.returnedAt.Add(d.ejectionTime(server.ejections))) {
+			server.ejections = 0
+		}
+		return
+	}
+
+	server.consecutiveErrors++
+	if server.consecutiveErrors >= d.ConsecutiveErrors {
+		d.eject(server)
+	}
+}
+
+// EjectedServers returns the servers currently ejected, sorted by URL.
+func (d *OutlierDetector) EjectedServers() []EjectedServer {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	var ejected []EjectedServer
+	for _, server := range d.servers {
+		if server.ejected {
+			ejected = append(ejected, EjectedServer{
+				Backend:      d.name,
+				URL:          server.url.String(),
+				Ejections:    server.ejections,
+				EjectedUntil: server.ejectedUntil,
+			})
+		}
+	}
+
+	sort.Slice(ejected, func(i, j int) bool {
+		return ejected[i].URL < ejected[j].URL
+	})
+	return ejected
+}
+
+// eject removes the server from the load balancer, unless the maximum ejection percentage is reached.
+// It must be called with the lock held.
+func (d *OutlierDetector) eject(server *outlierServer) {
+	server.consecutiveErrors = 0
+
+	ejectedCount := 0
+	for _, s := range d.servers {
+		if s.ejected {
+			ejectedCount++
+		}
+	}
+	total := len(d.LB.Servers()) + ejectedCount
+	if (ejectedCount+1)*100 > d.MaxEjectionPercent*total {
+		log.Warnf("Outlier detection: Maximum ejection percentage reached, not ejecting server. Backend: %q URL: %q", d.name, server.url.String())
+		return
+	}
+
+	server.weight = 1
+	if weighter, ok := d.LB.(serverWeighter); ok {
+		if weight, found := weighter.ServerWeight(server.url); found {
+			server.weight = weight
+		}
+	}
+
+	if err := d.LB.RemoveServer(server.url); err != nil {
+		log.Debugf("Outlier detection: Unable to remove server from the load balancer. Backend: %q URL: %q Reason: %s", d.name, server.url.String(), err)
+		return
+	}
+
+	server.ejections++
+	ejectionTime := d.ejectionTime(server.ejections)
+	server.ejected = true
+	server.ejectedUntil = time.Now().Add(ejectionTime)
+
+	log.Warnf("Outlier detection: Ejecting server for %s. Backend: %q URL: %q", ejectionTime, d.name, server.url.String())
+	d.metrics.BackendServerUpGauge().With("backend", d.name, "url", server.url.String()).Set(0)
+
+	time.AfterFunc(ejectionTime, func() {
+		d.restore(server)
+	})
+}
+
+// restore returns an ejected server to the load balancer.
+// With an active health check, the server is returned only if it is healthy,
+// and left to the health check otherwise.
+func (d *OutlierDetector) restore(server *outlierServer) {
+	d.lock.Lock()
+	ejected := server.ejected
+	d.lock.Unlock()
+	if !ejected {
+		return
+	}
+
+	// the server is checked outside of the lock, not to delay the requests recorded meanwhile
+	var healthErr error
+	if d.HealthCheck != nil && !d.HealthCheck.isDisabled(server.url) {
+		healthErr = checkHealth(server.url, d.HealthCheck)
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	server.ejected = false
+	server.returnedAt = time.Now()
+
+	if d.HealthCheck != nil {
+		if d.HealthCheck.isDisabled(server.url) {
+			log.Debugf("Outlier detection: Server disabled by the health check, not returning it. Backend: %q URL: %q", d.name, server.url.String())
+			return
+		}
+		if healthErr != nil {
+			log.Warnf("Outlier detection: Health check failed, leaving server to the health check. Backend: %q URL: %q Reason: %s", d.name, server.url.String(), healthErr)
+			d.HealthCheck.handOver(server.url)
+			return
+		}
+	}
+
+	log.Warnf("Outlier detection: Returning to server list. Backend: %q URL: %q", d.name, server.url.String())
+	if err := d.LB.UpsertServer(server.url, roundrobin.Weight(server.weight)); err != nil {
+		log.Errorf("Outlier detection: Unable to return server to the load balancer. Backend: %q URL: %q Reason: %s", d.name, server.url.String(), err)
+	}
+
+	d.metrics.BackendServerUpGauge().With("backend", d.name, "url", server.url.String()).Set(1)
+}
+
+// ejectionTime returns the duration of the n-th consecutive ejection of a server.
+func (d *OutlierDetector) ejectionTime(ejections int) time.Duration {
+	ejectionTime := d.BaseEjectionTime
+	for i := 1; i < ejections && ejectionTime < d.MaxEjectionTime; i++ {
+		ejectionTime *= 2
+	}
+	if ejectionTime > d.MaxEjectionTime {
+		ejectionTime = d.MaxEjectionTime
+	}
+	return ejectionTime
+}
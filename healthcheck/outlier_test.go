@@ -0,0 +1,270 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutlierDetectorEjection(t *testing.T) {
+	tests := []struct {
+		desc             string
+		statusCodes      []int
+		wantNumRemoved   int
+		wantNumEjected   int
+		wantGaugeValue   float64
+		wantGaugeUpdated bool
+	}{
+		{
+			desc:        "successful responses",
+			statusCodes: []int{http.StatusOK, http.StatusNotFound, http.StatusOK},
+		},
+		{
+			desc:        "errors below the threshold",
+			statusCodes: []int{http.StatusBadGateway, http.StatusInternalServerError},
+		},
+		{
+			desc:        "errors interrupted by a success",
+			statusCodes: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusOK, http.StatusBadGateway},
+		},
+		{
+			desc:             "consecutive errors",
+			statusCodes:      []int{http.StatusOK, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+			wantNumRemoved:   1,
+			wantNumEjected:   1,
+			wantGaugeValue:   0,
+			wantGaugeUpdated: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			serverURL := testhelpers.MustParseURL("http://10.0.0.1:80")
+			lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+			lb.servers = append(lb.servers, serverURL, testhelpers.MustParseURL("http://10.0.0.2:80"))
+
+			collectingMetrics := testhelpers.NewCollectingHealthCheckMetrics()
+			detector := NewOutlierDetector(OutlierOptions{
+				ConsecutiveErrors: 3,
+				BaseEjectionTime:  time.Hour,
+				LB:                lb,
+			}, "backendName", collectingMetrics)
+
+			for _, statusCode := range test.statusCodes {
+				detector.Record(serverURL, statusCode)
+			}
+
+			lb.Lock()
+			defer lb.Unlock()
+			assert.Equal(t, test.wantNumRemoved, lb.numRemovedServers)
+			assert.Len(t, detector.EjectedServers(), test.wantNumEjected)
+			if test.wantGaugeUpdated {
+				assert.Equal(t, test.wantGaugeValue, collectingMetrics.Gauge.GaugeValue)
+				assert.Equal(t, []string{"backend", "backendName", "url", "http://10.0.0.1:80"}, collectingMetrics.Gauge.LastLabelValues)
+			}
+		})
+	}
+}
+
+func TestOutlierDetectorRestore(t *testing.T) {
+	serverURL := testhelpers.MustParseURL("http://10.0.0.1:80")
+	lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+	lb.servers = append(lb.servers, serverURL, testhelpers.MustParseURL("http://10.0.0.2:80"))
+
+	collectingMetrics := testhelpers.NewCollectingHealthCheckMetrics()
+	detector := NewOutlierDetector(OutlierOptions{
+		ConsecutiveErrors: 1,
+		BaseEjectionTime:  50 * time.Millisecond,
+		LB:                lb,
+	}, "backendName", collectingMetrics)
+
+	detector.Record(serverURL, http.StatusBadGateway)
+
+	ejected := detector.EjectedServers()
+	require.Len(t, ejected, 1)
+	assert.Equal(t, "http://10.0.0.1:80", ejected[0].URL)
+	assert.Equal(t, 1, ejected[0].Ejections)
+
+	// requests recorded while the server is ejected are ignored
+	detector.Record(serverURL, http.StatusBadGateway)
+
+	deadline := time.Now().Add(time.Second)
+	for len(detector.EjectedServers()) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	require.Empty(t, detector.EjectedServers())
+	lb.Lock()
+	assert.Equal(t, 1, lb.numRemovedServers)
+	assert.Equal(t, 1, lb.numUpsertedServers)
+	assert.Len(t, lb.servers, 2)
+	lb.Unlock()
+	assert.Equal(t, float64(1), collectingMetrics.Gauge.GaugeValue)
+
+	// a new failure right after the return doubles the ejection time
+	detector.Record(serverURL, http.StatusBadGateway)
+
+	ejected = detector.EjectedServers()
+	require.Len(t, ejected, 1)
+	assert.Equal(t, 2, ejected[0].Ejections)
+	assert.InDelta(t, float64(100*time.Millisecond), float64(time.Until(ejected[0].EjectedUntil)), float64(50*time.Millisecond))
+}
+
+func TestOutlierDetectorRestoreWithHealthCheck(t *testing.T) {
+	tests := []struct {
+		desc             string
+		healthStatus     int
+		disabled         bool
+		wantNumUpserted  int
+		wantHandedOver   bool
+		wantGaugeUpdated bool
+	}{
+		{
+			desc:             "healthy server",
+			healthStatus:     http.StatusOK,
+			wantNumUpserted:  1,
+			wantGaugeUpdated: true,
+		},
+		{
+			desc:           "unhealthy server",
+			healthStatus:   http.StatusServiceUnavailable,
+			wantHandedOver: true,
+		},
+		{
+			desc:         "server disabled by the health check",
+			healthStatus: http.StatusOK,
+			disabled:     true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(test.healthStatus)
+			}))
+			defer ts.Close()
+
+			serverURL := testhelpers.MustParseURL(ts.URL)
+			lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+			lb.servers = append(lb.servers, serverURL, testhelpers.MustParseURL("http://10.0.0.2:80"))
+
+			backend := NewBackendHealthCheck(Options{Path: "/health", LB: lb}, "backendName")
+
+			collectingMetrics := testhelpers.NewCollectingHealthCheckMetrics()
+			detector := NewOutlierDetector(OutlierOptions{
+				ConsecutiveErrors: 1,
+				BaseEjectionTime:  time.Hour,
+				LB:                lb,
+				HealthCheck:       backend,
+			}, "backendName", collectingMetrics)
+
+			detector.Record(serverURL, http.StatusBadGateway)
+			require.Len(t, detector.EjectedServers(), 1)
+
+			if test.disabled {
+				backend.disabledURLs = append(backend.disabledURLs, serverURL)
+			}
+
+			server := detector.servers[serverURL.String()]
+			detector.restore(server)
+
+			assert.Empty(t, detector.EjectedServers())
+			lb.Lock()
+			assert.Equal(t, test.wantNumUpserted, lb.numUpsertedServers)
+			lb.Unlock()
+			assert.Equal(t, test.wantHandedOver, len(backend.handedOverURLs) == 1)
+			if test.wantGaugeUpdated {
+				assert.Equal(t, float64(1), collectingMetrics.Gauge.GaugeValue)
+			} else {
+				assert.Equal(t, float64(0), collectingMetrics.Gauge.GaugeValue)
+			}
+
+			// a server returned by the outlier detection is not returned twice
+			detector.restore(server)
+			lb.Lock()
+			assert.Equal(t, test.wantNumUpserted, lb.numUpsertedServers)
+			lb.Unlock()
+		})
+	}
+}
+
+func TestOutlierDetectorMaxEjectionPercent(t *testing.T) {
+	tests := []struct {
+		desc               string
+		numServers         int
+		maxEjectionPercent int
+		wantNumEjected     int
+	}{
+		{
+			desc:               "single server",
+			numServers:         1,
+			maxEjectionPercent: 50,
+			wantNumEjected:     0,
+		},
+		{
+			desc:               "half of the servers",
+			numServers:         4,
+			maxEjectionPercent: 50,
+			wantNumEjected:     2,
+		},
+		{
+			desc:               "all servers",
+			numServers:         3,
+			maxEjectionPercent: 100,
+			wantNumEjected:     3,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+			for i := 0; i < test.numServers; i++ {
+				lb.servers = append(lb.servers, testhelpers.MustParseURL("http://10.0.0."+strconv.Itoa(i+1)+":80"))
+			}
+			servers := append(lb.servers[:0:0], lb.servers...)
+
+			detector := NewOutlierDetector(OutlierOptions{
+				ConsecutiveErrors:  1,
+				BaseEjectionTime:   time.Hour,
+				MaxEjectionPercent: test.maxEjectionPercent,
+				LB:                 lb,
+			}, "backendName", testhelpers.NewCollectingHealthCheckMetrics())
+
+			for _, serverURL := range servers {
+				detector.Record(serverURL, http.StatusBadGateway)
+			}
+
+			assert.Len(t, detector.EjectedServers(), test.wantNumEjected)
+			assert.Len(t, lb.Servers(), test.numServers-test.wantNumEjected)
+		})
+	}
+}
+
+func TestOutlierDetectorEjectionTime(t *testing.T) {
+	detector := NewOutlierDetector(OutlierOptions{
+		BaseEjectionTime: 10 * time.Second,
+		MaxEjectionTime:  60 * time.Second,
+	}, "backendName", testhelpers.NewCollectingHealthCheckMetrics())
+
+	assert.Equal(t, 10*time.Second, detector.ejectionTime(1))
+	assert.Equal(t, 20*time.Second, detector.ejectionTime(2))
+	assert.Equal(t, 40*time.Second, detector.ejectionTime(3))
+	assert.Equal(t, 60*time.Second, detector.ejectionTime(4))
+	assert.Equal(t, 60*time.Second, detector.ejectionTime(10))
+}
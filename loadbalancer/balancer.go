@@ -0,0 +1,235 @@
+package loadbalancer
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/vulcand/oxy/roundrobin"
+	"github.com/vulcand/oxy/utils"
+)
+
+var errNoServer = errors.New("no servers in the pool")
+
+// Balancer is an HTTP load balancer forwarding each request to the server picked by its strategy.
+// It implements the healthcheck.LoadBalancer interface, so its servers can be health checked.
+type Balancer struct {
+	next          http.Handler
+	strategy      strategy
+	stickySession *roundrobin.StickySession
+
+	lock    sync.Mutex
+	servers []*server
+	// weights stores the weights of the servers, so that the weights are read from the
+	// roundrobin.ServerOption given to UpsertServer the same way as with the round robin balancers.
+	weights *roundrobin.RoundRobin
+}
+
+// server holds the state of a server used by the strategies.
+type server struct {
+	url    *url.URL
+	weight int
+	// active is the number of requests currently forwarded to the server.
+	active int
+	// latency is the exponentially weighted moving average of the response time of the server, in nanoseconds.
+	latency     float64
+	lastLatency time.Time
+}
+
+// strategy picks the server of the request, among servers with a non-zero weight.
+// It is called with the lock of the balancer held.
+type strategy interface {
+	pick(req *http.Request, servers []*server) *server
+}
+
+// serversObserver is implemented by the strategies keeping a state depending on the servers of the pool.
+// It is called with the lock of the balancer held, each time a server is added, updated or removed.
+type serversObserver interface {
+	serversChanged(servers []*server)
+}
+
+func newBalancer(next http.Handler, strategy strategy, stickySession *roundrobin.StickySession) *Balancer {
+	// The round robin is only used to store the weights of the servers, it never serves HTTP requests.
+	weights, _ := roundrobin.New(nil)
+
+	return &Balancer{
+		next:          next,
+		strategy:      strategy,
+		stickySession: stickySession,
+		weights:       weights,
+	}
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	srv, stuck, err := b.acquire(req)
+	if err != nil {
+		utils.DefaultHandler.ServeHTTP(rw, req, err)
+		return
+	}
+
+	if b.stickySession != nil && !stuck {
+		b.stickySession.StickBackend(srv.url, &rw)
+	}
+
+	// make shallow copy of request before changing anything to avoid side effects
+	newReq := *req
+	newReq.URL = srv.url
+
+	start := time.Now()
+	defer func() {
+		b.release(srv, time.Since(start))
+	}()
+	b.next.ServeHTTP(rw, &newReq)
+}
+
+// acquire picks the server of the request, and counts the request as active on that server.
+func (b *Balancer) acquire(req *http.Request) (*server, bool, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	srv, stuck := b.stickyServer(req)
+	if srv == nil {
+		candidates := b.candidates()
+		if len(candidates) == 0 {
+			return nil, false, errNoServer
+		}
+		srv = b.strategy.pick(req, candidates)
+	}
+
+	srv.active++
+	return srv, stuck, nil
+}
+
+// candidates returns the servers with a non-zero weight.
+// It must be called with the lock held.
+func (b *Balancer) candidates() []*server {
+	var candidates []*server
+	for _, s := range b.servers {
+		if s.weight > 0 {
+			candidates = append(candidates, s)
+		}
+	}
+	return candidates
+}
+
+// notifyStrategy must be called with the lock held.
+func (b *Balancer) notifyStrategy() {
+	if observer, ok := b.strategy.(serversObserver); ok {
+		observer.serversChanged(b.candidates())
+	}
+}
+
+// release records the end of a request forwarded to the server.
+func (b *Balancer) release(srv *server, latency time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	srv.active--
+	srv.observeLatency(latency, time.Now())
+}
+
+// stickyServer returns the server stored in the sticky session cookie of the request, if it is still in the pool.
+// It must be called with the lock held.
+func (b *Balancer) stickyServer(req *http.Request) (*server, bool) {
+	if b.stickySession == nil {
+		return nil, false
+	}
+
+	urls := make([]*url.URL, 0, len(b.servers))
+	for _, s := range b.servers {
+		urls = append(urls, s.url)
+	}
+
+	cookieURL, present, err := b.stickySession.GetBackend(req, urls)
+	if err != nil {
+		log.Warnf("Error using server from cookie: %v", err)
+	}
+	if !present {
+		return nil, false
+	}
+
+	srv, _ := b.findServer(cookieURL)
+	return srv, srv != nil
+}
+
+// UpsertServer adds or updates a server
+func (b *Balancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err := b.weights.UpsertServer(u, options...); err != nil {
+		return err
+	}
+	weight, _ := b.weights.ServerWeight(u)
+
+	if srv, _ := b.findServer(u); srv != nil {
+		srv.weight = weight
+	} else {
+		b.servers = append(b.servers, &server{url: copyURL(u), weight: weight})
+	}
+
+	b.notifyStrategy()
+	return nil
+}
+
+// RemoveServer removes a server
+func (b *Balancer) RemoveServer(u *url.URL) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err := b.weights.RemoveServer(u); err != nil {
+		return err
+	}
+
+	if _, index := b.findServer(u); index >= 0 {
+		b.servers = append(b.servers[:index], b.servers[index+1:]...)
+	}
+
+	b.notifyStrategy()
+	return nil
+}
+
+// Servers returns the list of servers
+func (b *Balancer) Servers() []*url.URL {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	urls := make([]*url.URL, 0, len(b.servers))
+	for _, s := range b.servers {
+		urls = append(urls, copyURL(s.url))
+	}
+	return urls
+}
+
+// ServerWeight returns the weight of a server
+func (b *Balancer) ServerWeight(u *url.URL) (int, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if srv, _ := b.findServer(u); srv != nil {
+		return srv.weight, true
+	}
+	return -1, false
+}
+
+// findServer must be called with the lock held.
+func (b *Balancer) findServer(u *url.URL) (*server, int) {
+	for i, s := range b.servers {
+		if sameURL(s.url, u) {
+			return s, i
+		}
+	}
+	return nil, -1
+}
+
+func sameURL(a, b *url.URL) bool {
+	return a.Path == b.Path && a.Host == b.Host && a.Scheme == b.Scheme
+}
+
+func copyURL(u *url.URL) *url.URL {
+	out := *u
+	return &out
+}
@@ -0,0 +1,256 @@
+package loadbalancer
+
+import (
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestStrategiesPick(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		strategy strategy
+		servers  []*server
+		expected string
+	}{
+		{
+			desc:     "least conn picks the server with the fewest active requests",
+			strategy: &leastConn{},
+			servers: []*server{
+				newTestServer("a", 1, 2, 0),
+				newTestServer("b", 1, 0, 0),
+				newTestServer("c", 1, 1, 0),
+			},
+			expected: "b",
+		},
+		{
+			desc:     "least conn honours the weights",
+			strategy: &leastConn{},
+			servers: []*server{
+				newTestServer("a", 3, 2, 0),
+				newTestServer("b", 1, 1, 0),
+			},
+			expected: "a",
+		},
+		{
+			desc:     "power of two choices picks the least loaded of two servers",
+			strategy: &powerOfTwoChoices{rand: rand.New(rand.NewSource(1))},
+			servers: []*server{
+				newTestServer("a", 1, 5, 0),
+				newTestServer("b", 1, 0, 0),
+			},
+			expected: "b",
+		},
+		{
+			desc:     "power of two choices with a single server",
+			strategy: &powerOfTwoChoices{rand: rand.New(rand.NewSource(1))},
+			servers: []*server{
+				newTestServer("a", 1, 5, 0),
+			},
+			expected: "a",
+		},
+		{
+			desc:     "ewma picks the fastest server",
+			strategy: &ewma{},
+			servers: []*server{
+				newTestServer("a", 1, 0, 100*time.Millisecond),
+				newTestServer("b", 1, 0, 10*time.Millisecond),
+			},
+			expected: "b",
+		},
+		{
+			desc:     "ewma takes the active requests into account",
+			strategy: &ewma{},
+			servers: []*server{
+				newTestServer("a", 1, 20, 10*time.Millisecond),
+				newTestServer("b", 1, 0, 100*time.Millisecond),
+			},
+			expected: "b",
+		},
+		{
+			desc:     "ewma tries the servers without latency measure first",
+			strategy: &ewma{},
+			servers: []*server{
+				newTestServer("a", 1, 0, 10*time.Millisecond),
+				newTestServer("b", 1, 0, 0),
+			},
+			expected: "b",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			for i := 0; i < 10; i++ {
+				srv := test.strategy.pick(httptest.NewRequest(http.MethodGet, "http://localhost/", nil), test.servers)
+				require.NotNil(t, srv)
+				assert.Equal(t, test.expected, srv.url.Host)
+			}
+		})
+	}
+}
+
+func TestBalancerSpreadsIdleRequests(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		balancer func(next http.Handler) *Balancer
+	}{
+		{
+			desc: "least conn",
+			balancer: func(next http.Handler) *Balancer {
+				return NewLeastConn(next, nil)
+			},
+		},
+		{
+			desc: "ewma",
+			balancer: func(next http.Handler) *Balancer {
+				return NewEWMA(next, nil)
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			counts := map[string]int{}
+			lb := test.balancer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				counts[req.URL.Host]++
+			}))
+			for _, host := range []string{"a", "b", "c"} {
+				require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://"+host)))
+			}
+
+			for i := 0; i < 30; i++ {
+				lb.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+			}
+
+			// The EWMA balancer is only expected to try each server, as the latencies are not equal.
+			for _, host := range []string{"a", "b", "c"} {
+				assert.NotZero(t, counts[host], "server %s", host)
+			}
+			if test.desc == "least conn" {
+				assert.Equal(t, map[string]int{"a": 10, "b": 10, "c": 10}, counts)
+			}
+		})
+	}
+}
+
+func TestBalancerReleasesOnPanic(t *testing.T) {
+	lb := NewLeastConn(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		panic(http.ErrAbortHandler)
+	}), nil)
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://a")))
+
+	assert.Panics(t, func() {
+		lb.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	})
+
+	require.Len(t, lb.servers, 1)
+	assert.Equal(t, 0, lb.servers[0].active)
+}
+
+func TestBalancerServers(t *testing.T) {
+	lb := NewLeastConn(http.NotFoundHandler(), nil)
+
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://a"), roundrobin.Weight(3)))
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://b")))
+	assert.Equal(t, []*url.URL{testhelpers.MustParseURL("http://a"), testhelpers.MustParseURL("http://b")}, lb.Servers())
+
+	weight, ok := lb.ServerWeight(testhelpers.MustParseURL("http://a"))
+	assert.True(t, ok)
+	assert.Equal(t, 3, weight)
+
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://a"), roundrobin.Weight(5)))
+	weight, _ = lb.ServerWeight(testhelpers.MustParseURL("http://a"))
+	assert.Equal(t, 5, weight)
+	assert.Len(t, lb.Servers(), 2)
+
+	require.NoError(t, lb.RemoveServer(testhelpers.MustParseURL("http://a")))
+	assert.Equal(t, []*url.URL{testhelpers.MustParseURL("http://b")}, lb.Servers())
+	assert.Error(t, lb.RemoveServer(testhelpers.MustParseURL("http://a")))
+
+	require.NoError(t, lb.RemoveServer(testhelpers.MustParseURL("http://b")))
+	recorder := httptest.NewRecorder()
+	lb.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestBalancerSkipsZeroWeight(t *testing.T) {
+	var hosts []string
+	lb := NewPowerOfTwoChoices(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		hosts = append(hosts, req.URL.Host)
+	}), nil)
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://a")))
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://b")))
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://a"), roundrobin.Weight(0)))
+
+	for i := 0; i < 5; i++ {
+		lb.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	}
+
+	assert.Equal(t, []string{"b", "b", "b", "b", "b"}, hosts)
+}
+
+func TestBalancerSticky(t *testing.T) {
+	lb := NewLeastConn(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(req.URL.Host))
+	}), roundrobin.NewStickySession("test"))
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://a")))
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://b")))
+
+	recorder := httptest.NewRecorder()
+	lb.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	first := recorder.Body.String()
+
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "test", cookies[0].Name)
+	assert.Equal(t, "http://"+first, cookies[0].Value)
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+		req.AddCookie(cookies[0])
+
+		recorder := httptest.NewRecorder()
+		lb.ServeHTTP(recorder, req)
+		assert.Equal(t, first, recorder.Body.String())
+		assert.Empty(t, recorder.Result().Cookies())
+	}
+}
+
+func TestServerObserveLatency(t *testing.T) {
+	now := time.Now()
+	srv := &server{}
+
+	srv.observeLatency(100*time.Millisecond, now)
+	assert.Equal(t, float64(100*time.Millisecond), srv.latency)
+
+	// a higher latency replaces the average
+	srv.observeLatency(200*time.Millisecond, now.Add(time.Millisecond))
+	assert.Equal(t, float64(200*time.Millisecond), srv.latency)
+
+	// a lower latency is averaged depending on the elapsed time
+	srv.observeLatency(0, now.Add(time.Millisecond+ewmaDecay))
+	assert.InDelta(t, float64(200*time.Millisecond)/2.718281828, srv.latency, float64(time.Millisecond))
+}
+
+func newTestServer(host string, weight int, active int, latency time.Duration) *server {
+	return &server{
+		url:     &url.URL{Scheme: "http", Host: host},
+		weight:  weight,
+		active:  active,
+		latency: float64(latency),
+	}
+}
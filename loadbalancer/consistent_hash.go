@@ -0,0 +1,136 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/containous/traefik/log"
+	"github.com/vulcand/oxy/roundrobin"
+	"github.com/vulcand/oxy/utils"
+)
+
+// DefaultLoadFactor is the default bound of the load of a server, relatively to the average load of the servers.
+const DefaultLoadFactor = 1.25
+
+// replicasPerWeight is the number of points of a server on the hash ring, per unit of weight.
+const replicasPerWeight = 100
+
+const cookieExtractorPrefix = "request.cookie."
+
+// NewConsistentHash creates a load balancer forwarding the requests with the same key to the same server,
+// as long as the server stays in the pool.
+// The load of a server is bounded to loadFactor times the average load of the servers, relatively to its weight:
+// the requests exceeding the bound are forwarded to the next servers of the hash ring.
+// The stickiness is disabled when stickySession is nil.
+func NewConsistentHash(next http.Handler, stickySession *roundrobin.StickySession, extractor utils.SourceExtractor, loadFactor float64) *Balancer {
+	if loadFactor < 1 {
+		loadFactor = DefaultLoadFactor
+	}
+	return newBalancer(next, &consistentHash{extractor: extractor, loadFactor: loadFactor}, stickySession)
+}
+
+// NewExtractor creates the extractor of the hash key of the requests.
+// In addition to the variables supported by the oxy extractors (client.ip, request.host and request.header.<name>),
+// the value of a cookie can be used with request.cookie.<name>.
+func NewExtractor(variable string) (utils.SourceExtractor, error) {
+	if strings.HasPrefix(variable, cookieExtractorPrefix) {
+		name := strings.TrimPrefix(variable, cookieExtractorPrefix)
+		if len(name) == 0 {
+			return nil, fmt.Errorf("empty cookie name in %q", variable)
+		}
+		return utils.ExtractorFunc(func(req *http.Request) (string, int64, error) {
+			cookie, err := req.Cookie(name)
+			if err != nil {
+				return "", 1, nil
+			}
+			return cookie.Value, 1, nil
+		}), nil
+	}
+	return utils.NewExtractor(variable)
+}
+
+// consistentHash picks the first server of the hash ring, from the hash of the request key, having a load under the bound.
+// The requests without a key are forwarded to the least loaded server.
+type consistentHash struct {
+	extractor  utils.SourceExtractor
+	loadFactor float64
+	ring       []ringPoint
+}
+
+type ringPoint struct {
+	hash   uint64
+	server *server
+}
+
+func (c *consistentHash) serversChanged(servers []*server) {
+	var ring []ringPoint
+	for _, srv := range servers {
+		for i := 0; i < srv.weight*replicasPerWeight; i++ {
+			ring = append(ring, ringPoint{hash: hashKey(srv.url.String() + "#" + strconv.Itoa(i)), server: srv})
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool {
+		return ring[i].hash < ring[j].hash
+	})
+	c.ring = ring
+}
+
+func (c *consistentHash) pick(req *http.Request, servers []*server) *server {
+	key, _, err := c.extractor.Extract(req)
+	if err != nil {
+		log.Debugf("Unable to extract the hash key of the request: %v", err)
+	}
+	if len(key) == 0 || len(c.ring) == 0 {
+		return leastLoaded(servers)
+	}
+
+	var active, weight int
+	for _, srv := range servers {
+		active += srv.active
+		weight += srv.weight
+	}
+
+	hash := hashKey(key)
+	start := sort.Search(len(c.ring), func(i int) bool {
+		return c.ring[i].hash >= hash
+	})
+	for i := 0; i < len(c.ring); i++ {
+		srv := c.ring[(start+i)%len(c.ring)].server
+		// The bound is rounded up, so that at least one server is always under the bound.
+		bound := math.Ceil(c.loadFactor * float64(active+1) * float64(srv.weight) / float64(weight))
+		if float64(srv.active+1) <= bound {
+			return srv
+		}
+	}
+	return c.ring[start%len(c.ring)].server
+}
+
+func leastLoaded(servers []*server) *server {
+	var selected *server
+	for _, srv := range servers {
+		if selected == nil || srv.load() < selected.load() {
+			selected = srv
+		}
+	}
+	return selected
+}
+
+// hashKey returns the FNV-1a hash of the key, with a final mix of the bits,
+// so that close keys are spread on the whole hash ring.
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,218 @@
+package loadbalancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestConsistentHashSameKeySameServer(t *testing.T) {
+	lb, hosts := newTestConsistentHash(t, "request.header.X-Tenant", "a", "b", "c")
+
+	for i := 0; i < 20; i++ {
+		tenant := "tenant" + strconv.Itoa(i)
+		first := serveTenant(lb, hosts, tenant)
+		for j := 0; j < 5; j++ {
+			assert.Equal(t, first, serveTenant(lb, hosts, tenant), "tenant %s", tenant)
+		}
+	}
+}
+
+func TestConsistentHashServerRemoval(t *testing.T) {
+	lb, hosts := newTestConsistentHash(t, "request.header.X-Tenant", "a", "b", "c", "d")
+
+	before := map[string]string{}
+	for i := 0; i < 100; i++ {
+		tenant := "tenant" + strconv.Itoa(i)
+		before[tenant] = serveTenant(lb, hosts, tenant)
+	}
+	assert.Len(t, uniqueValues(before), 4)
+
+	require.NoError(t, lb.RemoveServer(testhelpers.MustParseURL("http://d")))
+
+	for tenant, host := range before {
+		after := serveTenant(lb, hosts, tenant)
+		if host != "d" {
+			assert.Equal(t, host, after, "tenant %s", tenant)
+		} else {
+			assert.NotEqual(t, "d", after, "tenant %s", tenant)
+		}
+	}
+}
+
+func TestConsistentHashBoundedLoad(t *testing.T) {
+	strategy := &consistentHash{loadFactor: 1.25}
+	extractor, err := NewExtractor("request.header.X-Tenant")
+	require.NoError(t, err)
+	strategy.extractor = extractor
+
+	servers := []*server{
+		newTestServer("a", 1, 0, 0),
+		newTestServer("b", 1, 0, 0),
+		newTestServer("c", 1, 0, 0),
+	}
+	strategy.serversChanged(servers)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+	req.Header.Set("X-Tenant", "hot")
+
+	// the requests of a hot key are kept active: the next servers of the ring are used once the bound is reached
+	first := strategy.pick(req, servers)
+	for i := 0; i < 30; i++ {
+		srv := strategy.pick(req, servers)
+		srv.active++
+	}
+
+	// the bound is ceil(1.25 * 30 / 3)
+	assert.Equal(t, 13, first.active)
+	for _, srv := range servers {
+		assert.True(t, srv.active <= 13, "server %s has %d active requests", srv.url.Host, srv.active)
+	}
+}
+
+func TestConsistentHashWithoutKey(t *testing.T) {
+	strategy := &consistentHash{loadFactor: 1.25}
+	extractor, err := NewExtractor("request.header.X-Tenant")
+	require.NoError(t, err)
+	strategy.extractor = extractor
+
+	servers := []*server{
+		newTestServer("a", 1, 3, 0),
+		newTestServer("b", 1, 1, 0),
+		newTestServer("c", 1, 2, 0),
+	}
+	strategy.serversChanged(servers)
+
+	srv := strategy.pick(httptest.NewRequest(http.MethodGet, "http://localhost/", nil), servers)
+	require.NotNil(t, srv)
+	assert.Equal(t, "b", srv.url.Host)
+}
+
+func TestConsistentHashWeights(t *testing.T) {
+	lb, hosts := newTestConsistentHash(t, "request.header.X-Tenant")
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://a"), roundrobin.Weight(3)))
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://b"), roundrobin.Weight(1)))
+
+	counts := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		counts[serveTenant(lb, hosts, "tenant"+strconv.Itoa(i))]++
+	}
+
+	assert.InDelta(t, 750, counts["a"], 100)
+	assert.InDelta(t, 250, counts["b"], 100)
+}
+
+func TestNewExtractor(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		variable    string
+		request     func() *http.Request
+		expected    string
+		expectedErr bool
+	}{
+		{
+			desc:     "client ip",
+			variable: "client.ip",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+				req.RemoteAddr = "10.0.0.1:1234"
+				return req
+			},
+			expected: "10.0.0.1",
+		},
+		{
+			desc:     "header",
+			variable: "request.header.X-Tenant",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+				req.Header.Set("X-Tenant", "foo")
+				return req
+			},
+			expected: "foo",
+		},
+		{
+			desc:     "cookie",
+			variable: "request.cookie.session",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+				req.AddCookie(&http.Cookie{Name: "session", Value: "bar"})
+				return req
+			},
+			expected: "bar",
+		},
+		{
+			desc:     "missing cookie",
+			variable: "request.cookie.session",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+			},
+			expected: "",
+		},
+		{
+			desc:        "empty cookie name",
+			variable:    "request.cookie.",
+			expectedErr: true,
+		},
+		{
+			desc:        "unknown variable",
+			variable:    "foo",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			extractor, err := NewExtractor(test.variable)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			key, _, err := extractor.Extract(test.request())
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, key)
+		})
+	}
+}
+
+func newTestConsistentHash(t *testing.T, extractorFunc string, hosts ...string) (*Balancer, *string) {
+	t.Helper()
+
+	extractor, err := NewExtractor(extractorFunc)
+	require.NoError(t, err)
+
+	var lastHost string
+	lb := NewConsistentHash(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		lastHost = req.URL.Host
+	}), nil, extractor, 0)
+
+	for _, host := range hosts {
+		require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://"+host)))
+	}
+	return lb, &lastHost
+}
+
+func serveTenant(lb *Balancer, lastHost *string, tenant string) string {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+	req.Header.Set("X-Tenant", tenant)
+	lb.ServeHTTP(httptest.NewRecorder(), req)
+	return *lastHost
+}
+
+func uniqueValues(m map[string]string) map[string]struct{} {
+	values := map[string]struct{}{}
+	for _, v := range m {
+		values[v] = struct{}{}
+	}
+	return values
+}
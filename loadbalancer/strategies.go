@@ -0,0 +1,133 @@
+package loadbalancer
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+// ewmaDecay is the time constant of the moving average of the latency:
+// the weight of a latency measure is divided by e after ewmaDecay.
+const ewmaDecay = 10 * time.Second
+
+// NewLeastConn creates a load balancer forwarding each request to the server with the fewest active requests,
+// relatively to its weight. The stickiness is disabled when stickySession is nil.
+func NewLeastConn(next http.Handler, stickySession *roundrobin.StickySession) *Balancer {
+	return newBalancer(next, &leastConn{}, stickySession)
+}
+
+// NewPowerOfTwoChoices creates a load balancer picking two random servers, proportionally to their weights,
+// and forwarding each request to the one with the fewest active requests, relatively to its weight.
+// The stickiness is disabled when stickySession is nil.
+func NewPowerOfTwoChoices(next http.Handler, stickySession *roundrobin.StickySession) *Balancer {
+	return newBalancer(next, &powerOfTwoChoices{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}, stickySession)
+}
+
+// NewEWMA creates a load balancer forwarding each request to the server with the lowest moving average of its latency,
+// multiplied by its number of active requests and relatively to its weight.
+// The stickiness is disabled when stickySession is nil.
+func NewEWMA(next http.Handler, stickySession *roundrobin.StickySession) *Balancer {
+	return newBalancer(next, &ewma{}, stickySession)
+}
+
+// leastConn picks the server with the lowest load.
+// The ties are broken in a round robin fashion.
+type leastConn struct {
+	index int
+}
+
+func (l *leastConn) pick(_ *http.Request, servers []*server) *server {
+	l.index = (l.index + 1) % len(servers)
+
+	var selected *server
+	for i := range servers {
+		srv := servers[(l.index+i)%len(servers)]
+		if selected == nil || srv.load() < selected.load() {
+			selected = srv
+		}
+	}
+	return selected
+}
+
+// powerOfTwoChoices picks the server with the lowest load among two random servers.
+type powerOfTwoChoices struct {
+	rand *rand.Rand
+}
+
+func (p *powerOfTwoChoices) pick(_ *http.Request, servers []*server) *server {
+	first := p.randomServer(servers, nil)
+	second := p.randomServer(servers, first)
+	if second == nil || first.load() <= second.load() {
+		return first
+	}
+	return second
+}
+
+// randomServer picks a random server proportionally to the weights, excluding the given server.
+func (p *powerOfTwoChoices) randomServer(servers []*server, excluded *server) *server {
+	total := 0
+	for _, srv := range servers {
+		if srv != excluded {
+			total += srv.weight
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	n := p.rand.Intn(total)
+	for _, srv := range servers {
+		if srv == excluded {
+			continue
+		}
+		if n < srv.weight {
+			return srv
+		}
+		n -= srv.weight
+	}
+	return nil
+}
+
+// ewma picks the server with the lowest latency score.
+// The ties, such as between servers without any latency measure yet, are broken in a round robin fashion.
+type ewma struct {
+	index int
+}
+
+func (e *ewma) pick(_ *http.Request, servers []*server) *server {
+	e.index = (e.index + 1) % len(servers)
+
+	var selected *server
+	for i := range servers {
+		srv := servers[(e.index+i)%len(servers)]
+		if selected == nil || srv.latencyScore() < selected.latencyScore() {
+			selected = srv
+		}
+	}
+	return selected
+}
+
+// load returns the number of active requests of the server, including the request being balanced,
+// relatively to the weight of the server.
+func (s *server) load() float64 {
+	return float64(s.active+1) / float64(s.weight)
+}
+
+func (s *server) latencyScore() float64 {
+	return (s.latency + 1) * s.load()
+}
+
+// observeLatency updates the moving average of the latency of the server.
+// The average is sensitive to peaks: a latency higher than the average replaces it.
+func (s *server) observeLatency(latency time.Duration, now time.Time) {
+	if s.lastLatency.IsZero() || float64(latency) > s.latency {
+		s.latency = float64(latency)
+	} else {
+		w := math.Exp(-float64(now.Sub(s.lastLatency)) / float64(ewmaDecay))
+		s.latency = s.latency*w + float64(latency)*(1-w)
+	}
+	s.lastLatency = now
+}
@@ -19,9 +19,15 @@ var datadogTicker *time.Ticker
 
 // Metric names consistent with https://github.com/DataDog/integrations-extras/pull/64
 const (
-	ddMetricsReqsName    = "requests.total"
-	ddMetricsLatencyName = "request.duration"
-	ddRetriesTotalName   = "backend.retries.total"
+	ddMetricsReqsName           = "requests.total"
+	ddMetricsLatencyName        = "request.duration"
+	ddRetriesTotalName          = "backend.retries.total"
+	ddMirrorSuccessTotalName    = "backend.mirror.success.total"
+	ddMirrorFailureTotalName    = "backend.mirror.failure.total"
+	ddAccessLogFlushedTotalName = "accesslog.flushed.total"
+	ddAccessLogDroppedTotalName = "accesslog.dropped.total"
+	ddCacheHitsTotalName        = "frontend.cache.hits.total"
+	ddCacheMissesTotalName      = "frontend.cache.misses.total"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -35,6 +41,12 @@ func RegisterDatadog(config *types.Datadog) Registry {
 		backendReqsCounter:          datadogClient.NewCounter(ddMetricsReqsName, 1.0),
 		backendReqDurationHistogram: datadogClient.NewHistogram(ddMetricsLatencyName, 1.0),
 		backendRetriesCounter:       datadogClient.NewCounter(ddRetriesTotalName, 1.0),
+		backendMirrorSuccessCounter: datadogClient.NewCounter(ddMirrorSuccessTotalName, 1.0),
+		backendMirrorFailureCounter: datadogClient.NewCounter(ddMirrorFailureTotalName, 1.0),
+		accessLogFlushedCounter:     datadogClient.NewCounter(ddAccessLogFlushedTotalName, 1.0),
+		accessLogDroppedCounter:     datadogClient.NewCounter(ddAccessLogDroppedTotalName, 1.0),
+		cacheHitsCounter:            datadogClient.NewCounter(ddCacheHitsTotalName, 1.0),
+		cacheMissesCounter:          datadogClient.NewCounter(ddCacheMissesTotalName, 1.0),
 	}
 
 	return registry
@@ -25,9 +25,15 @@ type influxDBWriter struct {
 var influxDBTicker *time.Ticker
 
 const (
-	influxDBMetricsReqsName    = "traefik.requests.total"
-	influxDBMetricsLatencyName = "traefik.request.duration"
-	influxDBRetriesTotalName   = "traefik.backend.retries.total"
+	influxDBMetricsReqsName           = "traefik.requests.total"
+	influxDBMetricsLatencyName        = "traefik.request.duration"
+	influxDBRetriesTotalName          = "traefik.backend.retries.total"
+	influxDBMirrorSuccessTotalName    = "traefik.backend.mirror.success.total"
+	influxDBMirrorFailureTotalName    = "traefik.backend.mirror.failure.total"
+	influxDBAccessLogFlushedTotalName = "traefik.accesslog.flushed.total"
+	influxDBAccessLogDroppedTotalName = "traefik.accesslog.dropped.total"
+	influxDBCacheHitsTotalName        = "traefik.frontend.cache.hits.total"
+	influxDBCacheMissesTotalName      = "traefik.frontend.cache.misses.total"
 )
 
 // RegisterInfluxDB registers the metrics pusher if this didn't happen yet and creates a InfluxDB Registry instance.
@@ -41,6 +47,12 @@ func RegisterInfluxDB(config *types.InfluxDB) Registry {
 		backendReqsCounter:          influxDBClient.NewCounter(influxDBMetricsReqsName),
 		backendReqDurationHistogram: influxDBClient.NewHistogram(influxDBMetricsLatencyName),
 		backendRetriesCounter:       influxDBClient.NewCounter(influxDBRetriesTotalName),
+		backendMirrorSuccessCounter: influxDBClient.NewCounter(influxDBMirrorSuccessTotalName),
+		backendMirrorFailureCounter: influxDBClient.NewCounter(influxDBMirrorFailureTotalName),
+		accessLogFlushedCounter:     influxDBClient.NewCounter(influxDBAccessLogFlushedTotalName),
+		accessLogDroppedCounter:     influxDBClient.NewCounter(influxDBAccessLogDroppedTotalName),
+		cacheHitsCounter:            influxDBClient.NewCounter(influxDBCacheHitsTotalName),
+		cacheMissesCounter:          influxDBClient.NewCounter(influxDBCacheMissesTotalName),
 	}
 }
 
@@ -14,6 +14,8 @@ type Registry interface {
 	ConfigReloadsFailureCounter() metrics.Counter
 	LastConfigReloadSuccessGauge() metrics.Gauge
 	LastConfigReloadFailureGauge() metrics.Gauge
+	ConfigReloadsRejectedCounter() metrics.Counter
+	LastConfigReloadRejectedGauge() metrics.Gauge
 
 	// entry point metrics
 	EntrypointReqsCounter() metrics.Counter
@@ -26,6 +28,18 @@ type Registry interface {
 	BackendOpenConnsGauge() metrics.Gauge
 	BackendRetriesCounter() metrics.Counter
 	BackendServerUpGauge() metrics.Gauge
+
+	// mirroring metrics
+	BackendMirrorSuccessCounter() metrics.Counter
+	BackendMirrorFailureCounter() metrics.Counter
+
+	// access log metrics
+	AccessLogFlushedCounter() metrics.Counter
+	AccessLogDroppedCounter() metrics.Counter
+
+	// cache metrics
+	CacheHitsCounter() metrics.Counter
+	CacheMissesCounter() metrics.Counter
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -42,6 +56,8 @@ func NewMultiRegistry(registries []Registry) Registry {
 	configReloadsFailureCounter := []metrics.Counter{}
 	lastConfigReloadSuccessGauge := []metrics.Gauge{}
 	lastConfigReloadFailureGauge := []metrics.Gauge{}
+	configReloadsRejectedCounter := []metrics.Counter{}
+	lastConfigReloadRejectedGauge := []metrics.Gauge{}
 	entrypointReqsCounter := []metrics.Counter{}
 	entrypointReqDurationHistogram := []metrics.Histogram{}
 	entrypointOpenConnsGauge := []metrics.Gauge{}
@@ -50,6 +66,12 @@ func NewMultiRegistry(registries []Registry) Registry {
 	backendOpenConnsGauge := []metrics.Gauge{}
 	backendRetriesCounter := []metrics.Counter{}
 	backendServerUpGauge := []metrics.Gauge{}
+	backendMirrorSuccessCounter := []metrics.Counter{}
+	backendMirrorFailureCounter := []metrics.Counter{}
+	accessLogFlushedCounter := []metrics.Counter{}
+	accessLogDroppedCounter := []metrics.Counter{}
+	cacheHitsCounter := []metrics.Counter{}
+	cacheMissesCounter := []metrics.Counter{}
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -64,6 +86,12 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.LastConfigReloadFailureGauge() != nil {
 			lastConfigReloadFailureGauge = append(lastConfigReloadFailureGauge, r.LastConfigReloadFailureGauge())
 		}
+		if r.ConfigReloadsRejectedCounter() != nil {
+			configReloadsRejectedCounter = append(configReloadsRejectedCounter, r.ConfigReloadsRejectedCounter())
+		}
+		if r.LastConfigReloadRejectedGauge() != nil {
+			lastConfigReloadRejectedGauge = append(lastConfigReloadRejectedGauge, r.LastConfigReloadRejectedGauge())
+		}
 		if r.EntrypointReqsCounter() != nil {
 			entrypointReqsCounter = append(entrypointReqsCounter, r.EntrypointReqsCounter())
 		}
@@ -88,6 +116,24 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.BackendServerUpGauge() != nil {
 			backendServerUpGauge = append(backendServerUpGauge, r.BackendServerUpGauge())
 		}
+		if r.BackendMirrorSuccessCounter() != nil {
+			backendMirrorSuccessCounter = append(backendMirrorSuccessCounter, r.BackendMirrorSuccessCounter())
+		}
+		if r.BackendMirrorFailureCounter() != nil {
+			backendMirrorFailureCounter = append(backendMirrorFailureCounter, r.BackendMirrorFailureCounter())
+		}
+		if r.AccessLogFlushedCounter() != nil {
+			accessLogFlushedCounter = append(accessLogFlushedCounter, r.AccessLogFlushedCounter())
+		}
+		if r.AccessLogDroppedCounter() != nil {
+			accessLogDroppedCounter = append(accessLogDroppedCounter, r.AccessLogDroppedCounter())
+		}
+		if r.CacheHitsCounter() != nil {
+			cacheHitsCounter = append(cacheHitsCounter, r.CacheHitsCounter())
+		}
+		if r.CacheMissesCounter() != nil {
+			cacheMissesCounter = append(cacheMissesCounter, r.CacheMissesCounter())
+		}
 	}
 
 	return &standardRegistry{
@@ -96,6 +142,8 @@ func NewMultiRegistry(registries []Registry) Registry {
 		configReloadsFailureCounter:    multi.NewCounter(configReloadsFailureCounter...),
 		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
 		lastConfigReloadFailureGauge:   multi.NewGauge(lastConfigReloadFailureGauge...),
+		configReloadsRejectedCounter:   multi.NewCounter(configReloadsRejectedCounter...),
+		lastConfigReloadRejectedGauge:  multi.NewGauge(lastConfigReloadRejectedGauge...),
 		entrypointReqsCounter:          multi.NewCounter(entrypointReqsCounter...),
 		entrypointReqDurationHistogram: multi.NewHistogram(entrypointReqDurationHistogram...),
 		entrypointOpenConnsGauge:       multi.NewGauge(entrypointOpenConnsGauge...),
@@ -104,6 +152,12 @@ func NewMultiRegistry(registries []Registry) Registry {
 		backendOpenConnsGauge:          multi.NewGauge(backendOpenConnsGauge...),
 		backendRetriesCounter:          multi.NewCounter(backendRetriesCounter...),
 		backendServerUpGauge:           multi.NewGauge(backendServerUpGauge...),
+		backendMirrorSuccessCounter:    multi.NewCounter(backendMirrorSuccessCounter...),
+		backendMirrorFailureCounter:    multi.NewCounter(backendMirrorFailureCounter...),
+		accessLogFlushedCounter:        multi.NewCounter(accessLogFlushedCounter...),
+		accessLogDroppedCounter:        multi.NewCounter(accessLogDroppedCounter...),
+		cacheHitsCounter:               multi.NewCounter(cacheHitsCounter...),
+		cacheMissesCounter:             multi.NewCounter(cacheMissesCounter...),
 	}
 }
 
@@ -113,6 +167,8 @@ type standardRegistry struct {
 	configReloadsFailureCounter    metrics.Counter
 	lastConfigReloadSuccessGauge   metrics.Gauge
 	lastConfigReloadFailureGauge   metrics.Gauge
+	configReloadsRejectedCounter   metrics.Counter
+	lastConfigReloadRejectedGauge  metrics.Gauge
 	entrypointReqsCounter          metrics.Counter
 	entrypointReqDurationHistogram metrics.Histogram
 	entrypointOpenConnsGauge       metrics.Gauge
@@ -121,6 +177,12 @@ type standardRegistry struct {
 	backendOpenConnsGauge          metrics.Gauge
 	backendRetriesCounter          metrics.Counter
 	backendServerUpGauge           metrics.Gauge
+	backendMirrorSuccessCounter    metrics.Counter
+	backendMirrorFailureCounter    metrics.Counter
+	accessLogFlushedCounter        metrics.Counter
+	accessLogDroppedCounter        metrics.Counter
+	cacheHitsCounter               metrics.Counter
+	cacheMissesCounter             metrics.Counter
 }
 
 func (r *standardRegistry) IsEnabled() bool {
@@ -143,6 +205,14 @@ func (r *standardRegistry) LastConfigReloadFailureGauge() metrics.Gauge {
 	return r.lastConfigReloadFailureGauge
 }
 
+func (r *standardRegistry) ConfigReloadsRejectedCounter() metrics.Counter {
+	return r.configReloadsRejectedCounter
+}
+
+func (r *standardRegistry) LastConfigReloadRejectedGauge() metrics.Gauge {
+	return r.lastConfigReloadRejectedGauge
+}
+
 func (r *standardRegistry) EntrypointReqsCounter() metrics.Counter {
 	return r.entrypointReqsCounter
 }
@@ -174,3 +244,27 @@ func (r *standardRegistry) BackendRetriesCounter() metrics.Counter {
 func (r *standardRegistry) BackendServerUpGauge() metrics.Gauge {
 	return r.backendServerUpGauge
 }
+
+func (r *standardRegistry) BackendMirrorSuccessCounter() metrics.Counter {
+	return r.backendMirrorSuccessCounter
+}
+
+func (r *standardRegistry) BackendMirrorFailureCounter() metrics.Counter {
+	return r.backendMirrorFailureCounter
+}
+
+func (r *standardRegistry) AccessLogFlushedCounter() metrics.Counter {
+	return r.accessLogFlushedCounter
+}
+
+func (r *standardRegistry) AccessLogDroppedCounter() metrics.Counter {
+	return r.accessLogDroppedCounter
+}
+
+func (r *standardRegistry) CacheHitsCounter() metrics.Counter {
+	return r.cacheHitsCounter
+}
+
+func (r *standardRegistry) CacheMissesCounter() metrics.Counter {
+	return r.cacheMissesCounter
+}
@@ -22,6 +22,8 @@ const (
 	configReloadsFailuresTotalName = metricNamePrefix + "config_reloads_failure_total"
 	configLastReloadSuccessName    = metricNamePrefix + "config_last_reload_success"
 	configLastReloadFailureName    = metricNamePrefix + "config_last_reload_failure"
+	configReloadsRejectedTotalName = metricNamePrefix + "config_reloads_rejected_total"
+	configLastReloadRejectedName   = metricNamePrefix + "config_last_reload_rejected"
 
 	// entrypoint
 	entrypointReqsTotalName   = metricNamePrefix + "entrypoint_requests_total"
@@ -34,6 +36,18 @@ const (
 	backendOpenConnsName    = metricNamePrefix + "backend_open_connections"
 	backendRetriesTotalName = metricNamePrefix + "backend_retries_total"
 	backendServerUpName     = metricNamePrefix + "backend_server_up"
+
+	// mirroring
+	backendMirrorSuccessTotalName = metricNamePrefix + "backend_mirror_success_total"
+	backendMirrorFailureTotalName = metricNamePrefix + "backend_mirror_failure_total"
+
+	// access log
+	accessLogFlushedTotalName = metricNamePrefix + "accesslog_flushed_total"
+	accessLogDroppedTotalName = metricNamePrefix + "accesslog_dropped_total"
+
+	// cache
+	cacheHitsTotalName   = metricNamePrefix + "frontend_cache_hits_total"
+	cacheMissesTotalName = metricNamePrefix + "frontend_cache_misses_total"
 )
 
 const (
@@ -93,6 +107,14 @@ func RegisterPrometheus(config *types.Prometheus) Registry {
 		Name: configLastReloadFailureName,
 		Help: "Last config reload failure",
 	}, []string{})
+	configReloadsRejected := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: configReloadsRejectedTotalName,
+		Help: "Config reloads rejected by the strict reload mode, partitioned by provider.",
+	}, []string{"provider"})
+	lastConfigReloadRejected := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: configLastReloadRejectedName,
+		Help: "Last config reload rejected by the strict reload mode, partitioned by provider.",
+	}, []string{"provider"})
 
 	entrypointReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 		Name: entrypointReqsTotalName,
@@ -123,18 +145,47 @@ func RegisterPrometheus(config *types.Prometheus) Registry {
 	}, []string{"method", "protocol", "backend"})
 	backendRetries := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 		Name: backendRetriesTotalName,
-		Help: "How many request retries happened on a backend.",
-	}, []string{"backend"})
+		Help: "How many request retries happened on a backend, partitioned by reason.",
+	}, []string{"backend", "reason"})
 	backendServerUp := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
 		Name: backendServerUpName,
 		Help: "Backend server is up, described by gauge value of 0 or 1.",
 	}, []string{"backend", "url"})
 
+	backendMirrorSuccess := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: backendMirrorSuccessTotalName,
+		Help: "How many mirrored requests succeeded on a mirror backend.",
+	}, []string{"backend"})
+	backendMirrorFailure := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: backendMirrorFailureTotalName,
+		Help: "How many mirrored requests failed on a mirror backend.",
+	}, []string{"backend"})
+
+	accessLogFlushed := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: accessLogFlushedTotalName,
+		Help: "How many buffered access logs were written, by output.",
+	}, []string{"output"})
+	accessLogDropped := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: accessLogDroppedTotalName,
+		Help: "How many buffered access logs were dropped, by output.",
+	}, []string{"output"})
+
+	cacheHits := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: cacheHitsTotalName,
+		Help: "How many requests were served from the cache of a frontend.",
+	}, []string{"frontend"})
+	cacheMisses := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: cacheMissesTotalName,
+		Help: "How many cacheable requests were not found in the cache of a frontend.",
+	}, []string{"frontend"})
+
 	promState.describers = []func(chan<- *stdprometheus.Desc){
 		configReloads.cv.Describe,
 		configReloadsFailures.cv.Describe,
 		lastConfigReloadSuccess.gv.Describe,
 		lastConfigReloadFailure.gv.Describe,
+		configReloadsRejected.cv.Describe,
+		lastConfigReloadRejected.gv.Describe,
 		entrypointReqs.cv.Describe,
 		entrypointReqDurations.hv.Describe,
 		entrypointOpenConns.gv.Describe,
@@ -143,6 +194,12 @@ func RegisterPrometheus(config *types.Prometheus) Registry {
 		backendOpenConns.gv.Describe,
 		backendRetries.cv.Describe,
 		backendServerUp.gv.Describe,
+		backendMirrorSuccess.cv.Describe,
+		backendMirrorFailure.cv.Describe,
+		accessLogFlushed.cv.Describe,
+		accessLogDropped.cv.Describe,
+		cacheHits.cv.Describe,
+		cacheMisses.cv.Describe,
 	}
 	stdprometheus.MustRegister(promState)
 
@@ -152,6 +209,8 @@ func RegisterPrometheus(config *types.Prometheus) Registry {
 		configReloadsFailureCounter:    configReloadsFailures,
 		lastConfigReloadSuccessGauge:   lastConfigReloadSuccess,
 		lastConfigReloadFailureGauge:   lastConfigReloadFailure,
+		configReloadsRejectedCounter:   configReloadsRejected,
+		lastConfigReloadRejectedGauge:  lastConfigReloadRejected,
 		entrypointReqsCounter:          entrypointReqs,
 		entrypointReqDurationHistogram: entrypointReqDurations,
 		entrypointOpenConnsGauge:       entrypointOpenConns,
@@ -160,6 +219,12 @@ func RegisterPrometheus(config *types.Prometheus) Registry {
 		backendOpenConnsGauge:          backendOpenConns,
 		backendRetriesCounter:          backendRetries,
 		backendServerUpGauge:           backendServerUp,
+		backendMirrorSuccessCounter:    backendMirrorSuccess,
+		backendMirrorFailureCounter:    backendMirrorFailure,
+		accessLogFlushedCounter:        accessLogFlushed,
+		accessLogDroppedCounter:        accessLogDropped,
+		cacheHitsCounter:               cacheHits,
+		cacheMissesCounter:             cacheMisses,
 	}
 }
 
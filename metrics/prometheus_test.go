@@ -24,6 +24,8 @@ func TestPrometheus(t *testing.T) {
 	prometheusRegistry.ConfigReloadsFailureCounter().Add(1)
 	prometheusRegistry.LastConfigReloadSuccessGauge().Set(float64(time.Now().Unix()))
 	prometheusRegistry.LastConfigReloadFailureGauge().Set(float64(time.Now().Unix()))
+	prometheusRegistry.ConfigReloadsRejectedCounter().With("provider", "file").Add(1)
+	prometheusRegistry.LastConfigReloadRejectedGauge().With("provider", "file").Set(float64(time.Now().Unix()))
 
 	prometheusRegistry.
 		EntrypointReqsCounter().
@@ -52,12 +54,36 @@ func TestPrometheus(t *testing.T) {
 		Set(1)
 	prometheusRegistry.
 		BackendRetriesCounter().
-		With("backend", "backend1").
+		With("backend", "backend1", "reason", "status_code").
 		Add(1)
 	prometheusRegistry.
 		BackendServerUpGauge().
 		With("backend", "backend1", "url", "http://127.0.0.10:80").
 		Set(1)
+	prometheusRegistry.
+		BackendMirrorSuccessCounter().
+		With("backend", "mirror1").
+		Add(1)
+	prometheusRegistry.
+		BackendMirrorFailureCounter().
+		With("backend", "mirror1").
+		Add(1)
+	prometheusRegistry.
+		AccessLogFlushedCounter().
+		With("output", "file").
+		Add(1)
+	prometheusRegistry.
+		AccessLogDroppedCounter().
+		With("output", "file").
+		Add(1)
+	prometheusRegistry.
+		CacheHitsCounter().
+		With("frontend", "frontend1").
+		Add(1)
+	prometheusRegistry.
+		CacheMissesCounter().
+		With("frontend", "frontend1").
+		Add(1)
 
 	delayForTrackingCompletion()
 
@@ -84,6 +110,16 @@ func TestPrometheus(t *testing.T) {
 			name:   configLastReloadFailureName,
 			assert: buildTimestampAssert(t, configLastReloadFailureName),
 		},
+		{
+			name:   configReloadsRejectedTotalName,
+			labels: map[string]string{"provider": "file"},
+			assert: buildCounterAssert(t, configReloadsRejectedTotalName, 1),
+		},
+		{
+			name:   configLastReloadRejectedName,
+			labels: map[string]string{"provider": "file"},
+			assert: buildTimestampAssert(t, configLastReloadRejectedName),
+		},
 		{
 			name: entrypointReqsTotalName,
 			labels: map[string]string{
@@ -146,6 +182,7 @@ func TestPrometheus(t *testing.T) {
 			name: backendRetriesTotalName,
 			labels: map[string]string{
 				"backend": "backend1",
+				"reason":  "status_code",
 			},
 			assert: buildGreaterThanCounterAssert(t, backendRetriesTotalName, 1),
 		},
@@ -157,6 +194,48 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, backendServerUpName, 1),
 		},
+		{
+			name: backendMirrorSuccessTotalName,
+			labels: map[string]string{
+				"backend": "mirror1",
+			},
+			assert: buildCounterAssert(t, backendMirrorSuccessTotalName, 1),
+		},
+		{
+			name: backendMirrorFailureTotalName,
+			labels: map[string]string{
+				"backend": "mirror1",
+			},
+			assert: buildCounterAssert(t, backendMirrorFailureTotalName, 1),
+		},
+		{
+			name: accessLogFlushedTotalName,
+			labels: map[string]string{
+				"output": "file",
+			},
+			assert: buildCounterAssert(t, accessLogFlushedTotalName, 1),
+		},
+		{
+			name: accessLogDroppedTotalName,
+			labels: map[string]string{
+				"output": "file",
+			},
+			assert: buildCounterAssert(t, accessLogDroppedTotalName, 1),
+		},
+		{
+			name: cacheHitsTotalName,
+			labels: map[string]string{
+				"frontend": "frontend1",
+			},
+			assert: buildCounterAssert(t, cacheHitsTotalName, 1),
+		},
+		{
+			name: cacheMissesTotalName,
+			labels: map[string]string{
+				"frontend": "frontend1",
+			},
+			assert: buildCounterAssert(t, cacheMissesTotalName, 1),
+		},
 	}
 
 	for _, test := range tests {
@@ -18,9 +18,15 @@ var statsdClient = statsd.New("traefik.", kitlog.LoggerFunc(func(keyvals ...inte
 var statsdTicker *time.Ticker
 
 const (
-	statsdMetricsReqsName    = "requests.total"
-	statsdMetricsLatencyName = "request.duration"
-	statsdRetriesTotalName   = "backend.retries.total"
+	statsdMetricsReqsName           = "requests.total"
+	statsdMetricsLatencyName        = "request.duration"
+	statsdRetriesTotalName          = "backend.retries.total"
+	statsdMirrorSuccessTotalName    = "backend.mirror.success.total"
+	statsdMirrorFailureTotalName    = "backend.mirror.failure.total"
+	statsdAccessLogFlushedTotalName = "accesslog.flushed.total"
+	statsdAccessLogDroppedTotalName = "accesslog.dropped.total"
+	statsdCacheHitsTotalName        = "frontend.cache.hits.total"
+	statsdCacheMissesTotalName      = "frontend.cache.misses.total"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -34,6 +40,12 @@ func RegisterStatsd(config *types.Statsd) Registry {
 		backendReqsCounter:          statsdClient.NewCounter(statsdMetricsReqsName, 1.0),
 		backendReqDurationHistogram: statsdClient.NewTiming(statsdMetricsLatencyName, 1.0),
 		backendRetriesCounter:       statsdClient.NewCounter(statsdRetriesTotalName, 1.0),
+		backendMirrorSuccessCounter: statsdClient.NewCounter(statsdMirrorSuccessTotalName, 1.0),
+		backendMirrorFailureCounter: statsdClient.NewCounter(statsdMirrorFailureTotalName, 1.0),
+		accessLogFlushedCounter:     statsdClient.NewCounter(statsdAccessLogFlushedTotalName, 1.0),
+		accessLogDroppedCounter:     statsdClient.NewCounter(statsdAccessLogDroppedTotalName, 1.0),
+		cacheHitsCounter:            statsdClient.NewCounter(statsdCacheHitsTotalName, 1.0),
+		cacheMissesCounter:          statsdClient.NewCounter(statsdCacheMissesTotalName, 1.0),
 	}
 }
 
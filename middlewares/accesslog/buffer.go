@@ -0,0 +1,175 @@
+package accesslog
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+	"github.com/go-kit/kit/metrics"
+)
+
+const (
+	// BlockOnFull makes the requests wait for room in the buffer when it is full
+	BlockOnFull = "block"
+
+	// DropOnFull discards the access logs when the buffer is full
+	DropOnFull = "drop"
+
+	// DefaultBufferSize is the default number of access logs held by the buffer
+	DefaultBufferSize = 1000
+
+	// DefaultBufferFlushInterval is the default interval between the writes of the buffered access logs
+	DefaultBufferFlushInterval = time.Second
+)
+
+// bufferedWriter writes the access logs asynchronously.
+// The entries are held in a bounded buffer, and handed over in batches to the outputs by a background goroutine,
+// either at a regular interval or as soon as a whole buffer of entries is pending.
+type bufferedWriter struct {
+	entries       chan []byte
+	size          int
+	flushInterval time.Duration
+	dropOnFull    bool
+
+	outputs []*outputWorker
+
+	// closeMu prevents the writes on a closed buffer
+	closeMu sync.RWMutex
+	closed  bool
+	done    chan struct{}
+}
+
+func newBufferedWriter(outputs []namedOutput, config *types.AccessLogBuffer, flushedCounter, droppedCounter metrics.Counter) (*bufferedWriter, error) {
+	size := config.Size
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+
+	flushInterval := time.Duration(config.FlushInterval)
+	if flushInterval <= 0 {
+		flushInterval = DefaultBufferFlushInterval
+	}
+
+	var dropOnFull bool
+	switch config.OnFull {
+	case "", BlockOnFull:
+	case DropOnFull:
+		dropOnFull = true
+	default:
+		return nil, fmt.Errorf("unsupported behavior when the buffer is full: %q, must be %s or %s", config.OnFull, BlockOnFull, DropOnFull)
+	}
+
+	w := &bufferedWriter{
+		entries:       make(chan []byte, size),
+		size:          size,
+		flushInterval: flushInterval,
+		dropOnFull:    dropOnFull,
+		done:          make(chan struct{}),
+	}
+	for _, o := range outputs {
+		w.outputs = append(w.outputs, newOutputWorker(o, dropOnFull, flushedCounter, droppedCounter))
+	}
+	go w.run()
+
+	return w, nil
+}
+
+// Write adds an entry to the buffer.
+// When the buffer is full, it either waits for room in the buffer or discards the entry.
+func (w *bufferedWriter) Write(p []byte) (int, error) {
+	// the caller may reuse p once Write returns
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	w.closeMu.RLock()
+	defer w.closeMu.RUnlock()
+
+	if w.closed {
+		w.drop()
+		return 0, io.ErrClosedPipe
+	}
+
+	if !w.dropOnFull {
+		w.entries <- entry
+		return len(p), nil
+	}
+
+	select {
+	case w.entries <- entry:
+	default:
+		w.drop()
+	}
+	return len(p), nil
+}
+
+// drop counts an entry discarded before reaching the outputs, as dropped by each of them.
+func (w *bufferedWriter) drop() {
+	for _, out := range w.outputs {
+		out.droppedCounter.Add(1)
+	}
+}
+
+// Close writes the pending entries, stops the background goroutines and closes the outputs.
+func (w *bufferedWriter) Close() error {
+	w.closeMu.Lock()
+	if w.closed {
+		w.closeMu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.entries)
+	w.closeMu.Unlock()
+
+	<-w.done
+
+	for _, out := range w.outputs {
+		if err := out.Close(); err != nil {
+			log.Errorf("Error closing access log output %s: %v", out.name, err)
+		}
+	}
+	return nil
+}
+
+func (w *bufferedWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, w.size)
+
+	for {
+		select {
+		case entry, ok := <-w.entries:
+			if !ok {
+				w.flush(batch)
+				return
+			}
+
+			batch = append(batch, entry)
+			if len(batch) >= w.size {
+				batch = w.flush(batch)
+			}
+		case <-ticker.C:
+			batch = w.flush(batch)
+		}
+	}
+}
+
+// flush hands the batch over to the outputs, and returns the emptied batch.
+func (w *bufferedWriter) flush(batch [][]byte) [][]byte {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	// the batch is reused once flushed, the outputs get their own copy
+	entries := make([][]byte, len(batch))
+	copy(entries, batch)
+	for _, out := range w.outputs {
+		out.enqueue(entries)
+	}
+	return batch[:0]
+}
@@ -0,0 +1,208 @@
+package accesslog
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/types"
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferedWriterFlushInterval(t *testing.T) {
+	out := &syncBuffer{}
+	flushed := newOutputCounter()
+	dropped := newOutputCounter()
+
+	w, err := newBufferedWriter(fileOutputs(out), &types.AccessLogBuffer{FlushInterval: flaeg.Duration(10 * time.Millisecond)}, flushed, dropped)
+	require.NoError(t, err)
+	defer w.Close()
+
+	w.Write([]byte("foo\n"))
+	w.Write([]byte("bar\n"))
+
+	assert.True(t, waitFor(func() bool { return flushed.value("file") == 2 }))
+	assert.Equal(t, "foo\nbar\n", out.String())
+	assert.Equal(t, float64(0), dropped.value("file"))
+}
+
+func TestBufferedWriterFullBuffer(t *testing.T) {
+	out := &syncBuffer{}
+	flushed := newOutputCounter()
+	dropped := newOutputCounter()
+
+	w, err := newBufferedWriter(fileOutputs(out), &types.AccessLogBuffer{Size: 2, FlushInterval: flaeg.Duration(time.Hour)}, flushed, dropped)
+	require.NoError(t, err)
+	defer w.Close()
+
+	for i := 0; i < 5; i++ {
+		w.Write([]byte("foo\n"))
+	}
+
+	// the entries are written as soon as a whole buffer of entries is pending
+	assert.True(t, waitFor(func() bool { return flushed.value("file") == 4 }))
+	assert.Equal(t, "foo\nfoo\nfoo\nfoo\n", out.String())
+}
+
+func TestBufferedWriterDropOnFull(t *testing.T) {
+	out := &blockingWriter{written: make(chan struct{}), unblock: make(chan struct{})}
+	flushed := newOutputCounter()
+	dropped := newOutputCounter()
+
+	w, err := newBufferedWriter(fileOutputs(out), &types.AccessLogBuffer{Size: 1, FlushInterval: flaeg.Duration(time.Hour), OnFull: DropOnFull}, flushed, dropped)
+	require.NoError(t, err)
+
+	// the first entry is being written, the next ones are queued until the queue of the output is full
+	w.Write([]byte("foo\n"))
+	<-out.written
+	const count = 2 * outputQueueSize
+	for i := 0; i < count; i++ {
+		n, err := w.Write([]byte("bar\n"))
+		require.NoError(t, err)
+		assert.Equal(t, 4, n)
+	}
+	assert.True(t, waitFor(func() bool { return dropped.value("file") > 0 }))
+
+	close(out.unblock)
+	require.NoError(t, w.Close())
+	assert.Equal(t, float64(count+1), flushed.value("file")+dropped.value("file"))
+}
+
+func TestBufferedWriterClose(t *testing.T) {
+	out := &syncBuffer{}
+	flushed := newOutputCounter()
+	dropped := newOutputCounter()
+
+	w, err := newBufferedWriter(fileOutputs(out), &types.AccessLogBuffer{FlushInterval: flaeg.Duration(time.Hour)}, flushed, dropped)
+	require.NoError(t, err)
+
+	w.Write([]byte("foo\n"))
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "foo\n", out.String())
+	assert.Equal(t, float64(1), flushed.value("file"))
+
+	_, err = w.Write([]byte("bar\n"))
+	assert.Error(t, err)
+	assert.Equal(t, float64(1), dropped.value("file"))
+}
+
+func TestBufferedWriterOutputs(t *testing.T) {
+	file := &syncBuffer{}
+	slow := &blockingWriter{written: make(chan struct{}), unblock: make(chan struct{})}
+	flushed := newOutputCounter()
+	dropped := newOutputCounter()
+
+	outputs := []namedOutput{
+		{name: "failing", out: &failingOutput{failures: 1}},
+		{name: "slow", out: &fileOutput{file: slow}},
+		{name: "file", out: &fileOutput{file: file}},
+	}
+	w, err := newBufferedWriter(outputs, &types.AccessLogBuffer{Size: 1, FlushInterval: flaeg.Duration(time.Hour), OnFull: DropOnFull}, flushed, dropped)
+	require.NoError(t, err)
+
+	w.Write([]byte("foo\n"))
+	<-slow.written
+	w.Write([]byte("bar\n"))
+
+	// the slow output delays neither the other outputs nor the flushes
+	assert.True(t, waitFor(func() bool { return flushed.value("file") == 2 }))
+	assert.Equal(t, "foo\nbar\n", file.String())
+
+	close(slow.unblock)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, float64(1), dropped.value("failing"))
+	assert.Equal(t, float64(1), flushed.value("failing"))
+	assert.Equal(t, float64(2), flushed.value("slow"))
+	assert.Equal(t, float64(0), dropped.value("file"))
+}
+
+func TestNewBufferedWriterInvalidOnFull(t *testing.T) {
+	_, err := newBufferedWriter(fileOutputs(&syncBuffer{}), &types.AccessLogBuffer{OnFull: "foo"}, newOutputCounter(), newOutputCounter())
+	assert.Error(t, err)
+}
+
+func fileOutputs(file io.Writer) []namedOutput {
+	return []namedOutput{{name: "file", out: &fileOutput{file: file}}}
+}
+
+// outputCounter counts by output: the generic counters do not share their values with the counters returned by With.
+type outputCounter struct {
+	mu     *sync.Mutex
+	values map[string]float64
+	output string
+}
+
+func newOutputCounter() *outputCounter {
+	return &outputCounter{mu: &sync.Mutex{}, values: make(map[string]float64)}
+}
+
+func (c *outputCounter) With(labelValues ...string) metrics.Counter {
+	counter := *c
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		if labelValues[i] == "output" {
+			counter.output = labelValues[i+1]
+		}
+	}
+	return &counter
+}
+
+func (c *outputCounter) Add(delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[c.output] += delta
+}
+
+func (c *outputCounter) value(output string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[output]
+}
+
+// waitFor returns true as soon as the condition is met, or false after one second.
+func waitFor(condition func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return false
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// blockingWriter signals the first write, and blocks the writes until unblocked.
+type blockingWriter struct {
+	once    sync.Once
+	written chan struct{}
+	unblock chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.written) })
+	<-w.unblock
+	return len(p), nil
+}
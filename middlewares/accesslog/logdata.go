@@ -124,4 +124,6 @@ type LogData struct {
 	Request            http.Header
 	OriginResponse     http.Header
 	DownstreamResponse http.Header
+	// settings overrides the settings of the logger for the requests of a frontend
+	settings *settings
 }
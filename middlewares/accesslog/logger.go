@@ -3,6 +3,7 @@ package accesslog
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
@@ -12,6 +13,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/containous/traefik/metrics"
 	"github.com/containous/traefik/types"
 	"github.com/sirupsen/logrus"
 )
@@ -35,18 +37,35 @@ type LogHandler struct {
 	logger   *logrus.Logger
 	file     *os.File
 	filePath string
-	mu       sync.Mutex
+	settings *settings
+	buffer   *bufferedWriter
+	// fileOutput writes the buffered access logs to the file
+	fileOutput *fileOutput
+	mu         sync.Mutex
 }
 
 // NewLogHandler creates a new LogHandler
-func NewLogHandler(config *types.AccessLog) (*LogHandler, error) {
-	file := os.Stdout
+func NewLogHandler(config *types.AccessLog, registry metrics.Registry) (*LogHandler, error) {
+	s, err := newSettings(config.Filters, config.Fields)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access log settings: %s", err)
+	}
+
+	shippingOutputs, err := newShippingOutputs(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access log shipping: %s", err)
+	}
+
+	// stdout is only used when the access logs are neither written to a file nor shipped
+	var file *os.File
 	if len(config.FilePath) > 0 {
 		f, err := openAccessLogFile(config.FilePath)
 		if err != nil {
 			return nil, fmt.Errorf("error opening access log file: %s", err)
 		}
 		file = f
+	} else if len(shippingOutputs) == 0 {
+		file = os.Stdout
 	}
 
 	var formatter logrus.Formatter
@@ -60,13 +79,43 @@ func NewLogHandler(config *types.AccessLog) (*LogHandler, error) {
 		return nil, fmt.Errorf("unsupported access log format: %s", config.Format)
 	}
 
-	logger := &logrus.Logger{
-		Out:       file,
+	logHandler := &LogHandler{file: file, filePath: config.FilePath, settings: s}
+
+	var out io.Writer = file
+
+	bufferConfig := config.Buffer
+	if bufferConfig == nil && len(shippingOutputs) > 0 {
+		// the access logs are always shipped in batches
+		bufferConfig = &types.AccessLogBuffer{}
+	}
+	if len(shippingOutputs) > 0 && len(bufferConfig.OnFull) == 0 {
+		// an unreachable collector must not block the requests
+		shippingBufferConfig := *bufferConfig
+		shippingBufferConfig.OnFull = DropOnFull
+		bufferConfig = &shippingBufferConfig
+	}
+
+	if bufferConfig != nil {
+		outputs := shippingOutputs
+		if file != nil {
+			logHandler.fileOutput = &fileOutput{file: file}
+			outputs = append(outputs, namedOutput{name: "file", out: logHandler.fileOutput})
+		}
+
+		logHandler.buffer, err = newBufferedWriter(outputs, bufferConfig, registry.AccessLogFlushedCounter(), registry.AccessLogDroppedCounter())
+		if err != nil {
+			return nil, fmt.Errorf("invalid access log buffer: %s", err)
+		}
+		out = logHandler.buffer
+	}
+
+	logHandler.logger = &logrus.Logger{
+		Out:       out,
 		Formatter: formatter,
 		Hooks:     make(logrus.LevelHooks),
 		Level:     logrus.InfoLevel,
 	}
-	return &LogHandler{logger: logger, file: file, filePath: config.FilePath}, nil
+	return logHandler, nil
 }
 
 func openAccessLogFile(filePath string) (*os.File, error) {
@@ -142,8 +191,14 @@ func (l *LogHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next h
 	l.logTheRoundTrip(logDataTable, crr, crw)
 }
 
-// Close closes the Logger (i.e. the file etc).
+// Close closes the Logger (i.e. the file etc), once the buffered access logs are written.
 func (l *LogHandler) Close() error {
+	if l.buffer != nil {
+		l.buffer.Close()
+	}
+	if l.file == nil {
+		return nil
+	}
 	return l.file.Close()
 }
 
@@ -162,6 +217,11 @@ func (l *LogHandler) Rotate() error {
 	if err != nil {
 		return err
 	}
+	if l.fileOutput != nil {
+		l.fileOutput.setFile(l.file)
+		return nil
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.logger.Out = l.file
@@ -216,29 +276,53 @@ func (l *LogHandler) logTheRoundTrip(logDataTable *LogData, crr *captureRequestR
 		core[Overhead] = total
 	}
 
-	fields := logrus.Fields{}
-
-	for k, v := range logDataTable.Core {
-		fields[k] = v
+	filters, modes := l.settings.filters, l.settings.fields
+	if frontendSettings := logDataTable.settings; frontendSettings != nil {
+		if frontendSettings.filters != nil {
+			filters = frontendSettings.filters
+		}
+		if frontendSettings.fields != nil {
+			modes = frontendSettings.fields
+		}
 	}
 
-	for k := range logDataTable.Request {
-		fields["request_"+k] = logDataTable.Request.Get(k)
+	retryAttempts, _ := core[RetryAttempts].(int)
+	if !filters.keep(crw.Status(), retryAttempts, total) {
+		return
 	}
 
-	for k := range logDataTable.OriginResponse {
-		fields["origin_"+k] = logDataTable.OriginResponse.Get(k)
-	}
+	fields := logrus.Fields{}
 
-	for k := range logDataTable.DownstreamResponse {
-		fields["downstream_"+k] = logDataTable.DownstreamResponse.Get(k)
+	for k, v := range logDataTable.Core {
+		switch modes.fieldMode(k) {
+		case types.AccessLogKeep:
+			fields[k] = v
+		case types.AccessLogRedact:
+			fields[k] = redactedValue
+		}
 	}
 
+	addHeaders(fields, "request_", logDataTable.Request, modes)
+	addHeaders(fields, "origin_", logDataTable.OriginResponse, modes)
+	addHeaders(fields, "downstream_", logDataTable.DownstreamResponse, modes)
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.logger.WithFields(fields).Println()
 }
 
+// addHeaders adds the headers to the fields of the log entry, with the given prefix.
+func addHeaders(fields logrus.Fields, prefix string, headers http.Header, modes *fieldModes) {
+	for k := range headers {
+		switch modes.headerMode(k) {
+		case types.AccessLogKeep:
+			fields[prefix+k] = headers.Get(k)
+		case types.AccessLogRedact:
+			fields[prefix+k] = redactedValue
+		}
+	}
+}
+
 //-------------------------------------------------------------------------------------------------
 
 var requestCounter uint64 // Request ID
@@ -21,16 +21,23 @@ type CommonLogFormatter struct{}
 func (f *CommonLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	b := &bytes.Buffer{}
 
-	timestamp := entry.Data[StartUTC].(time.Time).Format(commonLogTimeFormat)
-	elapsedMillis := entry.Data[Duration].(time.Duration).Nanoseconds() / 1000000
+	// the fields can be dropped or redacted by the access log settings
+	timestamp := defaultValue
+	if startUTC, ok := entry.Data[StartUTC].(time.Time); ok {
+		timestamp = startUTC.Format(commonLogTimeFormat)
+	}
+	elapsed := defaultValue
+	if duration, ok := entry.Data[Duration].(time.Duration); ok {
+		elapsed = fmt.Sprintf("%dms", duration.Nanoseconds()/1000000)
+	}
 
-	_, err := fmt.Fprintf(b, "%s - %s [%s] \"%s %s %s\" %v %v %s %s %v %s %s %dms\n",
-		entry.Data[ClientHost],
-		entry.Data[ClientUsername],
+	_, err := fmt.Fprintf(b, "%s - %s [%s] \"%s %s %s\" %v %v %s %s %v %s %s %s\n",
+		toValue(entry.Data[ClientHost], defaultValue),
+		toValue(entry.Data[ClientUsername], defaultValue),
 		timestamp,
-		entry.Data[RequestMethod],
-		entry.Data[RequestPath],
-		entry.Data[RequestProtocol],
+		toValue(entry.Data[RequestMethod], defaultValue),
+		toValue(entry.Data[RequestPath], defaultValue),
+		toValue(entry.Data[RequestProtocol], defaultValue),
 		toLog(entry.Data[OriginStatus], defaultValue),
 		toLog(entry.Data[OriginContentSize], defaultValue),
 		toLog(entry.Data["request_Referer"], `"-"`),
@@ -38,7 +45,7 @@ func (f *CommonLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 		toLog(entry.Data[RequestCount], defaultValue),
 		toLog(entry.Data[FrontendName], defaultValue),
 		toLog(entry.Data[BackendURL], defaultValue),
-		elapsedMillis)
+		elapsed)
 
 	return b.Bytes(), err
 }
@@ -61,6 +68,14 @@ func toLog(v interface{}, defaultValue string) interface{} {
 
 }
 
+// toValue returns the value unquoted, or the default value when it is missing.
+func toValue(v interface{}, defaultValue string) interface{} {
+	if v == nil {
+		return defaultValue
+	}
+	return v
+}
+
 func quoted(s string, defaultValue string) string {
 	if len(s) == 0 {
 		return defaultValue
@@ -57,6 +57,20 @@ func TestCommonLogFormatter_Format(t *testing.T) {
 				BackendURL:           "http://10.0.0.2/toto",
 			},
 			expectedLog: `10.0.0.1 - Client [10/Nov/2009:23:00:00 +0000] "GET /foo http" 123 132 "referer" "agent" - "foo" "http://10.0.0.2/toto" 123000ms
+`,
+		},
+		{
+			name: "dropped and redacted data",
+			data: map[string]interface{}{
+				StartUTC:        redactedValue,
+				ClientHost:      "10.0.0.1",
+				ClientUsername:  redactedValue,
+				RequestMethod:   http.MethodGet,
+				RequestPath:     "/foo",
+				RequestProtocol: "http",
+				OriginStatus:    123,
+			},
+			expectedLog: `10.0.0.1 - REDACTED [-] "GET /foo http" 123 - "-" "-" - - - -
 `,
 		},
 	}
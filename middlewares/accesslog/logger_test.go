@@ -14,6 +14,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/metrics"
 	"github.com/containous/traefik/types"
 	shellwords "github.com/mattn/go-shellwords"
 	"github.com/stretchr/testify/assert"
@@ -48,7 +50,7 @@ func TestLogRotation(t *testing.T) {
 	rotatedFileName := fileName + ".rotated"
 
 	config := &types.AccessLog{FilePath: fileName, Format: CommonFormat}
-	logHandler, err := NewLogHandler(config)
+	logHandler, err := NewLogHandler(config, metrics.NewVoidRegistry())
 	if err != nil {
 		t.Fatalf("Error creating new log handler: %s", err)
 	}
@@ -244,6 +246,215 @@ func TestLoggerJSON(t *testing.T) {
 	assert.Equal(t, len(jsonData), assertCount, string(logData))
 }
 
+func TestLoggerBuffered(t *testing.T) {
+	tmpDir := createTempDir(t, CommonFormat)
+	defer os.RemoveAll(tmpDir)
+
+	logFilePath := filepath.Join(tmpDir, logFileNameSuffix)
+	config := &types.AccessLog{
+		FilePath: logFilePath,
+		Format:   CommonFormat,
+		Buffer:   &types.AccessLogBuffer{FlushInterval: flaeg.Duration(time.Hour)},
+	}
+	// the buffered access logs are written when the logger is closed
+	doLogging(t, config)
+
+	logData, err := ioutil.ReadFile(logFilePath)
+	require.NoError(t, err)
+
+	assertValidLogData(t, logData)
+}
+
+func TestLoggerHTTPShipping(t *testing.T) {
+	var entries []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		err := json.NewDecoder(req.Body).Decode(&entries)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	config := &types.AccessLog{
+		Format: JSONFormat,
+		HTTP:   &types.AccessLogHTTP{URL: server.URL},
+	}
+	// the shipped access logs are sent when the logger is closed
+	doLogging(t, config)
+
+	require.Len(t, entries, 1)
+	assert.Equal(t, testFrontendName, entries[0][FrontendName])
+	assert.Equal(t, testHostname, entries[0][RequestHost])
+}
+
+func TestNewLogHandlerInvalidShipping(t *testing.T) {
+	_, err := NewLogHandler(&types.AccessLog{
+		Format: JSONFormat,
+		Syslog: &types.AccessLogSyslog{Network: "unix", Address: "/dev/log"},
+	}, metrics.NewVoidRegistry())
+	assert.Error(t, err)
+}
+
+func TestLoggerJSONSettings(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         *types.AccessLog
+		frontendConfig *types.FrontendAccessLog
+		expectedLog    bool
+		expected       map[string]interface{}
+		absentKeys     []string
+	}{
+		{
+			desc:        "no settings",
+			config:      &types.AccessLog{Format: JSONFormat},
+			expectedLog: true,
+			expected: map[string]interface{}{
+				ClientUsername:       testUsername,
+				"request_User-Agent": testUserAgent,
+			},
+		},
+		{
+			desc: "status code filter matching",
+			config: &types.AccessLog{
+				Format:  JSONFormat,
+				Filters: &types.AccessLogFilters{StatusCodes: types.StatusCodes{"100-199"}},
+			},
+			expectedLog: true,
+		},
+		{
+			desc: "status code filter not matching",
+			config: &types.AccessLog{
+				Format:  JSONFormat,
+				Filters: &types.AccessLogFilters{StatusCodes: types.StatusCodes{"500-599"}},
+			},
+			expectedLog: false,
+		},
+		{
+			desc: "retry attempts filter",
+			config: &types.AccessLog{
+				Format:  JSONFormat,
+				Filters: &types.AccessLogFilters{StatusCodes: types.StatusCodes{"500"}, RetryAttempts: true},
+			},
+			expectedLog: true,
+		},
+		{
+			desc: "min duration filter",
+			config: &types.AccessLog{
+				Format:  JSONFormat,
+				Filters: &types.AccessLogFilters{MinDuration: flaeg.Duration(time.Hour)},
+			},
+			expectedLog: false,
+		},
+		{
+			desc: "frontend filters overriding the global filters",
+			config: &types.AccessLog{
+				Format:  JSONFormat,
+				Filters: &types.AccessLogFilters{StatusCodes: types.StatusCodes{"500-599"}},
+			},
+			frontendConfig: &types.FrontendAccessLog{
+				Filters: &types.AccessLogFilters{RetryAttempts: true},
+			},
+			expectedLog: true,
+		},
+		{
+			desc: "dropped and redacted fields",
+			config: &types.AccessLog{
+				Format: JSONFormat,
+				Fields: &types.AccessLogFields{
+					DefaultMode: types.AccessLogKeep,
+					Names: types.FieldNames{
+						ClientUsername: types.AccessLogRedact,
+						ClientHost:     types.AccessLogDrop,
+					},
+				},
+			},
+			expectedLog: true,
+			expected: map[string]interface{}{
+				ClientUsername:       redactedValue,
+				RequestHost:          testHostname,
+				"request_User-Agent": testUserAgent,
+			},
+			absentKeys: []string{ClientHost},
+		},
+		{
+			desc: "opt-in headers",
+			config: &types.AccessLog{
+				Format: JSONFormat,
+				Fields: &types.AccessLogFields{
+					Headers: &types.FieldHeaders{
+						DefaultMode: types.AccessLogDrop,
+						Names: types.FieldNames{
+							"user-agent": types.AccessLogKeep,
+							"Referer":    types.AccessLogRedact,
+						},
+					},
+				},
+			},
+			expectedLog: true,
+			expected: map[string]interface{}{
+				"request_User-Agent": testUserAgent,
+				"request_Referer":    redactedValue,
+			},
+			absentKeys: []string{"downstream_Content-Type"},
+		},
+		{
+			desc: "frontend fields overriding the global fields",
+			config: &types.AccessLog{
+				Format: JSONFormat,
+				Fields: &types.AccessLogFields{DefaultMode: types.AccessLogDrop},
+			},
+			frontendConfig: &types.FrontendAccessLog{
+				Fields: &types.AccessLogFields{
+					Names: types.FieldNames{ClientUsername: types.AccessLogDrop},
+				},
+			},
+			expectedLog: true,
+			expected: map[string]interface{}{
+				RequestHost: testHostname,
+			},
+			absentKeys: []string{ClientUsername},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := createTempDir(t, JSONFormat)
+			defer os.RemoveAll(tmpDir)
+
+			test.config.FilePath = filepath.Join(tmpDir, logFileNameSuffix)
+			doLoggingWithFrontend(t, test.config, test.frontendConfig)
+
+			logData, err := ioutil.ReadFile(test.config.FilePath)
+			require.NoError(t, err)
+
+			if !test.expectedLog {
+				assert.Empty(t, logData)
+				return
+			}
+
+			jsonData := make(map[string]interface{})
+			err = json.Unmarshal(logData, &jsonData)
+			require.NoError(t, err)
+
+			for key, value := range test.expected {
+				assert.Equal(t, value, jsonData[key], key)
+			}
+			for _, key := range test.absentKeys {
+				assert.NotContains(t, jsonData, key)
+			}
+		})
+	}
+}
+
+func TestNewLogHandlerInvalidSettings(t *testing.T) {
+	_, err := NewLogHandler(&types.AccessLog{
+		Format:  JSONFormat,
+		Filters: &types.AccessLogFilters{StatusCodes: types.StatusCodes{"500-foo"}},
+	}, metrics.NewVoidRegistry())
+	assert.Error(t, err)
+}
+
 func TestNewLogHandlerOutputStdout(t *testing.T) {
 	file, restoreStdout := captureStdout(t)
 	defer restoreStdout()
@@ -301,7 +512,11 @@ func createTempDir(t *testing.T, prefix string) string {
 }
 
 func doLogging(t *testing.T, config *types.AccessLog) {
-	logger, err := NewLogHandler(config)
+	doLoggingWithFrontend(t, config, nil)
+}
+
+func doLoggingWithFrontend(t *testing.T, config *types.AccessLog, frontendConfig *types.FrontendAccessLog) {
+	logger, err := NewLogHandler(config, metrics.NewVoidRegistry())
 	require.NoError(t, err)
 	defer logger.Close()
 
@@ -325,7 +540,17 @@ func doLogging(t *testing.T, config *types.AccessLog) {
 		},
 	}
 
-	logger.ServeHTTP(httptest.NewRecorder(), req, logWriterTestHandlerFunc)
+	if frontendConfig == nil {
+		logger.ServeHTTP(httptest.NewRecorder(), req, logWriterTestHandlerFunc)
+		return
+	}
+
+	saveSettings, err := NewSaveSettings(frontendConfig)
+	require.NoError(t, err)
+
+	logger.ServeHTTP(httptest.NewRecorder(), req, func(rw http.ResponseWriter, r *http.Request) {
+		saveSettings.ServeHTTP(rw, r, logWriterTestHandlerFunc)
+	})
 }
 
 func containsKeys(t *testing.T, expectedKeys []string, data map[string]interface{}) {
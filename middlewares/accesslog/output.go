@@ -0,0 +1,160 @@
+package accesslog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cenk/backoff"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+	"github.com/go-kit/kit/metrics"
+)
+
+// output writes batches of access logs, each access log being formatted and terminated by a newline.
+// The outputs must not retain the batches.
+type output interface {
+	writeBatch(entries [][]byte) error
+}
+
+// outputQueueSize is the number of batches waiting to be written by an output
+const outputQueueSize = 10
+
+// namedOutput is an output of the buffered access logs, with the number of retries of its failed batches.
+type namedOutput struct {
+	name    string
+	out     output
+	retries int
+}
+
+// newShippingOutputs creates the outputs shipping the access logs to remote collectors.
+func newShippingOutputs(config *types.AccessLog) ([]namedOutput, error) {
+	var outputs []namedOutput
+
+	if config.Syslog != nil {
+		syslog, err := newSyslogOutput(config.Syslog)
+		if err != nil {
+			return nil, fmt.Errorf("invalid syslog output: %v", err)
+		}
+		outputs = append(outputs, namedOutput{name: "syslog", out: syslog, retries: config.Syslog.Retries})
+	}
+
+	if config.HTTP != nil {
+		httpOut, err := newHTTPOutput(config.HTTP, config.Format)
+		if err != nil {
+			return nil, fmt.Errorf("invalid HTTP output: %v", err)
+		}
+		outputs = append(outputs, namedOutput{name: "http", out: httpOut, retries: config.HTTP.Retries})
+	}
+
+	return outputs, nil
+}
+
+// fileOutput writes the access logs to a file or to stdout.
+type fileOutput struct {
+	// mu protects file, which is swapped on rotation
+	mu   sync.Mutex
+	file io.Writer
+}
+
+func (o *fileOutput) writeBatch(entries [][]byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	_, err := o.file.Write(bytes.Join(entries, nil))
+	return err
+}
+
+func (o *fileOutput) setFile(file io.Writer) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.file = file
+}
+
+// outputWorker writes the batches to an output in its own goroutine, retrying the failed batches with an exponential backoff,
+// so that a slow or unreachable output delays neither the other outputs nor the flushes of the buffer.
+// The written and dropped access logs are counted for each output.
+type outputWorker struct {
+	name       string
+	out        output
+	retries    int
+	newBackOff func() backoff.BackOff
+	dropOnFull bool
+
+	batches        chan [][]byte
+	flushedCounter metrics.Counter
+	droppedCounter metrics.Counter
+	done           chan struct{}
+}
+
+func newOutputWorker(o namedOutput, dropOnFull bool, flushedCounter, droppedCounter metrics.Counter) *outputWorker {
+	w := &outputWorker{
+		name:           o.name,
+		out:            o.out,
+		retries:        o.retries,
+		newBackOff:     func() backoff.BackOff { return backoff.NewExponentialBackOff() },
+		dropOnFull:     dropOnFull,
+		batches:        make(chan [][]byte, outputQueueSize),
+		flushedCounter: flushedCounter.With("output", o.name),
+		droppedCounter: droppedCounter.With("output", o.name),
+		done:           make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// enqueue hands the batch over to the goroutine of the output.
+// When too many batches are waiting, it either waits for the output or discards the batch.
+func (w *outputWorker) enqueue(batch [][]byte) {
+	if !w.dropOnFull {
+		w.batches <- batch
+		return
+	}
+
+	select {
+	case w.batches <- batch:
+	default:
+		log.Errorf("Access log output %s is late, dropping %d access logs", w.name, len(batch))
+		w.droppedCounter.Add(float64(len(batch)))
+	}
+}
+
+func (w *outputWorker) run() {
+	defer close(w.done)
+
+	for batch := range w.batches {
+		if err := w.write(batch); err != nil {
+			log.Errorf("Error writing %d access logs to %s: %v", len(batch), w.name, err)
+			w.droppedCounter.Add(float64(len(batch)))
+		} else {
+			w.flushedCounter.Add(float64(len(batch)))
+		}
+	}
+}
+
+func (w *outputWorker) write(batch [][]byte) error {
+	if w.retries <= 0 {
+		return w.out.writeBatch(batch)
+	}
+
+	operation := func() error {
+		return w.out.writeBatch(batch)
+	}
+	notify := func(err error, time time.Duration) {
+		log.Debugf("Error writing access logs to %s, retrying in %s: %v", w.name, time, err)
+	}
+	return backoff.RetryNotify(operation, backoff.WithMaxRetries(w.newBackOff(), uint64(w.retries)), notify)
+}
+
+// Close writes the pending batches, and closes the output.
+func (w *outputWorker) Close() error {
+	close(w.batches)
+	<-w.done
+
+	if closer, ok := w.out.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package accesslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/containous/traefik/types"
+)
+
+const defaultHTTPOutputTimeout = 5 * time.Second
+
+// httpOutput ships the access logs to an HTTP collector, each batch being POSTed as a JSON array.
+// With the json format the array holds the access logs, with the common format it holds the lines as strings.
+type httpOutput struct {
+	url    string
+	client *http.Client
+	json   bool
+}
+
+func newHTTPOutput(config *types.AccessLogHTTP, format string) (*httpOutput, error) {
+	if len(config.URL) == 0 {
+		return nil, errors.New("missing URL")
+	}
+	if _, err := url.ParseRequestURI(config.URL); err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %v", config.URL, err)
+	}
+
+	timeout := time.Duration(config.Timeout)
+	if timeout <= 0 {
+		timeout = defaultHTTPOutputTimeout
+	}
+
+	return &httpOutput{
+		url:    config.URL,
+		client: &http.Client{Timeout: timeout},
+		json:   format == JSONFormat,
+	}, nil
+}
+
+func (o *httpOutput) writeBatch(entries [][]byte) error {
+	body, err := o.encode(entries)
+	if err != nil {
+		return err
+	}
+
+	resp, err := o.client.Post(o.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// the body is drained for the connection to be reused
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("received unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (o *httpOutput) encode(entries [][]byte) ([]byte, error) {
+	if o.json {
+		b := &bytes.Buffer{}
+		b.WriteByte('[')
+		for i, entry := range entries {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.Write(bytes.TrimRight(entry, "\n"))
+		}
+		b.WriteByte(']')
+		return b.Bytes(), nil
+	}
+
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = string(bytes.TrimRight(entry, "\n"))
+	}
+	return json.Marshal(lines)
+}
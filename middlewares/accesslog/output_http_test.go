@@ -0,0 +1,85 @@
+package accesslog
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPOutput(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		format        string
+		entries       [][]byte
+		status        int
+		expectedBody  string
+		expectedError bool
+	}{
+		{
+			desc:         "json format",
+			format:       JSONFormat,
+			entries:      [][]byte{[]byte(`{"foo":"bar"}` + "\n"), []byte(`{"foo":"baz"}` + "\n")},
+			status:       http.StatusOK,
+			expectedBody: `[{"foo":"bar"},{"foo":"baz"}]`,
+		},
+		{
+			desc:         "common format",
+			format:       CommonFormat,
+			entries:      [][]byte{[]byte(`10.0.0.1 - - "GET /foo HTTP/1.1" 200` + "\n")},
+			status:       http.StatusAccepted,
+			expectedBody: `["10.0.0.1 - - \"GET /foo HTTP/1.1\" 200"]`,
+		},
+		{
+			desc:          "collector error",
+			format:        JSONFormat,
+			entries:       [][]byte{[]byte(`{"foo":"bar"}` + "\n")},
+			status:        http.StatusServiceUnavailable,
+			expectedBody:  `[{"foo":"bar"}]`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var body string
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, http.MethodPost, req.Method)
+				assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+				b, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+				body = string(b)
+
+				rw.WriteHeader(test.status)
+			}))
+			defer server.Close()
+
+			out, err := newHTTPOutput(&types.AccessLogHTTP{URL: server.URL}, test.format)
+			require.NoError(t, err)
+
+			err = out.writeBatch(test.entries)
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedBody, body)
+		})
+	}
+}
+
+func TestNewHTTPOutputInvalidURL(t *testing.T) {
+	_, err := newHTTPOutput(&types.AccessLogHTTP{}, JSONFormat)
+	assert.Error(t, err)
+
+	_, err = newHTTPOutput(&types.AccessLogHTTP{URL: "foo"}, JSONFormat)
+	assert.Error(t, err)
+}
@@ -0,0 +1,167 @@
+package accesslog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/containous/traefik/types"
+)
+
+const (
+	defaultSyslogNetwork  = "udp"
+	defaultSyslogFacility = "local0"
+	defaultSyslogAppName  = "traefik"
+
+	// syslogSeverityInfo is the severity of the access logs
+	syslogSeverityInfo = 6
+
+	// rfc5424TimeFormat is the timestamp format of RFC 5424, limited to microseconds
+	rfc5424TimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+	syslogDialTimeout  = 5 * time.Second
+	syslogWriteTimeout = 5 * time.Second
+)
+
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// syslogOutput ships the access logs to a syslog server, as RFC 5424 messages.
+// Over TCP, the messages are framed with octet counting (RFC 6587).
+type syslogOutput struct {
+	network  string
+	address  string
+	priority int
+	hostname string
+	appName  string
+	procID   string
+
+	// conn is only used by the goroutine writing the batches
+	conn net.Conn
+}
+
+func newSyslogOutput(config *types.AccessLogSyslog) (*syslogOutput, error) {
+	if len(config.Address) == 0 {
+		return nil, errors.New("missing address")
+	}
+
+	network := config.Network
+	if len(network) == 0 {
+		network = defaultSyslogNetwork
+	}
+	if network != "tcp" && network != "udp" {
+		return nil, fmt.Errorf("unsupported network %q, must be tcp or udp", network)
+	}
+
+	facilityName := config.Facility
+	if len(facilityName) == 0 {
+		facilityName = defaultSyslogFacility
+	}
+	facility, ok := syslogFacilities[facilityName]
+	if !ok {
+		return nil, fmt.Errorf("unsupported facility %q", facilityName)
+	}
+
+	appName := config.AppName
+	if len(appName) == 0 {
+		appName = defaultSyslogAppName
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || len(hostname) == 0 {
+		hostname = "-"
+	}
+
+	return &syslogOutput{
+		network:  network,
+		address:  config.Address,
+		priority: facility*8 + syslogSeverityInfo,
+		hostname: hostname,
+		appName:  appName,
+		procID:   strconv.Itoa(os.Getpid()),
+	}, nil
+}
+
+func (o *syslogOutput) writeBatch(entries [][]byte) error {
+	if o.conn == nil {
+		conn, err := net.DialTimeout(o.network, o.address, syslogDialTimeout)
+		if err != nil {
+			return err
+		}
+		o.conn = conn
+	}
+
+	now := time.Now()
+
+	// a stalled server must not block the output
+	if err := o.conn.SetWriteDeadline(now.Add(syslogWriteTimeout)); err != nil {
+		o.closeConn()
+		return err
+	}
+
+	if o.network == "udp" {
+		// one message per datagram
+		for _, entry := range entries {
+			if _, err := o.conn.Write(o.message(now, entry)); err != nil {
+				o.closeConn()
+				return err
+			}
+		}
+		return nil
+	}
+
+	var b bytes.Buffer
+	for _, entry := range entries {
+		message := o.message(now, entry)
+		b.WriteString(strconv.Itoa(len(message)))
+		b.WriteByte(' ')
+		b.Write(message)
+	}
+	if _, err := o.conn.Write(b.Bytes()); err != nil {
+		o.closeConn()
+		return err
+	}
+	return nil
+}
+
+// message formats an access log as a RFC 5424 message, without message ID nor structured data.
+func (o *syslogOutput) message(now time.Time, entry []byte) []byte {
+	header := fmt.Sprintf("<%d>1 %s %s %s %s - - ", o.priority, now.Format(rfc5424TimeFormat), o.hostname, o.appName, o.procID)
+	return append([]byte(header), bytes.TrimRight(entry, "\n")...)
+}
+
+func (o *syslogOutput) closeConn() {
+	if o.conn != nil {
+		o.conn.Close()
+		o.conn = nil
+	}
+}
+
+func (o *syslogOutput) Close() error {
+	o.closeConn()
+	return nil
+}
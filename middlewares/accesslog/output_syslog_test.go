@@ -0,0 +1,123 @@
+package accesslog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var syslogMessageRegexp = regexp.MustCompile(`^<134>1 \d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{6}(Z|[+-]\d{2}:\d{2}) \S+ traefik \d+ - - (.*)$`)
+
+func TestSyslogOutputUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	out, err := newSyslogOutput(&types.AccessLogSyslog{Address: conn.LocalAddr().String()})
+	require.NoError(t, err)
+	defer out.Close()
+
+	err = out.writeBatch([][]byte{[]byte("foo\n"), []byte("bar\n")})
+	require.NoError(t, err)
+
+	buf := make([]byte, 1024)
+	for _, expected := range []string{"foo", "bar"} {
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+
+		matches := syslogMessageRegexp.FindStringSubmatch(string(buf[:n]))
+		require.NotNil(t, matches, string(buf[:n]))
+		assert.Equal(t, expected, matches[2])
+	}
+}
+
+func TestSyslogOutputTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	out, err := newSyslogOutput(&types.AccessLogSyslog{Network: "tcp", Address: listener.Addr().String(), Facility: "local0", AppName: "traefik"})
+	require.NoError(t, err)
+	defer out.Close()
+
+	err = out.writeBatch([][]byte{[]byte("foo\n"), []byte("bar baz\n")})
+	require.NoError(t, err)
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for _, expected := range []string{"foo", "bar baz"} {
+		// octet counting framing: the length of the message, a space and the message
+		length, err := reader.ReadString(' ')
+		require.NoError(t, err)
+		n, err := strconv.Atoi(length[:len(length)-1])
+		require.NoError(t, err)
+
+		message := make([]byte, n)
+		_, err = io.ReadFull(reader, message)
+		require.NoError(t, err)
+
+		matches := syslogMessageRegexp.FindStringSubmatch(string(message))
+		require.NotNil(t, matches, string(message))
+		assert.Equal(t, expected, matches[2])
+	}
+}
+
+func TestNewSyslogOutput(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		config           *types.AccessLogSyslog
+		expectedPriority int
+		expectedError    bool
+	}{
+		{
+			desc:             "default values",
+			config:           &types.AccessLogSyslog{Address: "localhost:514"},
+			expectedPriority: 134,
+		},
+		{
+			desc:             "facility",
+			config:           &types.AccessLogSyslog{Address: "localhost:514", Facility: "daemon"},
+			expectedPriority: 30,
+		},
+		{
+			desc:          "missing address",
+			config:        &types.AccessLogSyslog{},
+			expectedError: true,
+		},
+		{
+			desc:          "unsupported network",
+			config:        &types.AccessLogSyslog{Address: "localhost:514", Network: "unix"},
+			expectedError: true,
+		},
+		{
+			desc:          "unsupported facility",
+			config:        &types.AccessLogSyslog{Address: "localhost:514", Facility: "foo"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := newSyslogOutput(test.config)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedPriority, out.priority)
+		})
+	}
+}
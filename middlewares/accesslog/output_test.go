@@ -0,0 +1,98 @@
+package accesslog
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/cenk/backoff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputWorkerRetries(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		retries         int
+		failures        int
+		expectedCalls   int
+		expectedFlushed float64
+		expectedDropped float64
+	}{
+		{
+			desc:            "success",
+			retries:         2,
+			expectedCalls:   2,
+			expectedFlushed: 2,
+		},
+		{
+			desc:            "success after retries",
+			retries:         2,
+			failures:        2,
+			expectedCalls:   4,
+			expectedFlushed: 2,
+		},
+		{
+			desc:            "too many failures",
+			retries:         2,
+			failures:        3,
+			expectedCalls:   4,
+			expectedFlushed: 1,
+			expectedDropped: 1,
+		},
+		{
+			desc:            "without retries",
+			failures:        1,
+			expectedCalls:   2,
+			expectedFlushed: 1,
+			expectedDropped: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := &failingOutput{failures: test.failures}
+			flushed := newOutputCounter()
+			dropped := newOutputCounter()
+
+			w := newOutputWorker(namedOutput{name: "test", out: next, retries: test.retries}, false, flushed, dropped)
+			w.newBackOff = func() backoff.BackOff { return &backoff.ZeroBackOff{} }
+
+			// each batch gets its own retries
+			w.enqueue([][]byte{[]byte("foo\n")})
+			w.enqueue([][]byte{[]byte("bar\n")})
+			require.NoError(t, w.Close())
+
+			assert.Equal(t, test.expectedCalls, next.callCount())
+			assert.Equal(t, test.expectedFlushed, flushed.value("test"))
+			assert.Equal(t, test.expectedDropped, dropped.value("test"))
+		})
+	}
+}
+
+// failingOutput fails the given number of times.
+type failingOutput struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (o *failingOutput) writeBatch(entries [][]byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.calls++
+	if o.calls <= o.failures {
+		return errors.New("failing output")
+	}
+	return nil
+}
+
+func (o *failingOutput) callCount() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.calls
+}
@@ -8,7 +8,7 @@ import (
 type SaveRetries struct{}
 
 // Retried implements the RetryListener interface and will be called for each retry that happens.
-func (s *SaveRetries) Retried(req *http.Request, attempt int) {
+func (s *SaveRetries) Retried(req *http.Request, attempt int, reason string) {
 	// it is the request attempt x, but the retry attempt is x-1
 	if attempt > 0 {
 		attempt--
@@ -38,7 +38,7 @@ func TestSaveRetries(t *testing.T) {
 			req := httptest.NewRequest(http.MethodGet, "/some/path", nil)
 			reqWithDataTable := req.WithContext(context.WithValue(req.Context(), DataTableKey, logDataTable))
 
-			saveRetries.Retried(reqWithDataTable, test.requestAttempt)
+			saveRetries.Retried(reqWithDataTable, test.requestAttempt, "network_error")
 
 			if logDataTable.Core[RetryAttempts] != test.wantRetryAttemptsInLog {
 				t.Errorf("got %v in logDataTable, want %v", logDataTable.Core[RetryAttempts], test.wantRetryAttemptsInLog)
@@ -0,0 +1,180 @@
+package accesslog
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/containous/traefik/types"
+)
+
+// redactedValue replaces the values of the redacted fields and headers.
+const redactedValue = "REDACTED"
+
+// settings holds the filters and the field modes of the access logs, validated from the configuration.
+// The filters and the fields are nil when they are not configured: the global settings then keep every access log and every field,
+// and the settings of a frontend inherit the global ones.
+type settings struct {
+	filters *filters
+	fields  *fieldModes
+}
+
+type filters struct {
+	statusCodes   []types.StatusRange
+	retryAttempts bool
+	minDuration   time.Duration
+}
+
+type fieldModes struct {
+	defaultMode       string
+	names             map[string]string
+	headerDefaultMode string
+	// headers is keyed by canonical header names
+	headers map[string]string
+}
+
+func newSettings(filtersConfig *types.AccessLogFilters, fieldsConfig *types.AccessLogFields) (*settings, error) {
+	f, err := newFilters(filtersConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	fs, err := newFields(fieldsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings{filters: f, fields: fs}, nil
+}
+
+func newFilters(config *types.AccessLogFilters) (*filters, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	statusCodes, err := config.StatusCodes.Ranges()
+	if err != nil {
+		return nil, err
+	}
+
+	return &filters{
+		statusCodes:   statusCodes,
+		retryAttempts: config.RetryAttempts,
+		minDuration:   time.Duration(config.MinDuration),
+	}, nil
+}
+
+// keep returns true if the request matches at least one of the filters, or if no filter is set.
+func (f *filters) keep(status int, retryAttempts int, duration time.Duration) bool {
+	if f == nil || len(f.statusCodes) == 0 && !f.retryAttempts && f.minDuration <= 0 {
+		return true
+	}
+
+	for _, statusRange := range f.statusCodes {
+		if statusRange.Contains(status) {
+			return true
+		}
+	}
+	if f.retryAttempts && retryAttempts > 0 {
+		return true
+	}
+	return f.minDuration > 0 && duration > f.minDuration
+}
+
+func newFields(config *types.AccessLogFields) (*fieldModes, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	f := &fieldModes{
+		defaultMode:       types.AccessLogKeep,
+		names:             make(map[string]string),
+		headerDefaultMode: types.AccessLogKeep,
+		headers:           make(map[string]string),
+	}
+
+	if len(config.DefaultMode) > 0 {
+		if err := checkMode(config.DefaultMode); err != nil {
+			return nil, fmt.Errorf("invalid default mode of the fields: %v", err)
+		}
+		f.defaultMode = config.DefaultMode
+	}
+	for name, mode := range config.Names {
+		if err := checkMode(mode); err != nil {
+			return nil, fmt.Errorf("invalid mode of the field %s: %v", name, err)
+		}
+		f.names[name] = mode
+	}
+
+	if config.Headers != nil {
+		if len(config.Headers.DefaultMode) > 0 {
+			if err := checkMode(config.Headers.DefaultMode); err != nil {
+				return nil, fmt.Errorf("invalid default mode of the headers: %v", err)
+			}
+			f.headerDefaultMode = config.Headers.DefaultMode
+		}
+		for name, mode := range config.Headers.Names {
+			if err := checkMode(mode); err != nil {
+				return nil, fmt.Errorf("invalid mode of the header %s: %v", name, err)
+			}
+			f.headers[http.CanonicalHeaderKey(name)] = mode
+		}
+	}
+
+	return f, nil
+}
+
+func checkMode(mode string) error {
+	switch mode {
+	case types.AccessLogKeep, types.AccessLogDrop, types.AccessLogRedact:
+		return nil
+	default:
+		return fmt.Errorf("unsupported mode %q, must be one of %s, %s or %s", mode, types.AccessLogKeep, types.AccessLogDrop, types.AccessLogRedact)
+	}
+}
+
+// fieldMode returns the mode of the given field.
+func (f *fieldModes) fieldMode(name string) string {
+	if f == nil {
+		return types.AccessLogKeep
+	}
+	if mode, ok := f.names[name]; ok {
+		return mode
+	}
+	return f.defaultMode
+}
+
+// headerMode returns the mode of the given header, the name being canonical.
+func (f *fieldModes) headerMode(name string) string {
+	if f == nil {
+		return types.AccessLogKeep
+	}
+	if mode, ok := f.headers[name]; ok {
+		return mode
+	}
+	return f.headerDefaultMode
+}
+
+//-------------------------------------------------------------------------------------------------
+
+// SaveSettings sends the access log settings of a frontend to the logger,
+// overriding the filters and the fields of the global configuration for the requests of the frontend.
+type SaveSettings struct {
+	settings *settings
+}
+
+// NewSaveSettings creates a SaveSettings handler.
+func NewSaveSettings(config *types.FrontendAccessLog) (*SaveSettings, error) {
+	s, err := newSettings(config.Filters, config.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return &SaveSettings{settings: s}, nil
+}
+
+func (s *SaveSettings) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	table := GetLogDataTable(r)
+	table.settings = s.settings
+
+	next.ServeHTTP(rw, r)
+}
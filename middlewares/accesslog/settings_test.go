@@ -0,0 +1,185 @@
+package accesslog
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFiltersKeep(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        *types.AccessLogFilters
+		status        int
+		retryAttempts int
+		duration      time.Duration
+		expected      bool
+	}{
+		{
+			desc:     "no filters",
+			status:   http.StatusOK,
+			expected: true,
+		},
+		{
+			desc:     "empty filters",
+			config:   &types.AccessLogFilters{},
+			status:   http.StatusOK,
+			expected: true,
+		},
+		{
+			desc:     "status code in range",
+			config:   &types.AccessLogFilters{StatusCodes: types.StatusCodes{"200", "500-599"}},
+			status:   502,
+			expected: true,
+		},
+		{
+			desc:     "status code equal to a single status code",
+			config:   &types.AccessLogFilters{StatusCodes: types.StatusCodes{"200", "500-599"}},
+			status:   http.StatusOK,
+			expected: true,
+		},
+		{
+			desc:     "status code out of range",
+			config:   &types.AccessLogFilters{StatusCodes: types.StatusCodes{"200", "500-599"}},
+			status:   404,
+			expected: false,
+		},
+		{
+			desc:          "retried request",
+			config:        &types.AccessLogFilters{StatusCodes: types.StatusCodes{"500-599"}, RetryAttempts: true},
+			status:        http.StatusOK,
+			retryAttempts: 1,
+			expected:      true,
+		},
+		{
+			desc:     "request not retried",
+			config:   &types.AccessLogFilters{RetryAttempts: true},
+			status:   http.StatusOK,
+			expected: false,
+		},
+		{
+			desc:     "slow request",
+			config:   &types.AccessLogFilters{MinDuration: flaeg.Duration(time.Second)},
+			status:   http.StatusOK,
+			duration: 2 * time.Second,
+			expected: true,
+		},
+		{
+			desc:     "fast request",
+			config:   &types.AccessLogFilters{MinDuration: flaeg.Duration(time.Second)},
+			status:   http.StatusOK,
+			duration: time.Millisecond,
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := newFilters(test.config)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, f.keep(test.status, test.retryAttempts, test.duration))
+		})
+	}
+}
+
+func TestNewFiltersInvalidStatusCodes(t *testing.T) {
+	for _, value := range []string{"foo", "500-", "500-599-600", "599-500"} {
+		value := value
+		t.Run(value, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newFilters(&types.AccessLogFilters{StatusCodes: types.StatusCodes{value}})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewFields(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          *types.AccessLogFields
+		expectedError   bool
+		expectedFields  map[string]string
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:            "no fields",
+			expectedFields:  map[string]string{ClientHost: types.AccessLogKeep},
+			expectedHeaders: map[string]string{"User-Agent": types.AccessLogKeep},
+		},
+		{
+			desc: "default modes",
+			config: &types.AccessLogFields{
+				DefaultMode: types.AccessLogDrop,
+				Names:       types.FieldNames{ClientHost: types.AccessLogRedact},
+			},
+			expectedFields: map[string]string{
+				ClientHost:   types.AccessLogRedact,
+				ClientPort:   types.AccessLogDrop,
+				FrontendName: types.AccessLogDrop,
+			},
+			expectedHeaders: map[string]string{"User-Agent": types.AccessLogKeep},
+		},
+		{
+			desc: "header modes",
+			config: &types.AccessLogFields{
+				Headers: &types.FieldHeaders{
+					DefaultMode: types.AccessLogDrop,
+					Names:       types.FieldNames{"authorization": types.AccessLogRedact},
+				},
+			},
+			expectedFields: map[string]string{ClientHost: types.AccessLogKeep},
+			expectedHeaders: map[string]string{
+				"Authorization": types.AccessLogRedact,
+				"User-Agent":    types.AccessLogDrop,
+			},
+		},
+		{
+			desc:          "invalid default mode",
+			config:        &types.AccessLogFields{DefaultMode: "foo"},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid field mode",
+			config:        &types.AccessLogFields{Names: types.FieldNames{ClientHost: "foo"}},
+			expectedError: true,
+		},
+		{
+			desc: "invalid header mode",
+			config: &types.AccessLogFields{
+				Headers: &types.FieldHeaders{Names: types.FieldNames{"Authorization": "foo"}},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := newFields(test.config)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for name, mode := range test.expectedFields {
+				assert.Equal(t, mode, f.fieldMode(name), name)
+			}
+			for name, mode := range test.expectedHeaders {
+				assert.Equal(t, mode, f.headerMode(name), name)
+			}
+		})
+	}
+}
@@ -14,7 +14,7 @@ import (
 	"github.com/urfave/negroni"
 )
 
-// Authenticator is a middleware that provides HTTP basic and digest authentication
+// Authenticator is a middleware that provides HTTP basic, digest, forward, JWT and OpenID Connect authentication
 type Authenticator struct {
 	handler negroni.Handler
 	users   map[string]string
@@ -53,9 +53,26 @@ func NewAuthenticator(authConfig *types.Auth, tracingMiddleware *tracing.Tracing
 		tracingAuthenticator.name = "Auth Digest"
 		tracingAuthenticator.clientSpanKind = false
 	} else if authConfig.Forward != nil {
-		tracingAuthenticator.handler = createAuthForwardHandler(authConfig)
+		tracingAuthenticator.handler, err = NewForwardAuth(authConfig.Forward)
+		if err != nil {
+			return nil, err
+		}
 		tracingAuthenticator.name = "Auth Forward"
 		tracingAuthenticator.clientSpanKind = true
+	} else if authConfig.JWT != nil {
+		tracingAuthenticator.handler, err = NewJWTAuth(authConfig.JWT, authConfig.HeaderField)
+		if err != nil {
+			return nil, err
+		}
+		tracingAuthenticator.name = "Auth JWT"
+		tracingAuthenticator.clientSpanKind = false
+	} else if authConfig.OIDC != nil {
+		tracingAuthenticator.handler, err = NewOIDCAuth(authConfig.OIDC, authConfig.HeaderField)
+		if err != nil {
+			return nil, err
+		}
+		tracingAuthenticator.name = "Auth OIDC"
+		tracingAuthenticator.clientSpanKind = true
 	}
 	if tracingMiddleware != nil {
 		authenticator.handler = tracingMiddleware.NewNegroniHandlerWrapper(tracingAuthenticator.name, tracingAuthenticator.handler, tracingAuthenticator.clientSpanKind)
@@ -65,11 +82,6 @@ func NewAuthenticator(authConfig *types.Auth, tracingMiddleware *tracing.Tracing
 	return &authenticator, nil
 }
 
-func createAuthForwardHandler(authConfig *types.Auth) negroni.HandlerFunc {
-	return negroni.HandlerFunc(func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
-		Forward(authConfig.Forward, w, r, next)
-	})
-}
 func createAuthDigestHandler(digestAuth *goauth.DigestAuth, authConfig *types.Auth) negroni.HandlerFunc {
 	return negroni.HandlerFunc(func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 		if username, _ := digestAuth.CheckAuth(r); username == "" {
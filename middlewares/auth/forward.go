@@ -1,6 +1,9 @@
 package auth
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -17,10 +20,28 @@ const (
 	xForwardedURI = "X-Forwarded-Uri"
 )
 
-// Forward the authentication to a external server
-func Forward(config *types.Forward, w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+const (
+	// DefaultForwardMaxBodySize is the maximum size of the request bodies forwarded to the authentication server
+	DefaultForwardMaxBodySize = 1 << 20
+	// DefaultForwardCacheMaxEntries is the maximum number of cached decisions
+	DefaultForwardCacheMaxEntries = 10000
+)
+
+// DefaultForwardCacheKey is the list of the request attributes identifying a cached decision
+var DefaultForwardCacheKey = []string{"method", "host", "path", "header:Authorization", "header:Cookie"}
+
+// ForwardAuth forwards the authentication to an external server
+type ForwardAuth struct {
+	config      *types.Forward
+	client      *http.Client
+	maxBodySize int64
+	cache       *forwardCache
+}
+
+// NewForwardAuth builds a new ForwardAuth given a config
+func NewForwardAuth(config *types.Forward) (*ForwardAuth, error) {
 	// Ensure our request client does not follow redirects
-	httpClient := http.Client{
+	client := &http.Client{
 		CheckRedirect: func(r *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
@@ -28,36 +49,96 @@ func Forward(config *types.Forward, w http.ResponseWriter, r *http.Request, next
 	if config.TLS != nil {
 		tlsConfig, err := config.TLS.CreateTLSConfig()
 		if err != nil {
-			tracing.SetErrorAndDebugLog(r, "Unable to configure TLS to call %s. Cause %s", config.Address, err)
+			return nil, fmt.Errorf("unable to configure TLS to call %s: %v", config.Address, err)
+		}
+		client.Transport = &http.Transport{
+			TLSClientConfig: tlsConfig,
+		}
+	}
+
+	fa := &ForwardAuth{
+		config:      config,
+		client:      client,
+		maxBodySize: config.MaxBodySize,
+	}
+	if fa.maxBodySize <= 0 {
+		fa.maxBodySize = DefaultForwardMaxBodySize
+	}
+
+	if config.Cache != nil {
+		// The decisions depending on the body are not cached, the body not being part of the cache key.
+		if config.ForwardBody {
+			return nil, fmt.Errorf("the decisions of %s cannot be cached when the body is forwarded", config.Address)
+		}
+		cache, err := newForwardCache(config.Cache)
+		if err != nil {
+			return nil, err
+		}
+		fa.cache = cache
+	}
+
+	return fa, nil
+}
+
+func (fa *ForwardAuth) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	var cacheKey string
+	if fa.cache != nil {
+		cacheKey = fa.cache.key(r)
+		if headers, ok := fa.cache.get(cacheKey); ok {
+			log.Debugf("Using cached decision of %s", fa.config.Address)
+			fa.copyAuthResponseHeaders(headers, r)
+			r.RequestURI = r.URL.RequestURI()
+			next(w, r)
+			return
+		}
+	}
+
+	method := http.MethodGet
+	var body []byte
+	if fa.config.ForwardBody && r.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(io.LimitReader(r.Body, fa.maxBodySize+1))
+		if err != nil {
+			tracing.SetErrorAndDebugLog(r, "Error reading request body. Cause: %s", err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
-		httpClient.Transport = &http.Transport{
-			TLSClientConfig: tlsConfig,
+		if int64(len(body)) > fa.maxBodySize {
+			tracing.SetErrorAndDebugLog(r, "Request body larger than %d bytes", fa.maxBodySize)
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
 		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		method = r.Method
 	}
-	forwardReq, err := http.NewRequest(http.MethodGet, config.Address, nil)
+
+	var forwardBody io.Reader
+	if body != nil {
+		forwardBody = bytes.NewReader(body)
+	}
+
+	forwardReq, err := http.NewRequest(method, fa.config.Address, forwardBody)
 	tracing.LogRequest(tracing.GetSpan(r), forwardReq)
 	if err != nil {
-		tracing.SetErrorAndDebugLog(r, "Error calling %s. Cause %s", config.Address, err)
+		tracing.SetErrorAndDebugLog(r, "Error calling %s. Cause %s", fa.config.Address, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	writeHeader(r, forwardReq, config.TrustForwardHeader)
+	writeHeader(r, forwardReq, fa.config.TrustForwardHeader)
 
 	tracing.InjectRequestHeaders(forwardReq)
 
-	forwardResponse, forwardErr := httpClient.Do(forwardReq)
+	forwardResponse, forwardErr := fa.client.Do(forwardReq)
 	if forwardErr != nil {
-		tracing.SetErrorAndDebugLog(r, "Error calling %s. Cause: %s", config.Address, forwardErr)
+		tracing.SetErrorAndDebugLog(r, "Error calling %s. Cause: %s", fa.config.Address, forwardErr)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	body, readError := ioutil.ReadAll(forwardResponse.Body)
+	respBody, readError := ioutil.ReadAll(forwardResponse.Body)
 	if readError != nil {
-		tracing.SetErrorAndDebugLog(r, "Error reading body %s. Cause: %s", config.Address, readError)
+		tracing.SetErrorAndDebugLog(r, "Error reading body %s. Cause: %s", fa.config.Address, readError)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -66,14 +147,14 @@ func Forward(config *types.Forward, w http.ResponseWriter, r *http.Request, next
 	// Pass the forward response's body and selected headers if it
 	// didn't return a response within the range of [200, 300).
 	if forwardResponse.StatusCode < http.StatusOK || forwardResponse.StatusCode >= http.StatusMultipleChoices {
-		log.Debugf("Remote error %s. StatusCode: %d", config.Address, forwardResponse.StatusCode)
+		log.Debugf("Remote error %s. StatusCode: %d", fa.config.Address, forwardResponse.StatusCode)
 
 		// Grab the location header, if any.
 		redirectURL, err := forwardResponse.Location()
 
 		if err != nil {
 			if err != http.ErrNoLocation {
-				tracing.SetErrorAndDebugLog(r, "Error reading response location header %s. Cause: %s", config.Address, err)
+				tracing.SetErrorAndDebugLog(r, "Error reading response location header %s. Cause: %s", fa.config.Address, err)
 				w.WriteHeader(http.StatusInternalServerError)
 				return
 			}
@@ -89,14 +170,42 @@ func Forward(config *types.Forward, w http.ResponseWriter, r *http.Request, next
 
 		tracing.LogResponseCode(tracing.GetSpan(r), forwardResponse.StatusCode)
 		w.WriteHeader(forwardResponse.StatusCode)
-		w.Write(body)
+		w.Write(respBody)
 		return
 	}
 
+	headers := fa.authResponseHeaders(forwardResponse.Header)
+	if fa.cache != nil {
+		fa.cache.set(cacheKey, headers)
+	}
+	fa.copyAuthResponseHeaders(headers, r)
+
 	r.RequestURI = r.URL.RequestURI()
 	next(w, r)
 }
 
+// authResponseHeaders returns the configured headers of the authentication server response
+func (fa *ForwardAuth) authResponseHeaders(header http.Header) http.Header {
+	headers := make(http.Header)
+	for _, name := range fa.config.AuthResponseHeaders {
+		if values, ok := header[http.CanonicalHeaderKey(name)]; ok {
+			headers[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	return headers
+}
+
+// copyAuthResponseHeaders sets the configured headers on the forwarded request,
+// removing those sent by the client and not returned by the authentication server
+func (fa *ForwardAuth) copyAuthResponseHeaders(headers http.Header, r *http.Request) {
+	for _, name := range fa.config.AuthResponseHeaders {
+		r.Header.Del(name)
+		if values, ok := headers[http.CanonicalHeaderKey(name)]; ok {
+			r.Header[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+		}
+	}
+}
+
 func writeHeader(req *http.Request, forwardReq *http.Request, trustForwardHeader bool) {
 	utils.CopyHeaders(forwardReq.Header, req.Header)
 
@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/types"
+)
+
+// forwardCache stores the allow decisions of a forward authentication server
+type forwardCache struct {
+	ttl        time.Duration
+	attributes []string
+	maxEntries int
+	now        func() time.Time
+
+	lock    sync.Mutex
+	entries map[string]forwardCacheEntry
+}
+
+type forwardCacheEntry struct {
+	headers http.Header
+	expires time.Time
+}
+
+func newForwardCache(config *types.ForwardCache) (*forwardCache, error) {
+	if config.TTL <= 0 {
+		return nil, fmt.Errorf("forward auth cache TTL must be positive")
+	}
+
+	key := config.Key
+	if len(key) == 0 {
+		key = DefaultForwardCacheKey
+	}
+	for _, attribute := range key {
+		if err := checkForwardCacheAttribute(attribute); err != nil {
+			return nil, err
+		}
+	}
+
+	maxEntries := config.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultForwardCacheMaxEntries
+	}
+
+	return &forwardCache{
+		ttl:        time.Duration(config.TTL),
+		attributes: key,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]forwardCacheEntry),
+	}, nil
+}
+
+func checkForwardCacheAttribute(attribute string) error {
+	kind, name := splitForwardCacheAttribute(attribute)
+	switch kind {
+	case "method", "host", "path", "clientip":
+		if name == "" {
+			return nil
+		}
+	case "header", "cookie":
+		if name != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid forward auth cache key attribute %q", attribute)
+}
+
+func splitForwardCacheAttribute(attribute string) (string, string) {
+	parts := strings.SplitN(attribute, ":", 2)
+	kind := strings.ToLower(strings.TrimSpace(parts[0]))
+	if len(parts) == 1 {
+		return kind, ""
+	}
+	return kind, strings.TrimSpace(parts[1])
+}
+
+// key computes the cache key of a request from the configured attributes
+func (c *forwardCache) key(r *http.Request) string {
+	hash := sha256.New()
+	for _, attribute := range c.attributes {
+		hash.Write([]byte(attribute))
+		hash.Write([]byte{0})
+		hash.Write([]byte(forwardCacheAttributeValue(attribute, r)))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func forwardCacheAttributeValue(attribute string, r *http.Request) string {
+	kind, name := splitForwardCacheAttribute(attribute)
+	switch kind {
+	case "method":
+		return r.Method
+	case "host":
+		return r.Host
+	case "path":
+		// The auth server gets the query with the path in X-Forwarded-Uri.
+		return r.URL.RequestURI()
+	case "clientip":
+		if clientIP, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			return clientIP
+		}
+		return r.RemoteAddr
+	case "header":
+		return strings.Join(r.Header[http.CanonicalHeaderKey(name)], "\x00")
+	case "cookie":
+		if cookie, err := r.Cookie(name); err == nil {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+func (c *forwardCache) get(key string) (http.Header, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.headers, true
+}
+
+func (c *forwardCache) set(key string, headers http.Header) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		// Still full: evict an arbitrary entry
+		for k := range c.entries {
+			if len(c.entries) < c.maxEntries {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = forwardCacheEntry{headers: headers, expires: now.Add(c.ttl)}
+}
@@ -5,12 +5,17 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/containous/flaeg"
 	"github.com/containous/traefik/middlewares/tracing"
 	"github.com/containous/traefik/testhelpers"
 	"github.com/containous/traefik/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/urfave/negroni"
 )
 
@@ -268,3 +273,303 @@ func Test_writeHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestForwardAuthResponseHeaders(t *testing.T) {
+	authTs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Auth-User", "user@example.com")
+		w.Header().Set("X-Auth-Secret", "secret")
+		fmt.Fprintln(w, "Success")
+	}))
+	defer authTs.Close()
+
+	authMiddleware, err := NewAuthenticator(&types.Auth{
+		Forward: &types.Forward{
+			Address:             authTs.URL,
+			AuthResponseHeaders: []string{"X-Auth-User", "X-Auth-Groups"},
+		},
+	}, &tracing.Tracing{})
+	assert.NoError(t, err, "there should be no error")
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, r.Header.Get("X-Auth-User"))
+		fmt.Fprintln(w, r.Header.Get("X-Auth-Groups"))
+		fmt.Fprintln(w, r.Header.Get("X-Auth-Secret"))
+	})
+	n := negroni.New(authMiddleware)
+	n.UseHandler(handler)
+	ts := httptest.NewServer(n)
+	defer ts.Close()
+
+	req := testhelpers.MustNewRequest(http.MethodGet, ts.URL, nil)
+	req.Header.Set("X-Auth-Groups", "admin")
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, http.StatusOK, res.StatusCode, "they should be equal")
+
+	body, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, "user@example.com\n\n\n", string(body), "they should be equal")
+}
+
+func TestForwardAuthBody(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		forwardBody        bool
+		body               string
+		expectedStatusCode int
+		expectedAuthMethod string
+		expectedAuthBody   string
+	}{
+		{
+			desc:               "body not forwarded",
+			body:               "payload",
+			expectedStatusCode: http.StatusOK,
+			expectedAuthMethod: http.MethodGet,
+		},
+		{
+			desc:               "body forwarded",
+			forwardBody:        true,
+			body:               "payload",
+			expectedStatusCode: http.StatusOK,
+			expectedAuthMethod: http.MethodPost,
+			expectedAuthBody:   "payload",
+		},
+		{
+			desc:               "body too large",
+			forwardBody:        true,
+			body:               "large payload",
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var authMethod, authBody string
+			authTs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authMethod = r.Method
+				body, _ := ioutil.ReadAll(r.Body)
+				authBody = string(body)
+			}))
+			defer authTs.Close()
+
+			fa, err := NewForwardAuth(&types.Forward{
+				Address:     authTs.URL,
+				ForwardBody: test.forwardBody,
+				MaxBodySize: 10,
+			})
+			require.NoError(t, err)
+
+			next := func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				w.Write(body)
+			}
+
+			req := testhelpers.MustNewRequest(http.MethodPost, "http://foo.bar/baz", strings.NewReader(test.body))
+			recorder := httptest.NewRecorder()
+			fa.ServeHTTP(recorder, req, next)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+			assert.Equal(t, test.expectedAuthMethod, authMethod)
+			assert.Equal(t, test.expectedAuthBody, authBody)
+			if test.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, test.body, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestForwardAuthCache(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		key           []string
+		statusCode    int
+		firstRequest  func(req *http.Request)
+		secondRequest func(req *http.Request)
+		elapsed       time.Duration
+		expectedCalls int
+	}{
+		{
+			desc:          "allow decision cached",
+			expectedCalls: 1,
+		},
+		{
+			desc:          "deny decision not cached",
+			statusCode:    http.StatusForbidden,
+			expectedCalls: 2,
+		},
+		{
+			desc:          "expired decision",
+			elapsed:       time.Minute,
+			expectedCalls: 2,
+		},
+		{
+			desc:          "different authorization",
+			secondRequest: func(req *http.Request) { req.Header.Set("Authorization", "Bearer other") },
+			expectedCalls: 2,
+		},
+		{
+			desc:          "different path",
+			secondRequest: func(req *http.Request) { req.URL.Path = "/other" },
+			expectedCalls: 2,
+		},
+		{
+			desc:          "different query",
+			secondRequest: func(req *http.Request) { req.URL.RawQuery = "admin=true" },
+			expectedCalls: 2,
+		},
+		{
+			desc:          "path not in the key",
+			key:           []string{"header:Authorization"},
+			secondRequest: func(req *http.Request) { req.URL.Path = "/other" },
+			expectedCalls: 1,
+		},
+		{
+			desc:          "different cookie",
+			key:           []string{"Cookie:session"},
+			firstRequest:  func(req *http.Request) { req.AddCookie(&http.Cookie{Name: "session", Value: "foo"}) },
+			secondRequest: func(req *http.Request) { req.AddCookie(&http.Cookie{Name: "session", Value: "bar"}) },
+			expectedCalls: 2,
+		},
+		{
+			desc:          "different client IP",
+			key:           []string{"clientIP"},
+			secondRequest: func(req *http.Request) { req.RemoteAddr = "10.0.0.2:1234" },
+			expectedCalls: 2,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int
+			authTs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Header().Set("X-Auth-User", "user"+strconv.Itoa(calls))
+				if test.statusCode != 0 {
+					w.WriteHeader(test.statusCode)
+				}
+			}))
+			defer authTs.Close()
+
+			fa, err := NewForwardAuth(&types.Forward{
+				Address:             authTs.URL,
+				AuthResponseHeaders: []string{"X-Auth-User"},
+				Cache: &types.ForwardCache{
+					TTL: flaeg.Duration(time.Minute),
+					Key: test.key,
+				},
+			})
+			require.NoError(t, err)
+
+			now := time.Unix(1000, 0)
+			fa.cache.now = func() time.Time { return now }
+
+			next := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, r.Header.Get("X-Auth-User"))
+			}
+
+			for i, customize := range []func(req *http.Request){test.firstRequest, test.secondRequest} {
+				req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/baz", nil)
+				req.RemoteAddr = "10.0.0.1:1234"
+				req.Header.Set("Authorization", "Bearer token")
+				if customize != nil {
+					customize(req)
+				}
+				if i == 1 {
+					now = now.Add(test.elapsed)
+				}
+
+				recorder := httptest.NewRecorder()
+				fa.ServeHTTP(recorder, req, next)
+
+				if test.statusCode == 0 {
+					// The second response comes from the cache when the decision was not requested again
+					assert.Equal(t, "user"+strconv.Itoa(calls), recorder.Body.String())
+				}
+			}
+
+			assert.Equal(t, test.expectedCalls, calls)
+		})
+	}
+}
+
+func TestNewForwardAuth(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        types.Forward
+		expectedError bool
+	}{
+		{
+			desc:   "without cache",
+			config: types.Forward{Address: "http://auth.bar"},
+		},
+		{
+			desc: "with cache",
+			config: types.Forward{
+				Address: "http://auth.bar",
+				Cache: &types.ForwardCache{
+					TTL: flaeg.Duration(time.Minute),
+					Key: []string{"method", "HOST", "path", "clientIP", "header:X-Token", "cookie:session"},
+				},
+			},
+		},
+		{
+			desc: "cache without TTL",
+			config: types.Forward{
+				Address: "http://auth.bar",
+				Cache:   &types.ForwardCache{},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "invalid cache key attribute",
+			config: types.Forward{
+				Address: "http://auth.bar",
+				Cache: &types.ForwardCache{
+					TTL: flaeg.Duration(time.Minute),
+					Key: []string{"query"},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "cache with forwarded body",
+			config: types.Forward{
+				Address:     "http://auth.bar",
+				ForwardBody: true,
+				Cache:       &types.ForwardCache{TTL: flaeg.Duration(time.Minute)},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "cache key header without name",
+			config: types.Forward{
+				Address: "http://auth.bar",
+				Cache: &types.ForwardCache{
+					TTL: flaeg.Duration(time.Minute),
+					Key: []string{"header:"},
+				},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewForwardAuth(&test.config)
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
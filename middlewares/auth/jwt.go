@@ -0,0 +1,435 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+	"github.com/dgrijalva/jwt-go"
+	jose "gopkg.in/square/go-jose.v1"
+)
+
+const (
+	// DefaultJWKSRefreshInterval is the interval between two reloads of a JSON Web Key Set, when none is configured
+	DefaultJWKSRefreshInterval = time.Hour
+
+	// jwksMinRefreshInterval is the minimum interval between two reloads of a JSON Web Key Set,
+	// when a token is signed with an unknown key
+	jwksMinRefreshInterval = time.Minute
+
+	jwksFetchTimeout = 10 * time.Second
+)
+
+// JWTAuth is a middleware verifying the signature and the claims of the bearer tokens of the requests.
+type JWTAuth struct {
+	config      types.JWT
+	headerField string
+	secret      []byte
+	publicKeys  []interface{}
+	jwks        *jwks
+	now         func() time.Time
+}
+
+// NewJWTAuth creates a JWT authenticator, headerField being the request header set with the subject of the tokens.
+func NewJWTAuth(config *types.JWT, headerField string) (*JWTAuth, error) {
+	j := &JWTAuth{
+		config:      *config,
+		headerField: headerField,
+		secret:      []byte(config.Secret),
+		now:         time.Now,
+	}
+
+	for _, publicKey := range config.PublicKeys {
+		key, err := parsePublicKey(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		j.publicKeys = append(j.publicKeys, key)
+	}
+
+	if len(config.JWKS) > 0 {
+		refreshInterval := time.Duration(config.JWKSRefreshInterval)
+		if refreshInterval <= 0 {
+			refreshInterval = DefaultJWKSRefreshInterval
+		}
+		j.jwks = &jwks{
+			source:          config.JWKS,
+			refreshInterval: refreshInterval,
+			client:          &http.Client{Timeout: jwksFetchTimeout},
+			now:             time.Now,
+		}
+	}
+
+	if len(j.secret) == 0 && len(j.publicKeys) == 0 && j.jwks == nil {
+		return nil, errors.New("the JWT authentication requires a secret, public keys or a JSON Web Key Set")
+	}
+	return j, nil
+}
+
+func (j *JWTAuth) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	authorization := r.Header.Get("Authorization")
+	if len(authorization) < 7 || !strings.EqualFold(authorization[:7], "bearer ") {
+		log.Debugf("JWT auth failed: no bearer token")
+		rw.Header().Set("WWW-Authenticate", `Bearer realm="traefik"`)
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := j.verify(strings.TrimSpace(authorization[7:]))
+	if err != nil {
+		log.Debugf("JWT auth failed: %v", err)
+		rw.Header().Set("WWW-Authenticate", `Bearer realm="traefik", error="invalid_token"`)
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	log.Debugf("JWT auth succeeded")
+	if subject, ok := claims["sub"].(string); ok {
+		r.URL.User = url.User(subject)
+		if len(j.headerField) > 0 {
+			r.Header[j.headerField] = []string{subject}
+		}
+	}
+
+	for header, claim := range j.config.ClaimHeaders {
+		if value, ok := claimValue(claims, claim); ok {
+			r.Header.Set(header, value)
+		} else {
+			r.Header.Del(header)
+		}
+	}
+
+	next.ServeHTTP(rw, r)
+}
+
+// verify checks the signature and the claims of the token, and returns its claims.
+func (j *JWTAuth) verify(rawToken string) (jwtClaims, error) {
+	claims := jwtClaims{}
+	parser := &jwt.Parser{UseJSONNumber: true}
+	if _, err := parser.ParseWithClaims(rawToken, &claims, j.keyFunc); err != nil {
+		return nil, err
+	}
+
+	if err := j.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// keyFunc returns the key verifying the signature of the token, among the keys matching its algorithm and key ID.
+func (j *JWTAuth) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	var candidates []interface{}
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if len(j.secret) > 0 {
+			candidates = append(candidates, j.secret)
+		}
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		candidates = append(candidates, j.publicKeys...)
+	default:
+		return nil, fmt.Errorf("unsupported signing method %s", token.Method.Alg())
+	}
+	if j.jwks != nil {
+		candidates = append(candidates, j.jwks.getKeys(kid)...)
+	}
+
+	parts := strings.Split(token.Raw, ".")
+	signingString := strings.Join(parts[:2], ".")
+	for _, key := range candidates {
+		if !compatibleKey(token.Method, key) {
+			continue
+		}
+		if err := token.Method.Verify(signingString, parts[2], key); err == nil {
+			return key, nil
+		}
+	}
+	return nil, errors.New("no key verifies the signature")
+}
+
+func (j *JWTAuth) validateClaims(claims jwtClaims) error {
+	now := j.now()
+	clockSkew := time.Duration(j.config.ClockSkew)
+
+	if exp, ok, err := claims.time("exp"); err != nil {
+		return err
+	} else if !ok && !j.config.AllowNoExpiration {
+		return errors.New("token has no expiration")
+	} else if ok && !now.Before(exp.Add(clockSkew)) {
+		return errors.New("token is expired")
+	}
+
+	if nbf, ok, err := claims.time("nbf"); err != nil {
+		return err
+	} else if ok && now.Add(clockSkew).Before(nbf) {
+		return errors.New("token is not valid yet")
+	}
+
+	if len(j.config.Issuer) > 0 {
+		if iss, _ := claims["iss"].(string); iss != j.config.Issuer {
+			return fmt.Errorf("invalid issuer %q", iss)
+		}
+	}
+
+	if len(j.config.Audiences) > 0 && !claims.hasAudience(j.config.Audiences) {
+		return errors.New("invalid audience")
+	}
+	return nil
+}
+
+// jwtClaims are the claims of a token, validated by JWTAuth instead of the parser to take the clock skew into account.
+type jwtClaims map[string]interface{}
+
+// Valid implements jwt.Claims.
+func (c *jwtClaims) Valid() error {
+	return nil
+}
+
+// time returns the date of a NumericDate claim, and false if the claim is not set.
+func (c jwtClaims) time(name string) (time.Time, bool, error) {
+	value, ok := c[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	number, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("invalid %s claim", name)
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid %s claim: %v", name, err)
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))), true, nil
+}
+
+func (c jwtClaims) hasAudience(audiences []string) bool {
+	var tokenAudiences []string
+	switch aud := c["aud"].(type) {
+	case string:
+		tokenAudiences = append(tokenAudiences, aud)
+	case []interface{}:
+		for _, value := range aud {
+			if audience, ok := value.(string); ok {
+				tokenAudiences = append(tokenAudiences, audience)
+			}
+		}
+	}
+
+	for _, audience := range audiences {
+		for _, tokenAudience := range tokenAudiences {
+			if audience == tokenAudience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lookupClaim returns the value of the claim, the claims nested in objects being named with dots.
+func lookupClaim(claims map[string]interface{}, name string) (interface{}, bool) {
+	value, ok := claims[name]
+	if !ok {
+		parts := strings.SplitN(name, ".", 2)
+		nested, isObject := claims[parts[0]].(map[string]interface{})
+		if len(parts) < 2 || !isObject {
+			return nil, false
+		}
+		return lookupClaim(nested, parts[1])
+	}
+	return value, true
+}
+
+// claimValue returns the value of the claim as a header value.
+// The arrays are joined with commas, and the objects are encoded in JSON.
+func claimValue(claims map[string]interface{}, name string) (string, bool) {
+	value, ok := lookupClaim(claims, name)
+	if !ok {
+		return "", false
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case []interface{}:
+		var values []string
+		for _, element := range v {
+			if s, ok := element.(string); ok {
+				values = append(values, s)
+			} else {
+				encoded, _ := json.Marshal(element)
+				values = append(values, string(encoded))
+			}
+		}
+		return strings.Join(values, ","), true
+	case nil:
+		return "", false
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(encoded), true
+	}
+}
+
+func compatibleKey(method jwt.SigningMethod, key interface{}) bool {
+	switch method.(type) {
+	case *jwt.SigningMethodHMAC:
+		_, ok := key.([]byte)
+		return ok
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		_, ok := key.(*rsa.PublicKey)
+		return ok
+	case *jwt.SigningMethodECDSA:
+		_, ok := key.(*ecdsa.PublicKey)
+		return ok
+	}
+	return false
+}
+
+// parsePublicKey parses a PEM encoded RSA or ECDSA public key, or the file holding it.
+func parsePublicKey(publicKey string) (interface{}, error) {
+	content := []byte(publicKey)
+	if !strings.HasPrefix(strings.TrimSpace(publicKey), "-----BEGIN") {
+		var err error
+		content, err = ioutil.ReadFile(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the public key file %s: %v", publicKey, err)
+		}
+	}
+
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(content); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPublicKeyFromPEM(content); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("invalid public key %q: not a PEM encoded RSA or ECDSA public key", publicKey)
+}
+
+// jwks is a JSON Web Key Set loaded from an URL or a file, and reloaded periodically.
+type jwks struct {
+	source          string
+	refreshInterval time.Duration
+	client          *http.Client
+	now             func() time.Time
+
+	lock      sync.Mutex
+	keys      []jose.JsonWebKey
+	loaded    time.Time
+	attempted time.Time
+	// loading is closed when the load in progress ends, and nil when the set is not being loaded.
+	loading chan struct{}
+}
+
+// getKeys returns the public keys of the set, with the key ID if it is not empty.
+// The set is reloaded when it is too old, or when no key has the key ID, at most once per jwksMinRefreshInterval
+// even when the load fails. The set is loaded without holding the lock: while it is loaded by a request,
+// the other requests use the current keys, and only wait for the load if no current key matches.
+func (j *jwks) getKeys(kid string) []interface{} {
+	j.lock.Lock()
+	now := j.now()
+	keys := j.matchingKeys(kid)
+	loading := j.loading
+	if loading == nil && now.Sub(j.attempted) >= jwksMinRefreshInterval &&
+		(now.Sub(j.loaded) >= j.refreshInterval || len(keys) == 0 && len(kid) > 0) {
+		j.attempted = now
+		j.loading = make(chan struct{})
+		j.lock.Unlock()
+
+		j.load(now)
+		return j.currentKeys(kid)
+	}
+	j.lock.Unlock()
+
+	if loading == nil || len(keys) > 0 {
+		return keys
+	}
+	<-loading
+	return j.currentKeys(kid)
+}
+
+func (j *jwks) currentKeys(kid string) []interface{} {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.matchingKeys(kid)
+}
+
+func (j *jwks) matchingKeys(kid string) []interface{} {
+	var keys []interface{}
+	for _, key := range j.keys {
+		if len(kid) > 0 && key.KeyID != kid || key.Use == "enc" {
+			continue
+		}
+
+		switch k := key.Key.(type) {
+		case *rsa.PrivateKey:
+			keys = append(keys, &k.PublicKey)
+		case *ecdsa.PrivateKey:
+			keys = append(keys, &k.PublicKey)
+		default:
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// load reads the set, and ends the load in progress.
+func (j *jwks) load(now time.Time) {
+	var keys []jose.JsonWebKey
+	content, err := j.read()
+	if err != nil {
+		log.Errorf("Error loading the JSON Web Key Set %s: %v", j.source, err)
+	} else {
+		set := jose.JsonWebKeySet{}
+		if err := json.Unmarshal(content, &set); err != nil {
+			log.Errorf("Error decoding the JSON Web Key Set %s: %v", j.source, err)
+		} else {
+			keys = set.Keys
+		}
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if keys != nil {
+		j.keys = keys
+		j.loaded = now
+	}
+	close(j.loading)
+	j.loading = nil
+}
+
+func (j *jwks) read() ([]byte, error) {
+	if !strings.HasPrefix(j.source, "http://") && !strings.HasPrefix(j.source, "https://") {
+		return ioutil.ReadFile(j.source)
+	}
+
+	resp, err := j.client.Get(j.source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,481 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v1"
+)
+
+var jwtTestNow = time.Unix(1500000000, 0)
+
+func TestJWTAuth(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaPublicKey := encodePublicKey(t, &rsaKey.PublicKey)
+	ecPublicKey := encodePublicKey(t, &ecKey.PublicKey)
+
+	testCases := []struct {
+		desc            string
+		config          types.JWT
+		headerField     string
+		token           func() string
+		requestHeaders  map[string]string
+		expectedCode    int
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:   "no bearer token",
+			config: types.JWT{Secret: "secret"},
+			token: func() string {
+				return ""
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:   "HS256 token",
+			config: types.JWT{Secret: "secret"},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{"sub": "foo"})
+			},
+			headerField:  "X-WebAuth-User",
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:   "HS256 token with another secret",
+			config: types.JWT{Secret: "secret"},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodHS256, []byte("other"), "", jwt.MapClaims{"sub": "foo"})
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:   "RS256 token with a PEM public key",
+			config: types.JWT{PublicKeys: []string{rsaPublicKey}},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodRS256, rsaKey, "", jwt.MapClaims{"sub": "foo"})
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:   "ES256 token with a PEM public key",
+			config: types.JWT{PublicKeys: []string{rsaPublicKey, ecPublicKey}},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodES256, ecKey, "", jwt.MapClaims{"sub": "foo"})
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:   "HS256 token signed with the RSA public key",
+			config: types.JWT{PublicKeys: []string{rsaPublicKey}},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodHS256, []byte(rsaPublicKey), "", jwt.MapClaims{"sub": "foo"})
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:   "unsigned token",
+			config: types.JWT{Secret: "secret"},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", jwt.MapClaims{"sub": "foo"})
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:   "expired token",
+			config: types.JWT{Secret: "secret"},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{"exp": jwtTestNow.Add(-time.Second).Unix()})
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:   "expired token within the clock skew",
+			config: types.JWT{Secret: "secret", ClockSkew: flaeg.Duration(time.Minute)},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{"sub": "foo", "exp": jwtTestNow.Add(-time.Second).Unix()})
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:   "token without expiration",
+			config: types.JWT{Secret: "secret"},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{"sub": "foo", "exp": nil})
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:   "token without expiration allowed",
+			config: types.JWT{Secret: "secret", AllowNoExpiration: true},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{"sub": "foo", "exp": nil})
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:   "token not valid yet",
+			config: types.JWT{Secret: "secret"},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{"nbf": jwtTestNow.Add(time.Minute).Unix()})
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:   "invalid issuer",
+			config: types.JWT{Secret: "secret", Issuer: "https://auth.example.com"},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{"iss": "https://other.example.com"})
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:   "valid issuer and audience",
+			config: types.JWT{Secret: "secret", Issuer: "https://auth.example.com", Audiences: []string{"api"}},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{
+					"sub": "foo",
+					"iss": "https://auth.example.com",
+					"aud": []string{"web", "api"},
+				})
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:   "invalid audience",
+			config: types.JWT{Secret: "secret", Audiences: []string{"api"}},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{"aud": "web"})
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc: "claim headers",
+			config: types.JWT{
+				Secret: "secret",
+				ClaimHeaders: map[string]string{
+					"X-User":   "sub",
+					"X-Roles":  "roles",
+					"X-Tenant": "org.tenant",
+					"X-Admin":  "admin",
+					"X-Email":  "email",
+				},
+			},
+			token: func() string {
+				return signToken(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{
+					"sub":   "foo",
+					"roles": []string{"read", "write"},
+					"org":   map[string]interface{}{"tenant": "acme"},
+					"admin": true,
+				})
+			},
+			requestHeaders: map[string]string{"X-Email": "spoofed@example.com"},
+			expectedCode:   http.StatusOK,
+			expectedHeaders: map[string]string{
+				"X-User":   "foo",
+				"X-Roles":  "read,write",
+				"X-Tenant": "acme",
+				"X-Admin":  "true",
+				"X-Email":  "",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			authenticator, err := NewJWTAuth(&test.config, test.headerField)
+			require.NoError(t, err)
+			authenticator.now = func() time.Time { return jwtTestNow }
+
+			var forwarded *http.Request
+			next := func(rw http.ResponseWriter, req *http.Request) {
+				forwarded = req
+			}
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/baz", nil)
+			if token := test.token(); len(token) > 0 {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			for name, value := range test.requestHeaders {
+				req.Header.Set(name, value)
+			}
+
+			recorder := httptest.NewRecorder()
+			authenticator.ServeHTTP(recorder, req, next)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedCode != http.StatusOK {
+				assert.Nil(t, forwarded)
+				assert.Contains(t, recorder.Header().Get("WWW-Authenticate"), "Bearer")
+				return
+			}
+
+			require.NotNil(t, forwarded)
+			assert.Equal(t, "foo", forwarded.URL.User.Username())
+			if len(test.headerField) > 0 {
+				assert.Equal(t, []string{"foo"}, forwarded.Header[test.headerField])
+			}
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, forwarded.Header.Get(name), name)
+			}
+		})
+	}
+}
+
+func TestJWTAuthPublicKeyFile(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	publicKeyFile, err := ioutil.TempFile("", "jwt-public-key")
+	require.NoError(t, err)
+	defer os.Remove(publicKeyFile.Name())
+	_, err = publicKeyFile.Write([]byte(encodePublicKey(t, &key.PublicKey)))
+	require.NoError(t, err)
+	require.NoError(t, publicKeyFile.Close())
+
+	authenticator, err := NewJWTAuth(&types.JWT{PublicKeys: []string{publicKeyFile.Name()}}, "")
+	require.NoError(t, err)
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/baz", nil)
+	req.Header.Set("Authorization", "Bearer "+signToken(t, jwt.SigningMethodES256, key, "", jwt.MapClaims{"sub": "foo"}))
+	recorder := httptest.NewRecorder()
+	authenticator.ServeHTTP(recorder, req, func(rw http.ResponseWriter, req *http.Request) {})
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestJWTAuthJWKS(t *testing.T) {
+	firstKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	secondKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var lock sync.Mutex
+	var fetches int
+	set := jose.JsonWebKeySet{Keys: []jose.JsonWebKey{{Key: &firstKey.PublicKey, KeyID: "first", Use: "sig"}}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		fetches++
+		json.NewEncoder(rw).Encode(set)
+	}))
+	defer server.Close()
+
+	authenticator, err := NewJWTAuth(&types.JWT{JWKS: server.URL}, "")
+	require.NoError(t, err)
+
+	now := jwtTestNow
+	authenticator.now = func() time.Time { return now }
+	authenticator.jwks.now = func() time.Time { return now }
+
+	serve := func(token string) int {
+		req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/baz", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		recorder := httptest.NewRecorder()
+		authenticator.ServeHTTP(recorder, req, func(rw http.ResponseWriter, req *http.Request) {})
+		return recorder.Code
+	}
+
+	firstToken := signToken(t, jwt.SigningMethodRS256, firstKey, "first", jwt.MapClaims{"sub": "foo"})
+	secondToken := signToken(t, jwt.SigningMethodES256, secondKey, "second", jwt.MapClaims{"sub": "foo"})
+
+	assert.Equal(t, http.StatusOK, serve(firstToken))
+	assert.Equal(t, http.StatusOK, serve(firstToken))
+	assert.Equal(t, 1, fetches, "the key set should be cached")
+
+	lock.Lock()
+	set.Keys = append(set.Keys, jose.JsonWebKey{Key: &secondKey.PublicKey, KeyID: "second", Use: "sig"})
+	lock.Unlock()
+
+	assert.Equal(t, http.StatusUnauthorized, serve(secondToken), "the key set should not be reloaded too often")
+	assert.Equal(t, 1, fetches)
+
+	now = now.Add(2 * jwksMinRefreshInterval)
+	assert.Equal(t, http.StatusOK, serve(secondToken), "the key set should be reloaded for an unknown key ID")
+	assert.Equal(t, 2, fetches)
+
+	now = now.Add(DefaultJWKSRefreshInterval)
+	assert.Equal(t, http.StatusOK, serve(firstToken))
+	assert.Equal(t, 3, fetches, "the key set should be reloaded when it is too old")
+}
+
+func TestJWTAuthJWKSReloadDoesNotBlock(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	set := jose.JsonWebKeySet{Keys: []jose.JsonWebKey{{Key: &key.PublicKey, KeyID: "first", Use: "sig"}}}
+	reloading := make(chan struct{})
+	release := make(chan struct{})
+	var fetches int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&fetches, 1) > 1 {
+			close(reloading)
+			<-release
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(rw).Encode(set)
+	}))
+	defer server.Close()
+
+	authenticator, err := NewJWTAuth(&types.JWT{JWKS: server.URL}, "")
+	require.NoError(t, err)
+
+	var lock sync.Mutex
+	now := jwtTestNow
+	getNow := func() time.Time {
+		lock.Lock()
+		defer lock.Unlock()
+		return now
+	}
+	authenticator.now = getNow
+	authenticator.jwks.now = getNow
+
+	serve := func() int {
+		req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/baz", nil)
+		req.Header.Set("Authorization", "Bearer "+signToken(t, jwt.SigningMethodRS256, key, "first", jwt.MapClaims{"sub": "foo"}))
+		recorder := httptest.NewRecorder()
+		authenticator.ServeHTTP(recorder, req, func(rw http.ResponseWriter, req *http.Request) {})
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve())
+
+	lock.Lock()
+	now = now.Add(DefaultJWKSRefreshInterval)
+	lock.Unlock()
+
+	reloaded := make(chan int)
+	go func() { reloaded <- serve() }()
+	<-reloading
+
+	assert.Equal(t, http.StatusOK, serve(), "the current keys should be used while the key set is reloaded")
+	close(release)
+	assert.Equal(t, http.StatusOK, <-reloaded, "the current keys should be kept when the reload fails")
+
+	assert.Equal(t, http.StatusOK, serve())
+	assert.EqualValues(t, 2, atomic.LoadInt32(&fetches), "the failed reload should not be retried immediately")
+}
+
+func TestJWTAuthJWKSFile(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	content, err := json.Marshal(jose.JsonWebKeySet{Keys: []jose.JsonWebKey{{Key: key, KeyID: "private"}}})
+	require.NoError(t, err)
+
+	jwksFile, err := ioutil.TempFile("", "jwks")
+	require.NoError(t, err)
+	defer os.Remove(jwksFile.Name())
+	_, err = jwksFile.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, jwksFile.Close())
+
+	authenticator, err := NewJWTAuth(&types.JWT{JWKS: jwksFile.Name()}, "")
+	require.NoError(t, err)
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/baz", nil)
+	req.Header.Set("Authorization", "Bearer "+signToken(t, jwt.SigningMethodRS256, key, "private", jwt.MapClaims{"sub": "foo"}))
+	recorder := httptest.NewRecorder()
+	authenticator.ServeHTTP(recorder, req, func(rw http.ResponseWriter, req *http.Request) {})
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestNewJWTAuth(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        types.JWT
+		expectedError bool
+	}{
+		{
+			desc:   "secret",
+			config: types.JWT{Secret: "secret"},
+		},
+		{
+			desc:   "JSON Web Key Set",
+			config: types.JWT{JWKS: "https://auth.example.com/jwks.json"},
+		},
+		{
+			desc:          "no key",
+			config:        types.JWT{Issuer: "https://auth.example.com"},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid public key",
+			config:        types.JWT{PublicKeys: []string{"-----BEGIN PUBLIC KEY-----\nfoo\n-----END PUBLIC KEY-----"}},
+			expectedError: true,
+		},
+		{
+			desc:          "missing public key file",
+			config:        types.JWT{PublicKeys: []string{"/path/to/missing.pem"}},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewJWTAuth(&test.config, "")
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// signToken signs a token with the claims. The token expires in one hour if the claims have no exp,
+// and has no expiration if exp is nil.
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	if exp, ok := claims["exp"]; !ok {
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+	} else if exp == nil {
+		delete(claims, "exp")
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	if len(kid) > 0 {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func encodePublicKey(t *testing.T, key interface{}) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
@@ -0,0 +1,694 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+)
+
+const (
+	// DefaultOIDCCallbackPath is the path of the redirect URL, when none is configured
+	DefaultOIDCCallbackPath = "/_oidc/callback"
+	// DefaultOIDCCookieName is the name of the session cookie, when none is configured
+	DefaultOIDCCookieName = "_traefik_oidc"
+	// DefaultOIDCSessionLifetime is the duration after which the users have to log in again, when none is configured
+	DefaultOIDCSessionLifetime = 24 * time.Hour
+	// DefaultOIDCGroupsClaim is the claim holding the groups of the users, when none is configured
+	DefaultOIDCGroupsClaim = "groups"
+
+	oidcStateLifetime   = 10 * time.Minute
+	oidcRequestTimeout  = 10 * time.Second
+	oidcMaxResponseSize = 1 << 20
+	// oidcMaxCookieSize is the size limit of the cookies in the browsers, name and value included
+	oidcMaxCookieSize = 4096
+	// oidcRefreshReuse is the duration during which the tokens refreshed for a session are reused by the requests
+	// still sending the previous session cookie, the refresh tokens being possibly invalidated once used
+	oidcRefreshReuse = 30 * time.Second
+
+	oidcMinDiscoveryBackoff = time.Second
+	oidcMaxDiscoveryBackoff = time.Minute
+)
+
+var defaultOIDCScopes = []string{"openid", "email", "profile"}
+
+// OIDCAuth is a middleware logging the users in with the authorization code flow of an OpenID Connect provider.
+// The session of the users is kept in an encrypted cookie, and their tokens are refreshed when they expire.
+type OIDCAuth struct {
+	config          types.OIDC
+	headerField     string
+	scopes          []string
+	callbackPath    string
+	cookieName      string
+	sessionLifetime time.Duration
+	groupsClaim     string
+	aead            cipher.AEAD
+	client          *http.Client
+	now             func() time.Time
+
+	lock             sync.Mutex
+	provider         *oidcProvider
+	discovering      chan struct{}
+	discoveryErr     error
+	discoveryBackoff time.Duration
+	nextDiscovery    time.Time
+	refreshes        map[string]*oidcRefresh
+}
+
+// oidcRefresh is the refresh of the tokens of a session, shared by the concurrent requests of the session.
+type oidcRefresh struct {
+	done    chan struct{}
+	session *oidcSession
+	err     error
+	expires time.Time
+}
+
+// oidcProvider is the discovery document of an OpenID Connect provider.
+type oidcProvider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+
+	verifier *JWTAuth
+}
+
+// oidcSession is the content of the session cookie.
+type oidcSession struct {
+	Subject      string   `json:"sub"`
+	Email        string   `json:"email,omitempty"`
+	Groups       []string `json:"groups,omitempty"`
+	RefreshToken string   `json:"refreshToken,omitempty"`
+	Expires      int64    `json:"expires"`
+	Created      int64    `json:"created"`
+}
+
+// oidcState is the content of the cookie protecting the authorization requests against forgery.
+type oidcState struct {
+	State       string `json:"state"`
+	Nonce       string `json:"nonce"`
+	RedirectURI string `json:"redirectUri"`
+}
+
+type oidcTokens struct {
+	AccessToken  string `json:"access_token"`
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// NewOIDCAuth creates an OpenID Connect authenticator, headerField being the request header set with the email address of the users.
+func NewOIDCAuth(config *types.OIDC, headerField string) (*OIDCAuth, error) {
+	if len(config.Issuer) == 0 || len(config.ClientID) == 0 {
+		return nil, errors.New("the OpenID Connect authentication requires an issuer and a client ID")
+	}
+	if len(config.SessionSecret) == 0 {
+		return nil, errors.New("the OpenID Connect authentication requires a session secret")
+	}
+
+	key := sha256.Sum256([]byte(config.SessionSecret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &OIDCAuth{
+		config:          *config,
+		headerField:     headerField,
+		scopes:          config.Scopes,
+		callbackPath:    config.CallbackPath,
+		cookieName:      config.CookieName,
+		sessionLifetime: time.Duration(config.SessionLifetime),
+		groupsClaim:     config.GroupsClaim,
+		aead:            aead,
+		client:          &http.Client{Timeout: oidcRequestTimeout},
+		now:             time.Now,
+		refreshes:       make(map[string]*oidcRefresh),
+	}
+	if len(a.scopes) == 0 {
+		a.scopes = defaultOIDCScopes
+	}
+	if len(a.callbackPath) == 0 {
+		a.callbackPath = DefaultOIDCCallbackPath
+	}
+	if len(a.cookieName) == 0 {
+		a.cookieName = DefaultOIDCCookieName
+	}
+	if a.sessionLifetime <= 0 {
+		a.sessionLifetime = DefaultOIDCSessionLifetime
+	}
+	if len(a.groupsClaim) == 0 {
+		a.groupsClaim = DefaultOIDCGroupsClaim
+	}
+	return a, nil
+}
+
+func (a *OIDCAuth) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	provider, err := a.getProvider()
+	if err != nil {
+		log.Errorf("Error discovering the OpenID Connect provider %s: %v", a.config.Issuer, err)
+		http.Error(rw, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		return
+	}
+
+	if r.URL.Path == a.callbackPath {
+		a.callback(rw, r, provider)
+		return
+	}
+
+	session, err := a.getSession(rw, r, provider)
+	if err != nil {
+		log.Debugf("OIDC auth: invalid session: %v", err)
+	}
+	if session == nil {
+		a.login(rw, r, provider)
+		return
+	}
+
+	if !a.authorized(session) {
+		log.Debugf("OIDC auth failed: %s is not allowed", session.user())
+		http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	log.Debugf("OIDC auth succeeded")
+	r.URL.User = url.User(session.user())
+	if len(a.headerField) > 0 {
+		r.Header[a.headerField] = []string{session.user()}
+	}
+	next.ServeHTTP(rw, r)
+}
+
+// login redirects the users to the authorization endpoint of the provider.
+func (a *OIDCAuth) login(rw http.ResponseWriter, r *http.Request, provider *oidcProvider) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		log.Debugf("OIDC auth failed: no session")
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	state := &oidcState{RedirectURI: r.URL.RequestURI()}
+	var err error
+	if state.State, err = randomToken(); err == nil {
+		state.Nonce, err = randomToken()
+	}
+	if err == nil {
+		err = a.setCookie(rw, r, a.stateCookieName(), state, oidcStateLifetime)
+	}
+	if err != nil {
+		log.Errorf("Error creating the OpenID Connect state: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {a.config.ClientID},
+		"redirect_uri":  {a.redirectURL(r)},
+		"scope":         {strings.Join(a.scopes, " ")},
+		"state":         {state.State},
+		"nonce":         {state.Nonce},
+	}
+	separator := "?"
+	if strings.Contains(provider.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	http.Redirect(rw, r, provider.AuthorizationEndpoint+separator+query.Encode(), http.StatusFound)
+}
+
+// callback exchanges the authorization code for the tokens of the user, creates the session, and redirects to the requested URL.
+func (a *OIDCAuth) callback(rw http.ResponseWriter, r *http.Request, provider *oidcProvider) {
+	query := r.URL.Query()
+	if errorCode := query.Get("error"); len(errorCode) > 0 {
+		log.Debugf("OIDC auth failed: %s: %s", errorCode, query.Get("error_description"))
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	state := &oidcState{}
+	if err := a.getCookie(r, a.stateCookieName(), state); err != nil {
+		log.Debugf("OIDC auth failed: invalid state cookie: %v", err)
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(state.State), []byte(query.Get("state"))) != 1 {
+		log.Debugf("OIDC auth failed: invalid state")
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	a.deleteCookie(rw, a.stateCookieName())
+
+	tokens, err := a.requestTokens(provider, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {query.Get("code")},
+		"redirect_uri": {a.redirectURL(r)},
+	})
+	if err != nil {
+		log.Debugf("OIDC auth failed: %v", err)
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := provider.verifier.verify(tokens.IDToken)
+	if err != nil {
+		log.Debugf("OIDC auth failed: invalid ID token: %v", err)
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	if nonce, _ := claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(nonce), []byte(state.Nonce)) != 1 {
+		log.Debugf("OIDC auth failed: invalid nonce")
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	session, err := a.newSession(claims, tokens, a.now())
+	if err != nil {
+		log.Debugf("OIDC auth failed: %v", err)
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	if !a.authorized(session) {
+		log.Debugf("OIDC auth failed: %s is not allowed", session.user())
+		http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	if err := a.setSession(rw, r, session); err != nil {
+		log.Errorf("Error creating the OpenID Connect session: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	redirectURI := state.RedirectURI
+	if !strings.HasPrefix(redirectURI, "/") || strings.HasPrefix(redirectURI, "//") {
+		redirectURI = "/"
+	}
+	http.Redirect(rw, r, redirectURI, http.StatusFound)
+}
+
+// getSession returns the session of the request, refreshing its tokens if they are expired.
+// It returns nil if the user has to log in.
+func (a *OIDCAuth) getSession(rw http.ResponseWriter, r *http.Request, provider *oidcProvider) (*oidcSession, error) {
+	if _, err := r.Cookie(a.cookieName); err != nil {
+		return nil, nil
+	}
+
+	session := &oidcSession{}
+	if err := a.getCookie(r, a.cookieName, session); err != nil {
+		return nil, err
+	}
+
+	now := a.now()
+	if !now.Before(time.Unix(session.Created, 0).Add(a.sessionLifetime)) {
+		return nil, errors.New("session is expired")
+	}
+	if now.Before(time.Unix(session.Expires, 0)) {
+		return session, nil
+	}
+	if len(session.RefreshToken) == 0 {
+		return nil, errors.New("tokens are expired")
+	}
+
+	refreshed, err := a.refresh(session, provider, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.setSession(rw, r, refreshed); err != nil {
+		return nil, err
+	}
+	return refreshed, nil
+}
+
+// refresh refreshes the tokens of the session. The concurrent requests of the session share the same refresh,
+// and the refreshed session is reused for oidcRefreshReuse by the requests still sending the previous session cookie.
+func (a *OIDCAuth) refresh(session *oidcSession, provider *oidcProvider, now time.Time) (*oidcSession, error) {
+	a.lock.Lock()
+	for refreshToken, call := range a.refreshes {
+		if !call.expires.IsZero() && now.After(call.expires) {
+			delete(a.refreshes, refreshToken)
+		}
+	}
+	if call, ok := a.refreshes[session.RefreshToken]; ok {
+		a.lock.Unlock()
+		<-call.done
+		return call.session, call.err
+	}
+	call := &oidcRefresh{done: make(chan struct{})}
+	a.refreshes[session.RefreshToken] = call
+	a.lock.Unlock()
+
+	call.session, call.err = a.refreshTokens(session, provider, now)
+
+	a.lock.Lock()
+	if call.err != nil {
+		delete(a.refreshes, session.RefreshToken)
+	} else {
+		call.expires = now.Add(oidcRefreshReuse)
+	}
+	a.lock.Unlock()
+	close(call.done)
+	return call.session, call.err
+}
+
+func (a *OIDCAuth) refreshTokens(session *oidcSession, provider *oidcProvider, now time.Time) (*oidcSession, error) {
+	tokens, err := a.requestTokens(provider, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {session.RefreshToken},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing the tokens: %v", err)
+	}
+	if len(tokens.RefreshToken) == 0 {
+		tokens.RefreshToken = session.RefreshToken
+	}
+
+	refreshed := *session
+	if len(tokens.IDToken) > 0 {
+		claims, err := provider.verifier.verify(tokens.IDToken)
+		if err != nil {
+			return nil, fmt.Errorf("invalid refreshed ID token: %v", err)
+		}
+		newSession, err := a.newSession(claims, tokens, time.Unix(session.Created, 0))
+		if err != nil {
+			return nil, err
+		}
+		refreshed = *newSession
+	} else {
+		refreshed.RefreshToken = tokens.RefreshToken
+		refreshed.Expires = a.expires(tokens, nil, now)
+	}
+	return &refreshed, nil
+}
+
+func (a *OIDCAuth) newSession(claims jwtClaims, tokens *oidcTokens, created time.Time) (*oidcSession, error) {
+	subject, _ := claims["sub"].(string)
+	if len(subject) == 0 {
+		return nil, errors.New("no sub claim in the ID token")
+	}
+
+	session := &oidcSession{
+		Subject:      subject,
+		RefreshToken: tokens.RefreshToken,
+		Created:      created.Unix(),
+		Expires:      a.expires(tokens, claims, a.now()),
+	}
+
+	if email, ok := claims["email"].(string); ok && claims["email_verified"] != false {
+		session.Email = email
+	}
+
+	// Only the allowed groups are kept, the session cookie being limited in size.
+	if value, ok := lookupClaim(claims, a.groupsClaim); ok {
+		switch groups := value.(type) {
+		case string:
+			session.Groups = a.allowedGroups([]interface{}{groups})
+		case []interface{}:
+			session.Groups = a.allowedGroups(groups)
+		}
+	}
+	return session, nil
+}
+
+func (a *OIDCAuth) allowedGroups(groups []interface{}) []string {
+	var allowed []string
+	for _, group := range groups {
+		name, ok := group.(string)
+		if !ok {
+			continue
+		}
+		for _, allowedGroup := range a.config.AllowedGroups {
+			if name == allowedGroup {
+				allowed = append(allowed, name)
+				break
+			}
+		}
+	}
+	return allowed
+}
+
+// expires returns the expiration date of the tokens, given by the token response or by the ID token.
+func (a *OIDCAuth) expires(tokens *oidcTokens, claims jwtClaims, now time.Time) int64 {
+	if tokens.ExpiresIn > 0 {
+		return now.Add(time.Duration(tokens.ExpiresIn) * time.Second).Unix()
+	}
+	if exp, ok, err := claims.time("exp"); err == nil && ok {
+		return exp.Unix()
+	}
+	return now.Add(a.sessionLifetime).Unix()
+}
+
+// authorized returns true if the domain of the email address or one of the groups of the user is allowed.
+func (a *OIDCAuth) authorized(session *oidcSession) bool {
+	if len(a.config.AllowedDomains) == 0 && len(a.config.AllowedGroups) == 0 {
+		return true
+	}
+
+	if index := strings.LastIndex(session.Email, "@"); index >= 0 {
+		domain := session.Email[index+1:]
+		for _, allowedDomain := range a.config.AllowedDomains {
+			if strings.EqualFold(domain, allowedDomain) {
+				return true
+			}
+		}
+	}
+
+	for _, group := range session.Groups {
+		for _, allowedGroup := range a.config.AllowedGroups {
+			if group == allowedGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getProvider returns the discovery document of the provider, fetched on the first request.
+// The document is fetched without holding the lock, the concurrent requests waiting for the same fetch,
+// and a failed fetch is retried after a backoff growing up to oidcMaxDiscoveryBackoff.
+func (a *OIDCAuth) getProvider() (*oidcProvider, error) {
+	a.lock.Lock()
+	for a.provider == nil && a.discovering != nil {
+		discovering := a.discovering
+		a.lock.Unlock()
+		<-discovering
+		a.lock.Lock()
+	}
+	if a.provider != nil || a.now().Before(a.nextDiscovery) {
+		defer a.lock.Unlock()
+		if a.provider != nil {
+			return a.provider, nil
+		}
+		return nil, a.discoveryErr
+	}
+	discovering := make(chan struct{})
+	a.discovering = discovering
+	a.lock.Unlock()
+
+	provider, err := a.discover()
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if err != nil {
+		a.discoveryBackoff *= 2
+		if a.discoveryBackoff < oidcMinDiscoveryBackoff {
+			a.discoveryBackoff = oidcMinDiscoveryBackoff
+		} else if a.discoveryBackoff > oidcMaxDiscoveryBackoff {
+			a.discoveryBackoff = oidcMaxDiscoveryBackoff
+		}
+		a.discoveryErr = err
+		a.nextDiscovery = a.now().Add(a.discoveryBackoff)
+	} else {
+		a.provider = provider
+	}
+	a.discovering = nil
+	close(discovering)
+	return provider, err
+}
+
+// discover fetches the discovery document of the provider.
+func (a *OIDCAuth) discover() (*oidcProvider, error) {
+	issuer := strings.TrimSuffix(a.config.Issuer, "/")
+	resp, err := a.client.Get(issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	provider := &oidcProvider{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseSize)).Decode(provider); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(provider.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("the discovery document is issued by %q", provider.Issuer)
+	}
+	if len(provider.AuthorizationEndpoint) == 0 || len(provider.TokenEndpoint) == 0 || len(provider.JWKSURI) == 0 {
+		return nil, errors.New("the discovery document has no authorization endpoint, token endpoint or JWKS URI")
+	}
+
+	provider.verifier, err = NewJWTAuth(&types.JWT{
+		JWKS:      provider.JWKSURI,
+		Issuer:    provider.Issuer,
+		Audiences: []string{a.config.ClientID},
+	}, "")
+	if err != nil {
+		return nil, err
+	}
+	provider.verifier.now = func() time.Time { return a.now() }
+	provider.verifier.jwks.now = provider.verifier.now
+	return provider, nil
+}
+
+// requestTokens calls the token endpoint of the provider, authenticated with the client credentials.
+func (a *OIDCAuth) requestTokens(provider *oidcProvider, form url.Values) (*oidcTokens, error) {
+	req, err := http.NewRequest(http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(a.config.ClientID), url.QueryEscape(a.config.ClientSecret))
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, oidcMaxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from the token endpoint: %s", resp.StatusCode, body)
+	}
+
+	tokens := &oidcTokens{}
+	if err := json.Unmarshal(body, tokens); err != nil {
+		return nil, fmt.Errorf("invalid token response: %v", err)
+	}
+	if len(tokens.IDToken) == 0 && form.Get("grant_type") == "authorization_code" {
+		return nil, errors.New("no ID token in the token response")
+	}
+	return tokens, nil
+}
+
+// redirectURL returns the URL of the callback, on the host of the request.
+func (a *OIDCAuth) redirectURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + a.callbackPath
+}
+
+func (a *OIDCAuth) stateCookieName() string {
+	return a.cookieName + "_state"
+}
+
+func (a *OIDCAuth) setSession(rw http.ResponseWriter, r *http.Request, session *oidcSession) error {
+	lifetime := time.Unix(session.Created, 0).Add(a.sessionLifetime).Sub(a.now())
+	return a.setCookie(rw, r, a.cookieName, session, lifetime)
+}
+
+// setCookie sets a cookie holding the encrypted value, the name of the cookie being authenticated with it.
+func (a *OIDCAuth) setCookie(rw http.ResponseWriter, r *http.Request, name string, value interface{}, lifetime time.Duration) error {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, a.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	encrypted := base64.RawURLEncoding.EncodeToString(a.aead.Seal(nonce, nonce, plaintext, []byte(name)))
+	if len(name)+len(encrypted) > oidcMaxCookieSize {
+		return fmt.Errorf("the cookie %s is larger than %d bytes", name, oidcMaxCookieSize)
+	}
+	http.SetCookie(rw, &http.Cookie{
+		Name:     name,
+		Value:    encrypted,
+		Path:     "/",
+		Domain:   a.config.CookieDomain,
+		Expires:  a.now().Add(lifetime),
+		MaxAge:   int(lifetime.Seconds()),
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		HttpOnly: true,
+	})
+	return nil
+}
+
+// getCookie decrypts the value of a cookie set by setCookie.
+func (a *OIDCAuth) getCookie(r *http.Request, name string, value interface{}) error {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil {
+		return err
+	}
+	if len(encrypted) < a.aead.NonceSize() {
+		return errors.New("cookie is too short")
+	}
+
+	nonceSize := a.aead.NonceSize()
+	plaintext, err := a.aead.Open(nil, encrypted[:nonceSize], encrypted[nonceSize:], []byte(name))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(plaintext, value)
+}
+
+func (a *OIDCAuth) deleteCookie(rw http.ResponseWriter, name string) {
+	http.SetCookie(rw, &http.Cookie{
+		Name:     name,
+		Path:     "/",
+		Domain:   a.config.CookieDomain,
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+}
+
+// user returns the email address of the user, or its subject if it has none.
+func (s *oidcSession) user() string {
+	if len(s.Email) > 0 {
+		return s.Email
+	}
+	return s.Subject
+}
+
+func randomToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
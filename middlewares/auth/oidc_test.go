@@ -0,0 +1,617 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/types"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v1"
+)
+
+func TestOIDCAuth(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc           string
+		config         types.OIDC
+		claims         jwt.MapClaims
+		method         string
+		expectedStatus int
+		expectedUser   string
+	}{
+		{
+			desc:           "without restriction",
+			claims:         jwt.MapClaims{"sub": "1", "email": "jane@example.com"},
+			expectedStatus: http.StatusOK,
+			expectedUser:   "jane@example.com",
+		},
+		{
+			desc:           "without email",
+			claims:         jwt.MapClaims{"sub": "1"},
+			expectedStatus: http.StatusOK,
+			expectedUser:   "1",
+		},
+		{
+			desc:           "allowed domain",
+			config:         types.OIDC{AllowedDomains: []string{"example.org", "Example.COM"}},
+			claims:         jwt.MapClaims{"sub": "1", "email": "jane@example.com", "email_verified": true},
+			expectedStatus: http.StatusOK,
+			expectedUser:   "jane@example.com",
+		},
+		{
+			desc:           "forbidden domain",
+			config:         types.OIDC{AllowedDomains: []string{"example.org"}},
+			claims:         jwt.MapClaims{"sub": "1", "email": "jane@example.com"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "unverified email",
+			config:         types.OIDC{AllowedDomains: []string{"example.com"}},
+			claims:         jwt.MapClaims{"sub": "1", "email": "jane@example.com", "email_verified": false},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "allowed group",
+			config:         types.OIDC{AllowedGroups: []string{"admins"}},
+			claims:         jwt.MapClaims{"sub": "1", "email": "jane@example.com", "groups": []string{"users", "admins"}},
+			expectedStatus: http.StatusOK,
+			expectedUser:   "jane@example.com",
+		},
+		{
+			desc:           "allowed group in a nested claim",
+			config:         types.OIDC{AllowedGroups: []string{"admins"}, GroupsClaim: "realm_access.roles"},
+			claims:         jwt.MapClaims{"sub": "1", "email": "jane@example.com", "realm_access": map[string]interface{}{"roles": []string{"admins"}}},
+			expectedStatus: http.StatusOK,
+			expectedUser:   "jane@example.com",
+		},
+		{
+			desc:           "forbidden group",
+			config:         types.OIDC{AllowedGroups: []string{"admins"}},
+			claims:         jwt.MapClaims{"sub": "1", "email": "jane@example.com", "groups": []string{"users"}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "unsafe method without session",
+			claims:         jwt.MapClaims{"sub": "1", "email": "jane@example.com"},
+			method:         http.MethodPost,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			clock := &testClock{now: time.Unix(1500000000, 0)}
+			idp := newTestIdentityProvider(key, clock, test.claims)
+			defer idp.Close()
+
+			app, _ := newTestOIDCApp(t, idp, clock, test.config)
+			defer app.Close()
+
+			method := test.method
+			if len(method) == 0 {
+				method = http.MethodGet
+			}
+			req, err := http.NewRequest(method, app.URL+"/foo?bar=baz", nil)
+			require.NoError(t, err)
+
+			resp, body := doOIDCRequest(t, newOIDCClient(t, true), req)
+
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			if test.expectedStatus == http.StatusOK {
+				assert.Equal(t, test.expectedUser, body)
+				assert.Equal(t, "/foo?bar=baz", resp.Request.URL.RequestURI(), "the user should be redirected to the requested URL")
+			}
+		})
+	}
+}
+
+func TestOIDCAuthSession(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	clock := &testClock{now: time.Unix(1500000000, 0)}
+	idp := newTestIdentityProvider(key, clock, jwt.MapClaims{"sub": "1", "email": "jane@example.com"})
+	defer idp.Close()
+
+	app, _ := newTestOIDCApp(t, idp, clock, types.OIDC{SessionLifetime: flaeg.Duration(24 * time.Hour)})
+	defer app.Close()
+
+	client := newOIDCClient(t, true)
+	get := func() (*http.Response, string) {
+		req, err := http.NewRequest(http.MethodGet, app.URL+"/foo", nil)
+		require.NoError(t, err)
+		return doOIDCRequest(t, client, req)
+	}
+
+	resp, body := get()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "jane@example.com", body)
+	assert.Equal(t, 1, idp.count("authorize"))
+
+	resp, _ = get()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, idp.count("authorize"), "the session should be reused")
+	assert.Equal(t, 0, idp.count("refresh"))
+
+	clock.add(2 * time.Hour)
+	resp, _ = get()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, idp.count("authorize"))
+	assert.Equal(t, 1, idp.count("refresh"), "the expired tokens should be refreshed")
+
+	resp, _ = get()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, idp.count("refresh"), "the refreshed session should be reused")
+
+	idp.revokeRefreshTokens()
+	clock.add(2 * time.Hour)
+	resp, _ = get()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, idp.count("authorize"), "the user should log in again when the refresh fails")
+
+	clock.add(25 * time.Hour)
+	resp, _ = get()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, idp.count("authorize"), "the user should log in again when the session is expired")
+}
+
+func TestOIDCAuthConcurrentRefresh(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	clock := &testClock{now: time.Unix(1500000000, 0)}
+	idp := newTestIdentityProvider(key, clock, jwt.MapClaims{"sub": "1", "email": "jane@example.com"})
+	defer idp.Close()
+
+	app, _ := newTestOIDCApp(t, idp, clock, types.OIDC{})
+	defer app.Close()
+
+	client := newOIDCClient(t, true)
+	req, err := http.NewRequest(http.MethodGet, app.URL+"/foo", nil)
+	require.NoError(t, err)
+	resp, _ := doOIDCRequest(t, client, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	appURL, err := url.Parse(app.URL)
+	require.NoError(t, err)
+	var session *http.Cookie
+	for _, cookie := range client.Jar.Cookies(appURL) {
+		if cookie.Name == DefaultOIDCCookieName {
+			session = cookie
+		}
+	}
+	require.NotNil(t, session)
+
+	clock.add(2 * time.Hour)
+
+	get := func() int {
+		req, err := http.NewRequest(http.MethodGet, app.URL+"/foo", nil)
+		require.NoError(t, err)
+		req.AddCookie(session)
+		resp, _ := doOIDCRequest(t, newOIDCClient(t, false), req)
+		return resp.StatusCode
+	}
+
+	var wg sync.WaitGroup
+	codes := make([]int, 10)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = get()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+	assert.Equal(t, 1, idp.count("refresh"), "the concurrent requests should share the refresh")
+
+	assert.Equal(t, http.StatusOK, get(), "the previous session cookie should be accepted shortly after the refresh")
+	assert.Equal(t, 1, idp.count("refresh"))
+}
+
+func TestOIDCAuthRejectedRequests(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	clock := &testClock{now: time.Unix(1500000000, 0)}
+	idp := newTestIdentityProvider(key, clock, jwt.MapClaims{"sub": "1", "email": "jane@example.com"})
+	defer idp.Close()
+
+	app, authenticator := newTestOIDCApp(t, idp, clock, types.OIDC{})
+	defer app.Close()
+
+	testCases := []struct {
+		desc           string
+		path           string
+		cookie         *http.Cookie
+		expectedStatus int
+		expectedLogin  bool
+	}{
+		{
+			desc:          "no session",
+			path:          "/foo",
+			expectedLogin: true,
+		},
+		{
+			desc:          "tampered session",
+			path:          "/foo",
+			cookie:        &http.Cookie{Name: DefaultOIDCCookieName, Value: "dGFtcGVyZWQgc2Vzc2lvbiBjb29raWUgdmFsdWU"},
+			expectedLogin: true,
+		},
+		{
+			desc:          "state cookie used as session",
+			path:          "/foo",
+			cookie:        stateCookieAsSession(t, authenticator),
+			expectedLogin: true,
+		},
+		{
+			desc:           "callback without state cookie",
+			path:           DefaultOIDCCallbackPath + "?code=foo&state=bar",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "callback with an error",
+			path:           DefaultOIDCCallbackPath + "?error=access_denied",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, app.URL+test.path, nil)
+			require.NoError(t, err)
+			if test.cookie != nil {
+				req.AddCookie(test.cookie)
+			}
+
+			resp, _ := doOIDCRequest(t, newOIDCClient(t, false), req)
+
+			if test.expectedLogin {
+				assert.Equal(t, http.StatusFound, resp.StatusCode)
+				location, err := url.Parse(resp.Header.Get("Location"))
+				require.NoError(t, err)
+				assert.Equal(t, idp.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+				assert.Equal(t, "test-client", location.Query().Get("client_id"))
+				assert.Equal(t, app.URL+DefaultOIDCCallbackPath, location.Query().Get("redirect_uri"))
+				assert.Equal(t, "openid email profile", location.Query().Get("scope"))
+				assert.NotEmpty(t, location.Query().Get("state"))
+				assert.NotEmpty(t, location.Query().Get("nonce"))
+				return
+			}
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestOIDCAuthDiscoveryError(t *testing.T) {
+	var lock sync.Mutex
+	var discoveries int
+	issuer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		discoveries++
+		http.NotFound(rw, req)
+	}))
+	defer issuer.Close()
+
+	authenticator, err := NewOIDCAuth(&types.OIDC{Issuer: issuer.URL, ClientID: "test-client", SessionSecret: "secret"}, "")
+	require.NoError(t, err)
+	clock := &testClock{now: time.Unix(1500000000, 0)}
+	authenticator.now = clock.Now
+
+	serve := func() int {
+		recorder := httptest.NewRecorder()
+		authenticator.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar/baz", nil), func(rw http.ResponseWriter, req *http.Request) {})
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusBadGateway, serve())
+	assert.Equal(t, http.StatusBadGateway, serve())
+	assert.Equal(t, 1, discoveries, "the failed discovery should not be retried immediately")
+
+	clock.add(oidcMinDiscoveryBackoff)
+	assert.Equal(t, http.StatusBadGateway, serve())
+	assert.Equal(t, 2, discoveries)
+
+	clock.add(oidcMinDiscoveryBackoff)
+	assert.Equal(t, http.StatusBadGateway, serve())
+	assert.Equal(t, 2, discoveries, "the backoff should grow")
+
+	clock.add(oidcMinDiscoveryBackoff)
+	assert.Equal(t, http.StatusBadGateway, serve())
+	assert.Equal(t, 3, discoveries)
+}
+
+func TestOIDCAuthCookieSize(t *testing.T) {
+	authenticator, err := NewOIDCAuth(&types.OIDC{Issuer: "https://auth.example.com", ClientID: "test-client", SessionSecret: "secret"}, "")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://foo.bar", nil)
+
+	recorder := httptest.NewRecorder()
+	err = authenticator.setCookie(recorder, req, DefaultOIDCCookieName, &oidcSession{Subject: strings.Repeat("a", 2000)}, time.Minute)
+	require.NoError(t, err)
+	assert.Len(t, recorder.Result().Cookies(), 1)
+
+	recorder = httptest.NewRecorder()
+	err = authenticator.setCookie(recorder, req, DefaultOIDCCookieName, &oidcSession{Subject: strings.Repeat("a", 4000)}, time.Minute)
+	assert.Error(t, err)
+	assert.Empty(t, recorder.Result().Cookies())
+}
+
+func TestNewOIDCAuth(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        types.OIDC
+		expectedError bool
+	}{
+		{
+			desc:   "valid configuration",
+			config: types.OIDC{Issuer: "https://auth.example.com", ClientID: "client", SessionSecret: "secret"},
+		},
+		{
+			desc:          "no issuer",
+			config:        types.OIDC{ClientID: "client", SessionSecret: "secret"},
+			expectedError: true,
+		},
+		{
+			desc:          "no client ID",
+			config:        types.OIDC{Issuer: "https://auth.example.com", SessionSecret: "secret"},
+			expectedError: true,
+		},
+		{
+			desc:          "no session secret",
+			config:        types.OIDC{Issuer: "https://auth.example.com", ClientID: "client"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewOIDCAuth(&test.config, "")
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// newTestOIDCApp starts a server protected by an OpenID Connect authenticator, answering with the authenticated user.
+func newTestOIDCApp(t *testing.T, idp *testIdentityProvider, clock *testClock, config types.OIDC) (*httptest.Server, *OIDCAuth) {
+	config.Issuer = idp.URL
+	config.ClientID = "test-client"
+	config.ClientSecret = "test-secret"
+	config.SessionSecret = "session-secret"
+
+	authenticator, err := NewOIDCAuth(&config, "X-WebAuth-User")
+	require.NoError(t, err)
+	authenticator.now = clock.Now
+
+	app := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		authenticator.ServeHTTP(rw, req, func(rw http.ResponseWriter, req *http.Request) {
+			fmt.Fprint(rw, strings.Join(req.Header["X-WebAuth-User"], ","))
+		})
+	}))
+	return app, authenticator
+}
+
+func newOIDCClient(t *testing.T, followRedirects bool) *http.Client {
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+
+	client := &http.Client{Jar: jar}
+	if !followRedirects {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	return client
+}
+
+func doOIDCRequest(t *testing.T, client *http.Client, req *http.Request) (*http.Response, string) {
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, strings.TrimSpace(string(body))
+}
+
+// stateCookieAsSession returns a valid state cookie renamed as the session cookie.
+func stateCookieAsSession(t *testing.T, authenticator *OIDCAuth) *http.Cookie {
+	recorder := httptest.NewRecorder()
+	err := authenticator.setCookie(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar", nil), authenticator.stateCookieName(), &oidcState{State: "bar"}, time.Minute)
+	require.NoError(t, err)
+
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+	return &http.Cookie{Name: DefaultOIDCCookieName, Value: cookies[0].Value}
+}
+
+type testClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *testClock) add(duration time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(duration)
+}
+
+// testIdentityProvider is a stand-in OpenID Connect provider, logging in a single user without asking for credentials.
+type testIdentityProvider struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	clock  *testClock
+	claims jwt.MapClaims
+
+	lock          sync.Mutex
+	codes         map[string]string
+	refreshTokens map[string]bool
+	counts        map[string]int
+}
+
+func newTestIdentityProvider(key *rsa.PrivateKey, clock *testClock, claims jwt.MapClaims) *testIdentityProvider {
+	idp := &testIdentityProvider{
+		key:           key,
+		clock:         clock,
+		claims:        claims,
+		codes:         make(map[string]string),
+		refreshTokens: make(map[string]bool),
+		counts:        make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/authorize", idp.authorize)
+	mux.HandleFunc("/token", idp.token)
+	mux.HandleFunc("/jwks", idp.jwks)
+	idp.Server = httptest.NewServer(mux)
+	return idp
+}
+
+func (idp *testIdentityProvider) count(name string) int {
+	idp.lock.Lock()
+	defer idp.lock.Unlock()
+	return idp.counts[name]
+}
+
+func (idp *testIdentityProvider) revokeRefreshTokens() {
+	idp.lock.Lock()
+	defer idp.lock.Unlock()
+	idp.refreshTokens = make(map[string]bool)
+}
+
+func (idp *testIdentityProvider) discovery(rw http.ResponseWriter, req *http.Request) {
+	json.NewEncoder(rw).Encode(map[string]string{
+		"issuer":                 idp.URL,
+		"authorization_endpoint": idp.URL + "/authorize",
+		"token_endpoint":         idp.URL + "/token",
+		"jwks_uri":               idp.URL + "/jwks",
+	})
+}
+
+func (idp *testIdentityProvider) authorize(rw http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	if query.Get("client_id") != "test-client" || query.Get("response_type") != "code" {
+		http.Error(rw, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	idp.lock.Lock()
+	idp.counts["authorize"]++
+	code := fmt.Sprintf("code-%d", idp.counts["authorize"])
+	idp.codes[code] = query.Get("nonce")
+	idp.lock.Unlock()
+
+	redirectURI := query.Get("redirect_uri") + "?" + url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(rw, req, redirectURI, http.StatusFound)
+}
+
+func (idp *testIdentityProvider) token(rw http.ResponseWriter, req *http.Request) {
+	if clientID, clientSecret, _ := req.BasicAuth(); clientID != "test-client" || clientSecret != "test-secret" {
+		http.Error(rw, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+
+	idp.lock.Lock()
+	defer idp.lock.Unlock()
+
+	var nonce string
+	switch req.PostFormValue("grant_type") {
+	case "authorization_code":
+		var ok bool
+		nonce, ok = idp.codes[req.PostFormValue("code")]
+		if !ok {
+			http.Error(rw, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		delete(idp.codes, req.PostFormValue("code"))
+	case "refresh_token":
+		if !idp.refreshTokens[req.PostFormValue("refresh_token")] {
+			http.Error(rw, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		delete(idp.refreshTokens, req.PostFormValue("refresh_token"))
+		idp.counts["refresh"]++
+	default:
+		http.Error(rw, `{"error":"unsupported_grant_type"}`, http.StatusBadRequest)
+		return
+	}
+
+	now := idp.clock.Now()
+	claims := jwt.MapClaims{
+		"iss": idp.URL,
+		"aud": "test-client",
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	for name, value := range idp.claims {
+		claims[name] = value
+	}
+	if len(nonce) > 0 {
+		claims["nonce"] = nonce
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test"
+	idToken, err := token.SignedString(idp.key)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	idp.counts["token"]++
+	refreshToken := fmt.Sprintf("refresh-%d", idp.counts["token"])
+	idp.refreshTokens[refreshToken] = true
+
+	json.NewEncoder(rw).Encode(map[string]interface{}{
+		"access_token":  "access",
+		"token_type":    "Bearer",
+		"id_token":      idToken,
+		"refresh_token": refreshToken,
+		"expires_in":    3600,
+	})
+}
+
+func (idp *testIdentityProvider) jwks(rw http.ResponseWriter, req *http.Request) {
+	json.NewEncoder(rw).Encode(jose.JsonWebKeySet{
+		Keys: []jose.JsonWebKey{{Key: &idp.key.PublicKey, KeyID: "test", Use: "sig"}},
+	})
+}
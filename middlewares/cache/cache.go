@@ -0,0 +1,381 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+	"github.com/go-kit/kit/metrics"
+)
+
+const (
+	// StatusHeader is the response header telling whether the response was served from the cache
+	StatusHeader = "X-Cache-Status"
+	// StatusHit is the cache status of the responses served from the cache
+	StatusHit = "HIT"
+	// StatusMiss is the cache status of the responses forwarded to the backend, and stored when cacheable
+	StatusMiss = "MISS"
+	// StatusBypass is the cache status of the requests which cannot be served from the cache
+	StatusBypass = "BYPASS"
+
+	// DefaultMaxBodySize is the maximum size of a cached response body, when none is configured
+	DefaultMaxBodySize int64 = 1 << 20
+)
+
+// cacheableStatusCodes are the status codes of the responses which can be stored.
+var cacheableStatusCodes = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusNoContent:            true,
+	http.StatusMultipleChoices:      true,
+	http.StatusMovedPermanently:     true,
+	http.StatusNotFound:             true,
+	http.StatusMethodNotAllowed:     true,
+	http.StatusGone:                 true,
+	http.StatusRequestURITooLong:    true,
+	http.StatusNotImplemented:       true,
+	http.StatusPermanentRedirect:    true,
+}
+
+// Cache is a middleware serving the responses of the GET and HEAD requests from a storage,
+// following the Cache-Control, Expires and Vary headers.
+// A single variant of a response is kept per URL.
+type Cache struct {
+	config        types.Cache
+	storage       Storage
+	maxBodySize   int64
+	defaultTTL    time.Duration
+	hitsCounter   metrics.Counter
+	missesCounter metrics.Counter
+	now           func() time.Time
+}
+
+// New creates a cache middleware storing the responses in the storage.
+// The hits and misses counters may be nil.
+func New(config *types.Cache, storage Storage, hitsCounter, missesCounter metrics.Counter) *Cache {
+	maxBodySize := config.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+
+	return &Cache{
+		config:        *config,
+		storage:       storage,
+		maxBodySize:   maxBodySize,
+		defaultTTL:    time.Duration(config.DefaultTTL),
+		hitsCounter:   hitsCounter,
+		missesCounter: missesCounter,
+		now:           time.Now,
+	}
+}
+
+func (c *Cache) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	key := cacheKey(r)
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		if r.Method != http.MethodOptions && r.Method != http.MethodTrace {
+			// the unsafe methods invalidate the cached response of the URL
+			if err := c.storage.Delete(key); err != nil {
+				log.Errorf("Error deleting the cached response of %s: %v", key, err)
+			}
+		}
+		c.bypass(rw, r, next)
+		return
+	}
+
+	directives := parseCacheControl(r.Header)
+	if _, ok := directives["no-store"]; ok || len(r.Header.Get("Authorization")) > 0 || len(r.Header.Get("Range")) > 0 {
+		c.bypass(rw, r, next)
+		return
+	}
+
+	_, noCache := directives["no-cache"]
+	if !noCache && r.Header.Get("Pragma") != "no-cache" && c.serveFromCache(rw, r, key, directives) {
+		c.count(c.hitsCounter)
+		return
+	}
+	c.count(c.missesCounter)
+
+	rw.Header().Set(StatusHeader, StatusMiss)
+
+	recorder := &responseRecorder{rw: rw, header: make(http.Header), maxBodySize: c.maxBodySize}
+	next.ServeHTTP(recorder, r)
+
+	if recorder.hijacked {
+		return
+	}
+	if recorder.statusCode == 0 {
+		recorder.WriteHeader(http.StatusOK)
+	}
+	if r.Method != http.MethodGet {
+		return
+	}
+
+	entry := c.newEntry(r, recorder)
+	if entry == nil {
+		return
+	}
+	if err := c.storage.Set(key, entry); err != nil {
+		log.Errorf("Error storing the response of %s: %v", key, err)
+	}
+}
+
+func (c *Cache) bypass(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	rw.Header().Set(StatusHeader, StatusBypass)
+	next.ServeHTTP(rw, r)
+}
+
+// serveFromCache writes the cached response of the request, and returns false if there is no fresh one.
+func (c *Cache) serveFromCache(rw http.ResponseWriter, r *http.Request, key string, directives map[string]string) bool {
+	entry, err := c.storage.Get(key)
+	if err != nil {
+		log.Errorf("Error reading the cached response of %s: %v", key, err)
+		return false
+	}
+	if entry == nil || !entry.matches(r) {
+		return false
+	}
+
+	now := c.now()
+	if !now.Before(entry.Expires) {
+		return false
+	}
+
+	age := now.Sub(entry.Stored)
+	if maxAge, ok := parseSeconds(directives, "max-age"); ok && age > maxAge {
+		return false
+	}
+
+	header := rw.Header()
+	for name, values := range entry.Header {
+		header[name] = append(header[name], values...)
+	}
+	header.Set("Age", strconv.Itoa(int(age.Seconds())))
+	header.Set(StatusHeader, StatusHit)
+
+	rw.WriteHeader(entry.StatusCode)
+	if r.Method != http.MethodHead {
+		if _, err := rw.Write(entry.Body); err != nil {
+			log.Debugf("Error writing the cached response of %s: %v", key, err)
+		}
+	}
+	return true
+}
+
+// newEntry returns the entry storing the recorded response, or nil if the response cannot be stored.
+func (c *Cache) newEntry(r *http.Request, recorder *responseRecorder) *Entry {
+	header := recorder.header
+	if !cacheableStatusCodes[recorder.statusCode] || recorder.overflow || len(header.Get("Set-Cookie")) > 0 {
+		return nil
+	}
+
+	directives := parseCacheControl(header)
+	for _, directive := range []string{"no-store", "no-cache", "private"} {
+		if _, ok := directives[directive]; ok {
+			return nil
+		}
+	}
+
+	vary := make(map[string]string)
+	for _, name := range header["Vary"] {
+		for _, field := range strings.Split(name, ",") {
+			field = http.CanonicalHeaderKey(strings.TrimSpace(field))
+			if field == "*" {
+				return nil
+			}
+			if len(field) > 0 {
+				vary[field] = strings.Join(r.Header[field], ",")
+			}
+		}
+	}
+
+	now := c.now()
+	stored := now
+	if age, err := strconv.Atoi(header.Get("Age")); err == nil && age > 0 {
+		stored = now.Add(-time.Duration(age) * time.Second)
+	}
+
+	ttl, ok := freshnessLifetime(header, directives, now)
+	if !ok {
+		ttl = c.defaultTTL
+	}
+	if ttl <= 0 || !stored.Add(ttl).After(now) {
+		return nil
+	}
+
+	return &Entry{
+		StatusCode: recorder.statusCode,
+		Header:     cloneHeader(header),
+		Body:       recorder.body.Bytes(),
+		Vary:       vary,
+		Stored:     stored,
+		Expires:    stored.Add(ttl),
+	}
+}
+
+func (c *Cache) count(counter metrics.Counter) {
+	if counter != nil {
+		counter.Add(1)
+	}
+}
+
+// matches returns true if the request has the same values as the stored one for the headers listed in the Vary header.
+func (e *Entry) matches(r *http.Request) bool {
+	for name, value := range e.Vary {
+		if strings.Join(r.Header[name], ",") != value {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheKey returns the URL of the request.
+func cacheKey(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
+
+// freshnessLifetime returns the lifetime of the response given by its s-maxage or max-age directives, or its Expires header.
+// It returns false if the response has no freshness information.
+func freshnessLifetime(header http.Header, directives map[string]string, now time.Time) (time.Duration, bool) {
+	if maxAge, ok := parseSeconds(directives, "s-maxage"); ok {
+		return maxAge, true
+	}
+	if maxAge, ok := parseSeconds(directives, "max-age"); ok {
+		return maxAge, true
+	}
+
+	rawExpires, ok := header["Expires"]
+	if !ok {
+		return 0, false
+	}
+	expires, err := http.ParseTime(strings.Join(rawExpires, ""))
+	if err != nil {
+		// an invalid Expires header means that the response is already expired
+		return 0, true
+	}
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		date = now
+	}
+	return expires.Sub(date), true
+}
+
+// parseCacheControl returns the directives of the Cache-Control header, with their value if any.
+func parseCacheControl(header http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range header["Cache-Control"] {
+		for _, directive := range strings.Split(value, ",") {
+			parts := strings.SplitN(strings.TrimSpace(directive), "=", 2)
+			name := strings.ToLower(strings.TrimSpace(parts[0]))
+			if len(name) == 0 {
+				continue
+			}
+			if len(parts) == 2 {
+				directives[name] = strings.Trim(strings.TrimSpace(parts[1]), `"`)
+			} else {
+				directives[name] = ""
+			}
+		}
+	}
+	return directives
+}
+
+func parseSeconds(directives map[string]string, name string) (time.Duration, bool) {
+	value, ok := directives[name]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, true
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+func cloneHeader(header http.Header) http.Header {
+	clone := make(http.Header, len(header))
+	for name, values := range header {
+		clone[name] = append([]string(nil), values...)
+	}
+	return clone
+}
+
+// responseRecorder forwards the response while keeping a copy of its headers and of its body, up to maxBodySize bytes.
+type responseRecorder struct {
+	rw          http.ResponseWriter
+	header      http.Header
+	statusCode  int
+	body        bytes.Buffer
+	maxBodySize int64
+	overflow    bool
+	hijacked    bool
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.statusCode != 0 {
+		return
+	}
+	r.statusCode = code
+
+	header := r.rw.Header()
+	for name, values := range r.header {
+		header[name] = append(header[name], values...)
+	}
+	r.rw.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.statusCode == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if !r.overflow {
+		if int64(r.body.Len()+len(b)) > r.maxBodySize {
+			r.overflow = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(b)
+		}
+	}
+	return r.rw.Write(b)
+}
+
+func (r *responseRecorder) Flush() {
+	if r.statusCode == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.rw)
+	}
+	r.hijacked = true
+	return hijacker.Hijack()
+}
+
+func (r *responseRecorder) CloseNotify() <-chan bool {
+	if notifier, ok := r.rw.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+	return make(<-chan bool)
+}
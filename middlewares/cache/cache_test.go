@@ -0,0 +1,264 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		config           types.Cache
+		responseHeaders  map[string]string
+		statusCode       int
+		firstRequest     func(req *http.Request)
+		secondRequest    func(req *http.Request)
+		elapsed          time.Duration
+		expectedStatuses []string
+		expectedCalls    int
+	}{
+		{
+			desc:             "fresh response with max-age",
+			responseHeaders:  map[string]string{"Cache-Control": "public, max-age=60"},
+			expectedStatuses: []string{StatusMiss, StatusHit},
+			expectedCalls:    1,
+		},
+		{
+			desc:             "s-maxage overrides max-age",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60, s-maxage=0"},
+			expectedStatuses: []string{StatusMiss, StatusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "stale response",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			elapsed:          time.Minute,
+			expectedStatuses: []string{StatusMiss, StatusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc: "fresh response with Expires",
+			responseHeaders: map[string]string{
+				"Date":    "Mon, 02 Jan 2006 15:04:05 GMT",
+				"Expires": "Mon, 02 Jan 2006 15:05:05 GMT",
+			},
+			expectedStatuses: []string{StatusMiss, StatusHit},
+			expectedCalls:    1,
+		},
+		{
+			desc:             "invalid Expires",
+			responseHeaders:  map[string]string{"Expires": "0"},
+			config:           types.Cache{DefaultTTL: flaeg.Duration(time.Minute)},
+			expectedStatuses: []string{StatusMiss, StatusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "no freshness information",
+			expectedStatuses: []string{StatusMiss, StatusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "no freshness information with a default TTL",
+			config:           types.Cache{DefaultTTL: flaeg.Duration(time.Minute)},
+			expectedStatuses: []string{StatusMiss, StatusHit},
+			expectedCalls:    1,
+		},
+		{
+			desc:             "private response",
+			responseHeaders:  map[string]string{"Cache-Control": "private, max-age=60"},
+			expectedStatuses: []string{StatusMiss, StatusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "no-store response",
+			responseHeaders:  map[string]string{"Cache-Control": "no-store"},
+			config:           types.Cache{DefaultTTL: flaeg.Duration(time.Minute)},
+			expectedStatuses: []string{StatusMiss, StatusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc: "response with a cookie",
+			responseHeaders: map[string]string{
+				"Cache-Control": "max-age=60",
+				"Set-Cookie":    "session=foo",
+			},
+			expectedStatuses: []string{StatusMiss, StatusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "response status not cacheable",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			statusCode:       http.StatusInternalServerError,
+			expectedStatuses: []string{StatusMiss, StatusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "response body too large",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			config:           types.Cache{MaxBodySize: 2},
+			expectedStatuses: []string{StatusMiss, StatusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "same Vary header",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60", "Vary": "Accept-Language"},
+			firstRequest:     func(req *http.Request) { req.Header.Set("Accept-Language", "fr") },
+			secondRequest:    func(req *http.Request) { req.Header.Set("Accept-Language", "fr") },
+			expectedStatuses: []string{StatusMiss, StatusHit},
+			expectedCalls:    1,
+		},
+		{
+			desc:             "different Vary header",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60", "Vary": "Accept-Language"},
+			firstRequest:     func(req *http.Request) { req.Header.Set("Accept-Language", "fr") },
+			secondRequest:    func(req *http.Request) { req.Header.Set("Accept-Language", "en") },
+			expectedStatuses: []string{StatusMiss, StatusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "Vary on all the headers",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60", "Vary": "*"},
+			expectedStatuses: []string{StatusMiss, StatusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "request with no-cache",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			secondRequest:    func(req *http.Request) { req.Header.Set("Cache-Control", "no-cache") },
+			expectedStatuses: []string{StatusMiss, StatusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "request with a max-age lower than the age",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			secondRequest:    func(req *http.Request) { req.Header.Set("Cache-Control", "max-age=10") },
+			elapsed:          30 * time.Second,
+			expectedStatuses: []string{StatusMiss, StatusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "request with an authorization",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			secondRequest:    func(req *http.Request) { req.Header.Set("Authorization", "Basic Zm9vOmJhcg==") },
+			expectedStatuses: []string{StatusMiss, StatusBypass},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "HEAD request served from the cache",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			secondRequest:    func(req *http.Request) { req.Method = http.MethodHead },
+			expectedStatuses: []string{StatusMiss, StatusHit},
+			expectedCalls:    1,
+		},
+		{
+			desc:             "HEAD response not stored",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			firstRequest:     func(req *http.Request) { req.Method = http.MethodHead },
+			expectedStatuses: []string{StatusMiss, StatusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "unsafe method",
+			responseHeaders:  map[string]string{"Cache-Control": "max-age=60"},
+			firstRequest:     func(req *http.Request) { req.Method = http.MethodPost },
+			expectedStatuses: []string{StatusBypass, StatusMiss},
+			expectedCalls:    2,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int
+			next := func(rw http.ResponseWriter, req *http.Request) {
+				calls++
+				for name, value := range test.responseHeaders {
+					rw.Header().Set(name, value)
+				}
+				statusCode := test.statusCode
+				if statusCode == 0 {
+					statusCode = http.StatusOK
+				}
+				rw.WriteHeader(statusCode)
+				if req.Method != http.MethodHead {
+					rw.Write([]byte("hello " + strconv.Itoa(calls)))
+				}
+			}
+
+			now := time.Unix(1000, 0)
+			cache := New(&test.config, NewMemoryStorage(10), nil, nil)
+			cache.now = func() time.Time { return now }
+
+			var statuses []string
+			for i, customize := range []func(req *http.Request){test.firstRequest, test.secondRequest} {
+				req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/baz", nil)
+				if customize != nil {
+					customize(req)
+				}
+				if i == 1 {
+					now = now.Add(test.elapsed)
+				}
+
+				recorder := httptest.NewRecorder()
+				cache.ServeHTTP(recorder, req, next)
+
+				statuses = append(statuses, recorder.Header().Get(StatusHeader))
+				if recorder.Header().Get(StatusHeader) == StatusHit {
+					assert.Equal(t, strconv.Itoa(int(test.elapsed.Seconds())), recorder.Header().Get("Age"))
+					if req.Method == http.MethodGet {
+						assert.Equal(t, "hello 1", recorder.Body.String())
+					}
+				}
+			}
+
+			assert.Equal(t, test.expectedStatuses, statuses)
+			assert.Equal(t, test.expectedCalls, calls)
+		})
+	}
+}
+
+func TestCacheMetrics(t *testing.T) {
+	next := func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Write([]byte("hello"))
+	}
+
+	hits := &testhelpers.CollectingCounter{}
+	misses := &testhelpers.CollectingCounter{}
+	cache := New(&types.Cache{}, NewMemoryStorage(10), hits, misses)
+
+	for i := 0; i < 3; i++ {
+		cache.ServeHTTP(httptest.NewRecorder(), testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/baz", nil), next)
+	}
+	cache.ServeHTTP(httptest.NewRecorder(), testhelpers.MustNewRequest(http.MethodPost, "http://foo.bar/baz", nil), next)
+
+	assert.Equal(t, float64(2), hits.CounterValue)
+	assert.Equal(t, float64(1), misses.CounterValue)
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	next := func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Write([]byte("hello"))
+	}
+
+	storage := NewMemoryStorage(10)
+	cache := New(&types.Cache{}, storage, nil, nil)
+
+	cache.ServeHTTP(httptest.NewRecorder(), testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/baz", nil), next)
+	cache.ServeHTTP(httptest.NewRecorder(), testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/qux", nil), next)
+	assert.Len(t, storage.Keys(), 2)
+
+	cache.ServeHTTP(httptest.NewRecorder(), testhelpers.MustNewRequest(http.MethodDelete, "http://foo.bar/baz", nil), next)
+	assert.Equal(t, []string{"http://foo.bar/qux"}, storage.Keys())
+}
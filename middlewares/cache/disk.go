@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/containous/traefik/log"
+)
+
+const (
+	diskEntryExtension = ".cache"
+	diskTempPrefix     = "tmp-"
+)
+
+// DiskStorage is a Storage keeping the cached responses in files, named after the hash of their key.
+// The index of the entries is kept in memory: the storage only removes the files it created,
+// when they are evicted or deleted, and when the storage is closed.
+type DiskStorage struct {
+	path string
+	// prefix is unique to the storage, so that two storages sharing the directory never use the same files.
+	prefix  string
+	lock    sync.Mutex
+	entries *lru
+	closed  bool
+}
+
+// NewDiskStorage creates a disk storage holding up to maxEntries responses in the directory.
+// The files already in the directory are left untouched.
+func NewDiskStorage(path string, maxEntries int) (*DiskStorage, error) {
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, fmt.Errorf("error creating the cache directory %s: %v", path, err)
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("error generating the cache files prefix: %v", err)
+	}
+
+	return &DiskStorage{path: path, prefix: hex.EncodeToString(id), entries: newLRU(maxEntries)}, nil
+}
+
+// Get returns the entry stored under the key, or nil if there is none.
+func (d *DiskStorage) Get(key string) (*Entry, error) {
+	d.lock.Lock()
+	_, ok := d.entries.get(key)
+	d.lock.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	file, err := os.Open(d.filename(key))
+	if err != nil {
+		d.forget(key)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	entry := &Entry{}
+	if err := gob.NewDecoder(file).Decode(entry); err != nil {
+		d.forget(key)
+		return nil, fmt.Errorf("error decoding the cache file %s: %v", file.Name(), err)
+	}
+	return entry, nil
+}
+
+// Set stores the entry under the key.
+func (d *DiskStorage) Set(key string, entry *Entry) error {
+	file, err := ioutil.TempFile(d.path, diskTempPrefix)
+	if err != nil {
+		return err
+	}
+
+	err = gob.NewEncoder(file).Encode(entry)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("error writing the cache file of %s: %v", key, err)
+	}
+
+	// the files are renamed and removed under the lock, so that they always match the index
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.closed {
+		os.Remove(file.Name())
+		return nil
+	}
+
+	if err := os.Rename(file.Name(), d.filename(key)); err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("error writing the cache file of %s: %v", key, err)
+	}
+
+	for _, item := range d.entries.add(key, nil) {
+		d.removeFile(item.key)
+	}
+	return nil
+}
+
+// Delete removes the entry stored under the key, if any.
+func (d *DiskStorage) Delete(key string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if _, ok := d.entries.remove(key); !ok {
+		return nil
+	}
+
+	err := os.Remove(d.filename(key))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Keys returns the keys of the stored entries.
+func (d *DiskStorage) Keys() []string {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.entries.keys()
+}
+
+// Close removes the files of the stored entries. The entries set afterwards are dropped.
+func (d *DiskStorage) Close() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for _, key := range d.entries.keys() {
+		d.removeFile(key)
+	}
+	d.entries = newLRU(d.entries.maxEntries)
+	d.closed = true
+	return nil
+}
+
+// forget removes the key from the index, and returns true if it was present.
+func (d *DiskStorage) forget(key string) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	_, ok := d.entries.remove(key)
+	return ok
+}
+
+func (d *DiskStorage) removeFile(key string) {
+	if err := os.Remove(d.filename(key)); err != nil && !os.IsNotExist(err) {
+		log.Warnf("Error removing the cache file of %s: %v", key, err)
+	}
+}
+
+func (d *DiskStorage) filename(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(d.path, d.prefix+"-"+hex.EncodeToString(hash[:])+diskEntryExtension)
+}
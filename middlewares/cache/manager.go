@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"sync"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+	"github.com/go-kit/kit/metrics"
+)
+
+// ErrUnknownCache is returned when purging the cache of a frontend without cache.
+var ErrUnknownCache = errors.New("unknown cache")
+
+// Manager holds the caches of the frontends, so that the cached responses survive the configuration reloads and can be purged.
+type Manager struct {
+	lock   sync.RWMutex
+	caches map[string]*Cache
+	// validating is set when the caches are only created to validate a configuration.
+	validating bool
+}
+
+// NewManager creates a manager without caches.
+func NewManager() *Manager {
+	return &Manager{caches: make(map[string]*Cache)}
+}
+
+// NewValidationManager creates a manager checking the storages of the caches instead of creating them,
+// the caches being stored in memory so that validating a configuration leaves the disk untouched.
+func NewValidationManager() *Manager {
+	return &Manager{caches: make(map[string]*Cache), validating: true}
+}
+
+// New creates the cache middleware of a frontend.
+// The storage of the current cache of the frontend is reused when its configuration is unchanged.
+func (m *Manager) New(frontendName string, config *types.Cache, hitsCounter, missesCounter metrics.Counter) (*Cache, error) {
+	m.lock.RLock()
+	current, ok := m.caches[frontendName]
+	m.lock.RUnlock()
+
+	if ok && reflect.DeepEqual(current.config, *config) {
+		return New(config, current.storage, hitsCounter, missesCounter), nil
+	}
+
+	if m.validating {
+		if err := CheckStorage(config); err != nil {
+			return nil, err
+		}
+		return New(config, NewMemoryStorage(1), hitsCounter, missesCounter), nil
+	}
+
+	storage, err := NewStorage(frontendName, config)
+	if err != nil {
+		return nil, err
+	}
+	return New(config, storage, hitsCounter, missesCounter), nil
+}
+
+// SetCaches replaces the caches of the frontends, by frontend name.
+// The storages of the replaced caches are closed, unless the new caches reuse them.
+func (m *Manager) SetCaches(caches map[string]*Cache) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	used := make(map[Storage]bool)
+	for _, cache := range caches {
+		used[cache.storage] = true
+	}
+	for frontendName, cache := range m.caches {
+		if !used[cache.storage] {
+			closeStorage(frontendName, cache.storage)
+		}
+	}
+	m.caches = caches
+}
+
+// Close closes the storages of the caches.
+func (m *Manager) Close() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for frontendName, cache := range m.caches {
+		closeStorage(frontendName, cache.storage)
+	}
+	m.caches = make(map[string]*Cache)
+}
+
+func closeStorage(frontendName string, storage Storage) {
+	if err := storage.Close(); err != nil {
+		log.Errorf("Error closing the cache storage of frontend %s: %v", frontendName, err)
+	}
+}
+
+// Purge removes the cached responses of the frontend, or of all the frontends if the frontend name is empty.
+// When a path is given, only the responses of the URLs with this path are removed.
+// It returns the number of removed responses.
+func (m *Manager) Purge(frontendName string, path string) (int, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var caches []*Cache
+	if len(frontendName) == 0 {
+		for _, cache := range m.caches {
+			caches = append(caches, cache)
+		}
+	} else {
+		cache, ok := m.caches[frontendName]
+		if !ok {
+			return 0, ErrUnknownCache
+		}
+		caches = append(caches, cache)
+	}
+
+	var purged int
+	for _, cache := range caches {
+		for _, key := range cache.storage.Keys() {
+			if len(path) > 0 {
+				keyURL, err := url.Parse(key)
+				if err != nil || keyURL.Path != path {
+					continue
+				}
+			}
+			if err := cache.storage.Delete(key); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerNew(t *testing.T) {
+	manager := NewManager()
+
+	config := &types.Cache{MaxEntries: 10}
+	first, err := manager.New("frontend1", config, nil, nil)
+	require.NoError(t, err)
+	manager.SetCaches(map[string]*Cache{"frontend1": first})
+
+	reused, err := manager.New("frontend1", &types.Cache{MaxEntries: 10}, nil, nil)
+	require.NoError(t, err)
+	assert.True(t, first.storage == reused.storage, "the storage should be reused when the configuration is unchanged")
+
+	changed, err := manager.New("frontend1", &types.Cache{MaxEntries: 20}, nil, nil)
+	require.NoError(t, err)
+	assert.False(t, first.storage == changed.storage, "the storage should be recreated when the configuration changes")
+
+	_, err = manager.New("frontend2", &types.Cache{Storage: "unknown"}, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestManagerDiskStorages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	manager := NewManager()
+
+	config := &types.Cache{Storage: StorageDisk, Path: dir}
+	frontend1, err := manager.New("frontend1", config, nil, nil)
+	require.NoError(t, err)
+	frontend2, err := manager.New("frontend2", config, nil, nil)
+	require.NoError(t, err)
+	manager.SetCaches(map[string]*Cache{"frontend1": frontend1, "frontend2": frontend2})
+
+	require.NoError(t, frontend1.storage.Set("http://foo/a", &Entry{StatusCode: http.StatusOK}))
+	require.NoError(t, frontend2.storage.Set("http://foo/a", &Entry{StatusCode: http.StatusOK}))
+
+	file1 := frontend1.storage.(*DiskStorage).filename("http://foo/a")
+	file2 := frontend2.storage.(*DiskStorage).filename("http://foo/a")
+	assert.NotEqual(t, filepath.Dir(file1), filepath.Dir(file2), "each frontend should have its own directory")
+
+	// the storage of a configuration which is not applied leaves the files untouched
+	_, err = manager.New("frontend1", &types.Cache{Storage: StorageDisk, Path: dir, MaxEntries: 10}, nil, nil)
+	require.NoError(t, err)
+	_, err = os.Stat(file1)
+	assert.NoError(t, err)
+
+	changed, err := manager.New("frontend1", &types.Cache{Storage: StorageDisk, Path: dir, MaxEntries: 10}, nil, nil)
+	require.NoError(t, err)
+	manager.SetCaches(map[string]*Cache{"frontend1": changed, "frontend2": frontend2})
+
+	_, err = os.Stat(file1)
+	assert.True(t, os.IsNotExist(err), "the files of the replaced storage should be removed")
+	_, err = os.Stat(file2)
+	assert.NoError(t, err, "the files of the reused storage should be kept")
+
+	manager.Close()
+	_, err = os.Stat(file2)
+	assert.True(t, os.IsNotExist(err), "the files should be removed when the manager is closed")
+}
+
+func TestManagerPurge(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		frontend      string
+		path          string
+		expectedKeys  map[string][]string
+		expectedCount int
+		expectedError error
+	}{
+		{
+			desc:          "all the frontends",
+			expectedKeys:  map[string][]string{"frontend1": {}, "frontend2": {}},
+			expectedCount: 3,
+		},
+		{
+			desc:          "one frontend",
+			frontend:      "frontend1",
+			expectedKeys:  map[string][]string{"frontend1": {}, "frontend2": {"http://bar/foo"}},
+			expectedCount: 2,
+		},
+		{
+			desc:          "one path",
+			frontend:      "frontend1",
+			path:          "/foo",
+			expectedKeys:  map[string][]string{"frontend1": {"http://foo/bar?baz=1"}, "frontend2": {"http://bar/foo"}},
+			expectedCount: 1,
+		},
+		{
+			desc:          "one path in all the frontends",
+			path:          "/foo",
+			expectedKeys:  map[string][]string{"frontend1": {"http://foo/bar?baz=1"}, "frontend2": {}},
+			expectedCount: 2,
+		},
+		{
+			desc:          "unknown frontend",
+			frontend:      "frontend3",
+			expectedKeys:  map[string][]string{"frontend1": {"http://foo/bar?baz=1", "http://foo/foo"}, "frontend2": {"http://bar/foo"}},
+			expectedError: ErrUnknownCache,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			storages := map[string]Storage{
+				"frontend1": NewMemoryStorage(10),
+				"frontend2": NewMemoryStorage(10),
+			}
+			require.NoError(t, storages["frontend1"].Set("http://foo/foo", &Entry{StatusCode: http.StatusOK}))
+			require.NoError(t, storages["frontend1"].Set("http://foo/bar?baz=1", &Entry{StatusCode: http.StatusOK}))
+			require.NoError(t, storages["frontend2"].Set("http://bar/foo", &Entry{StatusCode: http.StatusOK}))
+
+			manager := NewManager()
+			manager.SetCaches(map[string]*Cache{
+				"frontend1": New(&types.Cache{}, storages["frontend1"], nil, nil),
+				"frontend2": New(&types.Cache{}, storages["frontend2"], nil, nil),
+			})
+
+			count, err := manager.Purge(test.frontend, test.path)
+			assert.Equal(t, test.expectedError, err)
+			assert.Equal(t, test.expectedCount, count)
+
+			for frontend, keys := range test.expectedKeys {
+				assert.Equal(t, keys, storages[frontend].Keys(), frontend)
+			}
+		})
+	}
+}
+
+func TestValidationManagerNew(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	stale := filepath.Join(dir, "stale.cache")
+	require.NoError(t, ioutil.WriteFile(stale, []byte("stale"), 0600))
+
+	manager := NewValidationManager()
+
+	_, err = manager.New("frontend1", &types.Cache{Storage: StorageDisk, Path: dir}, nil, nil)
+	require.NoError(t, err)
+	_, err = os.Stat(stale)
+	assert.NoError(t, err, "the disk storage should not be created")
+
+	_, err = manager.New("frontend2", &types.Cache{Storage: StorageDisk}, nil, nil)
+	assert.Error(t, err)
+
+	_, err = manager.New("frontend3", &types.Cache{Storage: "unknown"}, nil, nil)
+	assert.Error(t, err)
+}
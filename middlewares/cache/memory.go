@@ -0,0 +1,60 @@
+package cache
+
+import "sync"
+
+// MemoryStorage is a Storage keeping the cached responses in memory.
+type MemoryStorage struct {
+	lock    sync.Mutex
+	entries *lru
+}
+
+// NewMemoryStorage creates a memory storage holding up to maxEntries responses.
+func NewMemoryStorage(maxEntries int) *MemoryStorage {
+	return &MemoryStorage{entries: newLRU(maxEntries)}
+}
+
+// Get returns the entry stored under the key, or nil if there is none.
+func (m *MemoryStorage) Get(key string) (*Entry, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if value, ok := m.entries.get(key); ok {
+		return value.(*Entry), nil
+	}
+	return nil, nil
+}
+
+// Set stores the entry under the key.
+func (m *MemoryStorage) Set(key string, entry *Entry) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.entries.add(key, entry)
+	return nil
+}
+
+// Delete removes the entry stored under the key, if any.
+func (m *MemoryStorage) Delete(key string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.entries.remove(key)
+	return nil
+}
+
+// Keys returns the keys of the stored entries.
+func (m *MemoryStorage) Keys() []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.entries.keys()
+}
+
+// Close drops the stored entries.
+func (m *MemoryStorage) Close() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.entries = newLRU(m.entries.maxEntries)
+	return nil
+}
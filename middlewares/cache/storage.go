@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/containous/traefik/types"
+)
+
+const (
+	// StorageMemory keeps the cached responses in memory
+	StorageMemory = "memory"
+	// StorageDisk keeps the cached responses in files
+	StorageDisk = "disk"
+
+	// DefaultMaxEntries is the maximum number of cached responses, when none is configured
+	DefaultMaxEntries = 1000
+)
+
+// Entry is a cached response.
+type Entry struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Vary holds the values in the request of the headers listed in the Vary header of the response.
+	Vary    map[string]string
+	Stored  time.Time
+	Expires time.Time
+}
+
+// Storage stores the cached responses by key, evicting the least recently used ones when full.
+type Storage interface {
+	// Get returns the entry stored under the key, or nil if there is none.
+	Get(key string) (*Entry, error)
+	// Set stores the entry under the key.
+	Set(key string, entry *Entry) error
+	// Delete removes the entry stored under the key, if any.
+	Delete(key string) error
+	// Keys returns the keys of the stored entries.
+	Keys() []string
+	// Close releases the stored entries, once the storage is no longer used.
+	Close() error
+}
+
+// NewStorage creates the storage of the frontend described by the configuration.
+// The disk storage of each frontend is kept in its own subdirectory of the configured path.
+func NewStorage(frontendName string, config *types.Cache) (Storage, error) {
+	maxEntries := config.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+
+	if err := CheckStorage(config); err != nil {
+		return nil, err
+	}
+
+	if config.Storage == StorageDisk {
+		hash := sha256.Sum256([]byte(frontendName))
+		return NewDiskStorage(filepath.Join(config.Path, hex.EncodeToString(hash[:])), maxEntries)
+	}
+	return NewMemoryStorage(maxEntries), nil
+}
+
+// CheckStorage checks the storage described by the configuration, without creating it.
+func CheckStorage(config *types.Cache) error {
+	switch config.Storage {
+	case "", StorageMemory:
+		return nil
+	case StorageDisk:
+		if len(config.Path) == 0 {
+			return errors.New("the disk storage requires a path")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported storage %q, must be %s or %s", config.Storage, StorageMemory, StorageDisk)
+	}
+}
+
+// lru is a least recently used index of keys, holding up to maxEntries values.
+type lru struct {
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	value interface{}
+}
+
+func newLRU(maxEntries int) *lru {
+	return &lru{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (l *lru) get(key string) (interface{}, bool) {
+	element, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	l.ll.MoveToFront(element)
+	return element.Value.(*lruItem).value, true
+}
+
+// add adds or replaces the value of the key, and returns the evicted items.
+func (l *lru) add(key string, value interface{}) []*lruItem {
+	if element, ok := l.items[key]; ok {
+		l.ll.MoveToFront(element)
+		element.Value.(*lruItem).value = value
+		return nil
+	}
+
+	l.items[key] = l.ll.PushFront(&lruItem{key: key, value: value})
+
+	var evicted []*lruItem
+	for l.ll.Len() > l.maxEntries {
+		oldest := l.ll.Back()
+		item := l.ll.Remove(oldest).(*lruItem)
+		delete(l.items, item.key)
+		evicted = append(evicted, item)
+	}
+	return evicted
+}
+
+func (l *lru) remove(key string) (interface{}, bool) {
+	element, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	l.ll.Remove(element)
+	delete(l.items, key)
+	return element.Value.(*lruItem).value, true
+}
+
+func (l *lru) keys() []string {
+	keys := make([]string, 0, l.ll.Len())
+	for element := l.ll.Front(); element != nil; element = element.Next() {
+		keys = append(keys, element.Value.(*lruItem).key)
+	}
+	return keys
+}
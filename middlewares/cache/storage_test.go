@@ -0,0 +1,222 @@
+package cache
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		storage string
+	}{
+		{
+			desc:    "memory",
+			storage: StorageMemory,
+		},
+		{
+			desc:    "disk",
+			storage: StorageDisk,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dir, err := ioutil.TempDir("", "traefik-cache")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			storage, err := NewStorage("frontend", &types.Cache{Storage: test.storage, Path: dir, MaxEntries: 2})
+			require.NoError(t, err)
+
+			entry := func(body string) *Entry {
+				return &Entry{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": {"text/plain"}},
+					Body:       []byte(body),
+					Stored:     time.Unix(1000, 0).UTC(),
+					Expires:    time.Unix(1060, 0).UTC(),
+				}
+			}
+
+			actual, err := storage.Get("http://foo/a")
+			require.NoError(t, err)
+			assert.Nil(t, actual)
+
+			require.NoError(t, storage.Set("http://foo/a", entry("a")))
+			require.NoError(t, storage.Set("http://foo/b", entry("b")))
+
+			actual, err = storage.Get("http://foo/a")
+			require.NoError(t, err)
+			assert.Equal(t, entry("a"), actual)
+
+			// b is the least recently used entry
+			require.NoError(t, storage.Set("http://foo/c", entry("c")))
+			assert.Equal(t, []string{"http://foo/c", "http://foo/a"}, storage.Keys())
+
+			actual, err = storage.Get("http://foo/b")
+			require.NoError(t, err)
+			assert.Nil(t, actual)
+
+			require.NoError(t, storage.Set("http://foo/a", entry("new a")))
+			actual, err = storage.Get("http://foo/a")
+			require.NoError(t, err)
+			assert.Equal(t, entry("new a"), actual)
+
+			require.NoError(t, storage.Delete("http://foo/a"))
+			require.NoError(t, storage.Delete("http://foo/unknown"))
+			assert.Equal(t, []string{"http://foo/c"}, storage.Keys())
+		})
+	}
+}
+
+func TestDiskStorageFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traefik-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	other := filepath.Join(dir, "other"+diskEntryExtension)
+	require.NoError(t, ioutil.WriteFile(other, []byte("other"), 0600))
+
+	storage, err := NewDiskStorage(dir, 1)
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{other}, files, "the files of the directory should be left untouched")
+
+	require.NoError(t, storage.Set("http://foo/a", &Entry{StatusCode: http.StatusOK}))
+	require.NoError(t, storage.Set("http://foo/b", &Entry{StatusCode: http.StatusOK}))
+
+	files, err = filepath.Glob(filepath.Join(dir, "*"+diskEntryExtension))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{other, storage.filename("http://foo/b")}, files, "the evicted entries should be removed")
+
+	require.NoError(t, ioutil.WriteFile(storage.filename("http://foo/b"), []byte("corrupted"), 0600))
+	_, err = storage.Get("http://foo/b")
+	assert.Error(t, err)
+	assert.Empty(t, storage.Keys())
+}
+
+func TestDiskStorageSharedDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traefik-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	first, err := NewDiskStorage(dir, 10)
+	require.NoError(t, err)
+	require.NoError(t, first.Set("http://foo/a", &Entry{StatusCode: http.StatusOK}))
+
+	second, err := NewDiskStorage(dir, 10)
+	require.NoError(t, err)
+	require.NoError(t, second.Set("http://foo/a", &Entry{StatusCode: http.StatusNotFound}))
+	assert.NotEqual(t, first.filename("http://foo/a"), second.filename("http://foo/a"))
+
+	actual, err := first.Get("http://foo/a")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, actual.StatusCode)
+
+	require.NoError(t, first.Close())
+	assert.Empty(t, first.Keys())
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{second.filename("http://foo/a")}, files, "only the files of the closed storage should be removed")
+
+	require.NoError(t, first.Set("http://foo/b", &Entry{StatusCode: http.StatusOK}))
+	assert.Empty(t, first.Keys(), "a closed storage should drop the entries")
+
+	actual, err = second.Get("http://foo/a")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, actual.StatusCode)
+}
+
+func TestDiskStorageConcurrentWrites(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traefik-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	storage, err := NewDiskStorage(dir, 2)
+	require.NoError(t, err)
+
+	keys := []string{"http://foo/a", "http://foo/b", "http://foo/c"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				key := keys[(i+j)%len(keys)]
+				if i%3 == 0 {
+					assert.NoError(t, storage.Delete(key))
+				} else {
+					assert.NoError(t, storage.Set(key, &Entry{StatusCode: http.StatusOK}))
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var expected []string
+	for _, key := range storage.Keys() {
+		expected = append(expected, storage.filename(key))
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*"+diskEntryExtension))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, expected, files, "the files should match the index")
+}
+
+func TestNewStorage(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        *types.Cache
+		expectedError bool
+	}{
+		{
+			desc:   "default storage",
+			config: &types.Cache{},
+		},
+		{
+			desc:   "memory storage",
+			config: &types.Cache{Storage: StorageMemory, MaxEntries: 10},
+		},
+		{
+			desc:          "disk storage without path",
+			config:        &types.Cache{Storage: StorageDisk},
+			expectedError: true,
+		},
+		{
+			desc:          "unknown storage",
+			config:        &types.Cache{Storage: "redis"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			storage, err := NewStorage("frontend", test.config)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, storage)
+		})
+	}
+}
@@ -1,33 +1,383 @@
 package middlewares
 
 import (
+	"bufio"
 	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 
-	"github.com/NYTimes/gziphandler"
+	"github.com/andybalholm/brotli"
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
 )
 
-// Compress is a middleware that allows redirection
-type Compress struct{}
+const (
+	// EncodingBrotli is the content encoding of the responses compressed with brotli
+	EncodingBrotli = "br"
+
+	// EncodingGzip is the content encoding of the responses compressed with gzip
+	EncodingGzip = "gzip"
+
+	// DefaultCompressionMinSize is the default minimum size in bytes of the compressed responses
+	DefaultCompressionMinSize = 512
+)
+
+// defaultEncodings are the encodings enabled by default, by order of preference
+var defaultEncodings = []string{EncodingBrotli, EncodingGzip}
+
+// Compress is a middleware that compresses the responses with brotli or gzip, as negotiated with the Accept-Encoding header.
+// The zero value compresses with the default settings.
+type Compress struct {
+	encodings            []string
+	minSize              int
+	gzipLevel            int
+	brotliLevel          int
+	includedContentTypes []string
+	excludedContentTypes []string
+
+	gzipWriters   sync.Pool
+	brotliWriters sync.Pool
+}
+
+// NewCompress creates a compression middleware from the configuration, the default settings being used when it is nil.
+func NewCompress(config *types.Compression) (*Compress, error) {
+	c := &Compress{}
+	if config == nil {
+		return c, nil
+	}
+
+	for _, encoding := range config.Encodings {
+		encoding = strings.ToLower(strings.TrimSpace(encoding))
+		if encoding != EncodingBrotli && encoding != EncodingGzip {
+			return nil, fmt.Errorf("unsupported encoding %q, must be %s or %s", encoding, EncodingBrotli, EncodingGzip)
+		}
+		c.encodings = append(c.encodings, encoding)
+	}
+
+	if config.MinSize < 0 {
+		return nil, fmt.Errorf("invalid minimum size %d, must be positive", config.MinSize)
+	}
+	c.minSize = config.MinSize
+
+	if config.GzipLevel < 0 || config.GzipLevel > gzip.BestCompression {
+		return nil, fmt.Errorf("invalid gzip level %d, must be between %d and %d", config.GzipLevel, gzip.BestSpeed, gzip.BestCompression)
+	}
+	c.gzipLevel = config.GzipLevel
+
+	if config.BrotliLevel < 0 || config.BrotliLevel > brotli.BestCompression {
+		return nil, fmt.Errorf("invalid brotli level %d, must be between 1 and %d", config.BrotliLevel, brotli.BestCompression)
+	}
+	c.brotliLevel = config.BrotliLevel
+
+	c.includedContentTypes = normalizeContentTypes(config.IncludedContentTypes)
+	c.excludedContentTypes = normalizeContentTypes(config.ExcludedContentTypes)
+
+	return c, nil
+}
 
 // ServerHTTP is a function used by Negroni
 func (c *Compress) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	contentType := r.Header.Get("Content-Type")
 	if strings.HasPrefix(contentType, "application/grpc") {
 		next.ServeHTTP(rw, r)
-	} else {
-		gzipHandler(next).ServeHTTP(rw, r)
+		return
+	}
+
+	rw.Header().Add("Vary", "Accept-Encoding")
+
+	encoding := c.negotiate(r.Header.Get("Accept-Encoding"))
+	if len(encoding) == 0 {
+		next.ServeHTTP(rw, r)
+		return
 	}
+
+	crw := &compressResponseWriter{rw: rw, compress: c, encoding: encoding}
+	defer crw.close()
+
+	next.ServeHTTP(crw, r)
 }
 
-func gzipHandler(h http.Handler) http.Handler {
-	wrapper, err := gziphandler.GzipHandlerWithOpts(
-		gziphandler.CompressionLevel(gzip.DefaultCompression),
-		gziphandler.MinSize(gziphandler.DefaultMinSize))
+// negotiate returns the enabled encoding with the highest quality in the Accept-Encoding header,
+// the order of preference of the encodings breaking the ties. It returns an empty string when no encoding is acceptable.
+func (c *Compress) negotiate(acceptEncoding string) string {
+	qualities := make(map[string]float64)
+	for _, spec := range strings.Split(acceptEncoding, ",") {
+		parts := strings.Split(spec, ";")
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		if len(name) == 0 {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err != nil {
+					q = 0
+				}
+				quality = q
+			}
+		}
+		qualities[name] = quality
+	}
+
+	encodings := c.encodings
+	if len(encodings) == 0 {
+		encodings = defaultEncodings
+	}
+
+	var best string
+	var bestQuality float64
+	for _, encoding := range encodings {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality = qualities["*"]
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+// compressible returns true if the responses with the content type can be compressed.
+func (c *Compress) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
 	if err != nil {
-		log.Error(err)
+		mediaType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	}
+
+	for _, excluded := range c.excludedContentTypes {
+		if matchContentType(excluded, mediaType) {
+			return false
+		}
+	}
+
+	if len(c.includedContentTypes) == 0 {
+		return true
+	}
+	for _, included := range c.includedContentTypes {
+		if matchContentType(included, mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Compress) getMinSize() int {
+	if c.minSize > 0 {
+		return c.minSize
+	}
+	return DefaultCompressionMinSize
+}
+
+func (c *Compress) newCompressor(encoding string, w io.Writer) io.WriteCloser {
+	if encoding == EncodingBrotli {
+		if bw, ok := c.brotliWriters.Get().(*brotli.Writer); ok {
+			bw.Reset(w)
+			return &pooledCompressor{compressor: bw, pool: &c.brotliWriters}
+		}
+
+		level := c.brotliLevel
+		if level == 0 {
+			level = brotli.DefaultCompression
+		}
+		return &pooledCompressor{compressor: brotli.NewWriterLevel(w, level), pool: &c.brotliWriters}
+	}
+
+	if gw, ok := c.gzipWriters.Get().(*gzip.Writer); ok {
+		gw.Reset(w)
+		return &pooledCompressor{compressor: gw, pool: &c.gzipWriters}
+	}
+
+	level := c.gzipLevel
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	// the level is validated by NewCompress
+	gw, _ := gzip.NewWriterLevel(w, level)
+	return &pooledCompressor{compressor: gw, pool: &c.gzipWriters}
+}
+
+// normalizeContentTypes lowercases the content types, such as "text/html" or "text/*".
+func normalizeContentTypes(contentTypes []string) []string {
+	var normalized []string
+	for _, contentType := range contentTypes {
+		contentType = strings.ToLower(strings.TrimSpace(contentType))
+		if len(contentType) > 0 {
+			normalized = append(normalized, contentType)
+		}
+	}
+	return normalized
+}
+
+func matchContentType(pattern, mediaType string) bool {
+	if strings.HasSuffix(pattern, "/*") {
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == mediaType
+}
+
+type flushWriteCloser interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// pooledCompressor returns the compressor to its pool once closed.
+type pooledCompressor struct {
+	compressor flushWriteCloser
+	pool       *sync.Pool
+}
+
+func (p *pooledCompressor) Write(b []byte) (int, error) {
+	return p.compressor.Write(b)
+}
+
+func (p *pooledCompressor) Flush() error {
+	return p.compressor.Flush()
+}
+
+func (p *pooledCompressor) Close() error {
+	err := p.compressor.Close()
+	p.pool.Put(p.compressor)
+	return err
+}
+
+// compressResponseWriter buffers the beginning of the response, until it is large enough to be compressed.
+// The response is left untouched when it is already encoded, too small, or when its content type is not compressible.
+type compressResponseWriter struct {
+	rw       http.ResponseWriter
+	compress *Compress
+	encoding string
+
+	buf        []byte
+	statusCode int
+	started    bool
+	compressor io.WriteCloser
+}
+
+func (w *compressResponseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *compressResponseWriter) WriteHeader(code int) {
+	if w.started || w.statusCode != 0 {
+		return
+	}
+	w.statusCode = code
+}
+
+func (w *compressResponseWriter) Write(b []byte) (int, error) {
+	if !w.started {
+		if len(w.rw.Header().Get("Content-Encoding")) > 0 {
+			w.start(false)
+			return w.rw.Write(b)
+		}
+
+		w.buf = append(w.buf, b...)
+		if len(w.buf) >= w.compress.getMinSize() {
+			if err := w.start(false); err != nil {
+				return 0, err
+			}
+		}
+		return len(b), nil
+	}
+
+	if w.compressor != nil {
+		return w.compressor.Write(b)
+	}
+	return w.rw.Write(b)
+}
+
+// Flush starts the response, compressing the buffered bytes whatever their size.
+func (w *compressResponseWriter) Flush() {
+	if !w.started {
+		if err := w.start(true); err != nil {
+			log.Errorf("Error compressing the response: %v", err)
+			return
+		}
+	}
+
+	if flusher, ok := w.compressor.(interface{ Flush() error }); ok {
+		if err := flusher.Flush(); err != nil {
+			log.Errorf("Error flushing the compressed response: %v", err)
+		}
+	}
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.rw)
+	}
+	return hijacker.Hijack()
+}
+
+func (w *compressResponseWriter) CloseNotify() <-chan bool {
+	if notifier, ok := w.rw.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+	return make(<-chan bool)
+}
+
+// start writes the headers and the buffered bytes, compressed when the response can be compressed.
+func (w *compressResponseWriter) start(ignoreMinSize bool) error {
+	w.started = true
+
+	header := w.rw.Header()
+	if len(w.buf) > 0 && len(header.Get("Content-Type")) == 0 {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	statusCode := w.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	if len(w.buf) > 0 && (ignoreMinSize || len(w.buf) >= w.compress.getMinSize()) &&
+		len(header.Get("Content-Encoding")) == 0 && w.compress.compressible(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		w.compressor = w.compress.newCompressor(w.encoding, w.rw)
+	}
+
+	w.rw.WriteHeader(statusCode)
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+
+	if w.compressor != nil {
+		_, err := w.compressor.Write(buf)
+		return err
+	}
+	_, err := w.rw.Write(buf)
+	return err
+}
+
+// close writes the response when it has not been started yet, and terminates the compressed stream.
+func (w *compressResponseWriter) close() {
+	if !w.started {
+		if err := w.start(false); err != nil {
+			log.Errorf("Error compressing the response: %v", err)
+		}
+	}
+
+	if w.compressor != nil {
+		if err := w.compressor.Close(); err != nil {
+			log.Errorf("Error compressing the response: %v", err)
+		}
 	}
-	return wrapper(h)
 }
@@ -1,13 +1,16 @@
 package middlewares
 
 import (
+	"compress/gzip"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
-	"github.com/NYTimes/gziphandler"
+	"github.com/andybalholm/brotli"
 	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/negroni"
@@ -19,6 +22,7 @@ const (
 	contentTypeHeader     = "Content-Type"
 	varyHeader            = "Vary"
 	gzipValue             = "gzip"
+	brotliValue           = "br"
 )
 
 func TestShouldCompressWhenNoContentEncodingHeader(t *testing.T) {
@@ -27,7 +31,7 @@ func TestShouldCompressWhenNoContentEncodingHeader(t *testing.T) {
 	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
 	req.Header.Add(acceptEncodingHeader, gzipValue)
 
-	baseBody := generateBytes(gziphandler.DefaultMinSize)
+	baseBody := generateBytes(DefaultCompressionMinSize)
 	next := func(rw http.ResponseWriter, r *http.Request) {
 		rw.Write(baseBody)
 	}
@@ -49,7 +53,7 @@ func TestShouldNotCompressWhenContentEncodingHeader(t *testing.T) {
 	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
 	req.Header.Add(acceptEncodingHeader, gzipValue)
 
-	fakeCompressedBody := generateBytes(gziphandler.DefaultMinSize)
+	fakeCompressedBody := generateBytes(DefaultCompressionMinSize)
 	next := func(rw http.ResponseWriter, r *http.Request) {
 		rw.Header().Add(contentEncodingHeader, gzipValue)
 		rw.Header().Add(varyHeader, acceptEncodingHeader)
@@ -70,7 +74,7 @@ func TestShouldNotCompressWhenNoAcceptEncodingHeader(t *testing.T) {
 
 	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
 
-	fakeBody := generateBytes(gziphandler.DefaultMinSize)
+	fakeBody := generateBytes(DefaultCompressionMinSize)
 	next := func(rw http.ResponseWriter, r *http.Request) {
 		rw.Write(fakeBody)
 	}
@@ -89,7 +93,7 @@ func TestShouldNotCompressWhenGRPC(t *testing.T) {
 	req.Header.Add(acceptEncodingHeader, gzipValue)
 	req.Header.Add(contentTypeHeader, "application/grpc")
 
-	baseBody := generateBytes(gziphandler.DefaultMinSize)
+	baseBody := generateBytes(DefaultCompressionMinSize)
 	next := func(rw http.ResponseWriter, r *http.Request) {
 		rw.Write(baseBody)
 	}
@@ -238,6 +242,191 @@ func TestIntegrationShouldCompress(t *testing.T) {
 	}
 }
 
+func TestCompressNegotiation(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		config           *types.Compression
+		acceptEncoding   string
+		expectedEncoding string
+	}{
+		{
+			desc:             "brotli is preferred by default",
+			acceptEncoding:   "gzip, deflate, br",
+			expectedEncoding: brotliValue,
+		},
+		{
+			desc:             "gzip only",
+			acceptEncoding:   "gzip",
+			expectedEncoding: gzipValue,
+		},
+		{
+			desc:             "higher quality wins",
+			acceptEncoding:   "br;q=0.5, gzip;q=0.8",
+			expectedEncoding: gzipValue,
+		},
+		{
+			desc:             "refused encoding",
+			acceptEncoding:   "br;q=0, gzip",
+			expectedEncoding: gzipValue,
+		},
+		{
+			desc:             "wildcard",
+			acceptEncoding:   "*",
+			expectedEncoding: brotliValue,
+		},
+		{
+			desc:             "order of preference of the configuration",
+			config:           &types.Compression{Encodings: []string{"gzip", "br"}},
+			acceptEncoding:   "br, gzip",
+			expectedEncoding: gzipValue,
+		},
+		{
+			desc:           "encoding not enabled",
+			config:         &types.Compression{Encodings: []string{"gzip"}},
+			acceptEncoding: "br",
+		},
+		{
+			desc:           "unsupported encoding",
+			acceptEncoding: "deflate",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := NewCompress(test.config)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedEncoding, handler.negotiate(test.acceptEncoding))
+		})
+	}
+}
+
+func TestCompressPolicy(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		config           *types.Compression
+		contentType      string
+		bodySize         int
+		acceptEncoding   string
+		expectedEncoding string
+	}{
+		{
+			desc:             "brotli",
+			contentType:      "text/html",
+			bodySize:         DefaultCompressionMinSize,
+			acceptEncoding:   brotliValue,
+			expectedEncoding: brotliValue,
+		},
+		{
+			desc:           "too small",
+			contentType:    "text/html",
+			bodySize:       DefaultCompressionMinSize - 1,
+			acceptEncoding: gzipValue,
+		},
+		{
+			desc:             "configured minimum size",
+			config:           &types.Compression{MinSize: 10},
+			contentType:      "text/html",
+			bodySize:         10,
+			acceptEncoding:   gzipValue,
+			expectedEncoding: gzipValue,
+		},
+		{
+			desc:             "included content type",
+			config:           &types.Compression{IncludedContentTypes: []string{"text/*", "application/json"}},
+			contentType:      "application/json; charset=utf-8",
+			bodySize:         DefaultCompressionMinSize,
+			acceptEncoding:   gzipValue,
+			expectedEncoding: gzipValue,
+		},
+		{
+			desc:           "content type not included",
+			config:         &types.Compression{IncludedContentTypes: []string{"text/*"}},
+			contentType:    "application/json",
+			bodySize:       DefaultCompressionMinSize,
+			acceptEncoding: gzipValue,
+		},
+		{
+			desc:           "excluded content type",
+			config:         &types.Compression{ExcludedContentTypes: []string{"image/*"}},
+			contentType:    "image/png",
+			bodySize:       DefaultCompressionMinSize,
+			acceptEncoding: gzipValue,
+		},
+		{
+			desc:             "levels",
+			config:           &types.Compression{GzipLevel: 9, BrotliLevel: 11},
+			contentType:      "text/html",
+			bodySize:         DefaultCompressionMinSize,
+			acceptEncoding:   brotliValue,
+			expectedEncoding: brotliValue,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := NewCompress(test.config)
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Add(acceptEncodingHeader, test.acceptEncoding)
+
+			body := generateBytes(test.bodySize)
+			next := func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set(contentTypeHeader, test.contentType)
+				rw.Write(body)
+			}
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req, next)
+
+			assert.Equal(t, test.expectedEncoding, rw.Header().Get(contentEncodingHeader))
+			assert.Equal(t, acceptEncodingHeader, rw.Header().Get(varyHeader))
+
+			var reader io.Reader = rw.Body
+			switch test.expectedEncoding {
+			case brotliValue:
+				reader = brotli.NewReader(rw.Body)
+			case gzipValue:
+				reader, err = gzip.NewReader(rw.Body)
+				require.NoError(t, err)
+			}
+
+			actual, err := ioutil.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, body, actual)
+		})
+	}
+}
+
+func TestNewCompressInvalid(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config *types.Compression
+	}{
+		{desc: "unsupported encoding", config: &types.Compression{Encodings: []string{"deflate"}}},
+		{desc: "negative minimum size", config: &types.Compression{MinSize: -1}},
+		{desc: "invalid gzip level", config: &types.Compression{GzipLevel: 10}},
+		{desc: "invalid brotli level", config: &types.Compression{BrotliLevel: 12}},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewCompress(test.config)
+			assert.Error(t, err)
+		})
+	}
+}
+
 func generateBytes(len int) []byte {
 	var value []byte
 	for i := 0; i < len; i++ {
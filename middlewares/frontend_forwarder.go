@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/containous/traefik/log"
+)
+
+type forwarderKey struct{}
+
+// FrontendForwarder is a middleware passing the forwarder of a frontend to the handler of its backend,
+// in the request context. The handler of a backend is shared by the frontends of the backend,
+// while the forwarder holds the settings of the frontend, such as the headers of the responses.
+type FrontendForwarder struct {
+	next      http.Handler
+	forwarder http.Handler
+}
+
+// NewFrontendForwarder creates a new FrontendForwarder instance.
+func NewFrontendForwarder(next http.Handler, forwarder http.Handler) *FrontendForwarder {
+	return &FrontendForwarder{next: next, forwarder: forwarder}
+}
+
+func (f *FrontendForwarder) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	f.next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), forwarderKey{}, f.forwarder)))
+}
+
+// ContextForwarder forwards the requests to the backend servers with the forwarder set by FrontendForwarder.
+type ContextForwarder struct{}
+
+func (ContextForwarder) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	forwarder, ok := r.Context().Value(forwarderKey{}).(http.Handler)
+	if !ok {
+		log.Errorf("No forwarder set for the request to %s", r.URL)
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(http.StatusText(http.StatusInternalServerError)))
+		return
+	}
+	forwarder.ServeHTTP(rw, r)
+}
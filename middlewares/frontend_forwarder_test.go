@@ -0,0 +1,58 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrontendForwarder(t *testing.T) {
+	forwarder := func(name string) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("X-Forwarder", name)
+		})
+	}
+
+	shared := ContextForwarder{}
+
+	tests := []struct {
+		desc               string
+		handler            http.Handler
+		expectedStatusCode int
+		expectedForwarder  string
+	}{
+		{
+			desc:               "forwarder of the first frontend",
+			handler:            NewFrontendForwarder(shared, forwarder("a")),
+			expectedStatusCode: http.StatusOK,
+			expectedForwarder:  "a",
+		},
+		{
+			desc:               "forwarder of the second frontend",
+			handler:            NewFrontendForwarder(shared, forwarder("b")),
+			expectedStatusCode: http.StatusOK,
+			expectedForwarder:  "b",
+		},
+		{
+			desc:               "no forwarder",
+			handler:            shared,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			test.handler.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil))
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+			assert.Equal(t, test.expectedForwarder, recorder.Header().Get("X-Forwarder"))
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package middlewares
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// gRPC status codes, see https://github.com/grpc/grpc/blob/master/doc/statuscodes.md
+const (
+	grpcStatusUnknown          = 2
+	grpcStatusPermissionDenied = 7
+	grpcStatusUnimplemented    = 12
+	grpcStatusInternal         = 13
+	grpcStatusUnavailable      = 14
+	grpcStatusUnauthenticated  = 16
+)
+
+// GRPCErrors is a middleware converting the HTTP errors returned to the gRPC requests,
+// such as the errors of the proxy or of the other middlewares, into gRPC statuses.
+// The gRPC clients then get a meaningful status instead of an HTTP error with an HTML body.
+type GRPCErrors struct{}
+
+// NewGRPCErrors creates a new GRPCErrors middleware.
+func NewGRPCErrors() *GRPCErrors {
+	return &GRPCErrors{}
+}
+
+func (g *GRPCErrors) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if !isGRPCContentType(r.Header.Get("Content-Type")) {
+		next(rw, r)
+		return
+	}
+	next(newGRPCErrorsResponseWriter(rw), r)
+}
+
+// isGRPCContentType returns true if the content type is a gRPC content type, such as application/grpc+proto.
+// The gRPC-Web content types are excluded, as gRPC-Web sends the statuses in the body.
+func isGRPCContentType(contentType string) bool {
+	return contentType == "application/grpc" || strings.HasPrefix(contentType, "application/grpc+") || strings.HasPrefix(contentType, "application/grpc;")
+}
+
+// grpcStatusFromHTTP returns the gRPC status code corresponding to an HTTP status code,
+// as defined in https://github.com/grpc/grpc/blob/master/doc/http-grpc-status-mapping.md
+func grpcStatusFromHTTP(code int) int {
+	switch code {
+	case http.StatusBadRequest:
+		return grpcStatusInternal
+	case http.StatusUnauthorized:
+		return grpcStatusUnauthenticated
+	case http.StatusForbidden:
+		return grpcStatusPermissionDenied
+	case http.StatusNotFound:
+		return grpcStatusUnimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return grpcStatusUnavailable
+	default:
+		return grpcStatusUnknown
+	}
+}
+
+type grpcErrorsResponseWriter interface {
+	http.ResponseWriter
+	http.Flusher
+}
+
+func newGRPCErrorsResponseWriter(rw http.ResponseWriter) grpcErrorsResponseWriter {
+	writer := &grpcErrorsResponseWriterWithoutCloseNotify{responseWriter: rw}
+	if _, ok := rw.(http.CloseNotifier); ok {
+		return &grpcErrorsResponseWriterWithCloseNotify{writer}
+	}
+	return writer
+}
+
+type grpcErrorsResponseWriterWithoutCloseNotify struct {
+	responseWriter http.ResponseWriter
+	wroteHeader    bool
+	// discard is true when the response has been converted to a gRPC status, so the HTTP body is dropped.
+	discard bool
+}
+
+func (w *grpcErrorsResponseWriterWithoutCloseNotify) Header() http.Header {
+	return w.responseWriter.Header()
+}
+
+func (w *grpcErrorsResponseWriterWithoutCloseNotify) Write(buf []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.discard {
+		return len(buf), nil
+	}
+	return w.responseWriter.Write(buf)
+}
+
+func (w *grpcErrorsResponseWriterWithoutCloseNotify) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.responseWriter.Header()
+	if code == http.StatusOK || len(header.Get("Grpc-Status")) > 0 || isGRPCContentType(header.Get("Content-Type")) {
+		w.responseWriter.WriteHeader(code)
+		return
+	}
+
+	// The error is sent as a Trailers-Only response: the gRPC status is in the headers, without any body.
+	w.discard = true
+	header.Del("Content-Length")
+	header.Del("Content-Encoding")
+	header.Set("Content-Type", "application/grpc")
+	header.Set("Grpc-Status", strconv.Itoa(grpcStatusFromHTTP(code)))
+	header.Set("Grpc-Message", http.StatusText(code))
+	w.responseWriter.WriteHeader(http.StatusOK)
+}
+
+func (w *grpcErrorsResponseWriterWithoutCloseNotify) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.responseWriter.(http.Hijacker).Hijack()
+}
+
+func (w *grpcErrorsResponseWriterWithoutCloseNotify) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.discard {
+		return
+	}
+	if flusher, ok := w.responseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+type grpcErrorsResponseWriterWithCloseNotify struct {
+	*grpcErrorsResponseWriterWithoutCloseNotify
+}
+
+// CloseNotify returns a channel that receives at most a
+// single value (true) when the client connection has gone
+// away.
+func (w *grpcErrorsResponseWriterWithCloseNotify) CloseNotify() <-chan bool {
+	return w.responseWriter.(http.CloseNotifier).CloseNotify()
+}
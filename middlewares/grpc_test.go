@@ -0,0 +1,127 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGRPCErrors(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		contentType         string
+		handler             http.HandlerFunc
+		expectedCode        int
+		expectedContentType string
+		expectedGRPCStatus  string
+		expectedBody        string
+	}{
+		{
+			desc:        "not a gRPC request",
+			contentType: "text/plain",
+			handler: func(rw http.ResponseWriter, r *http.Request) {
+				http.Error(rw, "Bad Gateway", http.StatusBadGateway)
+			},
+			expectedCode:        http.StatusBadGateway,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "Bad Gateway\n",
+		},
+		{
+			desc:        "gRPC-Web request",
+			contentType: "application/grpc-web+proto",
+			handler: func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(http.StatusBadGateway)
+			},
+			expectedCode: http.StatusBadGateway,
+		},
+		{
+			desc:        "proxy error",
+			contentType: "application/grpc",
+			handler: func(rw http.ResponseWriter, r *http.Request) {
+				http.Error(rw, "Bad Gateway", http.StatusBadGateway)
+			},
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/grpc",
+			expectedGRPCStatus:  "14",
+		},
+		{
+			desc:        "unauthorized",
+			contentType: "application/grpc+proto",
+			handler: func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(http.StatusUnauthorized)
+				rw.Write([]byte("<html>Unauthorized</html>"))
+			},
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/grpc",
+			expectedGRPCStatus:  "16",
+		},
+		{
+			desc:        "gRPC response",
+			contentType: "application/grpc",
+			handler: func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set("Content-Type", "application/grpc")
+				rw.Header().Set("Grpc-Status", "5")
+				rw.WriteHeader(http.StatusOK)
+			},
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/grpc",
+			expectedGRPCStatus:  "5",
+		},
+		{
+			desc:        "gRPC status on an HTTP error",
+			contentType: "application/grpc",
+			handler: func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set("Grpc-Status", "8")
+				rw.WriteHeader(http.StatusServiceUnavailable)
+			},
+			expectedCode:       http.StatusServiceUnavailable,
+			expectedGRPCStatus: "8",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost/foo.Bar/Baz", nil)
+			req.Header.Set("Content-Type", test.contentType)
+
+			recorder := httptest.NewRecorder()
+			NewGRPCErrors().ServeHTTP(recorder, req, test.handler)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedContentType, recorder.Header().Get("Content-Type"))
+			assert.Equal(t, test.expectedGRPCStatus, recorder.Header().Get("Grpc-Status"))
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestGRPCStatusFromHTTP(t *testing.T) {
+	testCases := []struct {
+		code     int
+		expected int
+	}{
+		{code: http.StatusBadRequest, expected: grpcStatusInternal},
+		{code: http.StatusUnauthorized, expected: grpcStatusUnauthenticated},
+		{code: http.StatusForbidden, expected: grpcStatusPermissionDenied},
+		{code: http.StatusNotFound, expected: grpcStatusUnimplemented},
+		{code: http.StatusTooManyRequests, expected: grpcStatusUnavailable},
+		{code: http.StatusBadGateway, expected: grpcStatusUnavailable},
+		{code: http.StatusServiceUnavailable, expected: grpcStatusUnavailable},
+		{code: http.StatusGatewayTimeout, expected: grpcStatusUnavailable},
+		{code: http.StatusInternalServerError, expected: grpcStatusUnknown},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(http.StatusText(test.code), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, grpcStatusFromHTTP(test.code))
+		})
+	}
+}
@@ -123,7 +123,7 @@ type MetricsRetryListener struct {
 	backendName  string
 }
 
-// Retried tracks the retry in the RequestMetrics implementation.
-func (m *MetricsRetryListener) Retried(req *http.Request, attempt int) {
-	m.retryMetrics.BackendRetriesCounter().With("backend", m.backendName).Add(1)
+// Retried tracks the retry in the RequestMetrics implementation, partitioned by reason.
+func (m *MetricsRetryListener) Retried(req *http.Request, attempt int, reason string) {
+	m.retryMetrics.BackendRetriesCounter().With("backend", m.backendName, "reason", reason).Add(1)
 }
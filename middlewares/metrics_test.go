@@ -14,15 +14,15 @@ func TestMetricsRetryListener(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	retryMetrics := newCollectingRetryMetrics()
 	retryListener := NewMetricsRetryListener(retryMetrics, "backendName")
-	retryListener.Retried(req, 1)
-	retryListener.Retried(req, 2)
+	retryListener.Retried(req, 1, RetryReasonNetworkError)
+	retryListener.Retried(req, 2, RetryReasonNetworkError)
 
 	wantCounterValue := float64(2)
 	if retryMetrics.retriesCounter.CounterValue != wantCounterValue {
 		t.Errorf("got counter value of %d, want %d", retryMetrics.retriesCounter.CounterValue, wantCounterValue)
 	}
 
-	wantLabelValues := []string{"backend", "backendName"}
+	wantLabelValues := []string{"backend", "backendName", "reason", RetryReasonNetworkError}
 	if !reflect.DeepEqual(retryMetrics.retriesCounter.LastLabelValues, wantLabelValues) {
 		t.Errorf("wrong label values %v used, want %v", retryMetrics.retriesCounter.LastLabelValues, wantLabelValues)
 	}
@@ -0,0 +1,210 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+	"github.com/go-kit/kit/metrics"
+)
+
+// DefaultMirroringMaxBodySize is the maximum size of a request body buffered to be mirrored, when none is configured.
+const DefaultMirroringMaxBodySize int64 = 1 << 20
+
+// DefaultMirroringMaxInFlight is the maximum number of mirrored requests in flight.
+const DefaultMirroringMaxInFlight = 100
+
+// DefaultMirroringTimeout is the time after which a mirrored request is canceled.
+const DefaultMirroringTimeout = 30 * time.Second
+
+// Mirroring is a middleware that duplicates a percentage of the requests to a mirror handler.
+// The mirrored requests are sent asynchronously, and the responses of the mirror handler are discarded.
+// Requests with a body larger than maxBodySize are not mirrored, and neither are the requests sampled
+// while too many mirrored requests are in flight: they are counted as failures.
+type Mirroring struct {
+	// total is the number of requests served, accessed atomically and kept first for 64-bit alignment.
+	total uint64
+
+	next           http.Handler
+	mirror         http.Handler
+	mirrorName     string
+	percent        uint64
+	maxBodySize    int64
+	inFlight       chan struct{}
+	timeout        time.Duration
+	successCounter metrics.Counter
+	failureCounter metrics.Counter
+}
+
+// NewMirroring creates a new Mirroring middleware.
+// The success and failure counters may be nil.
+func NewMirroring(next http.Handler, mirror http.Handler, mirrorName string, percent int, maxBodySize int64, successCounter, failureCounter metrics.Counter) *Mirroring {
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMirroringMaxBodySize
+	}
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 100 {
+		percent = 100
+	}
+
+	return &Mirroring{
+		next:           next,
+		mirror:         mirror,
+		mirrorName:     mirrorName,
+		percent:        uint64(percent),
+		maxBodySize:    maxBodySize,
+		inFlight:       make(chan struct{}, DefaultMirroringMaxInFlight),
+		timeout:        DefaultMirroringTimeout,
+		successCounter: successCounter,
+		failureCounter: failureCounter,
+	}
+}
+
+func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if !m.sample() {
+		m.next.ServeHTTP(rw, r)
+		return
+	}
+
+	select {
+	case m.inFlight <- struct{}{}:
+	default:
+		log.Debugf("Too many mirrored requests in flight on %s, request %s %s not mirrored", m.mirrorName, r.Method, r.URL)
+		m.countFailure()
+		m.next.ServeHTTP(rw, r)
+		return
+	}
+
+	body, ok, err := m.bufferBody(r)
+	if err != nil {
+		log.Debugf("Error while reading body of request to mirror on %s: %v", m.mirrorName, err)
+	}
+	if !ok {
+		<-m.inFlight
+		m.next.ServeHTTP(rw, r)
+		return
+	}
+
+	mirrorReq := cloneRequest(r, body)
+	safe.Go(func() {
+		defer func() { <-m.inFlight }()
+		m.serveMirror(mirrorReq)
+	})
+
+	m.next.ServeHTTP(rw, r)
+}
+
+// sample spreads the mirrored requests evenly: the n-th request is mirrored
+// when n*percent/100 crosses an integer boundary.
+func (m *Mirroring) sample() bool {
+	if m.percent == 0 {
+		return false
+	}
+	n := atomic.AddUint64(&m.total, 1)
+	return n*m.percent/100 != (n-1)*m.percent/100
+}
+
+// bufferBody reads the body of the request, up to maxBodySize.
+// The body of the original request is restored in every case, and false is returned
+// when the body is too large, or cannot be read, to be mirrored.
+func (m *Mirroring) bufferBody(r *http.Request) ([]byte, bool, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true, nil
+	}
+	if r.ContentLength > m.maxBodySize {
+		return nil, false, nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, m.maxBodySize+1))
+	r.Body = &readCloser{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(body)) > m.maxBodySize {
+		return nil, false, nil
+	}
+	return body, true, nil
+}
+
+func (m *Mirroring) serveMirror(r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), m.timeout)
+	defer cancel()
+
+	rw := &discardResponseWriter{header: make(http.Header)}
+	m.mirror.ServeHTTP(rw, r.WithContext(ctx))
+
+	if rw.code >= http.StatusInternalServerError {
+		log.Debugf("Mirrored request %s %s failed on %s with status %d", r.Method, r.URL, m.mirrorName, rw.code)
+		m.countFailure()
+		return
+	}
+
+	if m.successCounter != nil {
+		m.successCounter.With("backend", m.mirrorName).Add(1)
+	}
+}
+
+func (m *Mirroring) countFailure() {
+	if m.failureCounter != nil {
+		m.failureCounter.With("backend", m.mirrorName).Add(1)
+	}
+}
+
+// cloneRequest creates a copy of the request, detached from the context of the original request.
+func cloneRequest(r *http.Request, body []byte) *http.Request {
+	req := r.WithContext(context.Background())
+
+	u := *r.URL
+	req.URL = &u
+
+	req.Header = make(http.Header, len(r.Header))
+	for k, v := range r.Header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+
+	if body == nil {
+		req.Body = nil
+		req.ContentLength = 0
+	} else {
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+	}
+
+	return req
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// discardResponseWriter discards the response body, and only records the status code.
+type discardResponseWriter struct {
+	header http.Header
+	code   int
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	return len(b), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
@@ -0,0 +1,307 @@
+package middlewares
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirroringPercent(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		percent      int
+		requests     int
+		wantMirrored int
+	}{
+		{
+			desc:         "no request mirrored",
+			percent:      0,
+			requests:     10,
+			wantMirrored: 0,
+		},
+		{
+			desc:         "every request mirrored",
+			percent:      100,
+			requests:     10,
+			wantMirrored: 10,
+		},
+		{
+			desc:         "a quarter of the requests mirrored",
+			percent:      25,
+			requests:     100,
+			wantMirrored: 25,
+		},
+		{
+			desc:         "percentage greater than 100",
+			percent:      150,
+			requests:     10,
+			wantMirrored: 10,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			mirror := newRecordingHandler(http.StatusOK)
+			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(http.StatusNoContent)
+			})
+
+			handler := NewMirroring(next, mirror, "mirror", test.percent, 0, nil, nil)
+
+			for i := 0; i < test.requests; i++ {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+				assert.Equal(t, http.StatusNoContent, recorder.Code)
+			}
+
+			assert.Len(t, mirror.waitRequests(t, test.wantMirrored), test.wantMirrored)
+		})
+	}
+}
+
+func TestMirroringBody(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		body        string
+		maxBodySize int64
+		wantMirror  bool
+	}{
+		{
+			desc:        "body smaller than the limit",
+			body:        "mirrored body",
+			maxBodySize: 64,
+			wantMirror:  true,
+		},
+		{
+			desc:        "body larger than the limit",
+			body:        "mirrored body",
+			maxBodySize: 4,
+			wantMirror:  false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			mirror := newRecordingHandler(http.StatusOK)
+			var nextBody []byte
+			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				var err error
+				nextBody, err = ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+			})
+
+			handler := NewMirroring(next, mirror, "mirror", 100, test.maxBodySize, nil, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost/", ioutil.NopCloser(strings.NewReader(test.body)))
+			req.ContentLength = -1
+			req.Header.Set("X-Foo", "bar")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.body, string(nextBody), "the original request body should be preserved")
+
+			if !test.wantMirror {
+				assert.Empty(t, mirror.waitRequests(t, 0))
+				return
+			}
+
+			requests := mirror.waitRequests(t, 1)
+			require.Len(t, requests, 1)
+			assert.Equal(t, test.body, requests[0].body)
+			assert.Equal(t, "bar", requests[0].header.Get("X-Foo"))
+		})
+	}
+}
+
+func TestMirroringCounters(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		mirrorCode  int
+		wantSuccess float64
+		wantFailure float64
+	}{
+		{
+			desc:        "mirror success",
+			mirrorCode:  http.StatusNotFound,
+			wantSuccess: 1,
+		},
+		{
+			desc:        "mirror failure",
+			mirrorCode:  http.StatusBadGateway,
+			wantFailure: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			mirror := newRecordingHandler(test.mirrorCode)
+			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {})
+
+			successCounter := &collectingCounter{}
+			failureCounter := &collectingCounter{}
+			handler := NewMirroring(next, mirror, "mirror", 100, 0, successCounter, failureCounter)
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+			require.Len(t, mirror.waitRequests(t, 1), 1)
+
+			// the counters are updated once the mirror handler has returned
+			time.Sleep(50 * time.Millisecond)
+			assert.Equal(t, test.wantSuccess, successCounter.value())
+			assert.Equal(t, test.wantFailure, failureCounter.value())
+		})
+	}
+}
+
+func TestMirroringMaxInFlight(t *testing.T) {
+	release := make(chan struct{})
+	mirrored := make(chan struct{}, 10)
+	mirror := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mirrored <- struct{}{}
+		<-release
+	})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	failureCounter := &collectingCounter{}
+	handler := NewMirroring(next, mirror, "mirror", 100, 0, nil, failureCounter)
+	handler.inFlight = make(chan struct{}, 1)
+
+	for i := 0; i < 3; i++ {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+		assert.Equal(t, http.StatusNoContent, recorder.Code, "the requests should not wait for the blocked mirror")
+	}
+
+	// Only the first request is mirrored while the mirror is blocked, the other ones are dropped.
+	<-mirrored
+	assert.Equal(t, float64(2), failureCounter.value())
+
+	close(release)
+	waitNoMirrorInFlight(t, handler)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	select {
+	case <-mirrored:
+	case <-time.After(time.Second):
+		t.Fatal("the request should be mirrored once the mirror is released")
+	}
+}
+
+func TestMirroringTimeout(t *testing.T) {
+	mirror := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		rw.WriteHeader(http.StatusGatewayTimeout)
+	})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {})
+
+	failureCounter := &collectingCounter{}
+	handler := NewMirroring(next, mirror, "mirror", 100, 0, nil, failureCounter)
+	handler.inFlight = make(chan struct{}, 1)
+	handler.timeout = 50 * time.Millisecond
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+
+	// The mirrored request is canceled after the timeout, and releases its slot.
+	waitNoMirrorInFlight(t, handler)
+	assert.Equal(t, float64(1), failureCounter.value())
+}
+
+type mirroredRequest struct {
+	body   string
+	header http.Header
+}
+
+type recordingHandler struct {
+	code     int
+	lock     sync.Mutex
+	requests []mirroredRequest
+}
+
+func newRecordingHandler(code int) *recordingHandler {
+	return &recordingHandler{code: code}
+}
+
+func (h *recordingHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	var body bytes.Buffer
+	if r.Body != nil {
+		body.ReadFrom(r.Body)
+	}
+
+	h.lock.Lock()
+	h.requests = append(h.requests, mirroredRequest{body: body.String(), header: r.Header})
+	h.lock.Unlock()
+
+	rw.WriteHeader(h.code)
+}
+
+// waitRequests waits for the expected number of mirrored requests, and returns the recorded ones.
+// When no request is expected, it waits long enough for an unexpected request to be recorded.
+func (h *recordingHandler) waitRequests(t *testing.T, expected int) []mirroredRequest {
+	t.Helper()
+
+	if expected == 0 {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(h.recorded()) < expected && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return h.recorded()
+}
+
+// waitNoMirrorInFlight waits for the mirrored requests in flight to be done.
+func waitNoMirrorInFlight(t *testing.T, m *Mirroring) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for len(m.inFlight) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Empty(t, m.inFlight, "mirrored requests still in flight")
+}
+
+func (h *recordingHandler) recorded() []mirroredRequest {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return append([]mirroredRequest(nil), h.requests...)
+}
+
+type collectingCounter struct {
+	lock         sync.Mutex
+	counterValue float64
+}
+
+func (c *collectingCounter) With(labelValues ...string) metrics.Counter {
+	return c
+}
+
+func (c *collectingCounter) Add(delta float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.counterValue += delta
+}
+
+func (c *collectingCounter) value() float64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.counterValue
+}
@@ -0,0 +1,91 @@
+package middlewares
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+
+	"github.com/containous/traefik/healthcheck"
+)
+
+// OutlierDetection is a middleware reporting the status code of the responses of the backend servers
+// to an outlier detector. It must wrap the forwarder, so that the request URL is the URL of the server.
+type OutlierDetection struct {
+	next     http.Handler
+	detector *healthcheck.OutlierDetector
+}
+
+// NewOutlierDetection creates a new OutlierDetection middleware.
+func NewOutlierDetection(next http.Handler, detector *healthcheck.OutlierDetector) *OutlierDetection {
+	return &OutlierDetection{
+		next:     next,
+		detector: detector,
+	}
+}
+
+func (o *OutlierDetection) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	serverURL := *r.URL
+	recorder := newOutlierResponseRecorder(rw)
+	o.next.ServeHTTP(recorder, r)
+	o.detector.Record(&serverURL, recorder.getCode())
+}
+
+type outlierResponseRecorder interface {
+	http.ResponseWriter
+	http.Flusher
+	getCode() int
+}
+
+func newOutlierResponseRecorder(rw http.ResponseWriter) outlierResponseRecorder {
+	recorder := &outlierResponseRecorderWithoutCloseNotify{
+		responseWriter: rw,
+		code:           http.StatusOK,
+	}
+	if _, ok := rw.(http.CloseNotifier); ok {
+		return &outlierResponseRecorderWithCloseNotify{recorder}
+	}
+	return recorder
+}
+
+type outlierResponseRecorderWithoutCloseNotify struct {
+	responseWriter http.ResponseWriter
+	code           int
+}
+
+func (r *outlierResponseRecorderWithoutCloseNotify) getCode() int {
+	return r.code
+}
+
+func (r *outlierResponseRecorderWithoutCloseNotify) Header() http.Header {
+	return r.responseWriter.Header()
+}
+
+func (r *outlierResponseRecorderWithoutCloseNotify) Write(buf []byte) (int, error) {
+	return r.responseWriter.Write(buf)
+}
+
+func (r *outlierResponseRecorderWithoutCloseNotify) WriteHeader(code int) {
+	r.code = code
+	r.responseWriter.WriteHeader(code)
+}
+
+func (r *outlierResponseRecorderWithoutCloseNotify) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.responseWriter.(http.Hijacker).Hijack()
+}
+
+func (r *outlierResponseRecorderWithoutCloseNotify) Flush() {
+	if flusher, ok := r.responseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+type outlierResponseRecorderWithCloseNotify struct {
+	*outlierResponseRecorderWithoutCloseNotify
+}
+
+// CloseNotify returns a channel that receives at most a
+// single value (true) when the client connection has gone
+// away.
+func (r *outlierResponseRecorderWithCloseNotify) CloseNotify() <-chan bool {
+	return r.responseWriter.(http.CloseNotifier).CloseNotify()
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// purgePeriod is the number of calls to Take between two purges of the expired buckets of the MemoryCounter.
+const purgePeriod = 1024
+
+// Counter keeps the state of the token buckets of the rate limiters.
+// The implementations backed by a shared store allow the rate limits to be global across several Traefik nodes.
+type Counter interface {
+	// Take takes a token from the bucket identified by the key.
+	// The bucket holds up to burst tokens, and is refilled with a token at each interval.
+	// It returns zero if a token was taken, or the delay before a token is available if the bucket is empty.
+	Take(key string, interval time.Duration, burst int64) (time.Duration, error)
+}
+
+// nextArrival implements a token bucket as a Generic Cell Rate Algorithm:
+// the state of a bucket is the theoretical arrival time (TAT) of the next request when the bucket is full.
+// It returns the new TAT if a token can be taken, or the delay before a token is available.
+func nextArrival(tat time.Time, now time.Time, interval time.Duration, burst int64) (time.Time, time.Duration) {
+	if tat.Before(now) {
+		tat = now
+	}
+
+	newTAT := tat.Add(interval)
+	if delay := newTAT.Sub(now) - time.Duration(burst)*interval; delay > 0 {
+		return tat, delay
+	}
+	return newTAT, 0
+}
+
+// MemoryCounter is a Counter keeping the buckets in memory, so the rate limits are local to the Traefik node.
+type MemoryCounter struct {
+	lock    sync.Mutex
+	buckets map[string]time.Time
+	takes   int
+	now     func() time.Time
+}
+
+// NewMemoryCounter creates a new MemoryCounter.
+func NewMemoryCounter() *MemoryCounter {
+	return &MemoryCounter{
+		buckets: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// Take takes a token from the bucket identified by the key.
+func (c *MemoryCounter) Take(key string, interval time.Duration, burst int64) (time.Duration, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+
+	c.takes++
+	if c.takes%purgePeriod == 0 {
+		c.purge(now)
+	}
+
+	tat, delay := nextArrival(c.buckets[key], now, interval, burst)
+	if delay > 0 {
+		return delay, nil
+	}
+	c.buckets[key] = tat
+	return 0, nil
+}
+
+// purge removes the full buckets, which are equivalent to missing buckets.
+// It must be called with the lock held.
+func (c *MemoryCounter) purge(now time.Time) {
+	for key, tat := range c.buckets {
+		if !tat.After(now) {
+			delete(c.buckets, key)
+		}
+	}
+}
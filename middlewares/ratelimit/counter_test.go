@@ -0,0 +1,243 @@
+package ratelimit
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextArrival(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	testCases := []struct {
+		desc          string
+		tat           time.Time
+		burst         int64
+		expectedTAT   time.Time
+		expectedDelay time.Duration
+	}{
+		{
+			desc:        "empty state",
+			burst:       1,
+			expectedTAT: now.Add(time.Second),
+		},
+		{
+			desc:        "state in the past",
+			tat:         now.Add(-time.Hour),
+			burst:       1,
+			expectedTAT: now.Add(time.Second),
+		},
+		{
+			desc:        "tokens left in the burst",
+			tat:         now.Add(2 * time.Second),
+			burst:       3,
+			expectedTAT: now.Add(3 * time.Second),
+		},
+		{
+			desc:          "empty bucket",
+			tat:           now.Add(3 * time.Second),
+			burst:         3,
+			expectedTAT:   now.Add(3 * time.Second),
+			expectedDelay: time.Second,
+		},
+		{
+			desc:          "empty bucket without burst",
+			tat:           now.Add(500 * time.Millisecond),
+			burst:         1,
+			expectedTAT:   now.Add(500 * time.Millisecond),
+			expectedDelay: 500 * time.Millisecond,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tat, delay := nextArrival(test.tat, now, time.Second, test.burst)
+			assert.Equal(t, test.expectedTAT, tat)
+			assert.Equal(t, test.expectedDelay, delay)
+		})
+	}
+}
+
+func TestMemoryCounter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	counter := NewMemoryCounter()
+	counter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		delay, err := counter.Take("foo", time.Second, 3)
+		require.NoError(t, err)
+		assert.Zero(t, delay)
+	}
+
+	delay, err := counter.Take("foo", time.Second, 3)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, delay)
+
+	// the buckets are independent
+	delay, err = counter.Take("bar", time.Second, 3)
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+
+	// a token is added at each interval
+	now = now.Add(time.Second)
+	delay, err = counter.Take("foo", time.Second, 3)
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+
+	delay, err = counter.Take("foo", time.Second, 3)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, delay)
+}
+
+func TestMemoryCounterPurge(t *testing.T) {
+	now := time.Unix(1000, 0)
+	counter := NewMemoryCounter()
+	counter.now = func() time.Time { return now }
+
+	_, err := counter.Take("foo", time.Second, 1)
+	require.NoError(t, err)
+	assert.Len(t, counter.buckets, 1)
+
+	now = now.Add(time.Hour)
+	for i := 1; i < purgePeriod; i++ {
+		_, err = counter.Take("bar", time.Millisecond, int64(purgePeriod))
+		require.NoError(t, err)
+	}
+
+	assert.Len(t, counter.buckets, 1)
+	assert.Contains(t, counter.buckets, "bar")
+}
+
+func TestKVCounter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	kv := newFakeStore()
+
+	counter := NewKVCounter(kv, "traefik/ratelimit")
+	counter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		delay, err := counter.Take("foo", time.Second, 2)
+		require.NoError(t, err)
+		assert.Zero(t, delay)
+	}
+
+	// another node shares the same bucket
+	other := NewKVCounter(kv, "traefik/ratelimit")
+	other.now = counter.now
+
+	delay, err := other.Take("foo", time.Second, 2)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, delay)
+
+	assert.Contains(t, kv.pairs, "traefik/ratelimit/foo")
+	assert.Equal(t, 3*time.Second, kv.ttl)
+}
+
+func TestKVCounterConflicts(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		conflicts   int
+		expectedErr bool
+	}{
+		{
+			desc:      "a few conflicts",
+			conflicts: 3,
+		},
+		{
+			desc:        "too many conflicts",
+			conflicts:   maxAttempts,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			kv := newFakeStore()
+			kv.conflicts = test.conflicts
+
+			delay, err := NewKVCounter(kv, "traefik/ratelimit").Take("foo", time.Second, 1)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Zero(t, delay)
+		})
+	}
+}
+
+func TestKVCounterStoreError(t *testing.T) {
+	kv := newFakeStore()
+	kv.err = errors.New("connection refused")
+
+	_, err := NewKVCounter(kv, "traefik/ratelimit").Take("foo", time.Second, 1)
+	assert.EqualError(t, err, "connection refused")
+}
+
+// fakeStore is an in-memory store supporting the operations used by the KVCounter.
+type fakeStore struct {
+	store.Store
+
+	lock      sync.Mutex
+	pairs     map[string]*store.KVPair
+	index     uint64
+	ttl       time.Duration
+	conflicts int
+	err       error
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{pairs: make(map[string]*store.KVPair)}
+}
+
+func (f *fakeStore) Get(key string, options *store.ReadOptions) (*store.KVPair, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	pair, ok := f.pairs[key]
+	if !ok {
+		return nil, store.ErrKeyNotFound
+	}
+	return pair, nil
+}
+
+func (f *fakeStore) AtomicPut(key string, value []byte, previous *store.KVPair, options *store.WriteOptions) (bool, *store.KVPair, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.conflicts > 0 {
+		f.conflicts--
+		return false, nil, store.ErrKeyModified
+	}
+
+	current, ok := f.pairs[key]
+	if previous == nil && ok {
+		return false, nil, store.ErrKeyExists
+	}
+	if previous != nil && (!ok || current.LastIndex != previous.LastIndex) {
+		return false, nil, store.ErrKeyModified
+	}
+
+	f.index++
+	pair := &store.KVPair{Key: key, Value: value, LastIndex: f.index}
+	f.pairs[key] = pair
+	if options != nil {
+		f.ttl = options.TTL
+	}
+	return true, pair, nil
+}
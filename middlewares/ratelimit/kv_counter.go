@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+)
+
+// maxAttempts is the number of attempts to update a bucket modified concurrently by other nodes.
+const maxAttempts = 10
+
+var errTooManyConflicts = errors.New("too many concurrent updates")
+
+// KVCounter is a Counter keeping the buckets in a KV store, so the rate limits are shared by the Traefik nodes of a cluster.
+// The buckets are updated with atomic operations, and expire once full on the stores supporting a TTL.
+type KVCounter struct {
+	store  store.Store
+	prefix string
+	now    func() time.Time
+}
+
+// NewKVCounter creates a new KVCounter storing the buckets under the given prefix.
+func NewKVCounter(kv store.Store, prefix string) *KVCounter {
+	return &KVCounter{
+		store:  kv,
+		prefix: prefix,
+		now:    time.Now,
+	}
+}
+
+// Take takes a token from the bucket identified by the key.
+func (c *KVCounter) Take(key string, interval time.Duration, burst int64) (time.Duration, error) {
+	key = c.prefix + "/" + key
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		previous, err := c.store.Get(key, nil)
+		if err == store.ErrKeyNotFound {
+			previous = nil
+		} else if err != nil {
+			return 0, err
+		}
+
+		var tat time.Time
+		if previous != nil {
+			if nanos, err := strconv.ParseInt(string(previous.Value), 10, 64); err == nil {
+				tat = time.Unix(0, nanos)
+			}
+		}
+
+		now := c.now()
+		newTAT, delay := nextArrival(tat, now, interval, burst)
+		if delay > 0 {
+			return delay, nil
+		}
+
+		// The bucket is full again at the new TAT. The TTL is rounded up to the second, the precision of the stores.
+		ttl := newTAT.Sub(now).Truncate(time.Second) + time.Second
+		value := []byte(strconv.FormatInt(newTAT.UnixNano(), 10))
+		_, _, err = c.store.AtomicPut(key, value, previous, &store.WriteOptions{TTL: ttl})
+		switch err {
+		case nil:
+			return 0, nil
+		case store.ErrKeyModified, store.ErrKeyExists:
+			continue
+		default:
+			return 0, err
+		}
+	}
+
+	return 0, errTooManyConflicts
+}
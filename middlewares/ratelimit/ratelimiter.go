@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+	"github.com/vulcand/oxy/utils"
+)
+
+// RateLimiter is a middleware limiting the rate of the requests of each source with token buckets kept by a Counter.
+// It behaves like the oxy rate limiter, but the buckets can be shared by several Traefik nodes.
+type RateLimiter struct {
+	next      http.Handler
+	extractor utils.SourceExtractor
+	counter   Counter
+	prefix    string
+	rates     []rate
+}
+
+type rate struct {
+	name     string
+	interval time.Duration
+	burst    int64
+}
+
+// New creates a new RateLimiter. The keys of the buckets in the counter start with the given prefix.
+func New(next http.Handler, extractor utils.SourceExtractor, rateSet map[string]*types.Rate, counter Counter, prefix string) (*RateLimiter, error) {
+	var rates []rate
+	for name, r := range rateSet {
+		period := time.Duration(r.Period)
+		if period <= 0 {
+			return nil, fmt.Errorf("invalid period for rate %s: %v, must be > 0", name, period)
+		}
+		if r.Average <= 0 {
+			return nil, fmt.Errorf("invalid average for rate %s: %d, must be > 0", name, r.Average)
+		}
+		if r.Burst <= 0 {
+			return nil, fmt.Errorf("invalid burst for rate %s: %d, must be > 0", name, r.Burst)
+		}
+		rates = append(rates, rate{name: name, interval: period / time.Duration(r.Average), burst: r.Burst})
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].name < rates[j].name
+	})
+
+	return &RateLimiter{
+		next:      next,
+		extractor: extractor,
+		counter:   counter,
+		prefix:    prefix,
+		rates:     rates,
+	}, nil
+}
+
+func (rl *RateLimiter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	source, _, err := rl.extractor.Extract(req)
+	if err != nil {
+		utils.DefaultHandler.ServeHTTP(rw, req, err)
+		return
+	}
+
+	for _, r := range rl.rates {
+		key := rl.prefix + "/" + url.PathEscape(r.name) + "/" + url.PathEscape(source)
+
+		delay, err := rl.counter.Take(key, r.interval, r.burst)
+		if err != nil {
+			// The requests are not limited while the counter is unavailable.
+			log.Errorf("Unable to check the rate limit %s: %v", key, err)
+			continue
+		}
+
+		if delay > 0 {
+			log.Debugf("Limiting request %s %s, rate %s reached: retry in %v", req.Method, req.URL, r.name, delay)
+			rw.Header().Set("X-Retry-In", delay.String())
+			rw.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprintf(rw, "max rate reached: retry-in %v", delay)
+			return
+		}
+	}
+
+	rl.next.ServeHTTP(rw, req)
+}
@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/utils"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	counter := NewMemoryCounter()
+	counter.now = func() time.Time { return now }
+
+	rateSet := map[string]*types.Rate{
+		"rate1": {Period: flaeg.Duration(10 * time.Second), Average: 10, Burst: 2},
+	}
+	// two nodes sharing the same counter
+	node1 := newTestRateLimiter(t, rateSet, counter)
+	node2 := newTestRateLimiter(t, rateSet, counter)
+
+	assert.Equal(t, http.StatusOK, serveSource(node1, "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, serveSource(node2, "10.0.0.1").Code)
+
+	recorder := serveSource(node1, "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "1s", recorder.Header().Get("X-Retry-In"))
+	assert.Equal(t, "max rate reached: retry-in 1s", recorder.Body.String())
+
+	// the sources are limited independently
+	assert.Equal(t, http.StatusOK, serveSource(node2, "10.0.0.2").Code)
+
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, serveSource(node2, "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serveSource(node1, "10.0.0.1").Code)
+}
+
+func TestRateLimiterMultipleRates(t *testing.T) {
+	now := time.Unix(1000, 0)
+	counter := NewMemoryCounter()
+	counter.now = func() time.Time { return now }
+
+	rl := newTestRateLimiter(t, map[string]*types.Rate{
+		"short": {Period: flaeg.Duration(time.Second), Average: 100, Burst: 100},
+		"long":  {Period: flaeg.Duration(time.Hour), Average: 3, Burst: 3},
+	}, counter)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, serveSource(rl, "10.0.0.1").Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, serveSource(rl, "10.0.0.1").Code)
+}
+
+func TestRateLimiterCounterError(t *testing.T) {
+	rl := newTestRateLimiter(t, map[string]*types.Rate{
+		"rate1": {Period: flaeg.Duration(time.Second), Average: 1, Burst: 1},
+	}, failingCounter{})
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, serveSource(rl, "10.0.0.1").Code)
+	}
+}
+
+func TestNewRateLimiterInvalidRates(t *testing.T) {
+	testCases := []struct {
+		desc string
+		rate *types.Rate
+	}{
+		{
+			desc: "no period",
+			rate: &types.Rate{Average: 1, Burst: 1},
+		},
+		{
+			desc: "no average",
+			rate: &types.Rate{Period: flaeg.Duration(time.Second), Burst: 1},
+		},
+		{
+			desc: "no burst",
+			rate: &types.Rate{Period: flaeg.Duration(time.Second), Average: 1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(http.NotFoundHandler(), utils.ExtractorFunc(nil), map[string]*types.Rate{"rate1": test.rate}, NewMemoryCounter(), "frontend")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func newTestRateLimiter(t *testing.T, rateSet map[string]*types.Rate, counter Counter) *RateLimiter {
+	t.Helper()
+
+	extractor, err := utils.NewExtractor("client.ip")
+	require.NoError(t, err)
+
+	rl, err := New(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}), extractor, rateSet, counter, "frontend")
+	require.NoError(t, err)
+	return rl
+}
+
+func serveSource(handler http.Handler, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+	req.RemoteAddr = ip + ":1234"
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+type failingCounter struct{}
+
+func (failingCounter) Take(key string, interval time.Duration, burst int64) (time.Duration, error) {
+	return 0, errors.New("unavailable")
+}
@@ -2,14 +2,40 @@ package middlewares
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"math/rand"
 	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
 )
 
+const (
+	// RetryReasonNetworkError is the reason of the retries following a network error
+	RetryReasonNetworkError = "network_error"
+
+	// RetryReasonStatusCode is the reason of the retries following a response with a retriable status code
+	RetryReasonStatusCode = "status_code"
+
+	// retryBudgetWindow is the period over which the retry budget is computed
+	retryBudgetWindow = 10 * time.Second
+
+	// retryMaxBodySize is the size of the largest request body buffered to be sent again on retries,
+	// the requests with a larger body being attempted only once
+	retryMaxBodySize = 64 * 1024
+)
+
+// idempotentMethods are the methods retried by default by a retry policy, cf RFC 7231 section 4.2.2
+var idempotentMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete}
+
 // Compile time validation that the response writer implements http interfaces correctly.
 var _ Stateful = &retryResponseWriterWithCloseNotify{}
 
@@ -18,6 +44,13 @@ type Retry struct {
 	attempts int
 	next     http.Handler
 	listener RetryListener
+
+	// the following fields are only set by a retry policy
+	statusCodes     []types.StatusRange
+	methods         map[string]bool
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	budget          *retryBudget
 }
 
 // NewRetry returns a new Retry instance
@@ -29,22 +62,94 @@ func NewRetry(attempts int, next http.Handler, listener RetryListener) *Retry {
 	}
 }
 
+// NewRetryWithPolicy returns a new Retry instance applying the retry policy of a frontend.
+// The attempts are used when the policy does not specify them.
+func NewRetryWithPolicy(config *types.Retry, attempts int, next http.Handler, listener RetryListener) (*Retry, error) {
+	statusCodes, err := config.StatusCodes.Ranges()
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Budget < 0 {
+		return nil, fmt.Errorf("invalid retry budget %v, must be positive", config.Budget)
+	}
+
+	retry := NewRetry(attempts, next, listener)
+	if config.Attempts > 0 {
+		retry.attempts = config.Attempts
+	}
+	retry.statusCodes = statusCodes
+	retry.initialInterval = time.Duration(config.InitialInterval)
+	retry.maxInterval = time.Duration(config.MaxInterval)
+
+	methods := config.Methods
+	if len(methods) == 0 {
+		methods = idempotentMethods
+	}
+	retry.methods = make(map[string]bool)
+	for _, method := range methods {
+		retry.methods[strings.ToUpper(strings.TrimSpace(method))] = true
+	}
+
+	if config.Budget > 0 {
+		retry.budget = newRetryBudget(config.Budget)
+	}
+
+	return retry, nil
+}
+
 func (retry *Retry) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
-	// if we might make multiple attempts, swap the body for an ioutil.NopCloser
-	// cf https://github.com/containous/traefik/issues/1008
-	if retry.attempts > 1 {
+	maxAttempts := retry.attempts
+	if retry.methods != nil && !retry.methods[r.Method] {
+		maxAttempts = 1
+	}
+
+	// if we might make multiple attempts, buffer the body to send it again on each attempt,
+	// and swap it for an ioutil.NopCloser cf https://github.com/containous/traefik/issues/1008
+	var replayBody []byte
+	if maxAttempts > 1 {
 		body := r.Body
 		defer body.Close()
 		r.Body = ioutil.NopCloser(body)
+
+		if body != http.NoBody && r.ContentLength != 0 {
+			content, err := ioutil.ReadAll(io.LimitReader(body, retryMaxBodySize+1))
+			if err != nil {
+				log.Debugf("Error reading the body of the request %v: %v", r.URL, err)
+				http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+
+			if len(content) > retryMaxBodySize {
+				maxAttempts = 1
+				r.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(content), body))
+			} else {
+				replayBody = content
+			}
+		}
+	}
+
+	if retry.budget != nil {
+		retry.budget.addRequest()
 	}
 
 	attempts := 1
 	for {
+		if replayBody != nil {
+			r.Body = ioutil.NopCloser(bytes.NewReader(replayBody))
+		}
+
 		netErrorOccurred := false
 		// We pass in a pointer to netErrorOccurred so that we can set it to true on network errors
 		// when proxying the HTTP requests to the backends. This happens in the custom RecordingErrorHandler.
 		newCtx := context.WithValue(r.Context(), defaultNetErrCtxKey, &netErrorOccurred)
-		retryResponseWriter := newRetryResponseWriter(rw, attempts >= retry.attempts, &netErrorOccurred)
+		retryResponseWriter := newRetryResponseWriter(rw, attempts >= maxAttempts, &netErrorOccurred)
+		if retry.methods != nil {
+			retryResponseWriter.setRetriableStatus(retry.isRetriableStatus)
+		}
+		if retry.budget != nil {
+			retryResponseWriter.setBudget(retry.budget)
+		}
 
 		retry.next.ServeHTTP(retryResponseWriter, r.WithContext(newCtx))
 		if !retryResponseWriter.ShouldRetry() {
@@ -53,7 +158,93 @@ func (retry *Retry) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 
 		attempts++
 		log.Debugf("New attempt %d for request: %v", attempts, r.URL)
-		retry.listener.Retried(r, attempts)
+		retry.listener.Retried(r, attempts, retryResponseWriter.RetryReason())
+
+		if interval := retry.backOff(attempts - 1); interval > 0 {
+			select {
+			case <-time.After(interval):
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+func (retry *Retry) isRetriableStatus(code int) bool {
+	for _, statusRange := range retry.statusCodes {
+		if statusRange.Contains(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// backOff returns the interval to wait before the given retry, starting at 1.
+// The interval grows exponentially, and half of it is randomized to spread the retries.
+func (retry *Retry) backOff(retryNumber int) time.Duration {
+	if retry.initialInterval <= 0 {
+		return 0
+	}
+
+	interval := retry.initialInterval
+	for i := 1; i < retryNumber && (retry.maxInterval <= 0 || interval < retry.maxInterval); i++ {
+		interval *= 2
+	}
+	if retry.maxInterval > 0 && interval > retry.maxInterval {
+		interval = retry.maxInterval
+	}
+
+	return interval/2 + time.Duration(rand.Int63n(int64(interval/2)+1))
+}
+
+// retryBudget limits the ratio of the retries to the requests, over the current and the previous windows.
+type retryBudget struct {
+	ratio float64
+
+	mu           sync.Mutex
+	windowStart  time.Time
+	requests     float64
+	retries      float64
+	prevRequests float64
+	prevRetries  float64
+}
+
+func newRetryBudget(ratio float64) *retryBudget {
+	return &retryBudget{ratio: ratio, windowStart: time.Now()}
+}
+
+func (b *retryBudget) addRequest() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rotate()
+	b.requests++
+}
+
+// withdraw records a retry and returns true if the budget allows it.
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rotate()
+	if b.retries+b.prevRetries >= b.ratio*(b.requests+b.prevRequests) {
+		return false
+	}
+	b.retries++
+	return true
+}
+
+func (b *retryBudget) rotate() {
+	elapsed := time.Since(b.windowStart)
+	switch {
+	case elapsed >= 2*retryBudgetWindow:
+		b.prevRequests, b.prevRetries = 0, 0
+		b.requests, b.retries = 0, 0
+		b.windowStart = time.Now()
+	case elapsed >= retryBudgetWindow:
+		b.prevRequests, b.prevRetries = b.requests, b.retries
+		b.requests, b.retries = 0, 0
+		b.windowStart = b.windowStart.Add(retryBudgetWindow)
 	}
 }
 
@@ -86,7 +277,8 @@ func (DefaultNetErrorRecorder) Record(ctx context.Context) {
 type RetryListener interface {
 	// Retried will be called when a retry happens, with the request attempt passed to it.
 	// For the first retry this will be attempt 2.
-	Retried(req *http.Request, attempt int)
+	// The reason is either RetryReasonNetworkError or RetryReasonStatusCode.
+	Retried(req *http.Request, attempt int, reason string)
 }
 
 // RetryListeners is a convenience type to construct a list of RetryListener and notify
@@ -94,9 +286,9 @@ type RetryListener interface {
 type RetryListeners []RetryListener
 
 // Retried exists to implement the RetryListener interface. It calls Retried on each of its slice entries.
-func (l RetryListeners) Retried(req *http.Request, attempt int) {
+func (l RetryListeners) Retried(req *http.Request, attempt int, reason string) {
 	for _, retryListener := range l {
-		retryListener.Retried(req, attempt)
+		retryListener.Retried(req, attempt, reason)
 	}
 }
 
@@ -104,6 +296,9 @@ type retryResponseWriter interface {
 	http.ResponseWriter
 	http.Flusher
 	ShouldRetry() bool
+	RetryReason() string
+	setRetriableStatus(retriableStatus func(code int) bool)
+	setBudget(budget *retryBudget)
 }
 
 func newRetryResponseWriter(rw http.ResponseWriter, attemptsExhausted bool, netErrorOccured *bool) retryResponseWriter {
@@ -122,22 +317,68 @@ type retryResponseWriterWithoutCloseNotify struct {
 	responseWriter    http.ResponseWriter
 	attemptsExhausted bool
 	netErrorOccured   *bool
+
+	// retriableStatus is set by a retry policy,
+	// the headers are then held until the status code is known, for the failed attempts not to leak them.
+	retriableStatus func(code int) bool
+	headers         http.Header
+	statusRetry     bool
+	written         bool
+
+	// budget is consumed once, the first time a retry is needed
+	budget      *retryBudget
+	budgetSpent *bool
+}
+
+func (rr *retryResponseWriterWithoutCloseNotify) setRetriableStatus(retriableStatus func(code int) bool) {
+	rr.retriableStatus = retriableStatus
+	rr.headers = make(http.Header)
+}
+
+func (rr *retryResponseWriterWithoutCloseNotify) setBudget(budget *retryBudget) {
+	rr.budget = budget
 }
 
 func (rr *retryResponseWriterWithoutCloseNotify) ShouldRetry() bool {
-	return *rr.netErrorOccured == true && !rr.attemptsExhausted
+	if !*rr.netErrorOccured && !rr.statusRetry || rr.attemptsExhausted {
+		return false
+	}
+	if rr.budget == nil {
+		return true
+	}
+	if rr.budgetSpent == nil {
+		allowed := rr.budget.withdraw()
+		rr.budgetSpent = &allowed
+	}
+	return *rr.budgetSpent
+}
+
+func (rr *retryResponseWriterWithoutCloseNotify) RetryReason() string {
+	if rr.statusRetry {
+		return RetryReasonStatusCode
+	}
+	return RetryReasonNetworkError
 }
 
 func (rr *retryResponseWriterWithoutCloseNotify) Header() http.Header {
 	if rr.ShouldRetry() {
 		return make(http.Header)
 	}
+	if rr.headers != nil && !rr.written {
+		return rr.headers
+	}
 	return rr.responseWriter.Header()
 }
 
 func (rr *retryResponseWriterWithoutCloseNotify) Write(buf []byte) (int, error) {
 	if rr.ShouldRetry() {
-		return 0, nil
+		return len(buf), nil
+	}
+	if rr.headers != nil && !rr.written {
+		rr.WriteHeader(http.StatusOK)
+		if rr.ShouldRetry() {
+			return len(buf), nil
+		}
 	}
 	return rr.responseWriter.Write(buf)
 }
@@ -146,6 +387,20 @@ func (rr *retryResponseWriterWithoutCloseNotify) WriteHeader(code int) {
 	if rr.ShouldRetry() {
 		return
 	}
+	if rr.retriableStatus != nil && !rr.written && rr.retriableStatus(code) {
+		rr.statusRetry = true
+		if rr.ShouldRetry() {
+			return
+		}
+	}
+
+	if rr.headers != nil && !rr.written {
+		header := rr.responseWriter.Header()
+		for name, values := range rr.headers {
+			header[name] = values
+		}
+	}
+	rr.written = true
 	rr.responseWriter.WriteHeader(code)
 }
 
@@ -154,6 +409,15 @@ func (rr *retryResponseWriterWithoutCloseNotify) Hijack() (net.Conn, *bufio.Read
 }
 
 func (rr *retryResponseWriterWithoutCloseNotify) Flush() {
+	if rr.ShouldRetry() {
+		return
+	}
+	if rr.headers != nil && !rr.written {
+		rr.WriteHeader(http.StatusOK)
+		if rr.ShouldRetry() {
+			return
+		}
+	}
 	if flusher, ok := rr.responseWriter.(http.Flusher); ok {
 		flusher.Flush()
 	}
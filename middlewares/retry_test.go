@@ -6,7 +6,14 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRetry(t *testing.T) {
@@ -62,6 +69,230 @@ func TestRetry(t *testing.T) {
 	}
 }
 
+func TestRetryWithPolicy(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          *types.Retry
+		method          string
+		failAtCalls     []int
+		netFailAtCalls  []int
+		expectedStatus  int
+		expectedBody    string
+		expectedCalls   int
+		expectedReasons []string
+	}{
+		{
+			desc:            "retries on a retriable status code",
+			config:          &types.Retry{Attempts: 3, StatusCodes: types.StatusCodes{"502", "503-504"}},
+			method:          http.MethodGet,
+			failAtCalls:     []int{1, 2},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "OK",
+			expectedCalls:   3,
+			expectedReasons: []string{RetryReasonStatusCode, RetryReasonStatusCode},
+		},
+		{
+			desc:            "returns the last response when the attempts are exhausted",
+			config:          &types.Retry{Attempts: 2, StatusCodes: types.StatusCodes{"503"}},
+			method:          http.MethodGet,
+			failAtCalls:     []int{1, 2},
+			expectedStatus:  http.StatusServiceUnavailable,
+			expectedBody:    "unavailable 2",
+			expectedCalls:   2,
+			expectedReasons: []string{RetryReasonStatusCode},
+		},
+		{
+			desc:           "does not retry a status code which is not retriable",
+			config:         &types.Retry{Attempts: 3, StatusCodes: types.StatusCodes{"502"}},
+			method:         http.MethodGet,
+			failAtCalls:    []int{1},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "unavailable 1",
+			expectedCalls:  1,
+		},
+		{
+			desc:            "retries on a network error",
+			config:          &types.Retry{Attempts: 3},
+			method:          http.MethodGet,
+			netFailAtCalls:  []int{1},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "OK",
+			expectedCalls:   2,
+			expectedReasons: []string{RetryReasonNetworkError},
+		},
+		{
+			desc:           "does not retry a non idempotent method",
+			config:         &types.Retry{Attempts: 3, StatusCodes: types.StatusCodes{"503"}},
+			method:         http.MethodPost,
+			netFailAtCalls: []int{1},
+			expectedStatus: http.StatusBadGateway,
+			expectedCalls:  1,
+		},
+		{
+			desc:            "retries the specified methods",
+			config:          &types.Retry{Attempts: 3, StatusCodes: types.StatusCodes{"503"}, Methods: []string{"post"}},
+			method:          http.MethodPost,
+			failAtCalls:     []int{1},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "OK",
+			expectedCalls:   2,
+			expectedReasons: []string{RetryReasonStatusCode},
+		},
+		{
+			desc:            "waits between the retries",
+			config:          &types.Retry{Attempts: 2, StatusCodes: types.StatusCodes{"503"}, InitialInterval: flaeg.Duration(time.Millisecond)},
+			method:          http.MethodGet,
+			failAtCalls:     []int{1},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "OK",
+			expectedCalls:   2,
+			expectedReasons: []string{RetryReasonStatusCode},
+		},
+		{
+			desc:            "stops retrying when the budget is exhausted",
+			config:          &types.Retry{Attempts: 5, StatusCodes: types.StatusCodes{"503"}, Budget: 0.5},
+			method:          http.MethodGet,
+			failAtCalls:     []int{1, 2, 3},
+			expectedStatus:  http.StatusServiceUnavailable,
+			expectedBody:    "unavailable 2",
+			expectedCalls:   2,
+			expectedReasons: []string{RetryReasonStatusCode},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := &statusFailingHTTPHandler{
+				failAtCalls:      test.failAtCalls,
+				netFailAtCalls:   test.netFailAtCalls,
+				netErrorRecorder: &DefaultNetErrorRecorder{},
+			}
+			listener := &countingRetryListener{}
+
+			retry, err := NewRetryWithPolicy(test.config, 1, next, listener)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(test.method, "http://localhost/", nil)
+			retry.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, test.expectedCalls, next.callNumber)
+			assert.Equal(t, test.expectedReasons, listener.reasons)
+			assert.Len(t, recorder.Header()["X-Attempt"], 1)
+		})
+	}
+}
+
+func TestRetryWithPolicyBody(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		body           string
+		expectedStatus int
+		expectedCalls  int
+	}{
+		{
+			desc:           "replays the body on retries",
+			body:           "foo",
+			expectedStatus: http.StatusOK,
+			expectedCalls:  2,
+		},
+		{
+			desc:           "does not retry a request with a large body",
+			body:           strings.Repeat("a", retryMaxBodySize+1),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCalls:  1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var bodies []string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+				bodies = append(bodies, string(body))
+
+				if len(bodies) == 1 {
+					rw.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			retry, err := NewRetryWithPolicy(&types.Retry{Attempts: 3, StatusCodes: types.StatusCodes{"503"}}, 1, next, &countingRetryListener{})
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "http://localhost/", strings.NewReader(test.body))
+			retry.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			require.Len(t, bodies, test.expectedCalls)
+			for _, body := range bodies {
+				assert.Equal(t, test.body, body, "each attempt should receive the whole body")
+			}
+		})
+	}
+}
+
+func TestNewRetryWithPolicyInvalid(t *testing.T) {
+	_, err := NewRetryWithPolicy(&types.Retry{StatusCodes: types.StatusCodes{"foo"}}, 1, http.NotFoundHandler(), RetryListeners{})
+	assert.Error(t, err)
+
+	_, err = NewRetryWithPolicy(&types.Retry{Budget: -1}, 1, http.NotFoundHandler(), RetryListeners{})
+	assert.Error(t, err)
+}
+
+func TestRetryBackOff(t *testing.T) {
+	retry := &Retry{initialInterval: 100 * time.Millisecond, maxInterval: 300 * time.Millisecond}
+
+	testCases := []struct {
+		retryNumber int
+		min         time.Duration
+		max         time.Duration
+	}{
+		{retryNumber: 1, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{retryNumber: 2, min: 100 * time.Millisecond, max: 200 * time.Millisecond},
+		{retryNumber: 3, min: 150 * time.Millisecond, max: 300 * time.Millisecond},
+		{retryNumber: 10, min: 150 * time.Millisecond, max: 300 * time.Millisecond},
+	}
+
+	for _, test := range testCases {
+		interval := retry.backOff(test.retryNumber)
+		assert.True(t, interval >= test.min && interval <= test.max, "retry %d: interval %s not in [%s, %s]", test.retryNumber, interval, test.min, test.max)
+	}
+
+	assert.Equal(t, time.Duration(0), (&Retry{}).backOff(1))
+}
+
+func TestRetryBudget(t *testing.T) {
+	budget := newRetryBudget(0.2)
+
+	for i := 0; i < 10; i++ {
+		budget.addRequest()
+	}
+	assert.True(t, budget.withdraw())
+	assert.True(t, budget.withdraw())
+	assert.False(t, budget.withdraw())
+
+	// the previous window still counts
+	budget.windowStart = budget.windowStart.Add(-retryBudgetWindow)
+	assert.False(t, budget.withdraw())
+
+	// both windows are expired
+	budget.windowStart = budget.windowStart.Add(-2 * retryBudgetWindow)
+	budget.addRequest()
+	assert.True(t, budget.withdraw())
+}
+
 func TestDefaultNetErrorRecorderSuccess(t *testing.T) {
 	boolNetErrorOccurred := false
 	recorder := DefaultNetErrorRecorder{}
@@ -93,8 +324,8 @@ func TestRetryListeners(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	retryListeners := RetryListeners{&countingRetryListener{}, &countingRetryListener{}}
 
-	retryListeners.Retried(req, 1)
-	retryListeners.Retried(req, 1)
+	retryListeners.Retried(req, 1, RetryReasonNetworkError)
+	retryListeners.Retried(req, 1, RetryReasonNetworkError)
 
 	for _, retryListener := range retryListeners {
 		listener := retryListener.(*countingRetryListener)
@@ -126,13 +357,46 @@ func (handler *networkFailingHTTPHandler) ServeHTTP(w http.ResponseWriter, r *ht
 	w.WriteHeader(http.StatusOK)
 }
 
+// statusFailingHTTPHandler is an http.Handler implementation failing with a 503 status code or with a network error at the given calls.
+type statusFailingHTTPHandler struct {
+	netErrorRecorder NetErrorRecorder
+	failAtCalls      []int
+	netFailAtCalls   []int
+	callNumber       int
+}
+
+func (handler *statusFailingHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler.callNumber++
+	w.Header().Add("X-Attempt", fmt.Sprint(handler.callNumber))
+
+	for _, failAtCall := range handler.netFailAtCalls {
+		if handler.callNumber == failAtCall {
+			handler.netErrorRecorder.Record(r.Context())
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+	}
+
+	for _, failAtCall := range handler.failAtCalls {
+		if handler.callNumber == failAtCall {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "unavailable %d", handler.callNumber)
+			return
+		}
+	}
+
+	w.Write([]byte("OK"))
+}
+
 // countingRetryListener is a RetryListener implementation to count the times the Retried fn is called.
 type countingRetryListener struct {
 	timesCalled int
+	reasons     []string
 }
 
-func (l *countingRetryListener) Retried(req *http.Request, attempt int) {
+func (l *countingRetryListener) Retried(req *http.Request, attempt int, reason string) {
 	l.timesCalled++
+	l.reasons = append(l.reasons, reason)
 }
 
 func TestRetryWithFlush(t *testing.T) {
@@ -0,0 +1,172 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/middlewares/tracing"
+	"github.com/containous/traefik/types"
+)
+
+// Headers of the TLS client certificate forwarded to the backends
+const (
+	TLSClientCertHeader            = "X-Forwarded-Ssl-Client-Cert"
+	TLSClientCertSubjectCNHeader   = TLSClientCertHeader + "-Subject-Cn"
+	TLSClientCertSANsHeader        = TLSClientCertHeader + "-Sans"
+	TLSClientCertSerialHeader      = TLSClientCertHeader + "-Serial"
+	TLSClientCertIssuerDNHeader    = TLSClientCertHeader + "-Issuer-Dn"
+	TLSClientCertNotBeforeHeader   = TLSClientCertHeader + "-Not-Before"
+	TLSClientCertNotAfterHeader    = TLSClientCertHeader + "-Not-After"
+	TLSClientCertFingerprintHeader = TLSClientCertHeader + "-Fingerprint"
+)
+
+// tlsClientCertFields maps the certificate fields to their header and value
+var tlsClientCertFields = map[string]struct {
+	header string
+	value  func(cert *x509.Certificate) string
+}{
+	"subjectcn": {TLSClientCertSubjectCNHeader, func(cert *x509.Certificate) string {
+		return cert.Subject.CommonName
+	}},
+	"sans": {TLSClientCertSANsHeader, func(cert *x509.Certificate) string {
+		return strings.Join(certificateSANs(cert), ",")
+	}},
+	"serial": {TLSClientCertSerialHeader, func(cert *x509.Certificate) string {
+		return strings.ToUpper(cert.SerialNumber.Text(16))
+	}},
+	"issuerdn": {TLSClientCertIssuerDNHeader, func(cert *x509.Certificate) string {
+		return cert.Issuer.String()
+	}},
+	"notbefore": {TLSClientCertNotBeforeHeader, func(cert *x509.Certificate) string {
+		return cert.NotBefore.UTC().Format(time.RFC3339)
+	}},
+	"notafter": {TLSClientCertNotAfterHeader, func(cert *x509.Certificate) string {
+		return cert.NotAfter.UTC().Format(time.RFC3339)
+	}},
+	"fingerprint": {TLSClientCertFingerprintHeader, func(cert *x509.Certificate) string {
+		fingerprint := sha256.Sum256(cert.Raw)
+		return hex.EncodeToString(fingerprint[:])
+	}},
+}
+
+// TLSClientCert is a middleware forwarding the selected fields of the TLS client certificate to the backends,
+// and rejecting the certificates matching none of the allowed subjects and SANs
+type TLSClientCert struct {
+	pem             bool
+	fields          []string
+	allowedSubjects []*regexp.Regexp
+	allowedSANs     []*regexp.Regexp
+}
+
+// NewTLSClientCert builds a new TLSClientCert given a config
+func NewTLSClientCert(config *types.TLSClientCert) (*TLSClientCert, error) {
+	tcc := &TLSClientCert{pem: config.PEM}
+
+	for _, field := range config.Fields {
+		name := strings.ToLower(strings.TrimSpace(field))
+		if _, ok := tlsClientCertFields[name]; !ok {
+			return nil, fmt.Errorf("unknown TLS client certificate field %q", field)
+		}
+		tcc.fields = append(tcc.fields, name)
+	}
+
+	var err error
+	tcc.allowedSubjects, err = compilePatterns(config.AllowedSubjects)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allowed subject: %v", err)
+	}
+	tcc.allowedSANs, err = compilePatterns(config.AllowedSANs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allowed SAN: %v", err)
+	}
+
+	return tcc, nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var regexps []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, err
+		}
+		regexps = append(regexps, re)
+	}
+	return regexps, nil
+}
+
+func (tcc *TLSClientCert) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	// The certificate headers sent by the client are never trusted
+	r.Header.Del(TLSClientCertHeader)
+	for _, field := range tlsClientCertFields {
+		r.Header.Del(field.header)
+	}
+
+	var cert *x509.Certificate
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		cert = r.TLS.PeerCertificates[0]
+	}
+
+	if len(tcc.allowedSubjects) > 0 || len(tcc.allowedSANs) > 0 {
+		if cert == nil {
+			tracing.SetErrorAndDebugLog(r, "no TLS client certificate - rejecting")
+			reject(rw)
+			return
+		}
+		if !tcc.allowed(cert) {
+			tracing.SetErrorAndDebugLog(r, "TLS client certificate %q matched none of the allowed subjects and SANs - rejecting", cert.Subject.CommonName)
+			reject(rw)
+			return
+		}
+	}
+
+	if cert != nil {
+		if tcc.pem {
+			r.Header.Set(TLSClientCertHeader, url.QueryEscape(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))))
+		}
+		for _, name := range tcc.fields {
+			field := tlsClientCertFields[name]
+			r.Header.Set(field.header, field.value(cert))
+		}
+	}
+
+	next.ServeHTTP(rw, r)
+}
+
+func (tcc *TLSClientCert) allowed(cert *x509.Certificate) bool {
+	for _, re := range tcc.allowedSubjects {
+		if re.MatchString(cert.Subject.CommonName) {
+			return true
+		}
+	}
+	for _, san := range certificateSANs(cert) {
+		for _, re := range tcc.allowedSANs {
+			if re.MatchString(san) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// certificateSANs returns the DNS names, email addresses, IP addresses and URIs of a certificate
+func certificateSANs(cert *x509.Certificate) []string {
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
@@ -0,0 +1,232 @@
+package middlewares
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/testhelpers"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSClientCert(t *testing.T) {
+	cert := createClientCertificate(t)
+
+	testCases := []struct {
+		desc               string
+		config             types.TLSClientCert
+		noCertificate      bool
+		requestHeaders     map[string]string
+		expectedStatusCode int
+		expectedHeaders    map[string]string
+	}{
+		{
+			desc: "selected fields",
+			config: types.TLSClientCert{
+				Fields: []string{"subjectCN", "sans", "serial", "issuerDN", "notBefore", "notAfter"},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				TLSClientCertSubjectCNHeader:   "client.example.com",
+				TLSClientCertSANsHeader:        "client.example.com,api.example.com,client@example.com,10.0.0.1,spiffe://example.com/client",
+				TLSClientCertSerialHeader:      "1F2A",
+				TLSClientCertIssuerDNHeader:    "CN=client.example.com,O=Example",
+				TLSClientCertNotBeforeHeader:   "2018-01-01T00:00:00Z",
+				TLSClientCertNotAfterHeader:    "2038-01-01T00:00:00Z",
+				TLSClientCertFingerprintHeader: "",
+				TLSClientCertHeader:            "",
+			},
+		},
+		{
+			desc: "fingerprint and PEM",
+			config: types.TLSClientCert{
+				PEM:    true,
+				Fields: []string{"fingerprint"},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				TLSClientCertSubjectCNHeader: "",
+			},
+		},
+		{
+			desc:   "spoofed headers removed",
+			config: types.TLSClientCert{Fields: []string{"subjectCN"}},
+			requestHeaders: map[string]string{
+				TLSClientCertSubjectCNHeader:  "admin",
+				TLSClientCertSerialHeader:     "1",
+				"X-Forwarded-Ssl-Client-Cert": "spoofed",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				TLSClientCertSubjectCNHeader:  "client.example.com",
+				TLSClientCertSerialHeader:     "",
+				"X-Forwarded-Ssl-Client-Cert": "",
+			},
+		},
+		{
+			desc:   "spoofed headers removed without certificate",
+			config: types.TLSClientCert{Fields: []string{"subjectCN"}},
+			requestHeaders: map[string]string{
+				TLSClientCertSubjectCNHeader: "admin",
+			},
+			noCertificate:      true,
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				TLSClientCertSubjectCNHeader: "",
+			},
+		},
+		{
+			desc:               "allowed subject",
+			config:             types.TLSClientCert{AllowedSubjects: []string{`.*\.example\.com`}},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "subject pattern anchored",
+			config:             types.TLSClientCert{AllowedSubjects: []string{`client`}},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			desc: "allowed SAN",
+			config: types.TLSClientCert{
+				AllowedSubjects: []string{"admin"},
+				AllowedSANs:     []string{"spiffe://example.com/.*"},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "no allowed SAN",
+			config:             types.TLSClientCert{AllowedSANs: []string{`.*\.example\.org`}},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			desc:               "no certificate",
+			config:             types.TLSClientCert{AllowedSubjects: []string{".*"}},
+			noCertificate:      true,
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tcc, err := NewTLSClientCert(&test.config)
+			require.NoError(t, err)
+
+			var forwarded http.Header
+			next := func(rw http.ResponseWriter, req *http.Request) {
+				forwarded = req.Header
+			}
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "https://foo.bar/", nil)
+			for name, value := range test.requestHeaders {
+				req.Header.Set(name, value)
+			}
+			if !test.noCertificate {
+				req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+			}
+
+			recorder := httptest.NewRecorder()
+			tcc.ServeHTTP(recorder, req, next)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+			if test.expectedStatusCode != http.StatusOK {
+				assert.Nil(t, forwarded)
+				return
+			}
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, forwarded.Get(name), name)
+			}
+			if test.config.PEM {
+				pemCert, err := url.QueryUnescape(forwarded.Get(TLSClientCertHeader))
+				require.NoError(t, err)
+				assert.True(t, strings.HasPrefix(pemCert, "-----BEGIN CERTIFICATE-----"))
+				assert.Len(t, forwarded.Get(TLSClientCertFingerprintHeader), 64)
+			}
+		})
+	}
+}
+
+func TestNewTLSClientCert(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        types.TLSClientCert
+		expectedError bool
+	}{
+		{
+			desc:   "valid",
+			config: types.TLSClientCert{Fields: []string{"SubjectCN", "fingerprint"}, AllowedSubjects: []string{"client"}},
+		},
+		{
+			desc:          "unknown field",
+			config:        types.TLSClientCert{Fields: []string{"subject"}},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid subject pattern",
+			config:        types.TLSClientCert{AllowedSubjects: []string{"("}},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid SAN pattern",
+			config:        types.TLSClientCert{AllowedSANs: []string{"["}},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewTLSClientCert(&test.config)
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func createClientCertificate(t *testing.T) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	uri, err := url.Parse("spiffe://example.com/client")
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:   big.NewInt(0x1f2a),
+		Subject:        pkix.Name{CommonName: "client.example.com", Organization: []string{"Example"}},
+		NotBefore:      time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:       time.Date(2038, 1, 1, 0, 0, 0, 0, time.UTC),
+		DNSNames:       []string{"client.example.com", "api.example.com"},
+		EmailAddresses: []string{"client@example.com"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+		URIs:           []*url.URL{uri},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
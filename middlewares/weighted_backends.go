@@ -0,0 +1,113 @@
+package middlewares
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/containous/traefik/healthcheck"
+)
+
+// WeightedBackends is a middleware spreading the requests of a frontend on several backends,
+// proportionally to their weights. The backends without any active server are skipped.
+// When a cookie name is set, a client keeps being served by the backend of its first request.
+type WeightedBackends struct {
+	cookieName string
+
+	lock     sync.Mutex
+	backends []*weightedBackend
+}
+
+type weightedBackend struct {
+	name          string
+	handler       http.Handler
+	lb            healthcheck.LoadBalancer
+	weight        int
+	currentWeight int
+}
+
+// NewWeightedBackends creates a new WeightedBackends middleware.
+// Sticky sessions are disabled when cookieName is empty.
+func NewWeightedBackends(cookieName string) *WeightedBackends {
+	return &WeightedBackends{cookieName: cookieName}
+}
+
+// AddBackend adds a backend handler with the given weight.
+// The load balancer of the backend, if any, is used to skip the backend when it has no active server.
+func (w *WeightedBackends) AddBackend(name string, handler http.Handler, lb healthcheck.LoadBalancer, weight int) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.backends = append(w.backends, &weightedBackend{
+		name:    name,
+		handler: handler,
+		lb:      lb,
+		weight:  weight,
+	})
+}
+
+func (w *WeightedBackends) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	backend := w.stickyBackend(r)
+	if backend == nil {
+		backend = w.nextBackend()
+		if backend != nil && w.cookieName != "" {
+			http.SetCookie(rw, &http.Cookie{Name: w.cookieName, Value: backend.name, Path: "/"})
+		}
+	}
+
+	if backend == nil {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		rw.Write([]byte(http.StatusText(http.StatusServiceUnavailable)))
+		return
+	}
+
+	backend.handler.ServeHTTP(rw, r)
+}
+
+func (w *WeightedBackends) stickyBackend(r *http.Request) *weightedBackend {
+	if w.cookieName == "" {
+		return nil
+	}
+
+	cookie, err := r.Cookie(w.cookieName)
+	if err != nil {
+		return nil
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for _, backend := range w.backends {
+		if backend.name == cookie.Value && backend.isAvailable() {
+			return backend
+		}
+	}
+	return nil
+}
+
+// nextBackend selects the next available backend with a smooth weighted round robin.
+func (w *WeightedBackends) nextBackend() *weightedBackend {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var selected *weightedBackend
+	total := 0
+	for _, backend := range w.backends {
+		if !backend.isAvailable() {
+			continue
+		}
+		backend.currentWeight += backend.weight
+		total += backend.weight
+		if selected == nil || backend.currentWeight > selected.currentWeight {
+			selected = backend
+		}
+	}
+
+	if selected != nil {
+		selected.currentWeight -= total
+	}
+	return selected
+}
+
+func (b *weightedBackend) isAvailable() bool {
+	return b.weight > 0 && (b.lb == nil || len(b.lb.Servers()) > 0)
+}
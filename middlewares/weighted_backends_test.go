@@ -0,0 +1,115 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestWeightedBackends(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		weights    map[string]int
+		emptyLB    string
+		requests   int
+		wantCounts map[string]int
+	}{
+		{
+			desc:       "requests spread by weight",
+			weights:    map[string]int{"v1": 3, "v2": 1},
+			requests:   8,
+			wantCounts: map[string]int{"v1": 6, "v2": 2},
+		},
+		{
+			desc:       "backend with a zero weight",
+			weights:    map[string]int{"v1": 1, "v2": 0},
+			requests:   4,
+			wantCounts: map[string]int{"v1": 4},
+		},
+		{
+			desc:       "backend without active server",
+			weights:    map[string]int{"v1": 1, "v2": 1},
+			emptyLB:    "v2",
+			requests:   4,
+			wantCounts: map[string]int{"v1": 4},
+		},
+		{
+			desc:       "no available backend",
+			weights:    map[string]int{"v1": 0},
+			requests:   2,
+			wantCounts: map[string]int{http.StatusText(http.StatusServiceUnavailable): 2},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewWeightedBackends("")
+			for _, name := range []string{"v1", "v2"} {
+				weight, ok := test.weights[name]
+				if !ok {
+					continue
+				}
+				handler.AddBackend(name, newNamedHandler(name), newTestLoadBalancer(t, name != test.emptyLB), weight)
+			}
+
+			counts := map[string]int{}
+			for i := 0; i < test.requests; i++ {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+				counts[recorder.Body.String()]++
+			}
+
+			assert.Equal(t, test.wantCounts, counts)
+		})
+	}
+}
+
+func TestWeightedBackendsSticky(t *testing.T) {
+	handler := NewWeightedBackends("weighted")
+	handler.AddBackend("v1", newNamedHandler("v1"), nil, 1)
+	handler.AddBackend("v2", newNamedHandler("v2"), nil, 1)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	first := recorder.Body.String()
+
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "weighted", cookies[0].Name)
+	assert.Equal(t, first, cookies[0].Value)
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+		req.AddCookie(cookies[0])
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		assert.Equal(t, first, recorder.Body.String())
+		assert.Empty(t, recorder.Result().Cookies())
+	}
+}
+
+func newNamedHandler(name string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(name))
+	})
+}
+
+func newTestLoadBalancer(t *testing.T, withServer bool) *roundrobin.RoundRobin {
+	t.Helper()
+
+	lb, err := roundrobin.New(nil)
+	require.NoError(t, err)
+	if withServer {
+		require.NoError(t, lb.UpsertServer(&url.URL{Scheme: "http", Host: "localhost:80"}))
+	}
+	return lb
+}
@@ -60,10 +60,17 @@ func (p *Provider) buildConfiguration(containersInspected []dockerData) *types.C
 		"getWhitelistSourceRange": getFuncSliceStringLabel(label.TraefikFrontendWhitelistSourceRange),
 		"getFrontendRule":         p.getFrontendRule,
 
-		"getRedirect":   getRedirect,
-		"getErrorPages": getErrorPages,
-		"getRateLimit":  getRateLimit,
-		"getHeaders":    getHeaders,
+		"getRedirect":         getRedirect,
+		"getErrorPages":       getErrorPages,
+		"getRateLimit":        getRateLimit,
+		"getHeaders":          getHeaders,
+		"getWeightedBackends": getWeightedBackends,
+		"getAccessLog":        getAccessLog,
+		"getRetry":            getRetry,
+		"getCompression":      getCompression,
+		"getCache":            getCache,
+		"getAuth":             getAuth,
+		"getTLSClientCert":    getTLSClientCert,
 
 		// Services
 		"hasServices":           hasServices,
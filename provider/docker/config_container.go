@@ -176,6 +176,13 @@ func getLoadBalancer(container dockerData) *types.LoadBalancer {
 		lb.Stickiness = &types.Stickiness{CookieName: cookieName}
 	}
 
+	if label.HasPrefix(container.Labels, label.TraefikBackendLoadBalancerConsistentHash) {
+		lb.ConsistentHash = &types.ConsistentHashing{
+			ExtractorFunc: label.GetStringValue(container.Labels, label.TraefikBackendLoadBalancerConsistentHashExtractorFunc, ""),
+			LoadFactor:    label.GetFloat64Value(container.Labels, label.TraefikBackendLoadBalancerConsistentHashLoadFactor, 0),
+		}
+	}
+
 	return lb
 }
 
@@ -208,9 +215,18 @@ func getHealthCheck(container dockerData) *types.HealthCheck {
 	interval := label.GetStringValue(container.Labels, label.TraefikBackendHealthCheckInterval, "")
 
 	return &types.HealthCheck{
-		Path:     path,
-		Port:     port,
-		Interval: interval,
+		Mode:               label.GetStringValue(container.Labels, label.TraefikBackendHealthCheckMode, ""),
+		Scheme:             label.GetStringValue(container.Labels, label.TraefikBackendHealthCheckScheme, ""),
+		Path:               path,
+		Port:               port,
+		Interval:           interval,
+		Timeout:            label.GetStringValue(container.Labels, label.TraefikBackendHealthCheckTimeout, ""),
+		Hostname:           label.GetStringValue(container.Labels, label.TraefikBackendHealthCheckHostname, ""),
+		Headers:            label.GetMapValue(container.Labels, label.TraefikBackendHealthCheckHeaders),
+		Status:             label.GetSliceStringValue(container.Labels, label.TraefikBackendHealthCheckStatus),
+		BodyRegex:          label.GetStringValue(container.Labels, label.TraefikBackendHealthCheckBodyRegex, ""),
+		HealthyThreshold:   label.GetIntValue(container.Labels, label.TraefikBackendHealthCheckHealthyThreshold, 0),
+		UnhealthyThreshold: label.GetIntValue(container.Labels, label.TraefikBackendHealthCheckUnhealthyThreshold, 0),
 	}
 }
 
@@ -250,6 +266,200 @@ func getRedirect(container dockerData) *types.Redirect {
 	return nil
 }
 
+func getWeightedBackends(container dockerData) *types.WeightedBackends {
+	value := label.GetStringValue(container.Labels, label.TraefikFrontendWeightedBackends, "")
+	if len(value) == 0 {
+		return nil
+	}
+
+	weights := label.ParseWeightedBackends(label.TraefikFrontendWeightedBackends, value)
+	if weights == nil {
+		return nil
+	}
+
+	weightedBackends := &types.WeightedBackends{Backends: make(map[string]int)}
+	for name, weight := range weights {
+		weightedBackends.Backends["backend-"+provider.Normalize(name)] = weight
+	}
+
+	if label.GetBoolValue(container.Labels, label.TraefikFrontendWeightedBackendsStickiness, false) {
+		weightedBackends.Stickiness = &types.Stickiness{
+			CookieName: label.GetStringValue(container.Labels, label.TraefikFrontendWeightedBackendsCookieName, ""),
+		}
+	}
+
+	return weightedBackends
+}
+
+func getAccessLog(container dockerData) *types.FrontendAccessLog {
+	if !label.HasPrefix(container.Labels, label.TraefikFrontendAccessLog) {
+		return nil
+	}
+
+	accessLog := &types.FrontendAccessLog{}
+
+	if label.HasPrefix(container.Labels, label.TraefikFrontendAccessLog+".filters") {
+		accessLog.Filters = &types.AccessLogFilters{
+			StatusCodes:   label.GetSliceStringValue(container.Labels, label.TraefikFrontendAccessLogFiltersStatusCodes),
+			RetryAttempts: label.GetBoolValue(container.Labels, label.TraefikFrontendAccessLogFiltersRetryAttempts, false),
+		}
+
+		minDuration := label.GetStringValue(container.Labels, label.TraefikFrontendAccessLogFiltersMinDuration, "")
+		if len(minDuration) > 0 {
+			if err := accessLog.Filters.MinDuration.Set(minDuration); err != nil {
+				log.Errorf("Invalid value for %s: %q, skipping...", label.TraefikFrontendAccessLogFiltersMinDuration, minDuration)
+			}
+		}
+	}
+
+	if label.HasPrefix(container.Labels, label.TraefikFrontendAccessLog+".fields") {
+		accessLog.Fields = &types.AccessLogFields{
+			DefaultMode: label.GetStringValue(container.Labels, label.TraefikFrontendAccessLogFieldsDefaultMode, ""),
+			Names:       getFieldNames(container.Labels, label.TraefikFrontendAccessLogFieldsNames),
+		}
+
+		if label.HasPrefix(container.Labels, label.TraefikFrontendAccessLog+".fields.headers") {
+			accessLog.Fields.Headers = &types.FieldHeaders{
+				DefaultMode: label.GetStringValue(container.Labels, label.TraefikFrontendAccessLogFieldsHeadersDefaultMode, ""),
+				Names:       getFieldNames(container.Labels, label.TraefikFrontendAccessLogFieldsHeadersNames),
+			}
+		}
+	}
+
+	return accessLog
+}
+
+// getFieldNames parses the modes of access log fields or headers, formatted as name=mode.
+func getFieldNames(labels map[string]string, labelName string) types.FieldNames {
+	value := label.GetStringValue(labels, labelName, "")
+	if len(value) == 0 {
+		return nil
+	}
+
+	var names types.FieldNames
+	if err := names.Set(value); err != nil {
+		log.Errorf("Invalid value for %s: %v, skipping...", labelName, err)
+		return nil
+	}
+	return names
+}
+
+func getRetry(container dockerData) *types.Retry {
+	if !label.HasPrefix(container.Labels, label.TraefikFrontendRetry) {
+		return nil
+	}
+
+	retry := &types.Retry{
+		Attempts:    label.GetIntValue(container.Labels, label.TraefikFrontendRetryAttempts, 0),
+		StatusCodes: label.GetSliceStringValue(container.Labels, label.TraefikFrontendRetryStatusCodes),
+		Methods:     label.GetSliceStringValue(container.Labels, label.TraefikFrontendRetryMethods),
+		Budget:      label.GetFloat64Value(container.Labels, label.TraefikFrontendRetryBudget, 0),
+	}
+
+	initialInterval := label.GetStringValue(container.Labels, label.TraefikFrontendRetryInitialInterval, "")
+	if len(initialInterval) > 0 {
+		if err := retry.InitialInterval.Set(initialInterval); err != nil {
+			log.Errorf("Invalid value for %s: %q, skipping...", label.TraefikFrontendRetryInitialInterval, initialInterval)
+		}
+	}
+
+	maxInterval := label.GetStringValue(container.Labels, label.TraefikFrontendRetryMaxInterval, "")
+	if len(maxInterval) > 0 {
+		if err := retry.MaxInterval.Set(maxInterval); err != nil {
+			log.Errorf("Invalid value for %s: %q, skipping...", label.TraefikFrontendRetryMaxInterval, maxInterval)
+		}
+	}
+
+	return retry
+}
+
+func getCompression(container dockerData) *types.Compression {
+	if !label.HasPrefix(container.Labels, label.TraefikFrontendCompression) ||
+		!label.GetBoolValue(container.Labels, label.TraefikFrontendCompressionEnable, true) {
+		return nil
+	}
+
+	return &types.Compression{
+		Encodings:            label.GetSliceStringValue(container.Labels, label.TraefikFrontendCompressionEncodings),
+		MinSize:              label.GetIntValue(container.Labels, label.TraefikFrontendCompressionMinSize, 0),
+		GzipLevel:            label.GetIntValue(container.Labels, label.TraefikFrontendCompressionGzipLevel, 0),
+		BrotliLevel:          label.GetIntValue(container.Labels, label.TraefikFrontendCompressionBrotliLevel, 0),
+		IncludedContentTypes: label.GetSliceStringValue(container.Labels, label.TraefikFrontendCompressionIncludedContentTypes),
+		ExcludedContentTypes: label.GetSliceStringValue(container.Labels, label.TraefikFrontendCompressionExcludedContentTypes),
+	}
+}
+
+func getCache(container dockerData) *types.Cache {
+	if !label.HasPrefix(container.Labels, label.TraefikFrontendCache) ||
+		!label.GetBoolValue(container.Labels, label.TraefikFrontendCacheEnable, true) {
+		return nil
+	}
+
+	cache := &types.Cache{
+		Storage:     label.GetStringValue(container.Labels, label.TraefikFrontendCacheStorage, ""),
+		Path:        label.GetStringValue(container.Labels, label.TraefikFrontendCachePath, ""),
+		MaxEntries:  label.GetIntValue(container.Labels, label.TraefikFrontendCacheMaxEntries, 0),
+		MaxBodySize: label.GetInt64Value(container.Labels, label.TraefikFrontendCacheMaxBodySize, 0),
+	}
+
+	defaultTTL := label.GetStringValue(container.Labels, label.TraefikFrontendCacheDefaultTTL, "")
+	if len(defaultTTL) > 0 {
+		if err := cache.DefaultTTL.Set(defaultTTL); err != nil {
+			log.Errorf("Invalid value for %s: %q, skipping...", label.TraefikFrontendCacheDefaultTTL, defaultTTL)
+		}
+	}
+
+	return cache
+}
+
+func getTLSClientCert(container dockerData) *types.TLSClientCert {
+	if !label.HasPrefix(container.Labels, label.TraefikFrontendTLSClientCert) {
+		return nil
+	}
+
+	return &types.TLSClientCert{
+		PEM:             label.GetBoolValue(container.Labels, label.TraefikFrontendTLSClientCertPEM, false),
+		Fields:          label.GetSliceStringValue(container.Labels, label.TraefikFrontendTLSClientCertFields),
+		AllowedSubjects: label.GetSliceStringValue(container.Labels, label.TraefikFrontendTLSClientCertAllowedSubjects),
+		AllowedSANs:     label.GetSliceStringValue(container.Labels, label.TraefikFrontendTLSClientCertAllowedSANs),
+	}
+}
+
+func getAuth(container dockerData) *types.Auth {
+	if !label.HasPrefix(container.Labels, label.TraefikFrontendAuthJWT) {
+		return nil
+	}
+
+	jwt := &types.JWT{
+		Secret:            label.GetStringValue(container.Labels, label.TraefikFrontendAuthJWTSecret, ""),
+		PublicKeys:        label.GetSliceStringValue(container.Labels, label.TraefikFrontendAuthJWTPublicKeys),
+		JWKS:              label.GetStringValue(container.Labels, label.TraefikFrontendAuthJWTJWKS, ""),
+		Issuer:            label.GetStringValue(container.Labels, label.TraefikFrontendAuthJWTIssuer, ""),
+		Audiences:         label.GetSliceStringValue(container.Labels, label.TraefikFrontendAuthJWTAudiences),
+		ClaimHeaders:      label.GetMapValue(container.Labels, label.TraefikFrontendAuthJWTClaimHeaders),
+		AllowNoExpiration: label.GetBoolValue(container.Labels, label.TraefikFrontendAuthJWTAllowNoExpiration, false),
+	}
+
+	refreshInterval := label.GetStringValue(container.Labels, label.TraefikFrontendAuthJWTJWKSRefreshInterval, "")
+	if len(refreshInterval) > 0 {
+		if err := jwt.JWKSRefreshInterval.Set(refreshInterval); err != nil {
+			log.Errorf("Invalid value for %s: %q, skipping...", label.TraefikFrontendAuthJWTJWKSRefreshInterval, refreshInterval)
+		}
+	}
+
+	clockSkew := label.GetStringValue(container.Labels, label.TraefikFrontendAuthJWTClockSkew, "")
+	if len(clockSkew) > 0 {
+		if err := jwt.ClockSkew.Set(clockSkew); err != nil {
+			log.Errorf("Invalid value for %s: %q, skipping...", label.TraefikFrontendAuthJWTClockSkew, clockSkew)
+		}
+	}
+
+	return &types.Auth{
+		JWT:         jwt,
+		HeaderField: label.GetStringValue(container.Labels, label.TraefikFrontendAuthHeaderField, ""),
+	}
+}
+
 func getErrorPages(container dockerData) map[string]*types.ErrorPage {
 	prefix := label.Prefix + label.BaseFrontendErrorPage
 	return label.ParseErrorPages(container.Labels, prefix, label.RegexpFrontendErrorPage)
@@ -267,6 +477,7 @@ func getRateLimit(container dockerData) *types.RateLimit {
 	return &types.RateLimit{
 		ExtractorFunc: extractorFunc,
 		RateSet:       limits,
+		Distributed:   label.GetBoolValue(container.Labels, label.TraefikFrontendRateLimitDistributed, false),
 	}
 }
 
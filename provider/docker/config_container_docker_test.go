@@ -65,6 +65,266 @@ func TestDockerBuildConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "when container has JWT auth labels",
+			containers: []docker.ContainerJSON{
+				containerJSON(
+					name("test"),
+					labels(map[string]string{
+						label.TraefikFrontendAuthHeaderField:          "X-WebAuth-User",
+						label.TraefikFrontendAuthJWTSecret:            `my"secret\`,
+						label.TraefikFrontendAuthJWTPublicKeys:        "-----BEGIN PUBLIC KEY-----\nMFkwEwYHKoZIzj0C\n-----END PUBLIC KEY-----",
+						label.TraefikFrontendAuthJWTIssuer:            `https://auth.example.com/"realm"`,
+						label.TraefikFrontendAuthJWTAllowNoExpiration: "true",
+					}),
+					ports(nat.PortMap{
+						"80/tcp": {},
+					}),
+					withNetwork("bridge", ipv4("127.0.0.1")),
+				),
+			},
+			expectedFrontends: map[string]*types.Frontend{
+				"frontend-Host-test-docker-localhost-0": {
+					Backend:        "backend-test",
+					PassHostHeader: true,
+					EntryPoints:    []string{},
+					BasicAuth:      []string{},
+					Auth: &types.Auth{
+						HeaderField: "X-WebAuth-User",
+						JWT: &types.JWT{
+							Secret:            `my"secret\`,
+							PublicKeys:        []string{"-----BEGIN PUBLIC KEY-----\nMFkwEwYHKoZIzj0C\n-----END PUBLIC KEY-----"},
+							Issuer:            `https://auth.example.com/"realm"`,
+							Audiences:         []string{},
+							AllowNoExpiration: true,
+						},
+					},
+					Routes: map[string]types.Route{
+						"route-frontend-Host-test-docker-localhost-0": {
+							Rule: "Host:test.docker.localhost",
+						},
+					},
+				},
+			},
+			expectedBackends: map[string]*types.Backend{
+				"backend-test": {
+					Servers: map[string]types.Server{
+						"server-test": {
+							URL:    "http://127.0.0.1:80",
+							Weight: 0,
+						},
+					},
+					CircuitBreaker: nil,
+				},
+			},
+		},
+		{
+			desc: "when container has weighted backends labels",
+			containers: []docker.ContainerJSON{
+				containerJSON(
+					name("test"),
+					labels(map[string]string{
+						label.TraefikFrontendWeightedBackends:           "test:90||canary:10",
+						label.TraefikFrontendWeightedBackendsStickiness: "true",
+					}),
+					ports(nat.PortMap{
+						"80/tcp": {},
+					}),
+					withNetwork("bridge", ipv4("127.0.0.1")),
+				),
+			},
+			expectedFrontends: map[string]*types.Frontend{
+				"frontend-Host-test-docker-localhost-0": {
+					Backend:        "backend-test",
+					PassHostHeader: true,
+					EntryPoints:    []string{},
+					BasicAuth:      []string{},
+					WeightedBackends: &types.WeightedBackends{
+						Backends: map[string]int{
+							"backend-test":   90,
+							"backend-canary": 10,
+						},
+						Stickiness: &types.Stickiness{},
+					},
+					Routes: map[string]types.Route{
+						"route-frontend-Host-test-docker-localhost-0": {
+							Rule: "Host:test.docker.localhost",
+						},
+					},
+				},
+			},
+			expectedBackends: map[string]*types.Backend{
+				"backend-test": {
+					Servers: map[string]types.Server{
+						"server-test": {
+							URL:    "http://127.0.0.1:80",
+							Weight: 0,
+						},
+					},
+					CircuitBreaker: nil,
+				},
+			},
+		},
+		{
+			desc: "when container has access log labels",
+			containers: []docker.ContainerJSON{
+				containerJSON(
+					name("test"),
+					labels(map[string]string{
+						label.TraefikFrontendAccessLogFiltersStatusCodes:   "500-599",
+						label.TraefikFrontendAccessLogFiltersMinDuration:   "1s",
+						label.TraefikFrontendAccessLogFieldsHeadersNames:   "Authorization=redact",
+						label.TraefikFrontendAccessLogFieldsNames:          "ClientUsername=drop",
+						label.TraefikFrontendAccessLogFiltersRetryAttempts: "true",
+					}),
+					ports(nat.PortMap{
+						"80/tcp": {},
+					}),
+					withNetwork("bridge", ipv4("127.0.0.1")),
+				),
+			},
+			expectedFrontends: map[string]*types.Frontend{
+				"frontend-Host-test-docker-localhost-0": {
+					Backend:        "backend-test",
+					PassHostHeader: true,
+					EntryPoints:    []string{},
+					BasicAuth:      []string{},
+					AccessLog: &types.FrontendAccessLog{
+						Filters: &types.AccessLogFilters{
+							StatusCodes:   types.StatusCodes{"500-599"},
+							RetryAttempts: true,
+							MinDuration:   flaeg.Duration(time.Second),
+						},
+						Fields: &types.AccessLogFields{
+							Names: types.FieldNames{"ClientUsername": "drop"},
+							Headers: &types.FieldHeaders{
+								Names: types.FieldNames{"Authorization": "redact"},
+							},
+						},
+					},
+					Routes: map[string]types.Route{
+						"route-frontend-Host-test-docker-localhost-0": {
+							Rule: "Host:test.docker.localhost",
+						},
+					},
+				},
+			},
+			expectedBackends: map[string]*types.Backend{
+				"backend-test": {
+					Servers: map[string]types.Server{
+						"server-test": {
+							URL:    "http://127.0.0.1:80",
+							Weight: 0,
+						},
+					},
+					CircuitBreaker: nil,
+				},
+			},
+		},
+		{
+			desc: "when container has retry labels",
+			containers: []docker.ContainerJSON{
+				containerJSON(
+					name("test"),
+					labels(map[string]string{
+						label.TraefikFrontendRetryAttempts:        "3",
+						label.TraefikFrontendRetryStatusCodes:     "502,503",
+						label.TraefikFrontendRetryMethods:         "GET",
+						label.TraefikFrontendRetryInitialInterval: "100ms",
+						label.TraefikFrontendRetryMaxInterval:     "1s",
+						label.TraefikFrontendRetryBudget:          "0.2",
+					}),
+					ports(nat.PortMap{
+						"80/tcp": {},
+					}),
+					withNetwork("bridge", ipv4("127.0.0.1")),
+				),
+			},
+			expectedFrontends: map[string]*types.Frontend{
+				"frontend-Host-test-docker-localhost-0": {
+					Backend:        "backend-test",
+					PassHostHeader: true,
+					EntryPoints:    []string{},
+					BasicAuth:      []string{},
+					Retry: &types.Retry{
+						Attempts:        3,
+						StatusCodes:     types.StatusCodes{"502", "503"},
+						Methods:         []string{"GET"},
+						InitialInterval: flaeg.Duration(100 * time.Millisecond),
+						MaxInterval:     flaeg.Duration(time.Second),
+						Budget:          0.2,
+					},
+					Routes: map[string]types.Route{
+						"route-frontend-Host-test-docker-localhost-0": {
+							Rule: "Host:test.docker.localhost",
+						},
+					},
+				},
+			},
+			expectedBackends: map[string]*types.Backend{
+				"backend-test": {
+					Servers: map[string]types.Server{
+						"server-test": {
+							URL:    "http://127.0.0.1:80",
+							Weight: 0,
+						},
+					},
+					CircuitBreaker: nil,
+				},
+			},
+		},
+		{
+			desc: "when container has compression labels",
+			containers: []docker.ContainerJSON{
+				containerJSON(
+					name("test"),
+					labels(map[string]string{
+						label.TraefikFrontendCompressionEncodings:            "br",
+						label.TraefikFrontendCompressionMinSize:              "1024",
+						label.TraefikFrontendCompressionGzipLevel:            "9",
+						label.TraefikFrontendCompressionBrotliLevel:          "4",
+						label.TraefikFrontendCompressionIncludedContentTypes: "text/*",
+						label.TraefikFrontendCompressionExcludedContentTypes: "text/event-stream",
+					}),
+					ports(nat.PortMap{
+						"80/tcp": {},
+					}),
+					withNetwork("bridge", ipv4("127.0.0.1")),
+				),
+			},
+			expectedFrontends: map[string]*types.Frontend{
+				"frontend-Host-test-docker-localhost-0": {
+					Backend:        "backend-test",
+					PassHostHeader: true,
+					EntryPoints:    []string{},
+					BasicAuth:      []string{},
+					Compression: &types.Compression{
+						Encodings:            []string{"br"},
+						MinSize:              1024,
+						GzipLevel:            9,
+						BrotliLevel:          4,
+						IncludedContentTypes: []string{"text/*"},
+						ExcludedContentTypes: []string{"text/event-stream"},
+					},
+					Routes: map[string]types.Route{
+						"route-frontend-Host-test-docker-localhost-0": {
+							Rule: "Host:test.docker.localhost",
+						},
+					},
+				},
+			},
+			expectedBackends: map[string]*types.Backend{
+				"backend-test": {
+					Servers: map[string]types.Server{
+						"server-test": {
+							URL:    "http://127.0.0.1:80",
+							Weight: 0,
+						},
+					},
+					CircuitBreaker: nil,
+				},
+			},
+		},
 		{
 			desc: "when container has label 'enable' to false",
 			containers: []docker.ContainerJSON{
@@ -98,21 +358,32 @@ func TestDockerBuildConfiguration(t *testing.T) {
 
 						label.TraefikBackend: "foobar",
 
-						label.TraefikBackendCircuitBreakerExpression:         "NetworkErrorRatio() > 0.5",
-						label.TraefikBackendHealthCheckPath:                  "/health",
-						label.TraefikBackendHealthCheckPort:                  "880",
-						label.TraefikBackendHealthCheckInterval:              "6",
-						label.TraefikBackendLoadBalancerMethod:               "drr",
-						label.TraefikBackendLoadBalancerSticky:               "true",
-						label.TraefikBackendLoadBalancerStickiness:           "true",
-						label.TraefikBackendLoadBalancerStickinessCookieName: "chocolate",
-						label.TraefikBackendMaxConnAmount:                    "666",
-						label.TraefikBackendMaxConnExtractorFunc:             "client.ip",
-						label.TraefikBackendBufferingMaxResponseBodyBytes:    "10485760",
-						label.TraefikBackendBufferingMemResponseBodyBytes:    "2097152",
-						label.TraefikBackendBufferingMaxRequestBodyBytes:     "10485760",
-						label.TraefikBackendBufferingMemRequestBodyBytes:     "2097152",
-						label.TraefikBackendBufferingRetryExpression:         "IsNetworkError() && Attempts() <= 2",
+						label.TraefikBackendCircuitBreakerExpression:                "NetworkErrorRatio() > 0.5",
+						label.TraefikBackendHealthCheckPath:                         "/health",
+						label.TraefikBackendHealthCheckPort:                         "880",
+						label.TraefikBackendHealthCheckInterval:                     "6",
+						label.TraefikBackendHealthCheckScheme:                       "http",
+						label.TraefikBackendHealthCheckTimeout:                      "3s",
+						label.TraefikBackendHealthCheckHostname:                     "foo.bar",
+						label.TraefikBackendHealthCheckHeaders:                      "X-Foo:bar",
+						label.TraefikBackendHealthCheckStatus:                       "200-299,302",
+						label.TraefikBackendHealthCheckBodyRegex:                    `"status":\s*"up"`,
+						label.TraefikBackendHealthCheckHealthyThreshold:             "2",
+						label.TraefikBackendHealthCheckUnhealthyThreshold:           "3",
+						label.TraefikBackendHealthCheckMode:                         "grpc",
+						label.TraefikBackendLoadBalancerMethod:                      "drr",
+						label.TraefikBackendLoadBalancerSticky:                      "true",
+						label.TraefikBackendLoadBalancerStickiness:                  "true",
+						label.TraefikBackendLoadBalancerStickinessCookieName:        "chocolate",
+						label.TraefikBackendLoadBalancerConsistentHashExtractorFunc: "request.header.X-Tenant",
+						label.TraefikBackendLoadBalancerConsistentHashLoadFactor:    "1.5",
+						label.TraefikBackendMaxConnAmount:                           "666",
+						label.TraefikBackendMaxConnExtractorFunc:                    "client.ip",
+						label.TraefikBackendBufferingMaxResponseBodyBytes:           "10485760",
+						label.TraefikBackendBufferingMemResponseBodyBytes:           "2097152",
+						label.TraefikBackendBufferingMaxRequestBodyBytes:            "10485760",
+						label.TraefikBackendBufferingMemRequestBodyBytes:            "2097152",
+						label.TraefikBackendBufferingRetryExpression:                "IsNetworkError() && Attempts() <= 2",
 
 						label.TraefikFrontendAuthBasic:            "test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/,test2:$apr1$d9hr9HBB$4HxwgUir3HP4EsggP/QNo0",
 						label.TraefikFrontendEntryPoints:          "http,https",
@@ -281,15 +552,28 @@ func TestDockerBuildConfiguration(t *testing.T) {
 						Stickiness: &types.Stickiness{
 							CookieName: "chocolate",
 						},
+						ConsistentHash: &types.ConsistentHashing{
+							ExtractorFunc: "request.header.X-Tenant",
+							LoadFactor:    1.5,
+						},
 					},
 					MaxConn: &types.MaxConn{
 						Amount:        666,
 						ExtractorFunc: "client.ip",
 					},
 					HealthCheck: &types.HealthCheck{
-						Path:     "/health",
-						Port:     880,
-						Interval: "6",
+						Mode:               "grpc",
+						Scheme:             "http",
+						Path:               "/health",
+						Port:               880,
+						Interval:           "6",
+						Timeout:            "3s",
+						Hostname:           "foo.bar",
+						Headers:            map[string]string{"X-Foo": "bar"},
+						Status:             []string{"200-299", "302"},
+						BodyRegex:          `"status":\s*"up"`,
+						HealthyThreshold:   2,
+						UnhealthyThreshold: 3,
 					},
 					Buffering: &types.Buffering{
 						MaxResponseBodyBytes: 10485760,
@@ -1153,6 +1437,23 @@ func TestDockerGetLoadBalancer(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "should return a consistent hash when consistent hash labels are set",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikBackendLoadBalancerMethod:                      "ConsistentHash",
+					label.TraefikBackendLoadBalancerConsistentHashExtractorFunc: "request.cookie.session",
+					label.TraefikBackendLoadBalancerConsistentHashLoadFactor:    "2",
+				})),
+			expected: &types.LoadBalancer{
+				Method: "ConsistentHash",
+				ConsistentHash: &types.ConsistentHashing{
+					ExtractorFunc: "request.cookie.session",
+					LoadFactor:    2,
+				},
+			},
+		},
 		{
 			desc: "should return a nil Stickiness when Stickiness is not set",
 			container: containerJSON(
@@ -1283,61 +1584,53 @@ func TestDockerGetRedirect(t *testing.T) {
 	}
 }
 
-func TestDockerGetRateLimit(t *testing.T) {
+func TestDockerGetWeightedBackends(t *testing.T) {
 	testCases := []struct {
 		desc      string
 		container docker.ContainerJSON
-		expected  *types.RateLimit
+		expected  *types.WeightedBackends
 	}{
 		{
-			desc: "should return nil when no rate limit labels",
+			desc: "should return nil when no weighted backends label",
 			container: containerJSON(
 				name("test1"),
 				labels(map[string]string{})),
 			expected: nil,
 		},
 		{
-			desc: "should return a struct when rate limit labels are defined",
+			desc: "should return a struct when weighted backends label",
 			container: containerJSON(
 				name("test1"),
 				labels(map[string]string{
-					label.TraefikFrontendRateLimitExtractorFunc:                                        "client.ip",
-					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitPeriod:  "6",
-					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitAverage: "12",
-					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitBurst:   "18",
-					label.Prefix + label.BaseFrontendRateLimit + "bar." + label.SuffixRateLimitPeriod:  "3",
-					label.Prefix + label.BaseFrontendRateLimit + "bar." + label.SuffixRateLimitAverage: "6",
-					label.Prefix + label.BaseFrontendRateLimit + "bar." + label.SuffixRateLimitBurst:   "9",
-				})),
-			expected: &types.RateLimit{
-				ExtractorFunc: "client.ip",
-				RateSet: map[string]*types.Rate{
-					"foo": {
-						Period:  flaeg.Duration(6 * time.Second),
-						Average: 12,
-						Burst:   18,
-					},
-					"bar": {
-						Period:  flaeg.Duration(3 * time.Second),
-						Average: 6,
-						Burst:   9,
-					},
+					label.TraefikFrontendWeightedBackends: "v1:90||v2.foo:10",
+				}),
+			),
+			expected: &types.WeightedBackends{
+				Backends: map[string]int{
+					"backend-v1":     90,
+					"backend-v2-foo": 10,
 				},
 			},
 		},
 		{
-			desc: "should return nil when ExtractorFunc is missing",
+			desc: "should return a struct with stickiness when stickiness labels",
 			container: containerJSON(
 				name("test1"),
 				labels(map[string]string{
-					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitPeriod:  "6",
-					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitAverage: "12",
-					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitBurst:   "18",
-					label.Prefix + label.BaseFrontendRateLimit + "bar." + label.SuffixRateLimitPeriod:  "3",
-					label.Prefix + label.BaseFrontendRateLimit + "bar." + label.SuffixRateLimitAverage: "6",
-					label.Prefix + label.BaseFrontendRateLimit + "bar." + label.SuffixRateLimitBurst:   "9",
-				})),
-			expected: nil,
+					label.TraefikFrontendWeightedBackends:           "v1:90||v2:10",
+					label.TraefikFrontendWeightedBackendsStickiness: "true",
+					label.TraefikFrontendWeightedBackendsCookieName: "canary",
+				}),
+			),
+			expected: &types.WeightedBackends{
+				Backends: map[string]int{
+					"backend-v1": 90,
+					"backend-v2": 10,
+				},
+				Stickiness: &types.Stickiness{
+					CookieName: "canary",
+				},
+			},
 		},
 	}
 
@@ -1348,22 +1641,505 @@ func TestDockerGetRateLimit(t *testing.T) {
 
 			dData := parseContainer(test.container)
 
-			actual := getRateLimit(dData)
+			actual := getWeightedBackends(dData)
 
 			assert.Equal(t, test.expected, actual)
 		})
 	}
 }
 
-func TestGetErrorPages(t *testing.T) {
+func TestDockerGetAccessLog(t *testing.T) {
 	testCases := []struct {
-		desc     string
-		data     dockerData
-		expected map[string]*types.ErrorPage
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.FrontendAccessLog
 	}{
 		{
-			desc: "2 errors pages",
-			data: parseContainer(containerJSON(
+			desc: "should return nil when no access log labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{})),
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when access log labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendAccessLogFiltersStatusCodes:       "200,500-599",
+					label.TraefikFrontendAccessLogFiltersRetryAttempts:     "true",
+					label.TraefikFrontendAccessLogFiltersMinDuration:       "10ms",
+					label.TraefikFrontendAccessLogFieldsDefaultMode:        "drop",
+					label.TraefikFrontendAccessLogFieldsNames:              "ClientUsername=redact RequestHost=keep",
+					label.TraefikFrontendAccessLogFieldsHeadersDefaultMode: "keep",
+					label.TraefikFrontendAccessLogFieldsHeadersNames:       "Authorization=redact",
+				}),
+			),
+			expected: &types.FrontendAccessLog{
+				Filters: &types.AccessLogFilters{
+					StatusCodes:   types.StatusCodes{"200", "500-599"},
+					RetryAttempts: true,
+					MinDuration:   flaeg.Duration(10 * time.Millisecond),
+				},
+				Fields: &types.AccessLogFields{
+					DefaultMode: "drop",
+					Names: types.FieldNames{
+						"ClientUsername": "redact",
+						"RequestHost":    "keep",
+					},
+					Headers: &types.FieldHeaders{
+						DefaultMode: "keep",
+						Names:       types.FieldNames{"Authorization": "redact"},
+					},
+				},
+			},
+		},
+		{
+			desc: "should return a struct with filters only when filter labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendAccessLogFiltersStatusCodes: "500-599",
+				}),
+			),
+			expected: &types.FrontendAccessLog{
+				Filters: &types.AccessLogFilters{
+					StatusCodes: types.StatusCodes{"500-599"},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getAccessLog(dData)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDockerGetRetry(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.Retry
+	}{
+		{
+			desc: "should return nil when no retry labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{})),
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when retry labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendRetryAttempts:        "3",
+					label.TraefikFrontendRetryStatusCodes:     "502,503",
+					label.TraefikFrontendRetryMethods:         "GET,POST",
+					label.TraefikFrontendRetryInitialInterval: "100ms",
+					label.TraefikFrontendRetryMaxInterval:     "1s",
+					label.TraefikFrontendRetryBudget:          "0.2",
+				}),
+			),
+			expected: &types.Retry{
+				Attempts:        3,
+				StatusCodes:     types.StatusCodes{"502", "503"},
+				Methods:         []string{"GET", "POST"},
+				InitialInterval: flaeg.Duration(100 * time.Millisecond),
+				MaxInterval:     flaeg.Duration(time.Second),
+				Budget:          0.2,
+			},
+		},
+		{
+			desc: "should skip an invalid interval",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendRetryAttempts:        "2",
+					label.TraefikFrontendRetryInitialInterval: "foo",
+				}),
+			),
+			expected: &types.Retry{
+				Attempts: 2,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getRetry(dData)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDockerGetCompression(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.Compression
+	}{
+		{
+			desc: "should return nil when no compression labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{})),
+			expected: nil,
+		},
+		{
+			desc: "should return nil when compression is disabled",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendCompressionEnable:  "false",
+					label.TraefikFrontendCompressionMinSize: "1024",
+				})),
+			expected: nil,
+		},
+		{
+			desc: "should return the default settings when compression is enabled",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendCompressionEnable: "true",
+				})),
+			expected: &types.Compression{},
+		},
+		{
+			desc: "should return a struct when compression labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendCompressionEncodings:            "br,gzip",
+					label.TraefikFrontendCompressionMinSize:              "1024",
+					label.TraefikFrontendCompressionGzipLevel:            "9",
+					label.TraefikFrontendCompressionBrotliLevel:          "4",
+					label.TraefikFrontendCompressionIncludedContentTypes: "text/*,application/json",
+					label.TraefikFrontendCompressionExcludedContentTypes: "text/event-stream",
+				}),
+			),
+			expected: &types.Compression{
+				Encodings:            []string{"br", "gzip"},
+				MinSize:              1024,
+				GzipLevel:            9,
+				BrotliLevel:          4,
+				IncludedContentTypes: []string{"text/*", "application/json"},
+				ExcludedContentTypes: []string{"text/event-stream"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getCompression(dData)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDockerGetCache(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.Cache
+	}{
+		{
+			desc: "should return nil when no cache labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{})),
+			expected: nil,
+		},
+		{
+			desc: "should return nil when cache is disabled",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendCacheEnable:     "false",
+					label.TraefikFrontendCacheMaxEntries: "100",
+				})),
+			expected: nil,
+		},
+		{
+			desc: "should return the default settings when cache is enabled",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendCacheEnable: "true",
+				})),
+			expected: &types.Cache{},
+		},
+		{
+			desc: "should return a struct when cache labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendCacheStorage:     "disk",
+					label.TraefikFrontendCachePath:        "/var/cache/traefik",
+					label.TraefikFrontendCacheMaxEntries:  "100",
+					label.TraefikFrontendCacheMaxBodySize: "4096",
+					label.TraefikFrontendCacheDefaultTTL:  "30s",
+				}),
+			),
+			expected: &types.Cache{
+				Storage:     "disk",
+				Path:        "/var/cache/traefik",
+				MaxEntries:  100,
+				MaxBodySize: 4096,
+				DefaultTTL:  flaeg.Duration(30 * time.Second),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getCache(dData)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDockerGetAuth(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.Auth
+	}{
+		{
+			desc: "should return nil when no JWT labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendAuthHeaderField: "X-WebAuth-User",
+				})),
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when JWT labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendAuthHeaderField:            "X-WebAuth-User",
+					label.TraefikFrontendAuthJWTSecret:              "mysecret",
+					label.TraefikFrontendAuthJWTPublicKeys:          "/keys/rsa.pem, /keys/ec.pem",
+					label.TraefikFrontendAuthJWTJWKS:                "https://auth.example.com/jwks.json",
+					label.TraefikFrontendAuthJWTJWKSRefreshInterval: "30m",
+					label.TraefikFrontendAuthJWTIssuer:              "https://auth.example.com",
+					label.TraefikFrontendAuthJWTAudiences:           "api,admin",
+					label.TraefikFrontendAuthJWTClockSkew:           "30s",
+					label.TraefikFrontendAuthJWTAllowNoExpiration:   "true",
+					label.TraefikFrontendAuthJWTClaimHeaders:        "X-User:sub||X-Roles:roles",
+				}),
+			),
+			expected: &types.Auth{
+				JWT: &types.JWT{
+					Secret:              "mysecret",
+					PublicKeys:          []string{"/keys/rsa.pem", "/keys/ec.pem"},
+					JWKS:                "https://auth.example.com/jwks.json",
+					JWKSRefreshInterval: flaeg.Duration(30 * time.Minute),
+					Issuer:              "https://auth.example.com",
+					Audiences:           []string{"api", "admin"},
+					ClockSkew:           flaeg.Duration(30 * time.Second),
+					AllowNoExpiration:   true,
+					ClaimHeaders:        map[string]string{"X-User": "sub", "X-Roles": "roles"},
+				},
+				HeaderField: "X-WebAuth-User",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getAuth(dData)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDockerGetTLSClientCert(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.TLSClientCert
+	}{
+		{
+			desc: "should return nil when no TLS client certificate labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{})),
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when TLS client certificate labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendTLSClientCertPEM:             "true",
+					label.TraefikFrontendTLSClientCertFields:          "subjectCN, sans, fingerprint",
+					label.TraefikFrontendTLSClientCertAllowedSubjects: `.*\.example\.com`,
+					label.TraefikFrontendTLSClientCertAllowedSANs:     "spiffe://example.com/.*",
+				}),
+			),
+			expected: &types.TLSClientCert{
+				PEM:             true,
+				Fields:          []string{"subjectCN", "sans", "fingerprint"},
+				AllowedSubjects: []string{`.*\.example\.com`},
+				AllowedSANs:     []string{"spiffe://example.com/.*"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getTLSClientCert(dData)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDockerGetRateLimit(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		container docker.ContainerJSON
+		expected  *types.RateLimit
+	}{
+		{
+			desc: "should return nil when no rate limit labels",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{})),
+			expected: nil,
+		},
+		{
+			desc: "should return a struct when rate limit labels are defined",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendRateLimitExtractorFunc:                                        "client.ip",
+					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitPeriod:  "6",
+					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitAverage: "12",
+					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitBurst:   "18",
+					label.Prefix + label.BaseFrontendRateLimit + "bar." + label.SuffixRateLimitPeriod:  "3",
+					label.Prefix + label.BaseFrontendRateLimit + "bar." + label.SuffixRateLimitAverage: "6",
+					label.Prefix + label.BaseFrontendRateLimit + "bar." + label.SuffixRateLimitBurst:   "9",
+				})),
+			expected: &types.RateLimit{
+				ExtractorFunc: "client.ip",
+				RateSet: map[string]*types.Rate{
+					"foo": {
+						Period:  flaeg.Duration(6 * time.Second),
+						Average: 12,
+						Burst:   18,
+					},
+					"bar": {
+						Period:  flaeg.Duration(3 * time.Second),
+						Average: 6,
+						Burst:   9,
+					},
+				},
+			},
+		},
+		{
+			desc: "should return a distributed rate limit when the label is set",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikFrontendRateLimitExtractorFunc:                                        "client.ip",
+					label.TraefikFrontendRateLimitDistributed:                                          "true",
+					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitPeriod:  "6",
+					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitAverage: "12",
+					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitBurst:   "18",
+				})),
+			expected: &types.RateLimit{
+				ExtractorFunc: "client.ip",
+				Distributed:   true,
+				RateSet: map[string]*types.Rate{
+					"foo": {
+						Period:  flaeg.Duration(6 * time.Second),
+						Average: 12,
+						Burst:   18,
+					},
+				},
+			},
+		},
+		{
+			desc: "should return nil when ExtractorFunc is missing",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitPeriod:  "6",
+					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitAverage: "12",
+					label.Prefix + label.BaseFrontendRateLimit + "foo." + label.SuffixRateLimitBurst:   "18",
+					label.Prefix + label.BaseFrontendRateLimit + "bar." + label.SuffixRateLimitPeriod:  "3",
+					label.Prefix + label.BaseFrontendRateLimit + "bar." + label.SuffixRateLimitAverage: "6",
+					label.Prefix + label.BaseFrontendRateLimit + "bar." + label.SuffixRateLimitBurst:   "9",
+				})),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dData := parseContainer(test.container)
+
+			actual := getRateLimit(dData)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestGetErrorPages(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		data     dockerData
+		expected map[string]*types.ErrorPage
+	}{
+		{
+			desc: "2 errors pages",
+			data: parseContainer(containerJSON(
 				labels(map[string]string{
 					label.Prefix + label.BaseFrontendErrorPage + "foo." + label.SuffixErrorPageStatus:  "404",
 					label.Prefix + label.BaseFrontendErrorPage + "foo." + label.SuffixErrorPageBackend: "foo_backend",
@@ -1449,6 +2225,46 @@ func TestDockerGetHealthCheck(t *testing.T) {
 				Interval: "6",
 			},
 		},
+		{
+			desc: "should return a struct with the request and response options",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikBackendHealthCheckScheme:             "https",
+					label.TraefikBackendHealthCheckPath:               "/health",
+					label.TraefikBackendHealthCheckTimeout:            "3s",
+					label.TraefikBackendHealthCheckHostname:           "foo.bar",
+					label.TraefikBackendHealthCheckHeaders:            "X-Foo:bar||X-Bar:foo",
+					label.TraefikBackendHealthCheckStatus:             "200-299, 302",
+					label.TraefikBackendHealthCheckBodyRegex:          "^OK$",
+					label.TraefikBackendHealthCheckHealthyThreshold:   "2",
+					label.TraefikBackendHealthCheckUnhealthyThreshold: "3",
+				})),
+			expected: &types.HealthCheck{
+				Scheme:             "https",
+				Path:               "/health",
+				Timeout:            "3s",
+				Hostname:           "foo.bar",
+				Headers:            map[string]string{"X-Foo": "bar", "X-Bar": "foo"},
+				Status:             []string{"200-299", "302"},
+				BodyRegex:          "^OK$",
+				HealthyThreshold:   2,
+				UnhealthyThreshold: 3,
+			},
+		},
+		{
+			desc: "should return a struct with the gRPC mode",
+			container: containerJSON(
+				name("test1"),
+				labels(map[string]string{
+					label.TraefikBackendHealthCheckMode: "grpc",
+					label.TraefikBackendHealthCheckPath: "/helloworld.Greeter",
+				})),
+			expected: &types.HealthCheck{
+				Mode: "grpc",
+				Path: "/helloworld.Greeter",
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -139,6 +139,7 @@ func getServiceRateLimit(container dockerData, serviceName string) *types.RateLi
 		return &types.RateLimit{
 			ExtractorFunc: extractorFunc,
 			RateSet:       label.ParseRateSets(serviceLabels, label.BaseFrontendRateLimit, label.RegexpBaseFrontendRateLimit),
+			Distributed:   getServiceBoolValue(container, serviceLabels, label.SuffixFrontendRateLimitDistributed, false),
 		}
 	}
 
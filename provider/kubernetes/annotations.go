@@ -27,8 +27,13 @@ const (
 	annotationKubernetesMaxConnAmount            = "ingress.kubernetes.io/max-conn-amount"
 	annotationKubernetesMaxConnExtractorFunc     = "ingress.kubernetes.io/max-conn-extractor-func"
 	annotationKubernetesRateLimit                = "ingress.kubernetes.io/rate-limit"
+	annotationKubernetesRetry                    = "ingress.kubernetes.io/retry"
 	annotationKubernetesErrorPages               = "ingress.kubernetes.io/error-pages"
 	annotationKubernetesBuffering                = "ingress.kubernetes.io/buffering"
+	annotationKubernetesHealthCheck              = "ingress.kubernetes.io/health-check"
+	annotationKubernetesProtocol                 = "ingress.kubernetes.io/protocol"
+	annotationKubernetesServiceWeights           = "ingress.kubernetes.io/service-weights"
+	annotationKubernetesServiceWeightsAffinity   = "ingress.kubernetes.io/service-weights-affinity"
 
 	annotationKubernetesSSLRedirect             = "ingress.kubernetes.io/ssl-redirect"
 	annotationKubernetesHSTSMaxAge              = "ingress.kubernetes.io/hsts-max-age"
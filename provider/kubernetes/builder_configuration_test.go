@@ -220,6 +220,15 @@ func headers(h *types.Headers) func(*types.Frontend) {
 	}
 }
 
+func weightedBackend(name string, weight int) func(*types.Frontend) {
+	return func(f *types.Frontend) {
+		if f.WeightedBackends == nil {
+			f.WeightedBackends = &types.WeightedBackends{Backends: make(map[string]int)}
+		}
+		f.WeightedBackends.Backends[name] = weight
+	}
+}
+
 func redirectEntryPoint(name string) func(*types.Frontend) {
 	return func(f *types.Frontend) {
 		if f.Redirect == nil {
@@ -284,6 +293,49 @@ func rateLimit(opts ...func(*types.RateLimit)) func(*types.Frontend) {
 	}
 }
 
+func retry(opts ...func(*types.Retry)) func(*types.Frontend) {
+	return func(f *types.Frontend) {
+		if f.Retry == nil {
+			f.Retry = &types.Retry{}
+		}
+
+		for _, opt := range opts {
+			opt(f.Retry)
+		}
+	}
+}
+
+func retryAttempts(attempts int) func(*types.Retry) {
+	return func(r *types.Retry) {
+		r.Attempts = attempts
+	}
+}
+
+func retryStatusCodes(statusCodes ...string) func(*types.Retry) {
+	return func(r *types.Retry) {
+		r.StatusCodes = statusCodes
+	}
+}
+
+func retryMethods(methods ...string) func(*types.Retry) {
+	return func(r *types.Retry) {
+		r.Methods = methods
+	}
+}
+
+func retryIntervals(initialInterval, maxInterval time.Duration) func(*types.Retry) {
+	return func(r *types.Retry) {
+		r.InitialInterval = flaeg.Duration(initialInterval)
+		r.MaxInterval = flaeg.Duration(maxInterval)
+	}
+}
+
+func retryBudget(budget float64) func(*types.Retry) {
+	return func(r *types.Retry) {
+		r.Budget = budget
+	}
+}
+
 func rateExtractorFunc(exp string) func(*types.RateLimit) {
 	return func(limit *types.RateLimit) {
 		limit.ExtractorFunc = exp
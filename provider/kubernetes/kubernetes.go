@@ -158,6 +158,12 @@ func (p *Provider) loadIngresses(k8sClient Client) (*types.Configuration, error)
 			continue
 		}
 
+		serviceWeights, err := getServiceWeights(i)
+		if err != nil {
+			log.Errorf("Error configuring service weights for ingress %s/%s: %v", i.Namespace, i.Name, err)
+			continue
+		}
+
 		tlsSection, err := getTLS(i, k8sClient)
 		if err != nil {
 			log.Errorf("Error configuring TLS for ingress %s/%s: %v", i.Namespace, i.Name, err)
@@ -215,6 +221,7 @@ func (p *Provider) loadIngresses(k8sClient Client) (*types.Configuration, error)
 						Headers:              getHeader(i),
 						Errors:               getErrorPages(i),
 						RateLimit:            getRateLimit(i),
+						Retry:                getRetry(i),
 					}
 				}
 
@@ -244,64 +251,142 @@ func (p *Provider) loadIngresses(k8sClient Client) (*types.Configuration, error)
 					continue
 				}
 
-				templateObjects.Backends[baseName].CircuitBreaker = getCircuitBreaker(service)
-				templateObjects.Backends[baseName].LoadBalancer = getLoadBalancer(service)
-				templateObjects.Backends[baseName].MaxConn = getMaxConn(service)
-				templateObjects.Backends[baseName].Buffering = getBuffering(service)
+				if err := loadServiceServers(k8sClient, templateObjects.Backends[baseName], service, pa.Backend.ServicePort); err != nil {
+					return nil, err
+				}
 
-				protocol := label.DefaultProtocol
-				for _, port := range service.Spec.Ports {
-					if equalPorts(port, pa.Backend.ServicePort) {
-						if port.Port == 443 {
-							protocol = "https"
-						}
+				weightedBackends, err := getWeightedBackends(k8sClient, i, serviceWeights, baseName, pa, templateObjects.Backends)
+				if err != nil {
+					return nil, err
+				}
+				templateObjects.Frontends[baseName].WeightedBackends = weightedBackends
+			}
+		}
+	}
+	return &templateObjects, nil
+}
 
-						if service.Spec.Type == "ExternalName" {
-							url := protocol + "://" + service.Spec.ExternalName
-							name := url
+// loadServiceServers fills the backend with the endpoints of the service port.
+func loadServiceServers(k8sClient Client, backend *types.Backend, service *corev1.Service, servicePort intstr.IntOrString) error {
+	backend.CircuitBreaker = getCircuitBreaker(service)
+	backend.LoadBalancer = getLoadBalancer(service)
+	backend.MaxConn = getMaxConn(service)
+	backend.Buffering = getBuffering(service)
+	backend.HealthCheck = getHealthCheck(service)
+
+	protocol := getStringValue(service.Annotations, annotationKubernetesProtocol, "")
+	for _, port := range service.Spec.Ports {
+		if equalPorts(port, servicePort) {
+			if len(protocol) == 0 {
+				protocol = label.DefaultProtocol
+				if port.Port == 443 {
+					protocol = "https"
+				}
+			}
 
-							templateObjects.Backends[baseName].Servers[name] = types.Server{
-								URL:    url,
-								Weight: 1,
-							}
-						} else {
-							endpoints, exists, err := k8sClient.GetEndpoints(service.Namespace, service.Name)
-							if err != nil {
-								log.Errorf("Error retrieving endpoints %s/%s: %v", service.Namespace, service.Name, err)
-								return nil, err
-							}
+			if service.Spec.Type == "ExternalName" {
+				url := protocol + "://" + service.Spec.ExternalName
+				name := url
 
-							if !exists {
-								log.Warnf("Endpoints not found for %s/%s", service.Namespace, service.Name)
-								break
-							}
+				backend.Servers[name] = types.Server{
+					URL:    url,
+					Weight: 1,
+				}
+			} else {
+				endpoints, exists, err := k8sClient.GetEndpoints(service.Namespace, service.Name)
+				if err != nil {
+					log.Errorf("Error retrieving endpoints %s/%s: %v", service.Namespace, service.Name, err)
+					return err
+				}
 
-							if len(endpoints.Subsets) == 0 {
-								log.Warnf("Endpoints not available for %s/%s", service.Namespace, service.Name)
-								break
-							}
+				if !exists {
+					log.Warnf("Endpoints not found for %s/%s", service.Namespace, service.Name)
+					break
+				}
 
-							for _, subset := range endpoints.Subsets {
-								for _, address := range subset.Addresses {
-									url := protocol + "://" + address.IP + ":" + strconv.Itoa(endpointPortNumber(port, subset.Ports))
-									name := url
-									if address.TargetRef != nil && address.TargetRef.Name != "" {
-										name = address.TargetRef.Name
-									}
-									templateObjects.Backends[baseName].Servers[name] = types.Server{
-										URL:    url,
-										Weight: 1,
-									}
-								}
-							}
+				if len(endpoints.Subsets) == 0 {
+					log.Warnf("Endpoints not available for %s/%s", service.Namespace, service.Name)
+					break
+				}
+
+				for _, subset := range endpoints.Subsets {
+					for _, address := range subset.Addresses {
+						url := protocol + "://" + address.IP + ":" + strconv.Itoa(endpointPortNumber(port, subset.Ports))
+						name := url
+						if address.TargetRef != nil && address.TargetRef.Name != "" {
+							name = address.TargetRef.Name
+						}
+						backend.Servers[name] = types.Server{
+							URL:    url,
+							Weight: 1,
 						}
-						break
 					}
 				}
 			}
+			break
 		}
 	}
-	return &templateObjects, nil
+	return nil
+}
+
+// getServiceWeights returns the weights of the services of an ingress, read from its service weights annotation.
+func getServiceWeights(i *extensionsv1beta1.Ingress) (map[string]int, error) {
+	weightsRaw := getStringValue(i.Annotations, annotationKubernetesServiceWeights, "")
+	if len(weightsRaw) == 0 {
+		return nil, nil
+	}
+
+	weights := map[string]int{}
+	if err := yaml.Unmarshal([]byte(weightsRaw), &weights); err != nil {
+		return nil, fmt.Errorf("invalid service weights %q: %v", weightsRaw, err)
+	}
+	return weights, nil
+}
+
+// getWeightedBackends builds the weighted backends of an ingress path, from the weights of its services.
+// The path service is served by the path backend, and a backend is added for each other service.
+func getWeightedBackends(k8sClient Client, i *extensionsv1beta1.Ingress, weights map[string]int, baseName string, pa extensionsv1beta1.HTTPIngressPath,
+	backends map[string]*types.Backend) (*types.WeightedBackends, error) {
+	if len(weights) == 0 {
+		return nil, nil
+	}
+
+	weightedBackends := &types.WeightedBackends{Backends: make(map[string]int)}
+	if getBoolValue(i.Annotations, annotationKubernetesServiceWeightsAffinity, false) {
+		weightedBackends.Stickiness = &types.Stickiness{}
+	}
+
+	for serviceName, weight := range weights {
+		if serviceName == pa.Backend.ServiceName {
+			weightedBackends.Backends[baseName] = weight
+			continue
+		}
+
+		service, exists, err := k8sClient.GetService(i.Namespace, serviceName)
+		if err != nil {
+			log.Errorf("Error while retrieving service information from k8s API %s/%s: %v", i.Namespace, serviceName, err)
+			return nil, err
+		}
+
+		if !exists {
+			log.Errorf("Service not found for %s/%s", i.Namespace, serviceName)
+			continue
+		}
+
+		backendName := baseName + "@" + serviceName
+		backends[backendName] = &types.Backend{
+			Servers: make(map[string]types.Server),
+			LoadBalancer: &types.LoadBalancer{
+				Method: "wrr",
+			},
+		}
+		if err := loadServiceServers(k8sClient, backends[backendName], service, pa.Backend.ServicePort); err != nil {
+			return nil, err
+		}
+		weightedBackends.Backends[backendName] = weight
+	}
+
+	return weightedBackends, nil
 }
 
 func (p *Provider) loadConfig(templateObjects types.Configuration) *types.Configuration {
@@ -506,13 +591,38 @@ func getBuffering(service *corev1.Service) *types.Buffering {
 	return buffering
 }
 
+func getHealthCheck(service *corev1.Service) *types.HealthCheck {
+	var healthCheck *types.HealthCheck
+
+	healthCheckRaw := getStringValue(service.Annotations, annotationKubernetesHealthCheck, "")
+
+	if len(healthCheckRaw) > 0 {
+		healthCheck = &types.HealthCheck{}
+		err := yaml.Unmarshal([]byte(healthCheckRaw), healthCheck)
+		if err != nil {
+			log.Error(err)
+			return nil
+		}
+		if len(healthCheck.Path) == 0 {
+			log.Errorf("Missing path in health check of service %s/%s, skipping...", service.Namespace, service.Name)
+			return nil
+		}
+	}
+
+	return healthCheck
+}
+
 func getLoadBalancer(service *corev1.Service) *types.LoadBalancer {
 	loadBalancer := &types.LoadBalancer{
 		Method: "wrr",
 	}
 
-	if getStringValue(service.Annotations, annotationKubernetesLoadBalancerMethod, "") == "drr" {
-		loadBalancer.Method = "drr"
+	if method := getStringValue(service.Annotations, annotationKubernetesLoadBalancerMethod, ""); len(method) > 0 {
+		if _, err := types.NewLoadBalancerMethod(&types.LoadBalancer{Method: method}); err != nil {
+			log.Warnf("Invalid load balancer method for service %s/%s: %v", service.Namespace, service.Name, err)
+		} else {
+			loadBalancer.Method = method
+		}
 	}
 
 	if sticky := service.Annotations[label.TraefikBackendLoadBalancerSticky]; len(sticky) > 0 {
@@ -621,3 +731,19 @@ func getRateLimit(i *extensionsv1beta1.Ingress) *types.RateLimit {
 
 	return rateLimit
 }
+
+func getRetry(i *extensionsv1beta1.Ingress) *types.Retry {
+	var retry *types.Retry
+
+	retryRaw := getStringValue(i.Annotations, annotationKubernetesRetry, "")
+	if len(retryRaw) > 0 {
+		retry = &types.Retry{}
+		err := yaml.Unmarshal([]byte(retryRaw), retry)
+		if err != nil {
+			log.Error(err)
+			return nil
+		}
+	}
+
+	return retry
+}
@@ -737,6 +737,27 @@ rateset:
 					iPaths(onePath(iPath("/ratelimit"), iBackend("service1", intstr.FromInt(80))))),
 			),
 		),
+		buildIngress(
+			iNamespace("testing"),
+			iAnnotation(annotationKubernetesIngressClass, "traefik"),
+			iAnnotation(annotationKubernetesRetry, `
+attempts: 3
+statuscodes:
+- "502"
+- "503"
+methods:
+- GET
+- POST
+initialinterval: 100ms
+maxinterval: 1s
+budget: 0.2
+`),
+			iRules(
+				iRule(
+					iHost("retry"),
+					iPaths(onePath(iPath("/retry"), iBackend("service1", intstr.FromInt(80))))),
+			),
+		),
 		buildIngress(
 			iNamespace("testing"),
 			iAnnotation(annotationKubernetesIngressClass, "traefik"),
@@ -874,6 +895,12 @@ rateset:
 					server("http://example.com", weight(1))),
 				lbMethod("wrr"),
 			),
+			backend("retry/retry",
+				servers(
+					server("http://example.com", weight(1)),
+					server("http://example.com", weight(1))),
+				lbMethod("wrr"),
+			),
 			backend("custom-headers/customheaders",
 				servers(
 					server("http://example.com", weight(1)),
@@ -957,6 +984,18 @@ rateset:
 					route("/ratelimit", "PathPrefix:/ratelimit"),
 					route("rate-limit", "Host:rate-limit")),
 			),
+			frontend("retry/retry",
+				passHostHeader(),
+				retry(
+					retryAttempts(3),
+					retryStatusCodes("502", "503"),
+					retryMethods("GET", "POST"),
+					retryIntervals(100*time.Millisecond, time.Second),
+					retryBudget(0.2)),
+				routes(
+					route("/retry", "PathPrefix:/retry"),
+					route("retry", "Host:retry")),
+			),
 			frontend("custom-headers/customheaders",
 				passHostHeader(),
 				headers(&types.Headers{
@@ -1207,6 +1246,280 @@ func TestPriorityHeaderValue(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestServiceWeights(t *testing.T) {
+	ingresses := []*extensionsv1beta1.Ingress{
+		buildIngress(
+			iNamespace("testing"),
+			iAnnotation(annotationKubernetesServiceWeights, "service1: 90\nservice2: 10\nunknown: 5\n"),
+			iRules(
+				iRule(
+					iHost("foo"),
+					iPaths(onePath(iPath("/bar"), iBackend("service1", intstr.FromInt(80))))),
+			),
+		),
+		buildIngress(
+			iNamespace("testing"),
+			iAnnotation(annotationKubernetesServiceWeights, "service1: [90\n"),
+			iRules(
+				iRule(
+					iHost("invalid"),
+					iPaths(onePath(iPath("/bar"), iBackend("service1", intstr.FromInt(80))))),
+			),
+		),
+	}
+
+	services := []*corev1.Service{
+		buildService(
+			sName("service1"),
+			sNamespace("testing"),
+			sUID("1"),
+			sSpec(
+				clusterIP("10.0.0.1"),
+				sType("ExternalName"),
+				sExternalName("example.com"),
+				sPorts(sPort(80, "http"))),
+		),
+		buildService(
+			sName("service2"),
+			sNamespace("testing"),
+			sUID("2"),
+			sSpec(
+				clusterIP("10.0.0.2"),
+				sType("ExternalName"),
+				sExternalName("canary.example.com"),
+				sPorts(sPort(80, "http"))),
+		),
+	}
+
+	var endpoints []*corev1.Endpoints
+	watchChan := make(chan interface{})
+	client := clientMock{
+		ingresses: ingresses,
+		services:  services,
+		endpoints: endpoints,
+		watchChan: watchChan,
+	}
+	provider := Provider{}
+
+	actual, err := provider.loadIngresses(client)
+	require.NoError(t, err, "error loading ingresses")
+
+	expected := buildConfiguration(
+		backends(
+			backend("foo/bar",
+				servers(server("http://example.com", weight(1))),
+				lbMethod("wrr"),
+			),
+			backend("foo/bar@service2",
+				servers(server("http://canary.example.com", weight(1))),
+				lbMethod("wrr"),
+			),
+		),
+		frontends(
+			frontend("foo/bar",
+				passHostHeader(),
+				weightedBackend("foo/bar", 90),
+				weightedBackend("foo/bar@service2", 10),
+				routes(
+					route("/bar", "PathPrefix:/bar"),
+					route("foo", "Host:foo")),
+			),
+		),
+	)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestGetHealthCheck(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		service  *corev1.Service
+		expected *types.HealthCheck
+	}{
+		{
+			desc:     "no health check annotation",
+			service:  buildService(sName("service1"), sNamespace("testing")),
+			expected: nil,
+		},
+		{
+			desc: "health check annotation",
+			service: buildService(
+				sName("service1"),
+				sNamespace("testing"),
+				sAnnotation(annotationKubernetesHealthCheck, `
+mode: grpc
+path: /health
+interval: 10s
+timeout: 3s
+hostname: foo.bar
+headers:
+  X-Foo: bar
+status:
+  - 200-299
+  - "302"
+bodyregex: ^OK$
+healthythreshold: 2
+unhealthythreshold: 3
+`)),
+			expected: &types.HealthCheck{
+				Mode:               "grpc",
+				Path:               "/health",
+				Interval:           "10s",
+				Timeout:            "3s",
+				Hostname:           "foo.bar",
+				Headers:            map[string]string{"X-Foo": "bar"},
+				Status:             []string{"200-299", "302"},
+				BodyRegex:          "^OK$",
+				HealthyThreshold:   2,
+				UnhealthyThreshold: 3,
+			},
+		},
+		{
+			desc: "health check annotation without path",
+			service: buildService(
+				sName("service1"),
+				sNamespace("testing"),
+				sAnnotation(annotationKubernetesHealthCheck, `interval: 10s`)),
+			expected: nil,
+		},
+		{
+			desc: "invalid health check annotation",
+			service: buildService(
+				sName("service1"),
+				sNamespace("testing"),
+				sAnnotation(annotationKubernetesHealthCheck, `path: [`)),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, getHealthCheck(test.service))
+		})
+	}
+}
+
+func TestLoadServiceServersProtocol(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		port        int32
+		annotations map[string]string
+		expected    string
+	}{
+		{
+			desc:     "default protocol",
+			port:     80,
+			expected: "http://10.10.0.1:8080",
+		},
+		{
+			desc:     "https port",
+			port:     443,
+			expected: "https://10.10.0.1:8080",
+		},
+		{
+			desc:        "h2c protocol annotation",
+			port:        80,
+			annotations: map[string]string{annotationKubernetesProtocol: "h2c"},
+			expected:    "h2c://10.10.0.1:8080",
+		},
+		{
+			desc:        "protocol annotation on https port",
+			port:        443,
+			annotations: map[string]string{annotationKubernetesProtocol: "h2c"},
+			expected:    "h2c://10.10.0.1:8080",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			service := buildService(
+				sName("service1"),
+				sNamespace("testing"),
+				sUID("1"),
+				sSpec(
+					clusterIP("10.0.0.1"),
+					sPorts(sPort(test.port, ""))),
+			)
+			service.Annotations = test.annotations
+
+			client := clientMock{
+				endpoints: []*corev1.Endpoints{
+					buildEndpoint(
+						eNamespace("testing"),
+						eName("service1"),
+						eUID("1"),
+						subset(
+							eAddresses(eAddress("10.10.0.1")),
+							ePorts(ePort(8080, ""))),
+					),
+				},
+			}
+
+			backend := &types.Backend{Servers: make(map[string]types.Server)}
+			err := loadServiceServers(client, backend, service, intstr.FromInt(int(test.port)))
+			require.NoError(t, err)
+
+			require.Len(t, backend.Servers, 1)
+			for _, server := range backend.Servers {
+				assert.Equal(t, test.expected, server.URL)
+			}
+		})
+	}
+}
+
+func TestGetLoadBalancerMethod(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		method   string
+		expected string
+	}{
+		{
+			desc:     "no method",
+			expected: "wrr",
+		},
+		{
+			desc:     "drr",
+			method:   "drr",
+			expected: "drr",
+		},
+		{
+			desc:     "least connections",
+			method:   "LeastConn",
+			expected: "LeastConn",
+		},
+		{
+			desc:     "ewma",
+			method:   "ewma",
+			expected: "ewma",
+		},
+		{
+			desc:     "invalid method",
+			method:   "foo",
+			expected: "wrr",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			service := buildService(sName("service1"), sNamespace("testing"))
+			if len(test.method) > 0 {
+				service = buildService(sName("service1"), sNamespace("testing"), sAnnotation(annotationKubernetesLoadBalancerMethod, test.method))
+			}
+
+			assert.Equal(t, test.expected, getLoadBalancer(service).Method)
+		})
+	}
+}
+
 func TestInvalidPassTLSCertValue(t *testing.T) {
 	ingresses := []*extensionsv1beta1.Ingress{
 		buildIngress(
@@ -1,26 +1,37 @@
 package kv
 
 const (
-	pathBackends                                = "/backends/"
-	pathBackendCircuitBreakerExpression         = "/circuitbreaker/expression"
-	pathBackendHealthCheckPath                  = "/healthcheck/path"
-	pathBackendHealthCheckPort                  = "/healthcheck/port"
-	pathBackendHealthCheckInterval              = "/healthcheck/interval"
-	pathBackendLoadBalancerMethod               = "/loadbalancer/method"
-	pathBackendLoadBalancerSticky               = "/loadbalancer/sticky"
-	pathBackendLoadBalancerStickiness           = "/loadbalancer/stickiness"
-	pathBackendLoadBalancerStickinessCookieName = "/loadbalancer/stickiness/cookiename"
-	pathBackendMaxConnAmount                    = "/maxconn/amount"
-	pathBackendMaxConnExtractorFunc             = "/maxconn/extractorfunc"
-	pathBackendServers                          = "/servers/"
-	pathBackendServerURL                        = "/url"
-	pathBackendServerWeight                     = "/weight"
-	pathBackendBuffering                        = "/buffering/"
-	pathBackendBufferingMaxResponseBodyBytes    = pathBackendBuffering + "maxresponsebodybytes"
-	pathBackendBufferingMemResponseBodyBytes    = pathBackendBuffering + "memresponsebodybytes"
-	pathBackendBufferingMaxRequestBodyBytes     = pathBackendBuffering + "maxrequestbodybytes"
-	pathBackendBufferingMemRequestBodyBytes     = pathBackendBuffering + "memrequestbodybytes"
-	pathBackendBufferingRetryExpression         = pathBackendBuffering + "retryexpression"
+	pathBackends                                       = "/backends/"
+	pathBackendCircuitBreakerExpression                = "/circuitbreaker/expression"
+	pathBackendHealthCheckPath                         = "/healthcheck/path"
+	pathBackendHealthCheckPort                         = "/healthcheck/port"
+	pathBackendHealthCheckInterval                     = "/healthcheck/interval"
+	pathBackendHealthCheckMode                         = "/healthcheck/mode"
+	pathBackendHealthCheckScheme                       = "/healthcheck/scheme"
+	pathBackendHealthCheckTimeout                      = "/healthcheck/timeout"
+	pathBackendHealthCheckHostname                     = "/healthcheck/hostname"
+	pathBackendHealthCheckHeaders                      = "/healthcheck/headers/"
+	pathBackendHealthCheckStatus                       = "/healthcheck/status"
+	pathBackendHealthCheckBodyRegex                    = "/healthcheck/bodyregex"
+	pathBackendHealthCheckHealthyThreshold             = "/healthcheck/healthythreshold"
+	pathBackendHealthCheckUnhealthyThreshold           = "/healthcheck/unhealthythreshold"
+	pathBackendLoadBalancerMethod                      = "/loadbalancer/method"
+	pathBackendLoadBalancerSticky                      = "/loadbalancer/sticky"
+	pathBackendLoadBalancerStickiness                  = "/loadbalancer/stickiness"
+	pathBackendLoadBalancerStickinessCookieName        = "/loadbalancer/stickiness/cookiename"
+	pathBackendLoadBalancerConsistentHashExtractorFunc = "/loadbalancer/consistenthash/extractorfunc"
+	pathBackendLoadBalancerConsistentHashLoadFactor    = "/loadbalancer/consistenthash/loadfactor"
+	pathBackendMaxConnAmount                           = "/maxconn/amount"
+	pathBackendMaxConnExtractorFunc                    = "/maxconn/extractorfunc"
+	pathBackendServers                                 = "/servers/"
+	pathBackendServerURL                               = "/url"
+	pathBackendServerWeight                            = "/weight"
+	pathBackendBuffering                               = "/buffering/"
+	pathBackendBufferingMaxResponseBodyBytes           = pathBackendBuffering + "maxresponsebodybytes"
+	pathBackendBufferingMemResponseBodyBytes           = pathBackendBuffering + "memresponsebodybytes"
+	pathBackendBufferingMaxRequestBodyBytes            = pathBackendBuffering + "maxrequestbodybytes"
+	pathBackendBufferingMemRequestBodyBytes            = pathBackendBuffering + "memrequestbodybytes"
+	pathBackendBufferingRetryExpression                = pathBackendBuffering + "retryexpression"
 
 	pathFrontends                      = "/frontends/"
 	pathFrontendBackend                = "/backend"
@@ -41,10 +52,66 @@ const (
 	pathFrontendRateLimit              = "/ratelimit/"
 	pathFrontendRateLimitRateSet       = pathFrontendRateLimit + "rateset/"
 	pathFrontendRateLimitExtractorFunc = pathFrontendRateLimit + "extractorfunc"
+	pathFrontendRateLimitDistributed   = pathFrontendRateLimit + "distributed"
 	pathFrontendRateLimitPeriod        = "/period"
 	pathFrontendRateLimitAverage       = "/average"
 	pathFrontendRateLimitBurst         = "/burst"
 
+	pathFrontendAccessLog                         = "/accesslog/"
+	pathFrontendAccessLogFilters                  = pathFrontendAccessLog + "filters/"
+	pathFrontendAccessLogFiltersStatusCodes       = pathFrontendAccessLogFilters + "statuscodes"
+	pathFrontendAccessLogFiltersRetryAttempts     = pathFrontendAccessLogFilters + "retryattempts"
+	pathFrontendAccessLogFiltersMinDuration       = pathFrontendAccessLogFilters + "minduration"
+	pathFrontendAccessLogFields                   = pathFrontendAccessLog + "fields/"
+	pathFrontendAccessLogFieldsDefaultMode        = pathFrontendAccessLogFields + "defaultmode"
+	pathFrontendAccessLogFieldsNames              = pathFrontendAccessLogFields + "names/"
+	pathFrontendAccessLogFieldsHeaders            = pathFrontendAccessLogFields + "headers/"
+	pathFrontendAccessLogFieldsHeadersDefaultMode = pathFrontendAccessLogFieldsHeaders + "defaultmode"
+	pathFrontendAccessLogFieldsHeadersNames       = pathFrontendAccessLogFieldsHeaders + "names/"
+
+	pathFrontendRetry                = "/retry/"
+	pathFrontendRetryAttempts        = pathFrontendRetry + "attempts"
+	pathFrontendRetryStatusCodes     = pathFrontendRetry + "statuscodes"
+	pathFrontendRetryMethods         = pathFrontendRetry + "methods"
+	pathFrontendRetryInitialInterval = pathFrontendRetry + "initialinterval"
+	pathFrontendRetryMaxInterval     = pathFrontendRetry + "maxinterval"
+	pathFrontendRetryBudget          = pathFrontendRetry + "budget"
+
+	pathFrontendCompression                     = "/compression/"
+	pathFrontendCompressionEnable               = pathFrontendCompression + "enable"
+	pathFrontendCompressionEncodings            = pathFrontendCompression + "encodings"
+	pathFrontendCompressionMinSize              = pathFrontendCompression + "minsize"
+	pathFrontendCompressionGzipLevel            = pathFrontendCompression + "gziplevel"
+	pathFrontendCompressionBrotliLevel          = pathFrontendCompression + "brotlilevel"
+	pathFrontendCompressionIncludedContentTypes = pathFrontendCompression + "includedcontenttypes"
+	pathFrontendCompressionExcludedContentTypes = pathFrontendCompression + "excludedcontenttypes"
+
+	pathFrontendCache            = "/cache/"
+	pathFrontendCacheEnable      = pathFrontendCache + "enable"
+	pathFrontendCacheStorage     = pathFrontendCache + "storage"
+	pathFrontendCachePath        = pathFrontendCache + "path"
+	pathFrontendCacheMaxEntries  = pathFrontendCache + "maxentries"
+	pathFrontendCacheMaxBodySize = pathFrontendCache + "maxbodysize"
+	pathFrontendCacheDefaultTTL  = pathFrontendCache + "defaultttl"
+
+	pathFrontendAuthHeaderField            = "/auth/headerfield"
+	pathFrontendAuthJWT                    = "/auth/jwt/"
+	pathFrontendAuthJWTSecret              = pathFrontendAuthJWT + "secret"
+	pathFrontendAuthJWTPublicKeys          = pathFrontendAuthJWT + "publickeys"
+	pathFrontendAuthJWTJWKS                = pathFrontendAuthJWT + "jwks"
+	pathFrontendAuthJWTJWKSRefreshInterval = pathFrontendAuthJWT + "jwksrefreshinterval"
+	pathFrontendAuthJWTIssuer              = pathFrontendAuthJWT + "issuer"
+	pathFrontendAuthJWTAudiences           = pathFrontendAuthJWT + "audiences"
+	pathFrontendAuthJWTClockSkew           = pathFrontendAuthJWT + "clockskew"
+	pathFrontendAuthJWTAllowNoExpiration   = pathFrontendAuthJWT + "allownoexpiration"
+	pathFrontendAuthJWTClaimHeaders        = pathFrontendAuthJWT + "claimheaders/"
+
+	pathFrontendTLSClientCert                = "/tlsclientcert/"
+	pathFrontendTLSClientCertPEM             = pathFrontendTLSClientCert + "pem"
+	pathFrontendTLSClientCertFields          = pathFrontendTLSClientCert + "fields"
+	pathFrontendTLSClientCertAllowedSubjects = pathFrontendTLSClientCert + "allowedsubjects"
+	pathFrontendTLSClientCertAllowedSANs     = pathFrontendTLSClientCert + "allowedsans"
+
 	pathFrontendCustomRequestHeaders    = "/headers/customrequestheaders/"
 	pathFrontendCustomResponseHeaders   = "/headers/customresponseheaders/"
 	pathFrontendAllowedHosts            = "/headers/allowedhosts"
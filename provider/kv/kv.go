@@ -61,6 +61,18 @@ func (p *Provider) SetKVClient(kvClient store.Store) {
 	p.kvClient = kvClient
 }
 
+// BuildConfiguration builds the configuration from the current content of the KV store
+func (p *Provider) BuildConfiguration() (*types.Configuration, error) {
+	if p.kvClient == nil {
+		return nil, errors.New("no KV store client")
+	}
+	configuration := p.buildConfiguration()
+	if configuration == nil {
+		return nil, errors.New("error building the configuration from the KV store")
+	}
+	return configuration, nil
+}
+
 func (p *Provider) watchKv(configurationChan chan<- types.ConfigMessage, prefix string, stop chan bool) error {
 	operation := func() error {
 		events, err := p.kvClient.WatchTree(p.Prefix, make(chan struct{}), nil)
@@ -111,7 +123,10 @@ func (p *Provider) Provide(configurationChan chan<- types.ConfigMessage, pool *s
 				}
 			})
 		}
-		configuration := p.buildConfiguration()
+		configuration, err := p.BuildConfiguration()
+		if err != nil {
+			return err
+		}
 		configurationChan <- types.ConfigMessage{
 			ProviderName:  string(p.storeType),
 			Configuration: configuration,
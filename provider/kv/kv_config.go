@@ -53,6 +53,12 @@ func (p *Provider) buildConfiguration() *types.Configuration {
 		"getErrorPages":           p.getErrorPages,
 		"getRateLimit":            p.getRateLimit,
 		"getHeaders":              p.getHeaders,
+		"getAccessLog":            p.getAccessLog,
+		"getRetry":                p.getRetry,
+		"getCompression":          p.getCompression,
+		"getCache":                p.getCache,
+		"getAuth":                 p.getAuth,
+		"getTLSClientCert":        p.getTLSClientCert,
 
 		// Backend functions
 		"getServers":              p.getServers,
@@ -69,6 +75,7 @@ func (p *Provider) buildConfiguration() *types.Configuration {
 	configuration, err := p.GetConfiguration("templates/kv.tmpl", KvFuncMap, templateObjects)
 	if err != nil {
 		log.Error(err)
+		return nil
 	}
 
 	for key, frontend := range configuration.Frontends {
@@ -185,6 +192,7 @@ func (p *Provider) getRateLimit(rootPath string) *types.RateLimit {
 	return &types.RateLimit{
 		ExtractorFunc: extractorFunc,
 		RateSet:       limits,
+		Distributed:   p.getBool(false, rootPath, pathFrontendRateLimitDistributed),
 	}
 }
 
@@ -219,6 +227,172 @@ func (p *Provider) getHeaders(rootPath string) *types.Headers {
 	return headers
 }
 
+func (p *Provider) getAccessLog(rootPath string) *types.FrontendAccessLog {
+	if len(p.list(rootPath, pathFrontendAccessLog)) == 0 {
+		return nil
+	}
+
+	accessLog := &types.FrontendAccessLog{}
+
+	if len(p.list(rootPath, pathFrontendAccessLogFilters)) > 0 {
+		accessLog.Filters = &types.AccessLogFilters{
+			StatusCodes:   p.getList(rootPath, pathFrontendAccessLogFiltersStatusCodes),
+			RetryAttempts: p.getBool(false, rootPath, pathFrontendAccessLogFiltersRetryAttempts),
+		}
+
+		rawMinDuration := p.get("", rootPath, pathFrontendAccessLogFiltersMinDuration)
+		if len(rawMinDuration) > 0 {
+			if err := accessLog.Filters.MinDuration.Set(rawMinDuration); err != nil {
+				log.Errorf("Invalid %q value: %q", rootPath+pathFrontendAccessLogFiltersMinDuration, rawMinDuration)
+			}
+		}
+	}
+
+	if len(p.list(rootPath, pathFrontendAccessLogFields)) > 0 {
+		accessLog.Fields = &types.AccessLogFields{
+			DefaultMode: p.get("", rootPath, pathFrontendAccessLogFieldsDefaultMode),
+			Names:       p.getFieldNames(rootPath, pathFrontendAccessLogFieldsNames),
+		}
+
+		if len(p.list(rootPath, pathFrontendAccessLogFieldsHeaders)) > 0 {
+			accessLog.Fields.Headers = &types.FieldHeaders{
+				DefaultMode: p.get("", rootPath, pathFrontendAccessLogFieldsHeadersDefaultMode),
+				Names:       p.getFieldNames(rootPath, pathFrontendAccessLogFieldsHeadersNames),
+			}
+		}
+	}
+
+	return accessLog
+}
+
+// getFieldNames returns the modes of access log fields or headers, the sub keys being the names.
+func (p *Provider) getFieldNames(keyParts ...string) types.FieldNames {
+	var names types.FieldNames
+
+	for _, name := range p.list(keyParts...) {
+		if names == nil {
+			names = make(types.FieldNames)
+		}
+		names[p.last(name)] = p.get("", name)
+	}
+
+	return names
+}
+
+func (p *Provider) getRetry(rootPath string) *types.Retry {
+	if len(p.list(rootPath, pathFrontendRetry)) == 0 {
+		return nil
+	}
+
+	retry := &types.Retry{
+		Attempts:    p.getInt(0, rootPath, pathFrontendRetryAttempts),
+		StatusCodes: p.getList(rootPath, pathFrontendRetryStatusCodes),
+		Methods:     p.getList(rootPath, pathFrontendRetryMethods),
+		Budget:      p.getFloat64(0, rootPath, pathFrontendRetryBudget),
+	}
+
+	rawInitialInterval := p.get("", rootPath, pathFrontendRetryInitialInterval)
+	if len(rawInitialInterval) > 0 {
+		if err := retry.InitialInterval.Set(rawInitialInterval); err != nil {
+			log.Errorf("Invalid %q value: %q", rootPath+pathFrontendRetryInitialInterval, rawInitialInterval)
+		}
+	}
+
+	rawMaxInterval := p.get("", rootPath, pathFrontendRetryMaxInterval)
+	if len(rawMaxInterval) > 0 {
+		if err := retry.MaxInterval.Set(rawMaxInterval); err != nil {
+			log.Errorf("Invalid %q value: %q", rootPath+pathFrontendRetryMaxInterval, rawMaxInterval)
+		}
+	}
+
+	return retry
+}
+
+func (p *Provider) getCompression(rootPath string) *types.Compression {
+	if len(p.list(rootPath, pathFrontendCompression)) == 0 || !p.getBool(true, rootPath, pathFrontendCompressionEnable) {
+		return nil
+	}
+
+	return &types.Compression{
+		Encodings:            p.getList(rootPath, pathFrontendCompressionEncodings),
+		MinSize:              p.getInt(0, rootPath, pathFrontendCompressionMinSize),
+		GzipLevel:            p.getInt(0, rootPath, pathFrontendCompressionGzipLevel),
+		BrotliLevel:          p.getInt(0, rootPath, pathFrontendCompressionBrotliLevel),
+		IncludedContentTypes: p.getList(rootPath, pathFrontendCompressionIncludedContentTypes),
+		ExcludedContentTypes: p.getList(rootPath, pathFrontendCompressionExcludedContentTypes),
+	}
+}
+
+func (p *Provider) getCache(rootPath string) *types.Cache {
+	if len(p.list(rootPath, pathFrontendCache)) == 0 || !p.getBool(true, rootPath, pathFrontendCacheEnable) {
+		return nil
+	}
+
+	cache := &types.Cache{
+		Storage:     p.get("", rootPath, pathFrontendCacheStorage),
+		Path:        p.get("", rootPath, pathFrontendCachePath),
+		MaxEntries:  p.getInt(0, rootPath, pathFrontendCacheMaxEntries),
+		MaxBodySize: p.getInt64(0, rootPath, pathFrontendCacheMaxBodySize),
+	}
+
+	rawDefaultTTL := p.get("", rootPath, pathFrontendCacheDefaultTTL)
+	if len(rawDefaultTTL) > 0 {
+		if err := cache.DefaultTTL.Set(rawDefaultTTL); err != nil {
+			log.Errorf("Invalid %q value: %q", rootPath+pathFrontendCacheDefaultTTL, rawDefaultTTL)
+		}
+	}
+
+	return cache
+}
+
+func (p *Provider) getAuth(rootPath string) *types.Auth {
+	if len(p.list(rootPath, pathFrontendAuthJWT)) == 0 {
+		return nil
+	}
+
+	jwt := &types.JWT{
+		Secret:            p.get("", rootPath, pathFrontendAuthJWTSecret),
+		PublicKeys:        p.getList(rootPath, pathFrontendAuthJWTPublicKeys),
+		JWKS:              p.get("", rootPath, pathFrontendAuthJWTJWKS),
+		Issuer:            p.get("", rootPath, pathFrontendAuthJWTIssuer),
+		Audiences:         p.getList(rootPath, pathFrontendAuthJWTAudiences),
+		ClaimHeaders:      p.getMap(rootPath, pathFrontendAuthJWTClaimHeaders),
+		AllowNoExpiration: p.getBool(false, rootPath, pathFrontendAuthJWTAllowNoExpiration),
+	}
+
+	rawRefreshInterval := p.get("", rootPath, pathFrontendAuthJWTJWKSRefreshInterval)
+	if len(rawRefreshInterval) > 0 {
+		if err := jwt.JWKSRefreshInterval.Set(rawRefreshInterval); err != nil {
+			log.Errorf("Invalid %q value: %q", rootPath+pathFrontendAuthJWTJWKSRefreshInterval, rawRefreshInterval)
+		}
+	}
+
+	rawClockSkew := p.get("", rootPath, pathFrontendAuthJWTClockSkew)
+	if len(rawClockSkew) > 0 {
+		if err := jwt.ClockSkew.Set(rawClockSkew); err != nil {
+			log.Errorf("Invalid %q value: %q", rootPath+pathFrontendAuthJWTClockSkew, rawClockSkew)
+		}
+	}
+
+	return &types.Auth{
+		JWT:         jwt,
+		HeaderField: p.get("", rootPath, pathFrontendAuthHeaderField),
+	}
+}
+
+func (p *Provider) getTLSClientCert(rootPath string) *types.TLSClientCert {
+	if len(p.list(rootPath, pathFrontendTLSClientCert)) == 0 {
+		return nil
+	}
+
+	return &types.TLSClientCert{
+		PEM:             p.getBool(false, rootPath, pathFrontendTLSClientCertPEM),
+		Fields:          p.getList(rootPath, pathFrontendTLSClientCertFields),
+		AllowedSubjects: p.getList(rootPath, pathFrontendTLSClientCertAllowedSubjects),
+		AllowedSANs:     p.getList(rootPath, pathFrontendTLSClientCertAllowedSANs),
+	}
+}
+
 func (p *Provider) getLoadBalancer(rootPath string) *types.LoadBalancer {
 	lb := &types.LoadBalancer{
 		Method: p.get(label.DefaultBackendLoadBalancerMethod, rootPath, pathBackendLoadBalancerMethod),
@@ -231,6 +405,13 @@ func (p *Provider) getLoadBalancer(rootPath string) *types.LoadBalancer {
 		}
 	}
 
+	if p.has(rootPath, pathBackendLoadBalancerConsistentHashExtractorFunc) || p.has(rootPath, pathBackendLoadBalancerConsistentHashLoadFactor) {
+		lb.ConsistentHash = &types.ConsistentHashing{
+			ExtractorFunc: p.get("", rootPath, pathBackendLoadBalancerConsistentHashExtractorFunc),
+			LoadFactor:    p.getFloat64(0, rootPath, pathBackendLoadBalancerConsistentHashLoadFactor),
+		}
+	}
+
 	return lb
 }
 
@@ -272,9 +453,18 @@ func (p *Provider) getHealthCheck(rootPath string) *types.HealthCheck {
 	interval := p.get("30s", rootPath, pathBackendHealthCheckInterval)
 
 	return &types.HealthCheck{
-		Path:     path,
-		Port:     port,
-		Interval: interval,
+		Mode:               p.get("", rootPath, pathBackendHealthCheckMode),
+		Scheme:             p.get("", rootPath, pathBackendHealthCheckScheme),
+		Path:               path,
+		Port:               port,
+		Interval:           interval,
+		Timeout:            p.get("", rootPath, pathBackendHealthCheckTimeout),
+		Hostname:           p.get("", rootPath, pathBackendHealthCheckHostname),
+		Headers:            p.getMap(rootPath, pathBackendHealthCheckHeaders),
+		Status:             p.getList(rootPath, pathBackendHealthCheckStatus),
+		BodyRegex:          p.get("", rootPath, pathBackendHealthCheckBodyRegex),
+		HealthyThreshold:   p.getInt(0, rootPath, pathBackendHealthCheckHealthyThreshold),
+		UnhealthyThreshold: p.getInt(0, rootPath, pathBackendHealthCheckUnhealthyThreshold),
 	}
 }
 
@@ -506,6 +696,21 @@ func (p *Provider) getInt64(defaultValue int64, keyParts ...string) int64 {
 	return value
 }
 
+func (p *Provider) getFloat64(defaultValue float64, keyParts ...string) float64 {
+	rawValue := p.get("", keyParts...)
+
+	if len(rawValue) == 0 {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseFloat(rawValue, 64)
+	if err != nil {
+		log.Errorf("Invalid value for %v: %s", keyParts, rawValue)
+		return defaultValue
+	}
+	return value
+}
+
 func (p *Provider) list(keyParts ...string) []string {
 	rootKey := strings.Join(keyParts, "")
 
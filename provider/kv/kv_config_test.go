@@ -62,6 +62,57 @@ func TestProviderBuildConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "JWT auth",
+			kvPairs: filler("traefik",
+				frontend("frontend",
+					withPair("backend", "backend"),
+					withPair("routes/route/rule", "Host:test.localhost"),
+					withPair(pathFrontendAuthHeaderField, "X-WebAuth-User"),
+					withPair(pathFrontendAuthJWTSecret, `my"secret\`),
+					withPair(pathFrontendAuthJWTPublicKeys, "-----BEGIN PUBLIC KEY-----\nMFkwEwYHKoZIzj0C\n-----END PUBLIC KEY-----"),
+					withPair(pathFrontendAuthJWTIssuer, `https://auth.example.com/"realm"`),
+					withPair(pathFrontendAuthJWTAllowNoExpiration, "true")),
+				backend("backend",
+					withPair("servers/server/url", "http://172.17.0.2:80")),
+			),
+			expected: &types.Configuration{
+				Backends: map[string]*types.Backend{
+					"backend": {
+						LoadBalancer: &types.LoadBalancer{Method: label.DefaultBackendLoadBalancerMethod},
+						Servers: map[string]types.Server{
+							"server": {
+								URL:    "http://172.17.0.2:80",
+								Weight: 0,
+							},
+						},
+					},
+				},
+				Frontends: map[string]*types.Frontend{
+					"frontend": {
+						Backend:        "backend",
+						PassHostHeader: true,
+						EntryPoints:    []string{},
+						BasicAuth:      []string{},
+						Auth: &types.Auth{
+							HeaderField: "X-WebAuth-User",
+							JWT: &types.JWT{
+								Secret:            `my"secret\`,
+								PublicKeys:        []string{"-----BEGIN PUBLIC KEY-----\nMFkwEwYHKoZIzj0C\n-----END PUBLIC KEY-----"},
+								Issuer:            `https://auth.example.com/"realm"`,
+								Audiences:         []string{},
+								AllowNoExpiration: true,
+							},
+						},
+						Routes: map[string]types.Route{
+							"route": {
+								Rule: "Host:test.localhost",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			desc: "all parameters",
 			kvPairs: filler("traefik",
@@ -71,6 +122,8 @@ func TestProviderBuildConfiguration(t *testing.T) {
 					withPair(pathBackendLoadBalancerSticky, "true"),
 					withPair(pathBackendLoadBalancerStickiness, "true"),
 					withPair(pathBackendLoadBalancerStickinessCookieName, "tomate"),
+					withPair(pathBackendLoadBalancerConsistentHashExtractorFunc, "request.header.X-Tenant"),
+					withPair(pathBackendLoadBalancerConsistentHashLoadFactor, "1.5"),
 					withPair(pathBackendHealthCheckPath, "/health"),
 					withPair(pathBackendHealthCheckPort, "80"),
 					withPair(pathBackendHealthCheckInterval, "30s"),
@@ -158,6 +211,10 @@ func TestProviderBuildConfiguration(t *testing.T) {
 							Stickiness: &types.Stickiness{
 								CookieName: "tomate",
 							},
+							ConsistentHash: &types.ConsistentHashing{
+								ExtractorFunc: "request.header.X-Tenant",
+								LoadFactor:    1.5,
+							},
 						},
 						MaxConn: &types.MaxConn{
 							Amount:        5,
@@ -1198,6 +1255,26 @@ func TestProviderGetRateLimit(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:     "with distributed limits",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withRateLimit("client.ip",
+						withPair(pathFrontendRateLimitDistributed, "true"),
+						withLimit("foo", "6", "12", "18")))),
+			expected: &types.RateLimit{
+				ExtractorFunc: "client.ip",
+				Distributed:   true,
+				RateSet: map[string]*types.Rate{
+					"foo": {
+						Average: 6,
+						Burst:   12,
+						Period:  flaeg.Duration(18 * time.Second),
+					},
+				},
+			},
+		},
 		{
 			desc:     "return nil when no extractor func",
 			rootPath: "traefik/frontends/foo",
@@ -1230,6 +1307,361 @@ func TestProviderGetRateLimit(t *testing.T) {
 	}
 }
 
+func TestProviderGetAccessLog(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.FrontendAccessLog
+	}{
+		{
+			desc:     "with filters and fields",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendAccessLogFiltersStatusCodes, "200,500-599"),
+					withPair(pathFrontendAccessLogFiltersRetryAttempts, "true"),
+					withPair(pathFrontendAccessLogFiltersMinDuration, "10ms"),
+					withPair(pathFrontendAccessLogFieldsDefaultMode, "drop"),
+					withPair(pathFrontendAccessLogFieldsNames+"ClientUsername", "redact"),
+					withPair(pathFrontendAccessLogFieldsHeadersDefaultMode, "keep"),
+					withPair(pathFrontendAccessLogFieldsHeadersNames+"Authorization", "redact"))),
+			expected: &types.FrontendAccessLog{
+				Filters: &types.AccessLogFilters{
+					StatusCodes:   types.StatusCodes{"200", "500-599"},
+					RetryAttempts: true,
+					MinDuration:   flaeg.Duration(10 * time.Millisecond),
+				},
+				Fields: &types.AccessLogFields{
+					DefaultMode: "drop",
+					Names:       types.FieldNames{"ClientUsername": "redact"},
+					Headers: &types.FieldHeaders{
+						DefaultMode: "keep",
+						Names:       types.FieldNames{"Authorization": "redact"},
+					},
+				},
+			},
+		},
+		{
+			desc:     "with fields only",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendAccessLogFieldsDefaultMode, "drop"))),
+			expected: &types.FrontendAccessLog{
+				Fields: &types.AccessLogFields{
+					DefaultMode: "drop",
+				},
+			},
+		},
+		{
+			desc:     "return nil when no access log keys",
+			rootPath: "traefik/frontends/foo",
+			kvPairs:  filler("traefik", frontend("foo")),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			actual := p.getAccessLog(test.rootPath)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestProviderGetRetry(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.Retry
+	}{
+		{
+			desc:     "with all the keys",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendRetryAttempts, "3"),
+					withPair(pathFrontendRetryStatusCodes, "502,503"),
+					withPair(pathFrontendRetryMethods, "GET,POST"),
+					withPair(pathFrontendRetryInitialInterval, "100ms"),
+					withPair(pathFrontendRetryMaxInterval, "1s"),
+					withPair(pathFrontendRetryBudget, "0.2"))),
+			expected: &types.Retry{
+				Attempts:        3,
+				StatusCodes:     types.StatusCodes{"502", "503"},
+				Methods:         []string{"GET", "POST"},
+				InitialInterval: flaeg.Duration(100 * time.Millisecond),
+				MaxInterval:     flaeg.Duration(time.Second),
+				Budget:          0.2,
+			},
+		},
+		{
+			desc:     "return nil when no retry keys",
+			rootPath: "traefik/frontends/foo",
+			kvPairs:  filler("traefik", frontend("foo")),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			actual := p.getRetry(test.rootPath)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestProviderGetCache(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.Cache
+	}{
+		{
+			desc:     "with all the keys",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendCacheStorage, "disk"),
+					withPair(pathFrontendCachePath, "/var/cache/traefik"),
+					withPair(pathFrontendCacheMaxEntries, "100"),
+					withPair(pathFrontendCacheMaxBodySize, "4096"),
+					withPair(pathFrontendCacheDefaultTTL, "30s"))),
+			expected: &types.Cache{
+				Storage:     "disk",
+				Path:        "/var/cache/traefik",
+				MaxEntries:  100,
+				MaxBodySize: 4096,
+				DefaultTTL:  flaeg.Duration(30 * time.Second),
+			},
+		},
+		{
+			desc:     "enabled with the default settings",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendCacheEnable, "true"))),
+			expected: &types.Cache{},
+		},
+		{
+			desc:     "disabled",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendCacheEnable, "false"),
+					withPair(pathFrontendCacheMaxEntries, "100"))),
+			expected: nil,
+		},
+		{
+			desc:     "return nil when no cache keys",
+			rootPath: "traefik/frontends/foo",
+			kvPairs:  filler("traefik", frontend("foo")),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			actual := p.getCache(test.rootPath)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestProviderGetAuth(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.Auth
+	}{
+		{
+			desc:     "with all the keys",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendAuthHeaderField, "X-WebAuth-User"),
+					withPair(pathFrontendAuthJWTSecret, "mysecret"),
+					withPair(pathFrontendAuthJWTPublicKeys, "/keys/rsa.pem,/keys/ec.pem"),
+					withPair(pathFrontendAuthJWTJWKS, "https://auth.example.com/jwks.json"),
+					withPair(pathFrontendAuthJWTJWKSRefreshInterval, "30m"),
+					withPair(pathFrontendAuthJWTIssuer, "https://auth.example.com"),
+					withPair(pathFrontendAuthJWTAudiences, "api,admin"),
+					withPair(pathFrontendAuthJWTClockSkew, "30s"),
+					withPair(pathFrontendAuthJWTAllowNoExpiration, "true"),
+					withPair(pathFrontendAuthJWTClaimHeaders+"X-User", "sub"),
+					withPair(pathFrontendAuthJWTClaimHeaders+"X-Roles", "roles"))),
+			expected: &types.Auth{
+				JWT: &types.JWT{
+					Secret:              "mysecret",
+					PublicKeys:          []string{"/keys/rsa.pem", "/keys/ec.pem"},
+					JWKS:                "https://auth.example.com/jwks.json",
+					JWKSRefreshInterval: flaeg.Duration(30 * time.Minute),
+					Issuer:              "https://auth.example.com",
+					Audiences:           []string{"api", "admin"},
+					ClockSkew:           flaeg.Duration(30 * time.Second),
+					AllowNoExpiration:   true,
+					ClaimHeaders:        map[string]string{"X-User": "sub", "X-Roles": "roles"},
+				},
+				HeaderField: "X-WebAuth-User",
+			},
+		},
+		{
+			desc:     "return nil when no JWT keys",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendAuthHeaderField, "X-WebAuth-User"))),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			actual := p.getAuth(test.rootPath)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestProviderGetTLSClientCert(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.TLSClientCert
+	}{
+		{
+			desc:     "with all the keys",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendTLSClientCertPEM, "true"),
+					withPair(pathFrontendTLSClientCertFields, "subjectCN,serial"),
+					withPair(pathFrontendTLSClientCertAllowedSubjects, "client"),
+					withPair(pathFrontendTLSClientCertAllowedSANs, "spiffe://example.com/.*"))),
+			expected: &types.TLSClientCert{
+				PEM:             true,
+				Fields:          []string{"subjectCN", "serial"},
+				AllowedSubjects: []string{"client"},
+				AllowedSANs:     []string{"spiffe://example.com/.*"},
+			},
+		},
+		{
+			desc:     "when no keys",
+			rootPath: "traefik/frontends/foo",
+			kvPairs:  filler("traefik", frontend("foo")),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			actual := p.getTLSClientCert(test.rootPath)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestProviderGetCompression(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rootPath string
+		kvPairs  []*store.KVPair
+		expected *types.Compression
+	}{
+		{
+			desc:     "with all the keys",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendCompressionEncodings, "br,gzip"),
+					withPair(pathFrontendCompressionMinSize, "1024"),
+					withPair(pathFrontendCompressionGzipLevel, "9"),
+					withPair(pathFrontendCompressionBrotliLevel, "4"),
+					withPair(pathFrontendCompressionIncludedContentTypes, "text/*,application/json"),
+					withPair(pathFrontendCompressionExcludedContentTypes, "text/event-stream"))),
+			expected: &types.Compression{
+				Encodings:            []string{"br", "gzip"},
+				MinSize:              1024,
+				GzipLevel:            9,
+				BrotliLevel:          4,
+				IncludedContentTypes: []string{"text/*", "application/json"},
+				ExcludedContentTypes: []string{"text/event-stream"},
+			},
+		},
+		{
+			desc:     "enabled with the default settings",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendCompressionEnable, "true"))),
+			expected: &types.Compression{},
+		},
+		{
+			desc:     "disabled",
+			rootPath: "traefik/frontends/foo",
+			kvPairs: filler("traefik",
+				frontend("foo",
+					withPair(pathFrontendCompressionEnable, "false"),
+					withPair(pathFrontendCompressionMinSize, "1024"))),
+			expected: nil,
+		},
+		{
+			desc:     "return nil when no compression keys",
+			rootPath: "traefik/frontends/foo",
+			kvPairs:  filler("traefik", frontend("foo")),
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProviderMock(test.kvPairs)
+
+			actual := p.getCompression(test.rootPath)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
 func TestProviderGetHeaders(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -1517,6 +1949,21 @@ func TestProviderGetLoadBalancer(t *testing.T) {
 				Method: "wrr",
 			},
 		},
+		{
+			desc:     "when consistent hash is set",
+			rootPath: "traefik/backends/foo",
+			kvPairs: filler("traefik",
+				backend("foo",
+					withPair(pathBackendLoadBalancerMethod, "ConsistentHash"),
+					withPair(pathBackendLoadBalancerConsistentHashExtractorFunc, "client.ip"),
+					withPair(pathBackendLoadBalancerConsistentHashLoadFactor, "foo"))),
+			expected: &types.LoadBalancer{
+				Method: "ConsistentHash",
+				ConsistentHash: &types.ConsistentHashing{
+					ExtractorFunc: "client.ip",
+				},
+			},
+		},
 		{
 			desc:     "when method is set",
 			rootPath: "traefik/backends/foo",
@@ -1694,13 +2141,31 @@ func TestProviderGetHealthCheck(t *testing.T) {
 			rootPath: "traefik/backends/foo",
 			kvPairs: filler("traefik",
 				backend("foo",
+					withPair(pathBackendHealthCheckMode, "grpc"),
+					withPair(pathBackendHealthCheckScheme, "https"),
 					withPair(pathBackendHealthCheckPath, "/health"),
 					withPair(pathBackendHealthCheckPort, "80"),
-					withPair(pathBackendHealthCheckInterval, "10s"))),
+					withPair(pathBackendHealthCheckInterval, "10s"),
+					withPair(pathBackendHealthCheckTimeout, "3s"),
+					withPair(pathBackendHealthCheckHostname, "foo.bar"),
+					withPair(pathBackendHealthCheckHeaders+"X-Foo", "bar"),
+					withPair(pathBackendHealthCheckStatus, "200-299,302"),
+					withPair(pathBackendHealthCheckBodyRegex, "^OK$"),
+					withPair(pathBackendHealthCheckHealthyThreshold, "2"),
+					withPair(pathBackendHealthCheckUnhealthyThreshold, "3"))),
 			expected: &types.HealthCheck{
-				Interval: "10s",
-				Path:     "/health",
-				Port:     80,
+				Mode:               "grpc",
+				Scheme:             "https",
+				Interval:           "10s",
+				Path:               "/health",
+				Port:               80,
+				Timeout:            "3s",
+				Hostname:           "foo.bar",
+				Headers:            map[string]string{"X-Foo": "bar"},
+				Status:             []string{"200-299", "302"},
+				BodyRegex:          "^OK$",
+				HealthyThreshold:   2,
+				UnhealthyThreshold: 3,
 			},
 		},
 		{
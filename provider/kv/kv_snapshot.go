@@ -0,0 +1,122 @@
+package kv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/abronan/valkeyrie/store"
+)
+
+var errSnapshotReadOnly = errors.New("KV snapshot is read-only")
+
+// SnapshotStore is a read-only store holding the key/value pairs of a snapshot of a KV store,
+// allowing to build the configuration of a KV store without connecting to it.
+type SnapshotStore map[string]string
+
+// LoadSnapshotStore reads a snapshot of a KV store from a JSON file holding an object of the key/value pairs
+func LoadSnapshotStore(filename string) (SnapshotStore, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := make(map[string]string)
+	if err := json.Unmarshal(content, &pairs); err != nil {
+		return nil, fmt.Errorf("error reading KV snapshot %s: %v", filename, err)
+	}
+
+	snapshot := make(SnapshotStore, len(pairs))
+	for key, value := range pairs {
+		snapshot[strings.TrimPrefix(key, pathSeparator)] = value
+	}
+	return snapshot, nil
+}
+
+// Get returns the pair of the key
+func (s SnapshotStore) Get(key string, options *store.ReadOptions) (*store.KVPair, error) {
+	value, ok := s[strings.TrimPrefix(key, pathSeparator)]
+	if !ok {
+		return nil, store.ErrKeyNotFound
+	}
+	return &store.KVPair{Key: key, Value: []byte(value)}, nil
+}
+
+// Exists tells whether keys exist under the key
+func (s SnapshotStore) Exists(key string, options *store.ReadOptions) (bool, error) {
+	key = strings.TrimPrefix(key, pathSeparator)
+	for k := range s {
+		if strings.HasPrefix(k, key) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// List returns the pairs of the keys under the prefix, recursively
+func (s SnapshotStore) List(prefix string, options *store.ReadOptions) ([]*store.KVPair, error) {
+	trimmedPrefix := strings.TrimPrefix(prefix, pathSeparator)
+
+	var keys []string
+	for key := range s {
+		if strings.HasPrefix(key, trimmedPrefix) && key != trimmedPrefix {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+	sort.Strings(keys)
+
+	var pairs []*store.KVPair
+	for _, key := range keys {
+		pairs = append(pairs, &store.KVPair{Key: prefix + strings.TrimPrefix(key, trimmedPrefix), Value: []byte(s[key])})
+	}
+	return pairs, nil
+}
+
+// Put is not supported
+func (s SnapshotStore) Put(key string, value []byte, options *store.WriteOptions) error {
+	return errSnapshotReadOnly
+}
+
+// Delete is not supported
+func (s SnapshotStore) Delete(key string) error {
+	return errSnapshotReadOnly
+}
+
+// Watch is not supported
+func (s SnapshotStore) Watch(key string, stopCh <-chan struct{}, options *store.ReadOptions) (<-chan *store.KVPair, error) {
+	return nil, errSnapshotReadOnly
+}
+
+// WatchTree is not supported
+func (s SnapshotStore) WatchTree(directory string, stopCh <-chan struct{}, options *store.ReadOptions) (<-chan []*store.KVPair, error) {
+	return nil, errSnapshotReadOnly
+}
+
+// NewLock is not supported
+func (s SnapshotStore) NewLock(key string, options *store.LockOptions) (store.Locker, error) {
+	return nil, errSnapshotReadOnly
+}
+
+// DeleteTree is not supported
+func (s SnapshotStore) DeleteTree(directory string) error {
+	return errSnapshotReadOnly
+}
+
+// AtomicPut is not supported
+func (s SnapshotStore) AtomicPut(key string, value []byte, previous *store.KVPair, options *store.WriteOptions) (bool, *store.KVPair, error) {
+	return false, nil, errSnapshotReadOnly
+}
+
+// AtomicDelete is not supported
+func (s SnapshotStore) AtomicDelete(key string, previous *store.KVPair) (bool, error) {
+	return false, errSnapshotReadOnly
+}
+
+// Close does nothing
+func (s SnapshotStore) Close() {}
@@ -0,0 +1,63 @@
+package kv
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotStoreList(t *testing.T) {
+	snapshot := SnapshotStore{
+		"traefik/backends/backend1/servers/server1/url": "http://127.0.0.1:80",
+		"traefik/backends/backend1/servers/server2/url": "http://127.0.0.1:81",
+		"traefik/frontends/frontend1/backend":           "backend1",
+	}
+
+	pairs, err := snapshot.List("/traefik/backends/", nil)
+	require.NoError(t, err)
+
+	expected := []*store.KVPair{
+		{Key: "/traefik/backends/backend1/servers/server1/url", Value: []byte("http://127.0.0.1:80")},
+		{Key: "/traefik/backends/backend1/servers/server2/url", Value: []byte("http://127.0.0.1:81")},
+	}
+	assert.Equal(t, expected, pairs)
+
+	_, err = snapshot.List("traefik/tls/", nil)
+	assert.Equal(t, store.ErrKeyNotFound, err)
+
+	assert.Equal(t, errSnapshotReadOnly, snapshot.Put("traefik/foo", []byte("bar"), nil))
+}
+
+func TestLoadSnapshotStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traefik-kv-snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "snapshot.json")
+	content := `{
+  "/traefik/backends/backend1/servers/server1/url": "http://127.0.0.1:80",
+  "/traefik/frontends/frontend1/backend": "backend1",
+  "/traefik/frontends/frontend1/routes/route1/rule": "Host:foo.bar"
+}`
+	require.NoError(t, ioutil.WriteFile(filename, []byte(content), 0644))
+
+	snapshot, err := LoadSnapshotStore(filename)
+	require.NoError(t, err)
+
+	provider := &Provider{Prefix: "traefik"}
+	provider.SetKVClient(snapshot)
+
+	config, err := provider.BuildConfiguration()
+	require.NoError(t, err)
+
+	require.Contains(t, config.Backends, "backend1")
+	assert.Equal(t, "http://127.0.0.1:80", config.Backends["backend1"].Servers["server1"].URL)
+	require.Contains(t, config.Frontends, "frontend1")
+	assert.Equal(t, "backend1", config.Frontends["frontend1"].Backend)
+	assert.Equal(t, "Host:foo.bar", config.Frontends["frontend1"].Routes["route1"].Rule)
+}
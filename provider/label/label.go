@@ -141,6 +141,18 @@ func GetInt64ValueP(labels *map[string]string, labelName string, defaultValue in
 	return GetInt64Value(*labels, labelName, defaultValue)
 }
 
+// GetFloat64Value get float64 value associated to a label
+func GetFloat64Value(labels map[string]string, labelName string, defaultValue float64) float64 {
+	if rawValue, ok := labels[labelName]; ok {
+		value, err := strconv.ParseFloat(rawValue, 64)
+		if err == nil {
+			return value
+		}
+		log.Errorf("Unable to parse %q: %q, falling back to %v. %v", labelName, rawValue, defaultValue, err)
+	}
+	return defaultValue
+}
+
 // GetSliceStringValue get a slice of string associated to a label
 func GetSliceStringValue(labels map[string]string, labelName string) []string {
 	var value []string
@@ -392,6 +404,33 @@ func ParseRateSets(labels map[string]string, labelPrefix string, labelRegex *reg
 	return rateSets
 }
 
+// ParseWeightedBackends parses a list of weighted backends, formatted as "backend1:weight1||backend2:weight2".
+// The entries with an invalid weight are skipped.
+func ParseWeightedBackends(labelName, values string) map[string]int {
+	weights := make(map[string]int)
+
+	for _, parts := range strings.Split(values, mapEntrySeparator) {
+		pair := strings.SplitN(parts, mapValueSeparator, 2)
+		if len(pair) != 2 {
+			log.Warnf("Could not load %q: %q, skipping...", labelName, parts)
+			continue
+		}
+
+		weight, err := strconv.Atoi(strings.TrimSpace(pair[1]))
+		if err != nil || weight < 0 {
+			log.Warnf("Invalid weight for %q: %q, skipping...", labelName, parts)
+			continue
+		}
+		weights[strings.TrimSpace(pair[0])] = weight
+	}
+
+	if len(weights) == 0 {
+		log.Errorf("Could not load %q, skipping...", labelName)
+		return nil
+	}
+	return weights
+}
+
 // IsEnabled Check if a container is enabled in Træfik
 func IsEnabled(labels map[string]string, exposedByDefault bool) bool {
 	return GetBoolValue(labels, TraefikEnable, exposedByDefault)
@@ -309,6 +309,57 @@ func TestGetIntValueP(t *testing.T) {
 	}
 }
 
+func TestGetFloat64Value(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		labels       map[string]string
+		labelName    string
+		defaultValue float64
+		expected     float64
+	}{
+		{
+			desc:      "empty map",
+			labelName: "foo",
+		},
+		{
+			desc:      "invalid float value",
+			labelName: "foo",
+			labels: map[string]string{
+				"foo": "bar",
+			},
+			defaultValue: 666,
+			expected:     666,
+		},
+		{
+			desc:      "integer value",
+			labelName: "foo",
+			labels: map[string]string{
+				"foo": "2",
+			},
+			defaultValue: 666,
+			expected:     2,
+		},
+		{
+			desc:      "float value",
+			labelName: "foo",
+			labels: map[string]string{
+				"foo": "1.25",
+			},
+			defaultValue: 666,
+			expected:     1.25,
+		},
+	}
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			got := GetFloat64Value(test.labels, test.labelName, test.defaultValue)
+			assert.Equal(t, test.expected, got)
+		})
+	}
+}
+
 func TestGetInt64Value(t *testing.T) {
 	testCases := []struct {
 		desc         string
@@ -1137,3 +1188,38 @@ func TestParseRateSets(t *testing.T) {
 		})
 	}
 }
+
+func TestParseWeightedBackends(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    string
+		expected map[string]int
+	}{
+		{
+			desc:     "2 weighted backends",
+			value:    "v1:90||v2: 10",
+			expected: map[string]int{"v1": 90, "v2": 10},
+		},
+		{
+			desc:     "invalid weight",
+			value:    "v1:90||v2:foo||v3:-1",
+			expected: map[string]int{"v1": 90},
+		},
+		{
+			desc:     "no valid weighted backend",
+			value:    "v1",
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			weights := ParseWeightedBackends(TraefikFrontendWeightedBackends, test.value)
+
+			assert.Equal(t, test.expected, weights)
+		})
+	}
+}
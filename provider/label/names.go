@@ -2,138 +2,262 @@ package label
 
 // Traefik labels
 const (
-	Prefix                                         = "traefik."
-	SuffixBackend                                  = "backend"
-	SuffixDomain                                   = "domain"
-	SuffixEnable                                   = "enable"
-	SuffixPort                                     = "port"
-	SuffixPortIndex                                = "portIndex"
-	SuffixProtocol                                 = "protocol"
-	SuffixTags                                     = "tags"
-	SuffixWeight                                   = "weight"
-	SuffixBackendID                                = "backend.id"
-	SuffixBackendCircuitBreaker                    = "backend.circuitbreaker"
-	SuffixBackendCircuitBreakerExpression          = "backend.circuitbreaker.expression"
-	SuffixBackendHealthCheckPath                   = "backend.healthcheck.path"
-	SuffixBackendHealthCheckPort                   = "backend.healthcheck.port"
-	SuffixBackendHealthCheckInterval               = "backend.healthcheck.interval"
-	SuffixBackendLoadBalancer                      = "backend.loadbalancer"
-	SuffixBackendLoadBalancerMethod                = SuffixBackendLoadBalancer + ".method"
-	SuffixBackendLoadBalancerSticky                = SuffixBackendLoadBalancer + ".sticky"
-	SuffixBackendLoadBalancerStickiness            = SuffixBackendLoadBalancer + ".stickiness"
-	SuffixBackendLoadBalancerStickinessCookieName  = SuffixBackendLoadBalancer + ".stickiness.cookieName"
-	SuffixBackendMaxConnAmount                     = "backend.maxconn.amount"
-	SuffixBackendMaxConnExtractorFunc              = "backend.maxconn.extractorfunc"
-	SuffixBackendBuffering                         = "backend.buffering"
-	SuffixBackendBufferingMaxRequestBodyBytes      = SuffixBackendBuffering + ".maxRequestBodyBytes"
-	SuffixBackendBufferingMemRequestBodyBytes      = SuffixBackendBuffering + ".memRequestBodyBytes"
-	SuffixBackendBufferingMaxResponseBodyBytes     = SuffixBackendBuffering + ".maxResponseBodyBytes"
-	SuffixBackendBufferingMemResponseBodyBytes     = SuffixBackendBuffering + ".memResponseBodyBytes"
-	SuffixBackendBufferingRetryExpression          = SuffixBackendBuffering + ".retryExpression"
-	SuffixFrontend                                 = "frontend"
-	SuffixFrontendAuthBasic                        = "frontend.auth.basic"
-	SuffixFrontendBackend                          = "frontend.backend"
-	SuffixFrontendEntryPoints                      = "frontend.entryPoints"
-	SuffixFrontendHeaders                          = "frontend.headers."
-	SuffixFrontendRequestHeaders                   = SuffixFrontendHeaders + "customRequestHeaders"
-	SuffixFrontendResponseHeaders                  = SuffixFrontendHeaders + "customResponseHeaders"
-	SuffixFrontendHeadersAllowedHosts              = SuffixFrontendHeaders + "allowedHosts"
-	SuffixFrontendHeadersHostsProxyHeaders         = SuffixFrontendHeaders + "hostsProxyHeaders"
-	SuffixFrontendHeadersSSLRedirect               = SuffixFrontendHeaders + "SSLRedirect"
-	SuffixFrontendHeadersSSLTemporaryRedirect      = SuffixFrontendHeaders + "SSLTemporaryRedirect"
-	SuffixFrontendHeadersSSLHost                   = SuffixFrontendHeaders + "SSLHost"
-	SuffixFrontendHeadersSSLProxyHeaders           = SuffixFrontendHeaders + "SSLProxyHeaders"
-	SuffixFrontendHeadersSTSSeconds                = SuffixFrontendHeaders + "STSSeconds"
-	SuffixFrontendHeadersSTSIncludeSubdomains      = SuffixFrontendHeaders + "STSIncludeSubdomains"
-	SuffixFrontendHeadersSTSPreload                = SuffixFrontendHeaders + "STSPreload"
-	SuffixFrontendHeadersForceSTSHeader            = SuffixFrontendHeaders + "forceSTSHeader"
-	SuffixFrontendHeadersFrameDeny                 = SuffixFrontendHeaders + "frameDeny"
-	SuffixFrontendHeadersCustomFrameOptionsValue   = SuffixFrontendHeaders + "customFrameOptionsValue"
-	SuffixFrontendHeadersContentTypeNosniff        = SuffixFrontendHeaders + "contentTypeNosniff"
-	SuffixFrontendHeadersBrowserXSSFilter          = SuffixFrontendHeaders + "browserXSSFilter"
-	SuffixFrontendHeadersContentSecurityPolicy     = SuffixFrontendHeaders + "contentSecurityPolicy"
-	SuffixFrontendHeadersPublicKey                 = SuffixFrontendHeaders + "publicKey"
-	SuffixFrontendHeadersReferrerPolicy            = SuffixFrontendHeaders + "referrerPolicy"
-	SuffixFrontendHeadersIsDevelopment             = SuffixFrontendHeaders + "isDevelopment"
-	SuffixFrontendPassHostHeader                   = "frontend.passHostHeader"
-	SuffixFrontendPassTLSCert                      = "frontend.passTLSCert"
-	SuffixFrontendPriority                         = "frontend.priority"
-	SuffixFrontendRateLimitExtractorFunc           = "frontend.rateLimit.extractorFunc"
-	SuffixFrontendRedirectEntryPoint               = "frontend.redirect.entryPoint"
-	SuffixFrontendRedirectRegex                    = "frontend.redirect.regex"
-	SuffixFrontendRedirectReplacement              = "frontend.redirect.replacement"
-	SuffixFrontendRedirectPermanent                = "frontend.redirect.permanent"
-	SuffixFrontendRule                             = "frontend.rule"
-	SuffixFrontendRuleType                         = "frontend.rule.type"
-	SuffixFrontendWhitelistSourceRange             = "frontend.whitelistSourceRange"
-	TraefikDomain                                  = Prefix + SuffixDomain
-	TraefikEnable                                  = Prefix + SuffixEnable
-	TraefikPort                                    = Prefix + SuffixPort
-	TraefikPortIndex                               = Prefix + SuffixPortIndex
-	TraefikProtocol                                = Prefix + SuffixProtocol
-	TraefikTags                                    = Prefix + SuffixTags
-	TraefikWeight                                  = Prefix + SuffixWeight
-	TraefikBackend                                 = Prefix + SuffixBackend
-	TraefikBackendID                               = Prefix + SuffixBackendID
-	TraefikBackendCircuitBreaker                   = Prefix + SuffixBackendCircuitBreaker
-	TraefikBackendCircuitBreakerExpression         = Prefix + SuffixBackendCircuitBreakerExpression
-	TraefikBackendHealthCheckPath                  = Prefix + SuffixBackendHealthCheckPath
-	TraefikBackendHealthCheckPort                  = Prefix + SuffixBackendHealthCheckPort
-	TraefikBackendHealthCheckInterval              = Prefix + SuffixBackendHealthCheckInterval
-	TraefikBackendLoadBalancer                     = Prefix + SuffixBackendLoadBalancer
-	TraefikBackendLoadBalancerMethod               = Prefix + SuffixBackendLoadBalancerMethod
-	TraefikBackendLoadBalancerSticky               = Prefix + SuffixBackendLoadBalancerSticky
-	TraefikBackendLoadBalancerStickiness           = Prefix + SuffixBackendLoadBalancerStickiness
-	TraefikBackendLoadBalancerStickinessCookieName = Prefix + SuffixBackendLoadBalancerStickinessCookieName
-	TraefikBackendMaxConnAmount                    = Prefix + SuffixBackendMaxConnAmount
-	TraefikBackendMaxConnExtractorFunc             = Prefix + SuffixBackendMaxConnExtractorFunc
-	TraefikBackendBuffering                        = Prefix + SuffixBackendBuffering
-	TraefikBackendBufferingMaxRequestBodyBytes     = Prefix + SuffixBackendBufferingMaxRequestBodyBytes
-	TraefikBackendBufferingMemRequestBodyBytes     = Prefix + SuffixBackendBufferingMemRequestBodyBytes
-	TraefikBackendBufferingMaxResponseBodyBytes    = Prefix + SuffixBackendBufferingMaxResponseBodyBytes
-	TraefikBackendBufferingMemResponseBodyBytes    = Prefix + SuffixBackendBufferingMemResponseBodyBytes
-	TraefikBackendBufferingRetryExpression         = Prefix + SuffixBackendBufferingRetryExpression
-	TraefikFrontend                                = Prefix + SuffixFrontend
-	TraefikFrontendAuthBasic                       = Prefix + SuffixFrontendAuthBasic
-	TraefikFrontendEntryPoints                     = Prefix + SuffixFrontendEntryPoints
-	TraefikFrontendPassHostHeader                  = Prefix + SuffixFrontendPassHostHeader
-	TraefikFrontendPassTLSCert                     = Prefix + SuffixFrontendPassTLSCert
-	TraefikFrontendPriority                        = Prefix + SuffixFrontendPriority
-	TraefikFrontendRateLimitExtractorFunc          = Prefix + SuffixFrontendRateLimitExtractorFunc
-	TraefikFrontendRedirectEntryPoint              = Prefix + SuffixFrontendRedirectEntryPoint
-	TraefikFrontendRedirectRegex                   = Prefix + SuffixFrontendRedirectRegex
-	TraefikFrontendRedirectReplacement             = Prefix + SuffixFrontendRedirectReplacement
-	TraefikFrontendRedirectPermanent               = Prefix + SuffixFrontendRedirectPermanent
-	TraefikFrontendRule                            = Prefix + SuffixFrontendRule
-	TraefikFrontendRuleType                        = Prefix + SuffixFrontendRuleType // k8s only
-	TraefikFrontendWhitelistSourceRange            = Prefix + SuffixFrontendWhitelistSourceRange
-	TraefikFrontendHeaders                         = Prefix + SuffixFrontendHeaders
-	TraefikFrontendRequestHeaders                  = Prefix + SuffixFrontendRequestHeaders
-	TraefikFrontendResponseHeaders                 = Prefix + SuffixFrontendResponseHeaders
-	TraefikFrontendAllowedHosts                    = Prefix + SuffixFrontendHeadersAllowedHosts
-	TraefikFrontendHostsProxyHeaders               = Prefix + SuffixFrontendHeadersHostsProxyHeaders
-	TraefikFrontendSSLRedirect                     = Prefix + SuffixFrontendHeadersSSLRedirect
-	TraefikFrontendSSLTemporaryRedirect            = Prefix + SuffixFrontendHeadersSSLTemporaryRedirect
-	TraefikFrontendSSLHost                         = Prefix + SuffixFrontendHeadersSSLHost
-	TraefikFrontendSSLProxyHeaders                 = Prefix + SuffixFrontendHeadersSSLProxyHeaders
-	TraefikFrontendSTSSeconds                      = Prefix + SuffixFrontendHeadersSTSSeconds
-	TraefikFrontendSTSIncludeSubdomains            = Prefix + SuffixFrontendHeadersSTSIncludeSubdomains
-	TraefikFrontendSTSPreload                      = Prefix + SuffixFrontendHeadersSTSPreload
-	TraefikFrontendForceSTSHeader                  = Prefix + SuffixFrontendHeadersForceSTSHeader
-	TraefikFrontendFrameDeny                       = Prefix + SuffixFrontendHeadersFrameDeny
-	TraefikFrontendCustomFrameOptionsValue         = Prefix + SuffixFrontendHeadersCustomFrameOptionsValue
-	TraefikFrontendContentTypeNosniff              = Prefix + SuffixFrontendHeadersContentTypeNosniff
-	TraefikFrontendBrowserXSSFilter                = Prefix + SuffixFrontendHeadersBrowserXSSFilter
-	TraefikFrontendContentSecurityPolicy           = Prefix + SuffixFrontendHeadersContentSecurityPolicy
-	TraefikFrontendPublicKey                       = Prefix + SuffixFrontendHeadersPublicKey
-	TraefikFrontendReferrerPolicy                  = Prefix + SuffixFrontendHeadersReferrerPolicy
-	TraefikFrontendIsDevelopment                   = Prefix + SuffixFrontendHeadersIsDevelopment
-	BaseFrontendErrorPage                          = "frontend.errors."
-	SuffixErrorPageBackend                         = "backend"
-	SuffixErrorPageQuery                           = "query"
-	SuffixErrorPageStatus                          = "status"
-	BaseFrontendRateLimit                          = "frontend.rateLimit.rateSet."
-	SuffixRateLimitPeriod                          = "period"
-	SuffixRateLimitAverage                         = "average"
-	SuffixRateLimitBurst                           = "burst"
+	Prefix                                                = "traefik."
+	SuffixBackend                                         = "backend"
+	SuffixDomain                                          = "domain"
+	SuffixEnable                                          = "enable"
+	SuffixPort                                            = "port"
+	SuffixPortIndex                                       = "portIndex"
+	SuffixProtocol                                        = "protocol"
+	SuffixTags                                            = "tags"
+	SuffixWeight                                          = "weight"
+	SuffixBackendID                                       = "backend.id"
+	SuffixBackendCircuitBreaker                           = "backend.circuitbreaker"
+	SuffixBackendCircuitBreakerExpression                 = "backend.circuitbreaker.expression"
+	SuffixBackendHealthCheckPath                          = "backend.healthcheck.path"
+	SuffixBackendHealthCheckPort                          = "backend.healthcheck.port"
+	SuffixBackendHealthCheckInterval                      = "backend.healthcheck.interval"
+	SuffixBackendHealthCheckMode                          = "backend.healthcheck.mode"
+	SuffixBackendHealthCheckScheme                        = "backend.healthcheck.scheme"
+	SuffixBackendHealthCheckTimeout                       = "backend.healthcheck.timeout"
+	SuffixBackendHealthCheckHostname                      = "backend.healthcheck.hostname"
+	SuffixBackendHealthCheckHeaders                       = "backend.healthcheck.headers"
+	SuffixBackendHealthCheckStatus                        = "backend.healthcheck.status"
+	SuffixBackendHealthCheckBodyRegex                     = "backend.healthcheck.bodyRegex"
+	SuffixBackendHealthCheckHealthyThreshold              = "backend.healthcheck.healthyThreshold"
+	SuffixBackendHealthCheckUnhealthyThreshold            = "backend.healthcheck.unhealthyThreshold"
+	SuffixBackendLoadBalancer                             = "backend.loadbalancer"
+	SuffixBackendLoadBalancerMethod                       = SuffixBackendLoadBalancer + ".method"
+	SuffixBackendLoadBalancerSticky                       = SuffixBackendLoadBalancer + ".sticky"
+	SuffixBackendLoadBalancerStickiness                   = SuffixBackendLoadBalancer + ".stickiness"
+	SuffixBackendLoadBalancerStickinessCookieName         = SuffixBackendLoadBalancer + ".stickiness.cookieName"
+	SuffixBackendLoadBalancerConsistentHash               = SuffixBackendLoadBalancer + ".consistentHash"
+	SuffixBackendLoadBalancerConsistentHashExtractorFunc  = SuffixBackendLoadBalancerConsistentHash + ".extractorFunc"
+	SuffixBackendLoadBalancerConsistentHashLoadFactor     = SuffixBackendLoadBalancerConsistentHash + ".loadFactor"
+	SuffixBackendMaxConnAmount                            = "backend.maxconn.amount"
+	SuffixBackendMaxConnExtractorFunc                     = "backend.maxconn.extractorfunc"
+	SuffixBackendBuffering                                = "backend.buffering"
+	SuffixBackendBufferingMaxRequestBodyBytes             = SuffixBackendBuffering + ".maxRequestBodyBytes"
+	SuffixBackendBufferingMemRequestBodyBytes             = SuffixBackendBuffering + ".memRequestBodyBytes"
+	SuffixBackendBufferingMaxResponseBodyBytes            = SuffixBackendBuffering + ".maxResponseBodyBytes"
+	SuffixBackendBufferingMemResponseBodyBytes            = SuffixBackendBuffering + ".memResponseBodyBytes"
+	SuffixBackendBufferingRetryExpression                 = SuffixBackendBuffering + ".retryExpression"
+	SuffixFrontend                                        = "frontend"
+	SuffixFrontendAccessLog                               = "frontend.accessLog"
+	SuffixFrontendAccessLogFiltersStatusCodes             = SuffixFrontendAccessLog + ".filters.statusCodes"
+	SuffixFrontendAccessLogFiltersRetryAttempts           = SuffixFrontendAccessLog + ".filters.retryAttempts"
+	SuffixFrontendAccessLogFiltersMinDuration             = SuffixFrontendAccessLog + ".filters.minDuration"
+	SuffixFrontendAccessLogFieldsDefaultMode              = SuffixFrontendAccessLog + ".fields.defaultMode"
+	SuffixFrontendAccessLogFieldsNames                    = SuffixFrontendAccessLog + ".fields.names"
+	SuffixFrontendAccessLogFieldsHeadersDefaultMode       = SuffixFrontendAccessLog + ".fields.headers.defaultMode"
+	SuffixFrontendAccessLogFieldsHeadersNames             = SuffixFrontendAccessLog + ".fields.headers.names"
+	SuffixFrontendAuthBasic                               = "frontend.auth.basic"
+	SuffixFrontendAuthHeaderField                         = "frontend.auth.headerField"
+	SuffixFrontendAuthJWT                                 = "frontend.auth.jwt"
+	SuffixFrontendAuthJWTSecret                           = SuffixFrontendAuthJWT + ".secret"
+	SuffixFrontendAuthJWTPublicKeys                       = SuffixFrontendAuthJWT + ".publicKeys"
+	SuffixFrontendAuthJWTJWKS                             = SuffixFrontendAuthJWT + ".jwks"
+	SuffixFrontendAuthJWTJWKSRefreshInterval              = SuffixFrontendAuthJWT + ".jwksRefreshInterval"
+	SuffixFrontendAuthJWTIssuer                           = SuffixFrontendAuthJWT + ".issuer"
+	SuffixFrontendAuthJWTAudiences                        = SuffixFrontendAuthJWT + ".audiences"
+	SuffixFrontendAuthJWTClockSkew                        = SuffixFrontendAuthJWT + ".clockSkew"
+	SuffixFrontendAuthJWTAllowNoExpiration                = SuffixFrontendAuthJWT + ".allowNoExpiration"
+	SuffixFrontendAuthJWTClaimHeaders                     = SuffixFrontendAuthJWT + ".claimHeaders"
+	SuffixFrontendBackend                                 = "frontend.backend"
+	SuffixFrontendCache                                   = "frontend.cache"
+	SuffixFrontendCacheEnable                             = SuffixFrontendCache + ".enable"
+	SuffixFrontendCacheStorage                            = SuffixFrontendCache + ".storage"
+	SuffixFrontendCachePath                               = SuffixFrontendCache + ".path"
+	SuffixFrontendCacheMaxEntries                         = SuffixFrontendCache + ".maxEntries"
+	SuffixFrontendCacheMaxBodySize                        = SuffixFrontendCache + ".maxBodySize"
+	SuffixFrontendCacheDefaultTTL                         = SuffixFrontendCache + ".defaultTTL"
+	SuffixFrontendCompression                             = "frontend.compression"
+	SuffixFrontendCompressionEnable                       = SuffixFrontendCompression + ".enable"
+	SuffixFrontendCompressionEncodings                    = SuffixFrontendCompression + ".encodings"
+	SuffixFrontendCompressionMinSize                      = SuffixFrontendCompression + ".minSize"
+	SuffixFrontendCompressionGzipLevel                    = SuffixFrontendCompression + ".gzipLevel"
+	SuffixFrontendCompressionBrotliLevel                  = SuffixFrontendCompression + ".brotliLevel"
+	SuffixFrontendCompressionIncludedContentTypes         = SuffixFrontendCompression + ".includedContentTypes"
+	SuffixFrontendCompressionExcludedContentTypes         = SuffixFrontendCompression + ".excludedContentTypes"
+	SuffixFrontendEntryPoints                             = "frontend.entryPoints"
+	SuffixFrontendHeaders                                 = "frontend.headers."
+	SuffixFrontendRequestHeaders                          = SuffixFrontendHeaders + "customRequestHeaders"
+	SuffixFrontendResponseHeaders                         = SuffixFrontendHeaders + "customResponseHeaders"
+	SuffixFrontendHeadersAllowedHosts                     = SuffixFrontendHeaders + "allowedHosts"
+	SuffixFrontendHeadersHostsProxyHeaders                = SuffixFrontendHeaders + "hostsProxyHeaders"
+	SuffixFrontendHeadersSSLRedirect                      = SuffixFrontendHeaders + "SSLRedirect"
+	SuffixFrontendHeadersSSLTemporaryRedirect             = SuffixFrontendHeaders + "SSLTemporaryRedirect"
+	SuffixFrontendHeadersSSLHost                          = SuffixFrontendHeaders + "SSLHost"
+	SuffixFrontendHeadersSSLProxyHeaders                  = SuffixFrontendHeaders + "SSLProxyHeaders"
+	SuffixFrontendHeadersSTSSeconds                       = SuffixFrontendHeaders + "STSSeconds"
+	SuffixFrontendHeadersSTSIncludeSubdomains             = SuffixFrontendHeaders + "STSIncludeSubdomains"
+	SuffixFrontendHeadersSTSPreload                       = SuffixFrontendHeaders + "STSPreload"
+	SuffixFrontendHeadersForceSTSHeader                   = SuffixFrontendHeaders + "forceSTSHeader"
+	SuffixFrontendHeadersFrameDeny                        = SuffixFrontendHeaders + "frameDeny"
+	SuffixFrontendHeadersCustomFrameOptionsValue          = SuffixFrontendHeaders + "customFrameOptionsValue"
+	SuffixFrontendHeadersContentTypeNosniff               = SuffixFrontendHeaders + "contentTypeNosniff"
+	SuffixFrontendHeadersBrowserXSSFilter                 = SuffixFrontendHeaders + "browserXSSFilter"
+	SuffixFrontendHeadersContentSecurityPolicy            = SuffixFrontendHeaders + "contentSecurityPolicy"
+	SuffixFrontendHeadersPublicKey                        = SuffixFrontendHeaders + "publicKey"
+	SuffixFrontendHeadersReferrerPolicy                   = SuffixFrontendHeaders + "referrerPolicy"
+	SuffixFrontendHeadersIsDevelopment                    = SuffixFrontendHeaders + "isDevelopment"
+	SuffixFrontendPassHostHeader                          = "frontend.passHostHeader"
+	SuffixFrontendPassTLSCert                             = "frontend.passTLSCert"
+	SuffixFrontendPriority                                = "frontend.priority"
+	SuffixFrontendRateLimitExtractorFunc                  = "frontend.rateLimit.extractorFunc"
+	SuffixFrontendRateLimitDistributed                    = "frontend.rateLimit.distributed"
+	SuffixFrontendRedirectEntryPoint                      = "frontend.redirect.entryPoint"
+	SuffixFrontendRedirectRegex                           = "frontend.redirect.regex"
+	SuffixFrontendRedirectReplacement                     = "frontend.redirect.replacement"
+	SuffixFrontendRedirectPermanent                       = "frontend.redirect.permanent"
+	SuffixFrontendRetry                                   = "frontend.retry"
+	SuffixFrontendRetryAttempts                           = SuffixFrontendRetry + ".attempts"
+	SuffixFrontendRetryStatusCodes                        = SuffixFrontendRetry + ".statusCodes"
+	SuffixFrontendRetryMethods                            = SuffixFrontendRetry + ".methods"
+	SuffixFrontendRetryInitialInterval                    = SuffixFrontendRetry + ".initialInterval"
+	SuffixFrontendRetryMaxInterval                        = SuffixFrontendRetry + ".maxInterval"
+	SuffixFrontendRetryBudget                             = SuffixFrontendRetry + ".budget"
+	SuffixFrontendRule                                    = "frontend.rule"
+	SuffixFrontendRuleType                                = "frontend.rule.type"
+	SuffixFrontendTLSClientCert                           = "frontend.tlsClientCert"
+	SuffixFrontendTLSClientCertPEM                        = SuffixFrontendTLSClientCert + ".pem"
+	SuffixFrontendTLSClientCertFields                     = SuffixFrontendTLSClientCert + ".fields"
+	SuffixFrontendTLSClientCertAllowedSubjects            = SuffixFrontendTLSClientCert + ".allowedSubjects"
+	SuffixFrontendTLSClientCertAllowedSANs                = SuffixFrontendTLSClientCert + ".allowedSANs"
+	SuffixFrontendWhitelistSourceRange                    = "frontend.whitelistSourceRange"
+	SuffixFrontendWeightedBackends                        = "frontend.weightedBackends"
+	SuffixFrontendWeightedBackendsStickiness              = SuffixFrontendWeightedBackends + ".stickiness"
+	SuffixFrontendWeightedBackendsCookieName              = SuffixFrontendWeightedBackends + ".stickiness.cookieName"
+	TraefikDomain                                         = Prefix + SuffixDomain
+	TraefikEnable                                         = Prefix + SuffixEnable
+	TraefikPort                                           = Prefix + SuffixPort
+	TraefikPortIndex                                      = Prefix + SuffixPortIndex
+	TraefikProtocol                                       = Prefix + SuffixProtocol
+	TraefikTags                                           = Prefix + SuffixTags
+	TraefikWeight                                         = Prefix + SuffixWeight
+	TraefikBackend                                        = Prefix + SuffixBackend
+	TraefikBackendID                                      = Prefix + SuffixBackendID
+	TraefikBackendCircuitBreaker                          = Prefix + SuffixBackendCircuitBreaker
+	TraefikBackendCircuitBreakerExpression                = Prefix + SuffixBackendCircuitBreakerExpression
+	TraefikBackendHealthCheckPath                         = Prefix + SuffixBackendHealthCheckPath
+	TraefikBackendHealthCheckPort                         = Prefix + SuffixBackendHealthCheckPort
+	TraefikBackendHealthCheckInterval                     = Prefix + SuffixBackendHealthCheckInterval
+	TraefikBackendHealthCheckMode                         = Prefix + SuffixBackendHealthCheckMode
+	TraefikBackendHealthCheckScheme                       = Prefix + SuffixBackendHealthCheckScheme
+	TraefikBackendHealthCheckTimeout                      = Prefix + SuffixBackendHealthCheckTimeout
+	TraefikBackendHealthCheckHostname                     = Prefix + SuffixBackendHealthCheckHostname
+	TraefikBackendHealthCheckHeaders                      = Prefix + SuffixBackendHealthCheckHeaders
+	TraefikBackendHealthCheckStatus                       = Prefix + SuffixBackendHealthCheckStatus
+	TraefikBackendHealthCheckBodyRegex                    = Prefix + SuffixBackendHealthCheckBodyRegex
+	TraefikBackendHealthCheckHealthyThreshold             = Prefix + SuffixBackendHealthCheckHealthyThreshold
+	TraefikBackendHealthCheckUnhealthyThreshold           = Prefix + SuffixBackendHealthCheckUnhealthyThreshold
+	TraefikBackendLoadBalancer                            = Prefix + SuffixBackendLoadBalancer
+	TraefikBackendLoadBalancerMethod                      = Prefix + SuffixBackendLoadBalancerMethod
+	TraefikBackendLoadBalancerSticky                      = Prefix + SuffixBackendLoadBalancerSticky
+	TraefikBackendLoadBalancerStickiness                  = Prefix + SuffixBackendLoadBalancerStickiness
+	TraefikBackendLoadBalancerStickinessCookieName        = Prefix + SuffixBackendLoadBalancerStickinessCookieName
+	TraefikBackendLoadBalancerConsistentHash              = Prefix + SuffixBackendLoadBalancerConsistentHash
+	TraefikBackendLoadBalancerConsistentHashExtractorFunc = Prefix + SuffixBackendLoadBalancerConsistentHashExtractorFunc
+	TraefikBackendLoadBalancerConsistentHashLoadFactor    = Prefix + SuffixBackendLoadBalancerConsistentHashLoadFactor
+	TraefikBackendMaxConnAmount                           = Prefix + SuffixBackendMaxConnAmount
+	TraefikBackendMaxConnExtractorFunc                    = Prefix + SuffixBackendMaxConnExtractorFunc
+	TraefikBackendBuffering                               = Prefix + SuffixBackendBuffering
+	TraefikBackendBufferingMaxRequestBodyBytes            = Prefix + SuffixBackendBufferingMaxRequestBodyBytes
+	TraefikBackendBufferingMemRequestBodyBytes            = Prefix + SuffixBackendBufferingMemRequestBodyBytes
+	TraefikBackendBufferingMaxResponseBodyBytes           = Prefix + SuffixBackendBufferingMaxResponseBodyBytes
+	TraefikBackendBufferingMemResponseBodyBytes           = Prefix + SuffixBackendBufferingMemResponseBodyBytes
+	TraefikBackendBufferingRetryExpression                = Prefix + SuffixBackendBufferingRetryExpression
+	TraefikFrontend                                       = Prefix + SuffixFrontend
+	TraefikFrontendAccessLog                              = Prefix + SuffixFrontendAccessLog
+	TraefikFrontendAccessLogFiltersStatusCodes            = Prefix + SuffixFrontendAccessLogFiltersStatusCodes
+	TraefikFrontendAccessLogFiltersRetryAttempts          = Prefix + SuffixFrontendAccessLogFiltersRetryAttempts
+	TraefikFrontendAccessLogFiltersMinDuration            = Prefix + SuffixFrontendAccessLogFiltersMinDuration
+	TraefikFrontendAccessLogFieldsDefaultMode             = Prefix + SuffixFrontendAccessLogFieldsDefaultMode
+	TraefikFrontendAccessLogFieldsNames                   = Prefix + SuffixFrontendAccessLogFieldsNames
+	TraefikFrontendAccessLogFieldsHeadersDefaultMode      = Prefix + SuffixFrontendAccessLogFieldsHeadersDefaultMode
+	TraefikFrontendAccessLogFieldsHeadersNames            = Prefix + SuffixFrontendAccessLogFieldsHeadersNames
+	TraefikFrontendAuthBasic                              = Prefix + SuffixFrontendAuthBasic
+	TraefikFrontendAuthHeaderField                        = Prefix + SuffixFrontendAuthHeaderField
+	TraefikFrontendAuthJWT                                = Prefix + SuffixFrontendAuthJWT
+	TraefikFrontendAuthJWTSecret                          = Prefix + SuffixFrontendAuthJWTSecret
+	TraefikFrontendAuthJWTPublicKeys                      = Prefix + SuffixFrontendAuthJWTPublicKeys
+	TraefikFrontendAuthJWTJWKS                            = Prefix + SuffixFrontendAuthJWTJWKS
+	TraefikFrontendAuthJWTJWKSRefreshInterval             = Prefix + SuffixFrontendAuthJWTJWKSRefreshInterval
+	TraefikFrontendAuthJWTIssuer                          = Prefix + SuffixFrontendAuthJWTIssuer
+	TraefikFrontendAuthJWTAudiences                       = Prefix + SuffixFrontendAuthJWTAudiences
+	TraefikFrontendAuthJWTClockSkew                       = Prefix + SuffixFrontendAuthJWTClockSkew
+	TraefikFrontendAuthJWTAllowNoExpiration               = Prefix + SuffixFrontendAuthJWTAllowNoExpiration
+	TraefikFrontendAuthJWTClaimHeaders                    = Prefix + SuffixFrontendAuthJWTClaimHeaders
+	TraefikFrontendCache                                  = Prefix + SuffixFrontendCache
+	TraefikFrontendCacheEnable                            = Prefix + SuffixFrontendCacheEnable
+	TraefikFrontendCacheStorage                           = Prefix + SuffixFrontendCacheStorage
+	TraefikFrontendCachePath                              = Prefix + SuffixFrontendCachePath
+	TraefikFrontendCacheMaxEntries                        = Prefix + SuffixFrontendCacheMaxEntries
+	TraefikFrontendCacheMaxBodySize                       = Prefix + SuffixFrontendCacheMaxBodySize
+	TraefikFrontendCacheDefaultTTL                        = Prefix + SuffixFrontendCacheDefaultTTL
+	TraefikFrontendCompression                            = Prefix + SuffixFrontendCompression
+	TraefikFrontendCompressionEnable                      = Prefix + SuffixFrontendCompressionEnable
+	TraefikFrontendCompressionEncodings                   = Prefix + SuffixFrontendCompressionEncodings
+	TraefikFrontendCompressionMinSize                     = Prefix + SuffixFrontendCompressionMinSize
+	TraefikFrontendCompressionGzipLevel                   = Prefix + SuffixFrontendCompressionGzipLevel
+	TraefikFrontendCompressionBrotliLevel                 = Prefix + SuffixFrontendCompressionBrotliLevel
+	TraefikFrontendCompressionIncludedContentTypes        = Prefix + SuffixFrontendCompressionIncludedContentTypes
+	TraefikFrontendCompressionExcludedContentTypes        = Prefix + SuffixFrontendCompressionExcludedContentTypes
+	TraefikFrontendEntryPoints                            = Prefix + SuffixFrontendEntryPoints
+	TraefikFrontendPassHostHeader                         = Prefix + SuffixFrontendPassHostHeader
+	TraefikFrontendPassTLSCert                            = Prefix + SuffixFrontendPassTLSCert
+	TraefikFrontendPriority                               = Prefix + SuffixFrontendPriority
+	TraefikFrontendRateLimitExtractorFunc                 = Prefix + SuffixFrontendRateLimitExtractorFunc
+	TraefikFrontendRateLimitDistributed                   = Prefix + SuffixFrontendRateLimitDistributed
+	TraefikFrontendRedirectEntryPoint                     = Prefix + SuffixFrontendRedirectEntryPoint
+	TraefikFrontendRedirectRegex                          = Prefix + SuffixFrontendRedirectRegex
+	TraefikFrontendRedirectReplacement                    = Prefix + SuffixFrontendRedirectReplacement
+	TraefikFrontendRedirectPermanent                      = Prefix + SuffixFrontendRedirectPermanent
+	TraefikFrontendRetry                                  = Prefix + SuffixFrontendRetry
+	TraefikFrontendRetryAttempts                          = Prefix + SuffixFrontendRetryAttempts
+	TraefikFrontendRetryStatusCodes                       = Prefix + SuffixFrontendRetryStatusCodes
+	TraefikFrontendRetryMethods                           = Prefix + SuffixFrontendRetryMethods
+	TraefikFrontendRetryInitialInterval                   = Prefix + SuffixFrontendRetryInitialInterval
+	TraefikFrontendRetryMaxInterval                       = Prefix + SuffixFrontendRetryMaxInterval
+	TraefikFrontendRetryBudget                            = Prefix + SuffixFrontendRetryBudget
+	TraefikFrontendRule                                   = Prefix + SuffixFrontendRule
+	TraefikFrontendRuleType                               = Prefix + SuffixFrontendRuleType // k8s only
+	TraefikFrontendTLSClientCert                          = Prefix + SuffixFrontendTLSClientCert
+	TraefikFrontendTLSClientCertPEM                       = Prefix + SuffixFrontendTLSClientCertPEM
+	TraefikFrontendTLSClientCertFields                    = Prefix + SuffixFrontendTLSClientCertFields
+	TraefikFrontendTLSClientCertAllowedSubjects           = Prefix + SuffixFrontendTLSClientCertAllowedSubjects
+	TraefikFrontendTLSClientCertAllowedSANs               = Prefix + SuffixFrontendTLSClientCertAllowedSANs
+	TraefikFrontendWhitelistSourceRange                   = Prefix + SuffixFrontendWhitelistSourceRange
+	TraefikFrontendWeightedBackends                       = Prefix + SuffixFrontendWeightedBackends
+	TraefikFrontendWeightedBackendsStickiness             = Prefix + SuffixFrontendWeightedBackendsStickiness
+	TraefikFrontendWeightedBackendsCookieName             = Prefix + SuffixFrontendWeightedBackendsCookieName
+	TraefikFrontendHeaders                                = Prefix + SuffixFrontendHeaders
+	TraefikFrontendRequestHeaders                         = Prefix + SuffixFrontendRequestHeaders
+	TraefikFrontendResponseHeaders                        = Prefix + SuffixFrontendResponseHeaders
+	TraefikFrontendAllowedHosts                           = Prefix + SuffixFrontendHeadersAllowedHosts
+	TraefikFrontendHostsProxyHeaders                      = Prefix + SuffixFrontendHeadersHostsProxyHeaders
+	TraefikFrontendSSLRedirect                            = Prefix + SuffixFrontendHeadersSSLRedirect
+	TraefikFrontendSSLTemporaryRedirect                   = Prefix + SuffixFrontendHeadersSSLTemporaryRedirect
+	TraefikFrontendSSLHost                                = Prefix + SuffixFrontendHeadersSSLHost
+	TraefikFrontendSSLProxyHeaders                        = Prefix + SuffixFrontendHeadersSSLProxyHeaders
+	TraefikFrontendSTSSeconds                             = Prefix + SuffixFrontendHeadersSTSSeconds
+	TraefikFrontendSTSIncludeSubdomains                   = Prefix + SuffixFrontendHeadersSTSIncludeSubdomains
+	TraefikFrontendSTSPreload                             = Prefix + SuffixFrontendHeadersSTSPreload
+	TraefikFrontendForceSTSHeader                         = Prefix + SuffixFrontendHeadersForceSTSHeader
+	TraefikFrontendFrameDeny                              = Prefix + SuffixFrontendHeadersFrameDeny
+	TraefikFrontendCustomFrameOptionsValue                = Prefix + SuffixFrontendHeadersCustomFrameOptionsValue
+	TraefikFrontendContentTypeNosniff                     = Prefix + SuffixFrontendHeadersContentTypeNosniff
+	TraefikFrontendBrowserXSSFilter                       = Prefix + SuffixFrontendHeadersBrowserXSSFilter
+	TraefikFrontendContentSecurityPolicy                  = Prefix + SuffixFrontendHeadersContentSecurityPolicy
+	TraefikFrontendPublicKey                              = Prefix + SuffixFrontendHeadersPublicKey
+	TraefikFrontendReferrerPolicy                         = Prefix + SuffixFrontendHeadersReferrerPolicy
+	TraefikFrontendIsDevelopment                          = Prefix + SuffixFrontendHeadersIsDevelopment
+	BaseFrontendErrorPage                                 = "frontend.errors."
+	SuffixErrorPageBackend                                = "backend"
+	SuffixErrorPageQuery                                  = "query"
+	SuffixErrorPageStatus                                 = "status"
+	BaseFrontendRateLimit                                 = "frontend.rateLimit.rateSet."
+	SuffixRateLimitPeriod                                 = "period"
+	SuffixRateLimitAverage                                = "average"
+	SuffixRateLimitBurst                                  = "burst"
 )
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/containous/mux"
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/types"
+	"github.com/mitchellh/copystructure"
+)
+
+// MatchedRoute describes the frontend of an entrypoint matching a request
+type MatchedRoute struct {
+	Frontend string
+	Rules    []string
+	Priority int
+	Backends []string
+	// Middlewares are the middlewares the request goes through before reaching the load balancer, in order
+	Middlewares []string
+}
+
+// MatchRoute builds the routing table of an HTTP entrypoint from the frontends of the configuration,
+// as the server builds it, and returns the route matching the request.
+// It returns nil if no frontend matches the request.
+func MatchRoute(globalConfiguration configuration.GlobalConfiguration, config *types.Configuration, entryPointName string, req *http.Request) (*MatchedRoute, error) {
+	entryPoint, ok := globalConfiguration.EntryPoints[entryPointName]
+	if !ok {
+		return nil, fmt.Errorf("undefined entrypoint '%s'", entryPointName)
+	}
+	if entryPoint.IsTCP() || entryPoint.IsUDP() {
+		return nil, fmt.Errorf("entrypoint %s is not an HTTP entrypoint", entryPointName)
+	}
+
+	s := newValidationServer(globalConfiguration)
+	entryPointMiddlewares, err := s.buildEntryPointMiddlewares(entryPointName, entryPoint)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the middlewares of entrypoint %s: %v", entryPointName, err)
+	}
+
+	// the configuration is completed and filtered while being built, as the server does, so a copy is built
+	configCopy, err := copystructure.Copy(config)
+	if err != nil {
+		return nil, fmt.Errorf("error copying the configuration: %v", err)
+	}
+	routingConfig := configCopy.(*types.Configuration)
+	s.defaultConfigurationValues(routingConfig)
+	built := s.buildConfig(types.Configurations{"routing": routingConfig}, globalConfiguration)
+
+	router := built.serverEntryPoints[entryPointName].httpRouter.GetHandler()
+	router.SortRoutes()
+
+	// the request is served by the first matching route of the router,
+	// which has no frontend if the frontend was skipped after its route was created
+	var matched *mux.Route
+	router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if matched == nil && route.Match(req, &mux.RouteMatch{}) {
+			matched = route
+		}
+		return mux.SkipRouter
+	})
+	route, ok := built.routes[entryPointName][matched]
+	if !ok {
+		return nil, nil
+	}
+	frontend := routingConfig.Frontends[route.frontend]
+
+	matchedRoute := &MatchedRoute{
+		Frontend: route.frontend,
+		Priority: matched.GetPriority(),
+		Backends: []string{frontend.Backend},
+	}
+	if frontend.WeightedBackends != nil {
+		matchedRoute.Backends = frontend.WeightedBackends.Names()
+	}
+	for _, routeName := range sortedRouteNames(frontend) {
+		matchedRoute.Rules = append(matchedRoute.Rules, frontend.Routes[routeName].Rule)
+	}
+	for _, entryPointMiddleware := range entryPointMiddlewares {
+		matchedRoute.Middlewares = append(matchedRoute.Middlewares, entryPointMiddleware.name)
+	}
+	matchedRoute.Middlewares = append(matchedRoute.Middlewares, route.middlewares...)
+
+	return matchedRoute, nil
+}
+
+func sortedRouteNames(frontend *types.Frontend) []string {
+	var names []string
+	for name := range frontend.Routes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
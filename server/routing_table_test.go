@@ -0,0 +1,233 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchRoute(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+			"tcp":  &configuration.EntryPoint{Protocol: "tcp"},
+		},
+		DefaultEntryPoints: []string{"http"},
+	}
+
+	withBasicAuth := func(f *types.Frontend) { f.BasicAuth = []string{"test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"} }
+	withCompression := func(f *types.Frontend) { f.Compression = &types.Compression{} }
+	withPriority := func(f *types.Frontend) { f.Priority = 100 }
+	withoutEntryPoints := func(f *types.Frontend) { f.EntryPoints = nil }
+	withCircuitBreaker := func(b *types.Backend) {
+		b.CircuitBreaker = &types.CircuitBreaker{Expression: "NetworkErrorRatio() > 0.5"}
+	}
+
+	config := buildDynamicConfig(
+		withFrontend("api", buildFrontend(withRoute("api", "Host:foo.bar;PathPrefixStrip:/api"), withBasicAuth, withCompression)),
+		withFrontend("host", buildFrontend(withRoute("host", "Host:foo.bar,baz.bar"))),
+		withFrontend("default", buildFrontend(withRoute("default", "PathPrefix:/"), withoutEntryPoints)),
+		withFrontend("admin", buildFrontend(withRoute("admin", "Host:foo.bar && Headers:X-Admin,true"), withPriority)),
+		withFrontend("invalid", buildFrontend(withRoute("invalid", "Host:foo.bar && Unknown:/"), withPriority)),
+		withFrontend("undefined", buildFrontend(withRoute("undefined", "Host:undefined.bar"), func(f *types.Frontend) { f.Backend = "undefined" })),
+		withBackend("backend", buildBackend(withCircuitBreaker)),
+	)
+
+	testCases := []struct {
+		desc       string
+		entryPoint string
+		url        string
+		headers    map[string]string
+		expected   *MatchedRoute
+		expectErr  bool
+	}{
+		{
+			desc:       "longest rule",
+			entryPoint: "http",
+			url:        "http://foo.bar/api/users",
+			expected: &MatchedRoute{
+				Frontend:    "api",
+				Rules:       []string{"Host:foo.bar;PathPrefixStrip:/api"},
+				Priority:    len("Host:foo.bar;PathPrefixStrip:/api"),
+				Backends:    []string{"backend"},
+				Middlewares: []string{"Strip prefix", "gRPC errors", "Basic auth", "Compress", "Circuit breaker"},
+			},
+		},
+		{
+			desc:       "shorter rule",
+			entryPoint: "http",
+			url:        "http://foo.bar/users",
+			expected: &MatchedRoute{
+				Frontend:    "host",
+				Rules:       []string{"Host:foo.bar,baz.bar"},
+				Priority:    len("Host:foo.bar,baz.bar"),
+				Backends:    []string{"backend"},
+				Middlewares: []string{"gRPC errors", "Circuit breaker"},
+			},
+		},
+		{
+			desc:       "frontend priority",
+			entryPoint: "http",
+			url:        "http://foo.bar/api/users",
+			headers:    map[string]string{"X-Admin": "true"},
+			expected: &MatchedRoute{
+				Frontend:    "admin",
+				Rules:       []string{"Host:foo.bar && Headers:X-Admin,true"},
+				Priority:    100,
+				Backends:    []string{"backend"},
+				Middlewares: []string{"gRPC errors", "Circuit breaker"},
+			},
+		},
+		{
+			desc:       "default entrypoints",
+			entryPoint: "http",
+			url:        "http://other.bar/users",
+			expected: &MatchedRoute{
+				Frontend:    "default",
+				Rules:       []string{"PathPrefix:/"},
+				Priority:    len("PathPrefix:/"),
+				Backends:    []string{"backend"},
+				Middlewares: []string{"gRPC errors", "Circuit breaker"},
+			},
+		},
+		{
+			desc:       "route of a frontend skipped for its undefined backend",
+			entryPoint: "http",
+			url:        "http://undefined.bar/users",
+		},
+		{
+			desc:       "undefined entrypoint",
+			entryPoint: "https",
+			url:        "http://foo.bar/",
+			expectErr:  true,
+		},
+		{
+			desc:       "TCP entrypoint",
+			entryPoint: "tcp",
+			url:        "http://foo.bar/",
+			expectErr:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, test.url, nil)
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+
+			matchedRoute, err := MatchRoute(globalConfig, config, test.entryPoint, req)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, matchedRoute)
+		})
+	}
+}
+
+func TestMatchRouteWeightedBackends(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"http": &configuration.EntryPoint{
+				ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true},
+				Redirect:         &types.Redirect{Regex: "^http://foo.bar/old/(.*)", Replacement: "http://foo.bar/$1"},
+			},
+		},
+		Retry: &configuration.Retry{},
+	}
+
+	frontend := buildFrontend(withRoute("path", "Path:/path"))
+	frontend.Backend = ""
+	frontend.WeightedBackends = &types.WeightedBackends{Backends: map[string]int{"v1": 3, "v2": 1}}
+
+	config := buildDynamicConfig(
+		withFrontend("frontend", frontend),
+		withBackend("v1", buildBackend()),
+		withBackend("v2", buildBackend(func(b *types.Backend) {
+			b.Buffering = &types.Buffering{RetryExpression: "IsNetworkError() && Attempts() < 2"}
+		})),
+	)
+
+	matchedRoute, err := MatchRoute(globalConfig, config, "http", httptest.NewRequest(http.MethodGet, "http://foo.bar/path", nil))
+	require.NoError(t, err)
+
+	expected := &MatchedRoute{
+		Frontend: "frontend",
+		Rules:    []string{"Path:/path"},
+		Priority: len("Path:/path"),
+		Backends: []string{"v1", "v2"},
+		Middlewares: []string{
+			"Entrypoint redirect", "gRPC errors", "Retry",
+			"Weighted backends", "Buffering (v2)",
+		},
+	}
+	assert.Equal(t, expected, matchedRoute)
+
+	matchedRoute, err = MatchRoute(globalConfig, config, "http", httptest.NewRequest(http.MethodGet, "http://foo.bar/other", nil))
+	require.NoError(t, err)
+	assert.Nil(t, matchedRoute)
+}
+
+func TestMatchRouteEntryPointMiddlewares(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"http": &configuration.EntryPoint{
+				ForwardedHeaders:     &configuration.ForwardedHeaders{Insecure: true},
+				Compress:             true,
+				WhitelistSourceRange: []string{"10.0.0.0/8"},
+			},
+		},
+	}
+
+	config := buildDynamicConfig(
+		withFrontend("public", buildFrontend(withRoute("public", "Host:public.bar"))),
+		withFrontend("private", buildFrontend(withRoute("private", "Host:private.bar"), func(f *types.Frontend) {
+			f.WhitelistSourceRange = []string{"10.0.0.0/24"}
+			f.Headers = &types.Headers{CustomRequestHeaders: map[string]string{"X-Private": "true"}}
+		})),
+		withBackend("backend", buildBackend()),
+	)
+
+	testCases := []struct {
+		desc     string
+		url      string
+		expected []string
+	}{
+		{
+			desc:     "frontend without middlewares",
+			url:      "http://public.bar/",
+			expected: []string{"Entrypoint compress", "Entrypoint IP whitelist", "gRPC errors"},
+		},
+		{
+			desc:     "frontend with middlewares on the same backend",
+			url:      "http://private.bar/",
+			expected: []string{"Entrypoint compress", "Entrypoint IP whitelist", "gRPC errors", "IP whitelist", "Header"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			matchedRoute, err := MatchRoute(globalConfig, config, "http", httptest.NewRequest(http.MethodGet, test.url, nil))
+			require.NoError(t, err)
+			require.NotNil(t, matchedRoute)
+
+			assert.Equal(t, test.expected, matchedRoute.Middlewares)
+		})
+	}
+}
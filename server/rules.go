@@ -6,18 +6,25 @@ import (
 	"net"
 	"net/http"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 
 	"github.com/BurntSushi/ty/fun"
 	"github.com/containous/mux"
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/tcp"
 	"github.com/containous/traefik/types"
+	"github.com/containous/traefik/whitelist"
+	"github.com/vulcand/oxy/forward"
 )
 
 // Rules holds rule parsing and configuration
 type Rules struct {
 	route *serverRoute
 	err   error
+	// forwardedHeaders tells which X-Forwarded-* headers are trusted by the ClientIP and Scheme matchers
+	forwardedHeaders *configuration.ForwardedHeaders
 }
 
 func (r *Rules) host(hosts ...string) *mux.Route {
@@ -168,8 +175,221 @@ func (r *Rules) query(query ...string) *mux.Route {
 	return r.route.route.Queries(queries...)
 }
 
-func (r *Rules) parseRules(expression string, onRule func(functionName string, function interface{}, arguments []string) error) error {
-	functions := map[string]interface{}{
+func (r *Rules) clientIP(ranges ...string) *mux.Route {
+	clientIPs, err := whitelist.NewIP(ranges, false)
+	if err != nil {
+		r.err = fmt.Errorf("invalid ClientIP rule: %v", err)
+		return r.route.route
+	}
+	trusted, err := r.trustedProxies()
+	if err != nil {
+		r.err = err
+		return r.route.route
+	}
+
+	return r.route.route.MatcherFunc(func(req *http.Request, route *mux.RouteMatch) bool {
+		ip := requestClientIP(req, trusted)
+		if ip == nil {
+			return false
+		}
+		contains, err := clientIPs.ContainsIP(ip)
+		return err == nil && contains
+	})
+}
+
+// trustedProxies returns a function telling whether the X-Forwarded-* headers set by a proxy are trusted
+func (r *Rules) trustedProxies() (func(ip net.IP) bool, error) {
+	switch {
+	case r.forwardedHeaders == nil || !r.forwardedHeaders.Insecure && len(r.forwardedHeaders.TrustedIPs) == 0:
+		return func(net.IP) bool { return false }, nil
+	case r.forwardedHeaders.Insecure:
+		return func(net.IP) bool { return true }, nil
+	}
+
+	trustedIPs, err := whitelist.NewIP(r.forwardedHeaders.TrustedIPs, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid forwarded headers trusted IPs: %v", err)
+	}
+	return func(ip net.IP) bool {
+		contains, err := trustedIPs.ContainsIP(ip)
+		return err == nil && contains
+	}, nil
+}
+
+// requestClientIP returns the IP address of the client of a request:
+// the right-most address of the X-Forwarded-For header which was not added by a trusted proxy,
+// or the remote address of the request when it is not a trusted proxy.
+func requestClientIP(req *http.Request, trusted func(ip net.IP) bool) net.IP {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil
+	}
+
+	var forwardedFor []string
+	for _, value := range req.Header[forward.XForwardedFor] {
+		forwardedFor = append(forwardedFor, strings.Split(value, ",")...)
+	}
+	for i := len(forwardedFor) - 1; i >= 0 && trusted(ip); i-- {
+		forwardedIP := net.ParseIP(strings.TrimSpace(forwardedFor[i]))
+		if forwardedIP == nil {
+			break
+		}
+		ip = forwardedIP
+	}
+	return ip
+}
+
+func (r *Rules) cookie(cookies ...string) *mux.Route {
+	if len(cookies)%2 != 0 {
+		r.err = fmt.Errorf("invalid Cookie rule: expected name and value pairs, got %d values", len(cookies))
+		return r.route.route
+	}
+
+	return r.route.route.MatcherFunc(func(req *http.Request, route *mux.RouteMatch) bool {
+		for i := 0; i < len(cookies); i += 2 {
+			cookie, err := req.Cookie(cookies[i])
+			if err != nil || cookie.Value != cookies[i+1] {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func (r *Rules) cookieRegexp(cookies ...string) *mux.Route {
+	if len(cookies)%2 != 0 {
+		r.err = fmt.Errorf("invalid CookieRegexp rule: expected name and regular expression pairs, got %d values", len(cookies))
+		return r.route.route
+	}
+
+	regexps := make(map[string]*regexp.Regexp)
+	for i := 0; i < len(cookies); i += 2 {
+		re, err := regexp.Compile(cookies[i+1])
+		if err != nil {
+			r.err = fmt.Errorf("invalid CookieRegexp rule: %v", err)
+			return r.route.route
+		}
+		regexps[cookies[i]] = re
+	}
+
+	return r.route.route.MatcherFunc(func(req *http.Request, route *mux.RouteMatch) bool {
+		for name, re := range regexps {
+			cookie, err := req.Cookie(name)
+			if err != nil || !re.MatchString(cookie.Value) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func (r *Rules) scheme(schemes ...string) *mux.Route {
+	for i, scheme := range schemes {
+		schemes[i] = strings.ToLower(scheme)
+		if schemes[i] != "http" && schemes[i] != "https" {
+			r.err = fmt.Errorf("invalid Scheme rule: unknown scheme %q", scheme)
+			return r.route.route
+		}
+	}
+	trusted, err := r.trustedProxies()
+	if err != nil {
+		r.err = err
+		return r.route.route
+	}
+
+	return r.route.route.MatcherFunc(func(req *http.Request, route *mux.RouteMatch) bool {
+		requestScheme := "http"
+		if req.TLS != nil {
+			requestScheme = "https"
+		}
+		if proto := req.Header.Get(forward.XForwardedProto); proto != "" && isTrustedRemoteAddr(req, trusted) {
+			requestScheme = strings.ToLower(proto)
+		}
+
+		for _, scheme := range schemes {
+			if scheme == requestScheme {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func isTrustedRemoteAddr(req *http.Request, trusted func(ip net.IP) bool) bool {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && trusted(ip)
+}
+
+func (r *Rules) httpVersion(versions ...string) *mux.Route {
+	type protocolVersion struct {
+		major, minor int
+	}
+
+	var protocolVersions []protocolVersion
+	for _, version := range versions {
+		value := strings.TrimPrefix(strings.ToUpper(version), "HTTP/")
+		if !strings.Contains(value, ".") {
+			value += ".0"
+		}
+		var v protocolVersion
+		if _, err := fmt.Sscanf(value, "%d.%d", &v.major, &v.minor); err != nil || fmt.Sprintf("%d.%d", v.major, v.minor) != value {
+			r.err = fmt.Errorf("invalid HTTPVersion rule: unknown version %q", version)
+			return r.route.route
+		}
+		protocolVersions = append(protocolVersions, v)
+	}
+
+	return r.route.route.MatcherFunc(func(req *http.Request, route *mux.RouteMatch) bool {
+		for _, v := range protocolVersions {
+			if req.ProtoMajor == v.major && req.ProtoMinor == v.minor {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// hostSNI matches the Server Name Indication of the TLS connection of the requests, which can differ from their Host header.
+// The catch-all host also matches the requests received without TLS.
+func (r *Rules) hostSNI(hosts ...string) *mux.Route {
+	for i, host := range hosts {
+		hosts[i] = types.CanonicalDomain(host)
+	}
+
+	return r.route.route.MatcherFunc(func(req *http.Request, route *mux.RouteMatch) bool {
+		var serverName string
+		if req.TLS != nil {
+			serverName = types.CanonicalDomain(req.TLS.ServerName)
+		}
+
+		for _, host := range hosts {
+			if host == tcp.CatchAllSNI {
+				return true
+			}
+			if len(serverName) == 0 {
+				continue
+			}
+			if host == serverName {
+				return true
+			}
+			if i := strings.Index(serverName, "."); i > 0 && strings.HasPrefix(host, "*.") && host[1:] == serverName[i:] {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (r *Rules) functions() map[string]interface{} {
+	return map[string]interface{}{
 		"Host":                 r.host,
 		"HostRegexp":           r.hostRegexp,
 		"Path":                 r.path,
@@ -185,96 +405,209 @@ func (r *Rules) parseRules(expression string, onRule func(functionName string, f
 		"ReplacePath":          r.replacePath,
 		"ReplacePathRegex":     r.replacePathRegex,
 		"Query":                r.query,
+		"HostSNI":              r.hostSNI,
+		"ClientIP":             r.clientIP,
+		"Cookie":               r.cookie,
+		"CookieRegexp":         r.cookieRegexp,
+		"Scheme":               r.scheme,
+		"HTTPVersion":          r.httpVersion,
 	}
+}
+
+// ruleModifiers are the functions modifying the requests,
+// which can only be combined with && or ; at the top level of a rule
+var ruleModifiers = map[string]bool{
+	"PathStrip":            true,
+	"PathStripRegex":       true,
+	"PathPrefixStrip":      true,
+	"PathPrefixStripRegex": true,
+	"AddPrefix":            true,
+	"ReplacePath":          true,
+	"ReplacePathRegex":     true,
+}
 
-	if len(expression) == 0 {
-		return errors.New("Empty rule")
+// parseRules parses a rule expression and checks its function names
+func (r *Rules) parseRules(expression string) (ruleNode, error) {
+	node, err := parseRuleExpression(expression)
+	if err != nil {
+		return nil, err
 	}
 
-	f := func(c rune) bool {
-		return c == ':'
+	functions := r.functions()
+	err = ruleMatchers(node, false, func(matcher ruleMatcher, _ bool) error {
+		if _, ok := functions[matcher.name]; !ok {
+			return &RuleSyntaxError{Expression: expression, Position: matcher.position, Message: fmt.Sprintf("unknown function %s", matcher.name)}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// apply adds a parsed rule expression to the route, the top level matchers being added directly,
+// and the ||, ! and their operands being compiled into a single matcher
+func (r *Rules) apply(expression string, node ruleNode) error {
+	switch n := node.(type) {
+	case ruleAnd:
+		for _, operand := range n {
+			if err := r.apply(expression, operand); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ruleMatcher:
+		return r.applyMatcher(n)
+	}
+
+	match, err := r.compile(expression, node)
+	if err != nil {
+		return err
 	}
+	r.route.route.MatcherFunc(match)
+	return nil
+}
 
-	// Allow multiple rules separated by ;
-	splitRule := func(c rune) bool {
-		return c == ';'
+func (r *Rules) applyMatcher(matcher ruleMatcher) error {
+	inputs := make([]reflect.Value, len(matcher.arguments))
+	for i := range matcher.arguments {
+		inputs[i] = reflect.ValueOf(matcher.arguments[i])
+	}
+	method := reflect.ValueOf(r.functions()[matcher.name])
+	if !method.IsValid() {
+		return fmt.Errorf("Method not found: '%s'", matcher.name)
 	}
 
-	parsedRules := strings.FieldsFunc(expression, splitRule)
+	resultRoute := method.Call(inputs)[0].Interface().(*mux.Route)
+	if r.err != nil {
+		return fmt.Errorf("Parsing error on rule: %v", r.err)
+	}
+	if resultRoute.GetError() != nil {
+		return fmt.Errorf("Parsing error on rule: %v", resultRoute.GetError())
+	}
+	return nil
+}
 
-	for _, rule := range parsedRules {
-		// get function
-		parsedFunctions := strings.FieldsFunc(rule, f)
-		if len(parsedFunctions) == 0 {
-			return fmt.Errorf("error parsing rule: '%s'", rule)
+// compile builds a matcher of a parsed rule expression, each function being applied to its own route
+func (r *Rules) compile(expression string, node ruleNode) (mux.MatcherFunc, error) {
+	switch n := node.(type) {
+	case ruleMatcher:
+		if ruleModifiers[n.name] {
+			return nil, &RuleSyntaxError{Expression: expression, Position: n.position, Message: fmt.Sprintf("%s cannot be used with || or !", n.name)}
 		}
-		functionName := strings.TrimSpace(parsedFunctions[0])
-		parsedFunction, ok := functions[functionName]
-		if !ok {
-			return fmt.Errorf("error parsing rule: '%s'. Unknown function: '%s'", rule, parsedFunctions[0])
+		rules := &Rules{route: &serverRoute{route: mux.NewRouter().NewRoute()}, forwardedHeaders: r.forwardedHeaders}
+		if err := rules.applyMatcher(n); err != nil {
+			return nil, err
 		}
-		parsedFunctions = append(parsedFunctions[:0], parsedFunctions[1:]...)
-		fargs := func(c rune) bool {
-			return c == ','
+		route := rules.route.route
+		return func(req *http.Request, _ *mux.RouteMatch) bool {
+			return route.Match(req, &mux.RouteMatch{})
+		}, nil
+
+	case ruleNot:
+		match, err := r.compile(expression, n.operand)
+		if err != nil {
+			return nil, err
 		}
-		// get function
-		parsedArgs := strings.FieldsFunc(strings.Join(parsedFunctions, ":"), fargs)
-		if len(parsedArgs) == 0 {
-			return fmt.Errorf("error parsing args from rule: '%s'", rule)
+		return func(req *http.Request, routeMatch *mux.RouteMatch) bool {
+			return !match(req, routeMatch)
+		}, nil
+
+	case ruleAnd:
+		matches, err := r.compileAll(expression, n)
+		if err != nil {
+			return nil, err
 		}
+		return func(req *http.Request, routeMatch *mux.RouteMatch) bool {
+			for _, match := range matches {
+				if !match(req, routeMatch) {
+					return false
+				}
+			}
+			return true
+		}, nil
 
-		for i := range parsedArgs {
-			parsedArgs[i] = strings.TrimSpace(parsedArgs[i])
+	case ruleOr:
+		matches, err := r.compileAll(expression, n)
+		if err != nil {
+			return nil, err
 		}
+		return func(req *http.Request, routeMatch *mux.RouteMatch) bool {
+			for _, match := range matches {
+				if match(req, routeMatch) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+	return nil, fmt.Errorf("unexpected rule node %T", node)
+}
 
-		err := onRule(functionName, parsedFunction, parsedArgs)
+func (r *Rules) compileAll(expression string, nodes []ruleNode) ([]mux.MatcherFunc, error) {
+	var matches []mux.MatcherFunc
+	for _, node := range nodes {
+		match, err := r.compile(expression, node)
 		if err != nil {
-			return fmt.Errorf("Parsing error on rule: %v", err)
+			return nil, err
 		}
+		matches = append(matches, match)
 	}
-	return nil
+	return matches, nil
 }
 
 // Parse parses rules expressions
 func (r *Rules) Parse(expression string) (*mux.Route, error) {
-	var resultRoute *mux.Route
-	err := r.parseRules(expression, func(functionName string, function interface{}, arguments []string) error {
-		inputs := make([]reflect.Value, len(arguments))
-		for i := range arguments {
-			inputs[i] = reflect.ValueOf(arguments[i])
-		}
-		method := reflect.ValueOf(function)
-		if method.IsValid() {
-			resultRoute = method.Call(inputs)[0].Interface().(*mux.Route)
-			if r.err != nil {
-				return r.err
-			}
-			if resultRoute.GetError() != nil {
-				return resultRoute.GetError()
-			}
-
-		} else {
-			return fmt.Errorf("Method not found: '%s'", functionName)
-		}
-		return nil
-	})
+	node, err := r.parseRules(expression)
+	if err == nil {
+		err = r.apply(expression, node)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error parsing rule: %v", err)
 	}
-	return resultRoute, nil
+	return r.route.route, nil
 }
 
 // ParseDomains parses rules expressions and returns domains
 func (r *Rules) ParseDomains(expression string) ([]string, error) {
 	domains := []string{}
-	err := r.parseRules(expression, func(functionName string, function interface{}, arguments []string) error {
-		if functionName == "Host" {
-			domains = append(domains, arguments...)
-		}
-		return nil
-	})
+	node, err := r.parseRules(expression)
+	if err == nil {
+		err = ruleMatchers(node, false, func(matcher ruleMatcher, negated bool) error {
+			if matcher.name == "Host" && !negated {
+				domains = append(domains, matcher.arguments...)
+			}
+			return nil
+		})
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error parsing domains: %v", err)
 	}
 	return fun.Map(types.CanonicalDomain, domains).([]string), nil
 }
+
+// ParseHostSNI parses the rules expression of a frontend on a TCP entry point and returns its SNI hosts
+func (r *Rules) ParseHostSNI(expression string) ([]string, error) {
+	var hosts []string
+	node, err := r.parseRules(expression)
+	if err == nil {
+		err = ruleMatchers(node, false, func(matcher ruleMatcher, negated bool) error {
+			if matcher.name != "HostSNI" {
+				return fmt.Errorf("rule %s is not supported on TCP entry points", matcher.name)
+			}
+			if negated {
+				return errors.New("HostSNI rule cannot be negated")
+			}
+			if len(hosts) > 0 {
+				return errors.New("only one HostSNI rule is allowed, use a comma separated list of hosts instead")
+			}
+			hosts = append(hosts, matcher.arguments...)
+			return nil
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing SNI hosts: %v", err)
+	}
+	return fun.Map(types.CanonicalDomain, hosts).([]string), nil
+}
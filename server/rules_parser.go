@@ -0,0 +1,272 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RuleSyntaxError is returned when a rule expression cannot be parsed
+type RuleSyntaxError struct {
+	Expression string
+	// Position is the 1-based position of the error in the expression
+	Position int
+	Message  string
+}
+
+func (e *RuleSyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d in %q", e.Message, e.Position, e.Expression)
+}
+
+// ruleNode is a node of a parsed rule expression:
+// a ruleMatcher, or a ruleAnd, ruleOr or ruleNot of other nodes
+type ruleNode interface{}
+
+type ruleMatcher struct {
+	name      string
+	arguments []string
+	position  int
+}
+
+type ruleAnd []ruleNode
+
+type ruleOr []ruleNode
+
+type ruleNot struct {
+	operand ruleNode
+}
+
+// ruleParser parses rule expressions with the following grammar,
+// ! binding tighter than && and ;, which bind tighter than ||:
+//
+//	expression = and { "||" and }
+//	and        = unary { ( "&&" | ";" ) unary }
+//	unary      = "!" unary | "(" expression ")" | matcher
+//	matcher    = name ":" argument { "," argument }
+//
+// The arguments end at the next ;, && or ||, or at the closing parenthesis of a group,
+// the parentheses and braces inside the arguments (such as in regular expressions) being kept.
+type ruleParser struct {
+	expression string
+	pos        int
+	groups     int
+}
+
+func parseRuleExpression(expression string) (ruleNode, error) {
+	p := &ruleParser{expression: expression}
+
+	p.skipSpaces()
+	if p.eof() {
+		return nil, p.errorf(p.pos, "empty rule")
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if !p.eof() {
+		return nil, p.errorf(p.pos, "unexpected %q", p.expression[p.pos])
+	}
+	return node, nil
+}
+
+func (p *ruleParser) parseOr() (ruleNode, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := ruleOr{node}
+	for {
+		p.skipSpaces()
+		if !p.consume("||") {
+			break
+		}
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, node)
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return operands, nil
+}
+
+func (p *ruleParser) parseAnd() (ruleNode, error) {
+	node, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := ruleAnd{node}
+	for {
+		p.skipSpaces()
+		if p.consume("&&") {
+			node, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, node)
+			continue
+		}
+
+		if !p.consume(";") {
+			break
+		}
+		// The legacy syntax allows empty rules between the semicolons
+		for p.skipSpaces(); p.consume(";"); p.skipSpaces() {
+		}
+		if p.eof() || p.peek(")") || p.peek("||") || p.peek("&&") {
+			break
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, node)
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return operands, nil
+}
+
+func (p *ruleParser) parseUnary() (ruleNode, error) {
+	p.skipSpaces()
+	switch {
+	case p.eof():
+		return nil, p.errorf(p.pos, "missing matcher")
+
+	case p.consume("!"):
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return ruleNot{operand: node}, nil
+
+	case p.peek("("):
+		start := p.pos
+		p.pos++
+		p.groups++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if !p.consume(")") {
+			return nil, p.errorf(start, "unclosed parenthesis")
+		}
+		p.groups--
+		return node, nil
+	}
+
+	return p.parseMatcher()
+}
+
+func (p *ruleParser) parseMatcher() (ruleNode, error) {
+	start := p.pos
+	for !p.eof() && isRuleNameChar(p.expression[p.pos]) {
+		p.pos++
+	}
+	name := p.expression[start:p.pos]
+	if len(name) == 0 {
+		return nil, p.errorf(start, "unexpected %q, expected a matcher", p.expression[p.pos])
+	}
+
+	p.skipSpaces()
+	if !p.consume(":") {
+		return nil, p.errorf(p.pos, "missing ':' after %s", name)
+	}
+
+	argumentsStart := p.pos
+	var parentheses int
+arguments:
+	for ; !p.eof(); p.pos++ {
+		switch {
+		case p.peek(";"):
+			break arguments
+		case parentheses == 0 && (p.peek("&&") || p.peek("||")):
+			break arguments
+		case p.peek("("):
+			parentheses++
+		case p.peek(")"):
+			if parentheses > 0 {
+				parentheses--
+			} else if p.groups > 0 {
+				break arguments
+			}
+		}
+	}
+
+	var arguments []string
+	for _, argument := range strings.FieldsFunc(p.expression[argumentsStart:p.pos], func(c rune) bool { return c == ',' }) {
+		arguments = append(arguments, strings.TrimSpace(argument))
+	}
+	if len(arguments) == 0 {
+		return nil, p.errorf(argumentsStart, "missing arguments for %s", name)
+	}
+
+	return ruleMatcher{name: name, arguments: arguments, position: start + 1}, nil
+}
+
+func isRuleNameChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+func (p *ruleParser) eof() bool {
+	return p.pos >= len(p.expression)
+}
+
+func (p *ruleParser) peek(token string) bool {
+	return strings.HasPrefix(p.expression[p.pos:], token)
+}
+
+func (p *ruleParser) consume(token string) bool {
+	if p.peek(token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *ruleParser) skipSpaces() {
+	for !p.eof() && (p.expression[p.pos] == ' ' || p.expression[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *ruleParser) errorf(pos int, format string, args ...interface{}) error {
+	return &RuleSyntaxError{
+		Expression: p.expression,
+		Position:   pos + 1,
+		Message:    fmt.Sprintf(format, args...),
+	}
+}
+
+// ruleMatchers calls fn for each matcher of a parsed rule expression, telling whether it is negated
+func ruleMatchers(node ruleNode, negated bool, fn func(matcher ruleMatcher, negated bool) error) error {
+	switch n := node.(type) {
+	case ruleMatcher:
+		return fn(n, negated)
+	case ruleAnd:
+		for _, operand := range n {
+			if err := ruleMatchers(operand, negated, fn); err != nil {
+				return err
+			}
+		}
+	case ruleOr:
+		for _, operand := range n {
+			if err := ruleMatchers(operand, negated, fn); err != nil {
+				return err
+			}
+		}
+	case ruleNot:
+		return ruleMatchers(n.operand, !negated, fn)
+	}
+	return nil
+}
@@ -1,11 +1,13 @@
 package server
 
 import (
+	"crypto/tls"
 	"net/http"
 	"net/url"
 	"testing"
 
 	"github.com/containous/mux"
+	"github.com/containous/traefik/configuration"
 	"github.com/containous/traefik/testhelpers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -72,6 +74,14 @@ func TestParseDomains(t *testing.T) {
 			expression: "Host: Foo.Bar ;Path:/test",
 			domain:     []string{"foo.bar"},
 		},
+		{
+			expression: "(Host:foo.bar || Host:test.bar) && Path:/test",
+			domain:     []string{"foo.bar", "test.bar"},
+		},
+		{
+			expression: "Host:foo.bar && !Host:test.bar",
+			domain:     []string{"foo.bar"},
+		},
 	}
 
 	for _, test := range tests {
@@ -87,6 +97,465 @@ func TestParseDomains(t *testing.T) {
 	}
 }
 
+func TestParseHostSNI(t *testing.T) {
+	tests := []struct {
+		expression    string
+		expectedHosts []string
+		expectedError bool
+	}{
+		{
+			expression:    "HostSNI:foo.bar,Test.Bar",
+			expectedHosts: []string{"foo.bar", "test.bar"},
+		},
+		{
+			expression:    "HostSNI:*",
+			expectedHosts: []string{"*"},
+		},
+		{
+			expression:    "HostSNI:foo.bar;Path:/test",
+			expectedError: true,
+		},
+		{
+			expression:    "HostSNI:foo.bar;HostSNI:test.bar",
+			expectedError: true,
+		},
+		{
+			expression:    "Host:foo.bar",
+			expectedError: true,
+		},
+		{
+			expression:    "!HostSNI:foo.bar",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.expression, func(t *testing.T) {
+			t.Parallel()
+
+			rules := &Rules{}
+			hosts, err := rules.ParseHostSNI(test.expression)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedHosts, hosts)
+		})
+	}
+}
+
+func TestParseExpression(t *testing.T) {
+	testCases := []struct {
+		expression string
+		urls       map[string]bool
+	}{
+		{
+			expression: "Host:foo.bar || Host:bar.foo",
+			urls: map[string]bool{
+				"http://foo.bar/":  true,
+				"http://bar.foo/":  true,
+				"http://foo.test/": false,
+			},
+		},
+		{
+			expression: "(Host:foo.bar || Host:bar.foo) && PathPrefix:/api",
+			urls: map[string]bool{
+				"http://foo.bar/api":  true,
+				"http://bar.foo/api":  true,
+				"http://bar.foo/web":  false,
+				"http://foo.test/api": false,
+			},
+		},
+		{
+			expression: "Host:foo.bar || Host:bar.foo && PathPrefix:/api",
+			urls: map[string]bool{
+				"http://foo.bar/web": true,
+				"http://bar.foo/api": true,
+				"http://bar.foo/web": false,
+			},
+		},
+		{
+			expression: "Host:foo.bar && !PathPrefix:/admin",
+			urls: map[string]bool{
+				"http://foo.bar/api":   true,
+				"http://foo.bar/admin": false,
+				"http://bar.foo/api":   false,
+			},
+		},
+		{
+			expression: "!(Path:/a || Path:/b)",
+			urls: map[string]bool{
+				"http://foo.bar/a": false,
+				"http://foo.bar/b": false,
+				"http://foo.bar/c": true,
+			},
+		},
+		{
+			expression: "!!Path:/a",
+			urls: map[string]bool{
+				"http://foo.bar/a": true,
+				"http://foo.bar/b": false,
+			},
+		},
+		{
+			expression: "Host:foo.bar;Path:/a,/b;",
+			urls: map[string]bool{
+				"http://foo.bar/a": true,
+				"http://foo.bar/b": true,
+				"http://foo.bar/c": false,
+			},
+		},
+		{
+			expression: "HostRegexp:{subdomain:(foo\\.)?bar\\.com} || Host:foo.test",
+			urls: map[string]bool{
+				"http://foo.bar.com": true,
+				"http://bar.com":     true,
+				"http://foo.test":    true,
+				"http://fooubar.com": false,
+			},
+		},
+		{
+			expression: "(Path:/{id:(a|b)} || Query:debug=true)",
+			urls: map[string]bool{
+				"http://foo.bar/a":            true,
+				"http://foo.bar/c?debug=true": true,
+				"http://foo.bar/c":            false,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.expression, func(t *testing.T) {
+			t.Parallel()
+
+			rules := &Rules{route: &serverRoute{route: mux.NewRouter().NewRoute()}}
+			route, err := rules.Parse(test.expression)
+			require.NoError(t, err)
+
+			for testURL, expected := range test.urls {
+				req := testhelpers.MustNewRequest(http.MethodGet, testURL, nil)
+				assert.Equal(t, expected, route.Match(req, &mux.RouteMatch{}), testURL)
+			}
+		})
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	testCases := []struct {
+		expression       string
+		expectedPosition int
+		expectedMessage  string
+	}{
+		{
+			expression:       "",
+			expectedPosition: 1,
+			expectedMessage:  "empty rule",
+		},
+		{
+			expression:       "Host:foo.bar ||",
+			expectedPosition: 16,
+			expectedMessage:  "missing matcher",
+		},
+		{
+			expression:       "(Host:foo.bar || Host:bar.foo",
+			expectedPosition: 1,
+			expectedMessage:  "unclosed parenthesis",
+		},
+		{
+			expression:       "(Host:foo.bar))",
+			expectedPosition: 15,
+			expectedMessage:  "unexpected ')'",
+		},
+		{
+			expression:       "Host:foo.bar && Path",
+			expectedPosition: 21,
+			expectedMessage:  "missing ':' after Path",
+		},
+		{
+			expression:       "Host:foo.bar && Path:",
+			expectedPosition: 22,
+			expectedMessage:  "missing arguments for Path",
+		},
+		{
+			expression:       "Host:foo.bar && Foo:bar",
+			expectedPosition: 17,
+			expectedMessage:  "unknown function Foo",
+		},
+		{
+			expression:       "Host:foo.bar && &&Path:/",
+			expectedPosition: 17,
+			expectedMessage:  "unexpected '&', expected a matcher",
+		},
+		{
+			expression:       "Host:foo.bar || PathPrefixStrip:/api",
+			expectedPosition: 17,
+			expectedMessage:  "PathPrefixStrip cannot be used with || or !",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.expression, func(t *testing.T) {
+			t.Parallel()
+
+			rules := &Rules{route: &serverRoute{route: mux.NewRouter().NewRoute()}}
+			_, err := rules.Parse(test.expression)
+			require.Error(t, err)
+
+			node, err := rules.parseRules(test.expression)
+			if err == nil {
+				err = rules.apply(test.expression, node)
+			}
+			syntaxErr, ok := err.(*RuleSyntaxError)
+			require.True(t, ok, "unexpected error %v", err)
+			assert.Equal(t, test.expectedPosition, syntaxErr.Position)
+			assert.Equal(t, test.expectedMessage, syntaxErr.Message)
+		})
+	}
+}
+
+func TestRequestMatchers(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		expression       string
+		forwardedHeaders *configuration.ForwardedHeaders
+		request          func() *http.Request
+		expected         bool
+	}{
+		{
+			desc:       "ClientIP matching the remote address",
+			expression: "ClientIP:10.0.0.0/8, 192.168.1.1",
+			request:    newRequest("10.1.2.3:1234", nil),
+			expected:   true,
+		},
+		{
+			desc:       "ClientIP matching an address",
+			expression: "ClientIP:10.0.0.0/8, 192.168.1.1",
+			request:    newRequest("192.168.1.1:1234", nil),
+			expected:   true,
+		},
+		{
+			desc:       "ClientIP not matching the remote address",
+			expression: "ClientIP:10.0.0.0/8",
+			request:    newRequest("172.16.0.1:1234", nil),
+			expected:   false,
+		},
+		{
+			desc:       "ClientIP ignoring untrusted forwarded headers",
+			expression: "ClientIP:10.0.0.0/8",
+			request:    newRequest("172.16.0.1:1234", map[string]string{"X-Forwarded-For": "10.1.2.3"}),
+			expected:   false,
+		},
+		{
+			desc:             "ClientIP with insecure forwarded headers",
+			expression:       "ClientIP:10.0.0.0/8",
+			forwardedHeaders: &configuration.ForwardedHeaders{Insecure: true},
+			request:          newRequest("172.16.0.1:1234", map[string]string{"X-Forwarded-For": "10.1.2.3, 172.16.0.2"}),
+			expected:         true,
+		},
+		{
+			desc:             "ClientIP with a trusted proxy",
+			expression:       "ClientIP:10.0.0.0/8",
+			forwardedHeaders: &configuration.ForwardedHeaders{TrustedIPs: []string{"172.16.0.0/16"}},
+			request:          newRequest("172.16.0.1:1234", map[string]string{"X-Forwarded-For": "10.1.2.3, 172.16.0.2"}),
+			expected:         true,
+		},
+		{
+			desc:             "ClientIP with a spoofed forwarded header",
+			expression:       "ClientIP:10.0.0.0/8",
+			forwardedHeaders: &configuration.ForwardedHeaders{TrustedIPs: []string{"172.16.0.0/16"}},
+			request:          newRequest("172.16.0.1:1234", map[string]string{"X-Forwarded-For": "10.1.2.3, 8.8.8.8"}),
+			expected:         false,
+		},
+		{
+			desc:       "Cookie",
+			expression: "Cookie:session, foo, lang, fr",
+			request:    newRequest("10.0.0.1:1234", map[string]string{"Cookie": "session=foo; lang=fr"}),
+			expected:   true,
+		},
+		{
+			desc:       "Cookie with another value",
+			expression: "Cookie:session, foo",
+			request:    newRequest("10.0.0.1:1234", map[string]string{"Cookie": "session=bar"}),
+			expected:   false,
+		},
+		{
+			desc:       "Cookie missing",
+			expression: "Cookie:session, foo",
+			request:    newRequest("10.0.0.1:1234", nil),
+			expected:   false,
+		},
+		{
+			desc:       "CookieRegexp",
+			expression: "CookieRegexp:version, ^v[0-9]+$",
+			request:    newRequest("10.0.0.1:1234", map[string]string{"Cookie": "version=v2"}),
+			expected:   true,
+		},
+		{
+			desc:       "CookieRegexp not matching",
+			expression: "CookieRegexp:version, ^v[0-9]+$",
+			request:    newRequest("10.0.0.1:1234", map[string]string{"Cookie": "version=beta"}),
+			expected:   false,
+		},
+		{
+			desc:       "Scheme http",
+			expression: "Scheme:http",
+			request:    newRequest("10.0.0.1:1234", nil),
+			expected:   true,
+		},
+		{
+			desc:       "Scheme https without TLS",
+			expression: "Scheme:HTTPS",
+			request:    newRequest("10.0.0.1:1234", map[string]string{"X-Forwarded-Proto": "https"}),
+			expected:   false,
+		},
+		{
+			desc:             "Scheme https from a trusted proxy",
+			expression:       "Scheme:https",
+			forwardedHeaders: &configuration.ForwardedHeaders{TrustedIPs: []string{"10.0.0.1"}},
+			request:          newRequest("10.0.0.1:1234", map[string]string{"X-Forwarded-Proto": "https"}),
+			expected:         true,
+		},
+		{
+			desc:       "HTTPVersion",
+			expression: "HTTPVersion:HTTP/1.0, 1.1",
+			request:    newRequest("10.0.0.1:1234", nil),
+			expected:   true,
+		},
+		{
+			desc:       "HTTPVersion not matching",
+			expression: "HTTPVersion:2",
+			request:    newRequest("10.0.0.1:1234", nil),
+			expected:   false,
+		},
+		{
+			desc:       "combined with other rules",
+			expression: "Host:foo.bar && (ClientIP:10.0.0.0/8 || Cookie:beta, true)",
+			request:    newRequest("172.16.0.1:1234", map[string]string{"Cookie": "beta=true"}),
+			expected:   true,
+		},
+		{
+			desc:             "negated ClientIP behind a trusted proxy",
+			expression:       "!ClientIP:1.2.3.4",
+			forwardedHeaders: &configuration.ForwardedHeaders{TrustedIPs: []string{"10.0.0.1"}},
+			request:          newRequest("10.0.0.1:1234", map[string]string{"X-Forwarded-For": "1.2.3.4"}),
+			expected:         false,
+		},
+		{
+			desc:             "negated ClientIP not matching behind a trusted proxy",
+			expression:       "!ClientIP:1.2.3.4",
+			forwardedHeaders: &configuration.ForwardedHeaders{TrustedIPs: []string{"10.0.0.1"}},
+			request:          newRequest("10.0.0.1:1234", map[string]string{"X-Forwarded-For": "5.6.7.8"}),
+			expected:         true,
+		},
+		{
+			desc:             "ClientIP in an alternative behind a trusted proxy",
+			expression:       "ClientIP:1.2.3.4 || Cookie:beta, true",
+			forwardedHeaders: &configuration.ForwardedHeaders{TrustedIPs: []string{"10.0.0.1"}},
+			request:          newRequest("10.0.0.1:1234", map[string]string{"X-Forwarded-For": "1.2.3.4"}),
+			expected:         true,
+		},
+		{
+			desc:       "HostSNI matching the server name",
+			expression: "HostSNI:other.bar, Foo.Bar",
+			request:    newTLSRequest("foo.bar"),
+			expected:   true,
+		},
+		{
+			desc:       "HostSNI not matching the server name",
+			expression: "HostSNI:foo.bar",
+			request:    newTLSRequest("other.bar"),
+			expected:   false,
+		},
+		{
+			desc:       "HostSNI matching a wildcard",
+			expression: "HostSNI:*.bar",
+			request:    newTLSRequest("foo.bar"),
+			expected:   true,
+		},
+		{
+			desc:       "HostSNI without TLS",
+			expression: "HostSNI:foo.bar",
+			request:    newRequest("10.0.0.1:1234", nil),
+			expected:   false,
+		},
+		{
+			desc:       "HostSNI catch-all without TLS",
+			expression: "HostSNI:*",
+			request:    newRequest("10.0.0.1:1234", nil),
+			expected:   true,
+		},
+		{
+			desc:             "ClientIP of the proxy in an alternative behind a trusted proxy",
+			expression:       "ClientIP:10.0.0.1 || Cookie:beta, true",
+			forwardedHeaders: &configuration.ForwardedHeaders{TrustedIPs: []string{"10.0.0.1"}},
+			request:          newRequest("10.0.0.1:1234", map[string]string{"X-Forwarded-For": "1.2.3.4"}),
+			expected:         false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rules := &Rules{
+				route:            &serverRoute{route: mux.NewRouter().NewRoute()},
+				forwardedHeaders: test.forwardedHeaders,
+			}
+			route, err := rules.Parse(test.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, route.Match(test.request(), &mux.RouteMatch{}))
+		})
+	}
+}
+
+func TestRequestMatchersErrors(t *testing.T) {
+	testCases := []string{
+		"ClientIP:10.0.0.0/33",
+		"Cookie:session",
+		"CookieRegexp:session, (",
+		"Scheme:ftp",
+		"HTTPVersion:1.x",
+	}
+
+	for _, expression := range testCases {
+		expression := expression
+		t.Run(expression, func(t *testing.T) {
+			t.Parallel()
+
+			rules := &Rules{route: &serverRoute{route: mux.NewRouter().NewRoute()}}
+			_, err := rules.Parse(expression)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func newRequest(remoteAddr string, headers map[string]string) func() *http.Request {
+	return func() *http.Request {
+		req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/", nil)
+		req.RemoteAddr = remoteAddr
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		return req
+	}
+}
+
+// newTLSRequest returns a request to foo.bar, received on a TLS connection with the given server name.
+func newTLSRequest(serverName string) func() *http.Request {
+	return func() *http.Request {
+		req := testhelpers.MustNewRequest(http.MethodGet, "https://foo.bar/", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.TLS = &tls.ConnectionState{ServerName: serverName}
+		return req
+	}
+}
+
 func TestPriorites(t *testing.T) {
 	router := mux.NewRouter()
 	router.StrictSlash(true)
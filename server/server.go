@@ -25,19 +25,25 @@ import (
 	"github.com/containous/mux"
 	"github.com/containous/traefik/cluster"
 	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/h2c"
 	"github.com/containous/traefik/healthcheck"
+	"github.com/containous/traefik/loadbalancer"
 	"github.com/containous/traefik/log"
 	"github.com/containous/traefik/metrics"
 	"github.com/containous/traefik/middlewares"
 	"github.com/containous/traefik/middlewares/accesslog"
 	mauth "github.com/containous/traefik/middlewares/auth"
+	"github.com/containous/traefik/middlewares/cache"
+	mratelimit "github.com/containous/traefik/middlewares/ratelimit"
 	"github.com/containous/traefik/middlewares/redirect"
 	"github.com/containous/traefik/middlewares/tracing"
 	"github.com/containous/traefik/provider"
 	"github.com/containous/traefik/safe"
 	"github.com/containous/traefik/server/cookie"
+	"github.com/containous/traefik/tcp"
 	traefikTls "github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
+	"github.com/containous/traefik/udp"
 	"github.com/containous/traefik/whitelist"
 	"github.com/eapache/channels"
 	"github.com/sirupsen/logrus"
@@ -64,6 +70,7 @@ type Server struct {
 	signals                       chan os.Signal
 	stopChan                      chan bool
 	currentConfigurations         safe.Safe
+	lastRejectedConfiguration     safe.Safe
 	providerConfigUpdateMap       map[string]chan types.ConfigMessage
 	globalConfiguration           configuration.GlobalConfiguration
 	accessLoggerMiddleware        *accesslog.LogHandler
@@ -73,15 +80,22 @@ type Server struct {
 	defaultForwardingRoundTripper http.RoundTripper
 	metricsRegistry               metrics.Registry
 	provider                      provider.Provider
+	rateLimitCounter              mratelimit.Counter
+	cacheManager                  *cache.Manager
 }
 
 type serverEntryPoints map[string]*serverEntryPoint
 
 type serverEntryPoint struct {
-	httpServer *http.Server
-	listener   net.Listener
-	httpRouter *middlewares.HandlerSwitcher
-	certs      safe.Safe
+	httpServer  *http.Server
+	listener    net.Listener
+	httpRouter  *middlewares.HandlerSwitcher
+	tcpRouter   *tcp.HandlerSwitcher
+	tcpConns    *tcpConnections
+	udpConn     net.PacketConn
+	udpProxy    *udp.Proxy
+	udpBalancer *udp.BalancerSwitcher
+	certs       safe.Safe
 }
 
 type serverRoute struct {
@@ -110,10 +124,11 @@ func NewServer(globalConfiguration configuration.GlobalConfiguration, provider p
 	server.globalConfiguration = globalConfiguration
 	if server.globalConfiguration.API != nil {
 		server.globalConfiguration.API.CurrentConfigurations = &server.currentConfigurations
+		server.globalConfiguration.API.LastRejectedConfiguration = &server.lastRejectedConfiguration
 	}
 
 	server.routinesPool = safe.NewPool(context.Background())
-	server.defaultForwardingRoundTripper = createHTTPTransport(globalConfiguration)
+	server.defaultForwardingRoundTripper = createForwardingRoundTripper(globalConfiguration, createHTTPTransport(globalConfiguration))
 
 	server.tracingMiddleware = globalConfiguration.Tracing
 	if globalConfiguration.Tracing != nil && globalConfiguration.Tracing.Backend != "" {
@@ -121,10 +136,20 @@ func NewServer(globalConfiguration configuration.GlobalConfiguration, provider p
 	}
 
 	server.metricsRegistry = registerMetricClients(globalConfiguration.Metrics)
+	server.cacheManager = cache.NewManager()
+	if server.globalConfiguration.API != nil {
+		server.globalConfiguration.API.HealthCheck = healthcheck.GetHealthCheck(server.metricsRegistry)
+		server.globalConfiguration.API.Caches = server.cacheManager
+	}
 
 	if globalConfiguration.Cluster != nil {
 		// leadership creation if cluster mode
 		server.leadership = cluster.NewLeadership(server.routinesPool.Ctx(), globalConfiguration.Cluster)
+
+		if globalConfiguration.Cluster.Store != nil && globalConfiguration.Cluster.Store.Store != nil {
+			// the buckets of the distributed rate limits are shared by the nodes of the cluster
+			server.rateLimitCounter = mratelimit.NewKVCounter(globalConfiguration.Cluster.Store.Store, globalConfiguration.Cluster.Store.Prefix+"/ratelimit")
+		}
 	}
 
 	if globalConfiguration.AccessLogsFile != "" {
@@ -133,7 +158,7 @@ func NewServer(globalConfiguration configuration.GlobalConfiguration, provider p
 
 	if globalConfiguration.AccessLog != nil {
 		var err error
-		server.accessLoggerMiddleware, err = accesslog.NewLogHandler(globalConfiguration.AccessLog)
+		server.accessLoggerMiddleware, err = accesslog.NewLogHandler(globalConfiguration.AccessLog, server.metricsRegistry)
 		if err != nil {
 			log.Warnf("Unable to create log handler: %s", err)
 		}
@@ -147,14 +172,7 @@ func NewServer(globalConfiguration configuration.GlobalConfiguration, provider p
 // in Traefik at this point in time. Setting this value to the default of 100 could lead to confusing
 // behaviour and backwards compatibility issues.
 func createHTTPTransport(globalConfiguration configuration.GlobalConfiguration) *http.Transport {
-	dialer := &net.Dialer{
-		Timeout:   configuration.DefaultDialTimeout,
-		KeepAlive: 30 * time.Second,
-		DualStack: true,
-	}
-	if globalConfiguration.ForwardingTimeouts != nil {
-		dialer.Timeout = time.Duration(globalConfiguration.ForwardingTimeouts.DialTimeout)
-	}
+	dialer := createDialer(globalConfiguration)
 
 	transport := &http.Transport{
 		Proxy:                 http.ProxyFromEnvironment,
@@ -180,6 +198,36 @@ func createHTTPTransport(globalConfiguration configuration.GlobalConfiguration)
 	return transport
 }
 
+// createForwardingRoundTripper wraps the transport, so that the requests to the servers with the h2c scheme
+// are forwarded with HTTP/2 over cleartext TCP.
+func createForwardingRoundTripper(globalConfiguration configuration.GlobalConfiguration, transport *http.Transport) http.RoundTripper {
+	return h2c.NewTransport(transport, createDialer(globalConfiguration))
+}
+
+// getWebsocketTLSClientConfig returns the TLS configuration of the round tripper, used by the forwarder for the websocket connections.
+// It is never nil, as the forwarder can only read it from an *http.Transport otherwise.
+func getWebsocketTLSClientConfig(roundTripper http.RoundTripper) *tls.Config {
+	if transport, ok := roundTripper.(*h2c.Transport); ok && transport.TLSClientConfig() != nil {
+		return transport.TLSClientConfig()
+	}
+	if transport, ok := roundTripper.(*http.Transport); ok && transport.TLSClientConfig != nil {
+		return transport.TLSClientConfig
+	}
+	return &tls.Config{}
+}
+
+func createDialer(globalConfiguration configuration.GlobalConfiguration) *net.Dialer {
+	dialer := &net.Dialer{
+		Timeout:   configuration.DefaultDialTimeout,
+		KeepAlive: 30 * time.Second,
+		DualStack: true,
+	}
+	if globalConfiguration.ForwardingTimeouts != nil {
+		dialer.Timeout = time.Duration(globalConfiguration.ForwardingTimeouts.DialTimeout)
+	}
+	return dialer
+}
+
 func createRootCACertPool(rootCAs traefikTls.RootCAs) *x509.CertPool {
 	roots := x509.NewCertPool()
 
@@ -222,6 +270,28 @@ func (s *Server) Stop() {
 		wg.Add(1)
 		go func(serverEntryPointName string, serverEntryPoint *serverEntryPoint) {
 			defer wg.Done()
+			if serverEntryPoint.tcpRouter != nil {
+				if err := serverEntryPoint.listener.Close(); err != nil {
+					log.Debugf("Error closing TCP listener: %s", err)
+				}
+				graceTimeOut := time.Duration(s.globalConfiguration.LifeCycle.GraceTimeOut)
+				ctx, cancel := context.WithTimeout(context.Background(), graceTimeOut)
+				log.Debugf("Waiting %s seconds before killing connections on entrypoint %s...", graceTimeOut, serverEntryPointName)
+				if err := serverEntryPoint.tcpConns.shutdown(ctx); err != nil {
+					log.Debugf("Wait is over due to: %s", err)
+				}
+				cancel()
+				log.Debugf("Entrypoint %s closed", serverEntryPointName)
+				return
+			}
+			if serverEntryPoint.udpBalancer != nil {
+				if err := serverEntryPoint.udpConn.Close(); err != nil {
+					log.Debugf("Error closing UDP connection: %s", err)
+				}
+				serverEntryPoint.udpProxy.Close()
+				log.Debugf("Entrypoint %s closed", serverEntryPointName)
+				return
+			}
 			graceTimeOut := time.Duration(s.globalConfiguration.LifeCycle.GraceTimeOut)
 			ctx, cancel := context.WithTimeout(context.Background(), graceTimeOut)
 			log.Debugf("Waiting %s seconds before killing connections on entrypoint %s...", graceTimeOut, serverEntryPointName)
@@ -262,6 +332,7 @@ func (s *Server) Close() {
 			log.Errorf("Error closing access log file: %s", err)
 		}
 	}
+	s.cacheManager.Close()
 	cancel()
 }
 
@@ -281,6 +352,16 @@ func (s *Server) startHTTPServers() {
 	s.serverEntryPoints = s.buildEntryPoints(s.globalConfiguration)
 
 	for newServerEntryPointName, newServerEntryPoint := range s.serverEntryPoints {
+		if s.globalConfiguration.EntryPoints[newServerEntryPointName].IsTCP() {
+			serverEntryPoint := s.setupTCPServerEntryPoint(newServerEntryPointName, newServerEntryPoint)
+			go s.startTCPServer(serverEntryPoint)
+			continue
+		}
+		if s.globalConfiguration.EntryPoints[newServerEntryPointName].IsUDP() {
+			serverEntryPoint := s.setupUDPServerEntryPoint(newServerEntryPointName, newServerEntryPoint)
+			go s.startUDPServer(serverEntryPoint)
+			continue
+		}
 		serverEntryPoint := s.setupServerEntryPoint(newServerEntryPointName, newServerEntryPoint)
 		go s.startServer(serverEntryPoint, s.globalConfiguration)
 	}
@@ -313,24 +394,15 @@ func (s *Server) setupServerEntryPoint(newServerEntryPointName string, newServer
 		}
 
 	}
-	if s.globalConfiguration.EntryPoints[newServerEntryPointName].Auth != nil {
-		authMiddleware, err := mauth.NewAuthenticator(s.globalConfiguration.EntryPoints[newServerEntryPointName].Auth, s.tracingMiddleware)
-		if err != nil {
-			log.Fatal("Error starting server: ", err)
-		}
-		serverMiddlewares = append(serverMiddlewares, s.wrapNegroniHandlerWithAccessLog(authMiddleware, fmt.Sprintf("Auth for entrypoint %s", newServerEntryPointName)))
-		serverInternalMiddlewares = append(serverInternalMiddlewares, authMiddleware)
-	}
-	if s.globalConfiguration.EntryPoints[newServerEntryPointName].Compress {
-		serverMiddlewares = append(serverMiddlewares, &middlewares.Compress{})
+	entryPointMiddlewares, err := s.buildEntryPointMiddlewares(newServerEntryPointName, s.globalConfiguration.EntryPoints[newServerEntryPointName])
+	if err != nil {
+		log.Fatal("Error starting server: ", err)
 	}
-	if len(s.globalConfiguration.EntryPoints[newServerEntryPointName].WhitelistSourceRange) > 0 {
-		ipWhitelistMiddleware, err := middlewares.NewIPWhitelister(s.globalConfiguration.EntryPoints[newServerEntryPointName].WhitelistSourceRange)
-		if err != nil {
-			log.Fatal("Error starting server: ", err)
+	for _, entryPointMiddleware := range entryPointMiddlewares {
+		serverMiddlewares = append(serverMiddlewares, entryPointMiddleware.handler)
+		if entryPointMiddleware.internal != nil {
+			serverInternalMiddlewares = append(serverInternalMiddlewares, entryPointMiddleware.internal)
 		}
-		serverMiddlewares = append(serverMiddlewares, s.wrapNegroniHandlerWithAccessLog(ipWhitelistMiddleware, fmt.Sprintf("ipwhitelister for entrypoint %s", newServerEntryPointName)))
-		serverInternalMiddlewares = append(serverInternalMiddlewares, ipWhitelistMiddleware)
 	}
 	newSrv, listener, err := s.prepareServer(newServerEntryPointName, s.globalConfiguration.EntryPoints[newServerEntryPointName], newServerEntryPoint.httpRouter, serverMiddlewares, serverInternalMiddlewares)
 	if err != nil {
@@ -343,6 +415,52 @@ func (s *Server) setupServerEntryPoint(newServerEntryPointName string, newServer
 	return serverEntryPoint
 }
 
+// entryPointMiddleware is a middleware configured on an entrypoint.
+type entryPointMiddleware struct {
+	name    string
+	handler negroni.Handler
+	// internal is the middleware of the internal routes of the entrypoint, if it applies to them
+	internal negroni.Handler
+}
+
+// buildEntryPointMiddlewares builds the middlewares configured on the entrypoint, in the order the requests go through them.
+func (s *Server) buildEntryPointMiddlewares(entryPointName string, entryPoint *configuration.EntryPoint) ([]entryPointMiddleware, error) {
+	var entryPointMiddlewares []entryPointMiddleware
+	if entryPoint.Auth != nil {
+		authMiddleware, err := mauth.NewAuthenticator(entryPoint.Auth, s.tracingMiddleware)
+		if err != nil {
+			return nil, err
+		}
+		entryPointMiddlewares = append(entryPointMiddlewares, entryPointMiddleware{
+			name:     "Entrypoint auth",
+			handler:  s.wrapNegroniHandlerWithAccessLog(authMiddleware, fmt.Sprintf("Auth for entrypoint %s", entryPointName)),
+			internal: authMiddleware,
+		})
+	}
+	if entryPoint.Compress || entryPoint.Compression != nil {
+		compressMiddleware, err := middlewares.NewCompress(entryPoint.Compression)
+		if err != nil {
+			return nil, err
+		}
+		entryPointMiddlewares = append(entryPointMiddlewares, entryPointMiddleware{
+			name:    "Entrypoint compress",
+			handler: compressMiddleware,
+		})
+	}
+	if len(entryPoint.WhitelistSourceRange) > 0 {
+		ipWhitelistMiddleware, err := middlewares.NewIPWhitelister(entryPoint.WhitelistSourceRange)
+		if err != nil {
+			return nil, err
+		}
+		entryPointMiddlewares = append(entryPointMiddlewares, entryPointMiddleware{
+			name:     "Entrypoint IP whitelist",
+			handler:  s.wrapNegroniHandlerWithAccessLog(ipWhitelistMiddleware, fmt.Sprintf("ipwhitelister for entrypoint %s", entryPointName)),
+			internal: ipWhitelistMiddleware,
+		})
+	}
+	return entryPointMiddlewares, nil
+}
+
 func (s *Server) listenProviders(stop chan bool) {
 	for {
 		select {
@@ -444,10 +562,25 @@ func (s *Server) loadConfiguration(configMsg types.ConfigMessage) {
 	newConfigurations[configMsg.ProviderName] = configMsg.Configuration
 
 	s.metricsRegistry.ConfigReloadsCounter().Add(1)
+
 	newServerEntryPoints, err := s.loadConfig(newConfigurations, s.globalConfiguration)
+	if rejectedErr, ok := err.(*rejectedConfigurationError); ok {
+		s.rejectConfiguration(configMsg, rejectionReasons(rejectedErr.diagnostics))
+		return
+	}
 	if err == nil {
 		s.metricsRegistry.LastConfigReloadSuccessGauge().Set(float64(time.Now().Unix()))
 		for newServerEntryPointName, newServerEntryPoint := range newServerEntryPoints {
+			if newServerEntryPoint.tcpRouter != nil {
+				s.serverEntryPoints[newServerEntryPointName].tcpRouter.UpdateHandler(newServerEntryPoint.tcpRouter.GetHandler())
+				log.Infof("Server configuration reloaded on %s", s.globalConfiguration.EntryPoints[newServerEntryPointName].Address)
+				continue
+			}
+			if newServerEntryPoint.udpBalancer != nil {
+				s.serverEntryPoints[newServerEntryPointName].udpBalancer.UpdateBalancer(newServerEntryPoint.udpBalancer.GetBalancer())
+				log.Infof("Server configuration reloaded on %s", s.globalConfiguration.EntryPoints[newServerEntryPointName].Address)
+				continue
+			}
 			s.serverEntryPoints[newServerEntryPointName].httpRouter.UpdateHandler(newServerEntryPoint.httpRouter.GetHandler())
 			if s.globalConfiguration.EntryPoints[newServerEntryPointName].TLS == nil {
 				if newServerEntryPoint.certs.Get() != nil {
@@ -473,6 +606,11 @@ func (s *Server) loadHTTPSConfiguration(configurations types.Configurations, def
 	// Get all certificates
 	for _, configuration := range configurations {
 		if configuration.TLS != nil && len(configuration.TLS) > 0 {
+			for _, tlsConfiguration := range configuration.TLS {
+				if tlsConfiguration.Certificate == nil {
+					return nil, errors.New("missing certificate")
+				}
+			}
 			if err := traefikTls.SortTLSPerEntryPoints(configuration.TLS, newEPCertificates, defaultEntryPoints); err != nil {
 				return nil, err
 			}
@@ -749,16 +887,36 @@ func (s *Server) prepareServer(entryPointName string, entryPoint *configuration.
 		return nil, nil, err
 	}
 
+	listener, err := buildListener(entryPoint)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &http.Server{
+			Addr:         entryPoint.Address,
+			Handler:      internalMuxRouter,
+			TLSConfig:    tlsConfig,
+			ReadTimeout:  readTimeout,
+			WriteTimeout: writeTimeout,
+			IdleTimeout:  idleTimeout,
+			ErrorLog:     httpServerLogger,
+		},
+		listener,
+		nil
+}
+
+// buildListener opens the TCP listener of an entry point, handling the ProxyProtocol if enabled
+func buildListener(entryPoint *configuration.EntryPoint) (net.Listener, error) {
 	listener, err := net.Listen("tcp", entryPoint.Address)
 	if err != nil {
 		log.Error("Error opening listener ", err)
-		return nil, nil, err
+		return nil, err
 	}
 
 	if entryPoint.ProxyProtocol != nil {
 		IPs, err := whitelist.NewIP(entryPoint.ProxyProtocol.TrustedIPs, entryPoint.ProxyProtocol.Insecure)
 		if err != nil {
-			return nil, nil, fmt.Errorf("error creating whitelist: %s", err)
+			return nil, fmt.Errorf("error creating whitelist: %s", err)
 		}
 		log.Infof("Enabling ProxyProtocol for trusted IPs %v", entryPoint.ProxyProtocol.TrustedIPs)
 		listener = &proxyproto.Listener{
@@ -773,17 +931,7 @@ func (s *Server) prepareServer(entryPointName string, entryPoint *configuration.
 		}
 	}
 
-	return &http.Server{
-			Addr:         entryPoint.Address,
-			Handler:      internalMuxRouter,
-			TLSConfig:    tlsConfig,
-			ReadTimeout:  readTimeout,
-			WriteTimeout: writeTimeout,
-			IdleTimeout:  idleTimeout,
-			ErrorLog:     httpServerLogger,
-		},
-		listener,
-		nil
+	return listener, nil
 }
 
 func (s *Server) buildInternalRouter(entryPointName, path string, internalMiddlewares []negroni.Handler) *mux.Router {
@@ -837,7 +985,19 @@ func buildServerTimeouts(globalConfig configuration.GlobalConfiguration) (readTi
 
 func (s *Server) buildEntryPoints(globalConfiguration configuration.GlobalConfiguration) map[string]*serverEntryPoint {
 	serverEntryPoints := make(map[string]*serverEntryPoint)
-	for entryPointName := range globalConfiguration.EntryPoints {
+	for entryPointName, entryPoint := range globalConfiguration.EntryPoints {
+		if entryPoint.IsTCP() {
+			serverEntryPoints[entryPointName] = &serverEntryPoint{
+				tcpRouter: tcp.NewHandlerSwitcher(tcp.NewRouter()),
+			}
+			continue
+		}
+		if entryPoint.IsUDP() {
+			serverEntryPoints[entryPointName] = &serverEntryPoint{
+				udpBalancer: udp.NewBalancerSwitcher(nil),
+			}
+			continue
+		}
 		router := s.buildDefaultHTTPRouter()
 		serverEntryPoints[entryPointName] = &serverEntryPoint{
 			httpRouter: middlewares.NewHandlerSwitcher(router),
@@ -858,7 +1018,7 @@ func (s *Server) getRoundTripper(entryPointName string, globalConfiguration conf
 
 		transport := createHTTPTransport(globalConfiguration)
 		transport.TLSClientConfig = tlsConfig
-		return transport, nil
+		return createForwardingRoundTripper(globalConfiguration, transport), nil
 	}
 
 	return s.defaultForwardingRoundTripper, nil
@@ -867,13 +1027,73 @@ func (s *Server) getRoundTripper(entryPointName string, globalConfiguration conf
 // loadConfig returns a new gorilla.mux Route from the specified global configuration and the dynamic
 // provider configurations.
 func (s *Server) loadConfig(configurations types.Configurations, globalConfiguration configuration.GlobalConfiguration) (map[string]*serverEntryPoint, error) {
-	serverEntryPoints := s.buildEntryPoints(globalConfiguration)
+	built := s.buildConfig(configurations, globalConfiguration)
+	if globalConfiguration.StrictReload && HasErrors(built.diagnostics) {
+		return nil, &rejectedConfigurationError{diagnostics: built.diagnostics}
+	}
+
+	healthcheck.GetHealthCheck(s.metricsRegistry).SetBackendsConfiguration(s.routinesPool.Ctx(), built.backendsHealthCheck)
+	healthcheck.GetHealthCheck(s.metricsRegistry).SetOutlierDetectors(built.outlierDetectors)
+	s.cacheManager.SetCaches(built.caches)
+	//sort routes and update certificates
+	for serverEntryPointName, serverEntryPoint := range built.serverEntryPoints {
+		if serverEntryPoint.httpRouter == nil {
+			continue
+		}
+		serverEntryPoint.httpRouter.GetHandler().SortRoutes()
+		_, exists := built.certificates[serverEntryPointName]
+		if exists {
+			serverEntryPoint.certs.Set(built.certificates[serverEntryPointName])
+		}
+	}
+
+	return built.serverEntryPoints, built.certificatesErr
+}
+
+// builtConfiguration holds what is built from the dynamic configurations before being applied,
+// with the problems found while building it.
+type builtConfiguration struct {
+	serverEntryPoints   map[string]*serverEntryPoint
+	backendsHealthCheck map[string]*healthcheck.BackendHealthCheck
+	outlierDetectors    map[string]*healthcheck.OutlierDetector
+	caches              map[string]*cache.Cache
+	certificates        map[string]*traefikTls.DomainsCertificates
+	certificatesErr     error
+	// routes describe the frontends of the routes of the HTTP entrypoints, by entrypoint
+	routes map[string]map[*mux.Route]*builtRoute
+	// diagnostics hold the reasons of the frontends skipped or partially wired,
+	// rejecting the whole configuration in strict reload mode
+	diagnostics diagnostics
+}
+
+// builtRoute describes the frontend of a route and the middlewares its requests go through, in order.
+type builtRoute struct {
+	frontend    string
+	middlewares []string
+}
+
+// buildConfig builds the entrypoints and the backend helpers from the dynamic configurations, without applying them:
+// the health checks are not started and the caches are not set.
+func (s *Server) buildConfig(configurations types.Configurations, globalConfiguration configuration.GlobalConfiguration) *builtConfiguration {
+	built := &builtConfiguration{
+		serverEntryPoints:   s.buildEntryPoints(globalConfiguration),
+		backendsHealthCheck: map[string]*healthcheck.BackendHealthCheck{},
+		outlierDetectors:    map[string]*healthcheck.OutlierDetector{},
+		caches:              map[string]*cache.Cache{},
+		routes:              map[string]map[*mux.Route]*builtRoute{},
+	}
+	serverEntryPoints := built.serverEntryPoints
+	diags := &built.diagnostics
 	redirectHandlers := make(map[string]negroni.Handler)
-	backends := map[string]http.Handler{}
-	backendsHealthCheck := map[string]*healthcheck.BackendHealthCheck{}
+	backends := map[string]*backendHandler{}
+	tcpBackends := map[string]tcp.Handler{}
+	udpFrontends := map[string]string{}
 	errorHandler := NewRecordingErrorHandler(middlewares.DefaultNetErrorRecorder{})
 
-	for _, config := range configurations {
+	// providers are walked in a stable order, so that the frontend kept on a UDP entrypoint
+	// does not depend on the map iteration order
+	for _, providerName := range sortedProviderNames(configurations) {
+		config := configurations[providerName]
 		frontendNames := sortedFrontendNamesForConfig(config)
 	frontend:
 		for _, frontendName := range frontendNames {
@@ -885,6 +1105,7 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 			for _, entryPointName := range frontend.EntryPoints {
 				if _, ok := serverEntryPoints[entryPointName]; !ok {
 					log.Errorf("Undefined entrypoint '%s' for frontend %s", entryPointName, frontendName)
+					diags.errorf("frontends", frontendName, "undefined entrypoint '%s'", entryPointName)
 				} else {
 					frontendEntryPoints = append(frontendEntryPoints, entryPointName)
 				}
@@ -894,318 +1115,609 @@ func (s *Server) loadConfig(configurations types.Configurations, globalConfigura
 			if len(frontend.EntryPoints) == 0 {
 				log.Errorf("No entrypoint defined for frontend %s", frontendName)
 				log.Errorf("Skipping frontend %s...", frontendName)
+				diags.errorf("frontends", frontendName, "no entrypoint defined")
 				continue frontend
 			}
 			for _, entryPointName := range frontend.EntryPoints {
 				log.Debugf("Wiring frontend %s to entryPoint %s", frontendName, entryPointName)
 
+				if globalConfiguration.EntryPoints[entryPointName].IsTCP() {
+					err := s.loadTCPFrontendConfig(serverEntryPoints[entryPointName].tcpRouter.GetHandler(), entryPointName, frontendName, config, tcpBackends, built.backendsHealthCheck, globalConfiguration, diags)
+					if err != nil {
+						log.Errorf("Error creating TCP frontend %s: %v", frontendName, err)
+						log.Errorf("Skipping frontend %s...", frontendName)
+						diags.errorf("frontends", frontendName, "error creating TCP frontend on entrypoint %s: %v", entryPointName, err)
+						continue frontend
+					}
+					continue
+				}
+
+				if globalConfiguration.EntryPoints[entryPointName].IsUDP() {
+					if udpFrontend, ok := udpFrontends[entryPointName]; ok {
+						log.Errorf("Frontend %s is already defined on UDP entrypoint %s", udpFrontend, entryPointName)
+						log.Errorf("Skipping frontend %s...", frontendName)
+						diags.errorf("frontends", frontendName, "frontend %s is already defined on UDP entrypoint %s", udpFrontend, entryPointName)
+						continue frontend
+					}
+					if err := s.loadUDPFrontendConfig(serverEntryPoints[entryPointName].udpBalancer, frontendName, config, diags); err != nil {
+						log.Errorf("Error creating UDP frontend %s: %v", frontendName, err)
+						log.Errorf("Skipping frontend %s...", frontendName)
+						diags.errorf("frontends", frontendName, "error creating UDP frontend on entrypoint %s: %v", entryPointName, err)
+						continue frontend
+					}
+					udpFrontends[entryPointName] = frontendName
+					continue
+				}
+
 				newServerRoute := &serverRoute{route: serverEntryPoints[entryPointName].httpRouter.GetHandler().NewRoute().Name(frontendName)}
 				for routeName, route := range frontend.Routes {
-					err := getRoute(newServerRoute, &route)
+					err := getRoute(newServerRoute, &route, globalConfiguration.EntryPoints[entryPointName].ForwardedHeaders)
 					if err != nil {
 						log.Errorf("Error creating route for frontend %s: %v", frontendName, err)
 						log.Errorf("Skipping frontend %s...", frontendName)
+						diags.errorf("frontends", frontendName, "error creating route %s: %v", routeName, err)
 						continue frontend
 					}
 					log.Debugf("Creating route %s %s", routeName, route.Rule)
 				}
 
 				entryPoint := globalConfiguration.EntryPoints[entryPointName]
-				n := negroni.New()
+				var entryPointRedirect negroni.Handler
 				if entryPoint.Redirect != nil {
 					if redirectHandlers[entryPointName] != nil {
-						n.Use(redirectHandlers[entryPointName])
+						entryPointRedirect = redirectHandlers[entryPointName]
 					} else if handler, err := s.buildRedirectHandler(entryPointName, entryPoint.Redirect); err != nil {
 						log.Errorf("Error loading entrypoint configuration for frontend %s: %v", frontendName, err)
 						log.Errorf("Skipping frontend %s...", frontendName)
+						diags.errorf("frontends", frontendName, "error loading the redirect of entrypoint %s: %v", entryPointName, err)
 						continue frontend
 					} else {
-						handlerToUse := s.wrapNegroniHandlerWithAccessLog(handler, fmt.Sprintf("entrypoint redirect for %s", frontendName))
-						n.Use(handlerToUse)
-						redirectHandlers[entryPointName] = handlerToUse
+						entryPointRedirect = s.wrapNegroniHandlerWithAccessLog(handler, fmt.Sprintf("entrypoint redirect for %s", frontendName))
+						redirectHandlers[entryPointName] = entryPointRedirect
 					}
 				}
-				if backends[entryPointName+frontend.Backend] == nil {
-					log.Debugf("Creating backend %s", frontend.Backend)
 
-					roundTripper, err := s.getRoundTripper(entryPointName, globalConfiguration, frontend.PassTLSCert, entryPoint.TLS)
+				backendNames := []string{frontend.Backend}
+				if frontend.WeightedBackends != nil {
+					backendNames = frontend.WeightedBackends.Names()
+				}
+				// The backend handlers are shared by the frontends of the backends, and the middlewares of the frontend
+				// are built once, in front of the handler of its backend, or of its weighted backends.
+				frontendBackends := map[string]http.Handler{}
+				frontendBackendLoadBalancers := map[string]healthcheck.LoadBalancer{}
+				frontendBackendMiddlewares := map[string][]string{}
+				countServers := 0
+				for _, backendName := range backendNames {
+					backend, err := s.getBackendHandler(backends, entryPointName, backendName, config, globalConfiguration, built)
 					if err != nil {
-						log.Errorf("Failed to create RoundTripper for frontend %s: %v", frontendName, err)
+						log.Errorf("Error creating backend %s for frontend %s: %v", backendName, frontendName, err)
 						log.Errorf("Skipping frontend %s...", frontendName)
+						diags.errorf("frontends", frontendName, "error creating backend %s: %v", backendName, err)
 						continue frontend
 					}
 
-					rewriter, err := NewHeaderRewriter(entryPoint.ForwardedHeaders.TrustedIPs, entryPoint.ForwardedHeaders.Insecure)
+					backendFrontend := *frontend
+					backendFrontend.Backend = backendName
+
+					forwarder, err := s.buildForwarder(entryPointName, frontendName, &backendFrontend, globalConfiguration, errorHandler)
 					if err != nil {
-						log.Errorf("Error creating rewriter for frontend %s: %v", frontendName, err)
+						log.Errorf("Error creating forwarder of backend %s for frontend %s: %v", backendName, frontendName, err)
 						log.Errorf("Skipping frontend %s...", frontendName)
+						diags.errorf("frontends", frontendName, "error creating forwarder of backend %s: %v", backendName, err)
 						continue frontend
 					}
 
-					headerMiddleware := middlewares.NewHeaderFromStruct(frontend.Headers)
-					var responseModifier func(res *http.Response) error
-					if headerMiddleware != nil {
-						responseModifier = headerMiddleware.ModifyResponseHeaders
-					}
+					frontendBackends[backendName] = middlewares.NewFrontendForwarder(backend.handler, forwarder)
+					frontendBackendLoadBalancers[backendName] = backend.lb
+					frontendBackendMiddlewares[backendName] = backend.middlewares
+					countServers += len(config.Backends[backendName].Servers)
+				}
 
-					var fwd http.Handler
+				next := frontendBackends[frontend.Backend]
+				if frontend.WeightedBackends != nil {
+					next = s.buildWeightedBackendsHandler(frontendName, frontend.WeightedBackends, frontendBackends, frontendBackendLoadBalancers)
+				}
 
-					fwd, err = forward.New(
-						forward.Stream(true),
-						forward.PassHostHeader(frontend.PassHostHeader),
-						forward.RoundTripper(roundTripper),
-						forward.ErrorHandler(errorHandler),
-						forward.Rewriter(rewriter),
-						forward.ResponseModifier(responseModifier),
-					)
+				var names []string
+				n := negroni.New()
+				if entryPointRedirect != nil {
+					n.Use(entryPointRedirect)
+					names = append(names, "Entrypoint redirect")
+				}
+				frontendMiddlewares, err := s.loadFrontendHandler(n, entryPointName, frontendName, frontend, next, countServers, config, globalConfiguration, built)
+				if err != nil {
+					log.Errorf("Error creating middlewares of frontend %s: %v", frontendName, err)
+					log.Errorf("Skipping frontend %s...", frontendName)
+					diags.errorf("frontends", frontendName, "error creating middlewares: %v", err)
+					continue frontend
+				}
 
-					if err != nil {
-						log.Errorf("Error creating forwarder for frontend %s: %v", frontendName, err)
-						log.Errorf("Skipping frontend %s...", frontendName)
-						continue frontend
+				if frontend.Priority > 0 {
+					newServerRoute.route.Priority(frontend.Priority)
+				}
+				routeMiddlewares := s.wireFrontendBackend(newServerRoute, n, frontendName, diags)
+				routeMiddlewares = append(append(routeMiddlewares, names...), frontendMiddlewares...)
+
+				if frontend.WeightedBackends != nil {
+					routeMiddlewares = append(routeMiddlewares, "Weighted backends")
+				}
+				for _, backendName := range backendNames {
+					for _, name := range frontendBackendMiddlewares[backendName] {
+						if frontend.WeightedBackends != nil {
+							name = fmt.Sprintf("%s (%s)", name, backendName)
+						}
+						routeMiddlewares = append(routeMiddlewares, name)
 					}
+				}
+				if built.routes[entryPointName] == nil {
+					built.routes[entryPointName] = make(map[*mux.Route]*builtRoute)
+				}
+				built.routes[entryPointName][newServerRoute.route] = &builtRoute{frontend: frontendName, middlewares: routeMiddlewares}
 
-					if s.tracingMiddleware.IsEnabled() {
-						tm := s.tracingMiddleware.NewForwarderMiddleware(frontendName, frontend.Backend)
+				if err := newServerRoute.route.GetError(); err != nil {
+					log.Errorf("Error building route: %s", err)
+					diags.errorf("frontends", frontendName, "error building route: %v", err)
+				}
+			}
+		}
+	}
 
-						next := fwd
-						fwd = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-							tm.ServeHTTP(w, r, next.ServeHTTP)
-						})
-					}
+	// Get new certificates list sorted per entrypoints
+	built.certificates, built.certificatesErr = s.loadHTTPSConfiguration(configurations, globalConfiguration.DefaultEntryPoints)
+	if built.certificatesErr != nil {
+		diags.errorf("tls", "", "error loading certificates: %v", built.certificatesErr)
+	}
 
-					var rr *roundrobin.RoundRobin
-					var saveFrontend http.Handler
-					if s.accessLoggerMiddleware != nil {
-						saveBackend := accesslog.NewSaveBackend(fwd, frontend.Backend)
-						saveFrontend = accesslog.NewSaveFrontend(saveBackend, frontendName)
-						rr, _ = roundrobin.New(saveFrontend)
-					} else {
-						rr, _ = roundrobin.New(fwd)
-					}
+	return built
+}
 
-					if config.Backends[frontend.Backend] == nil {
-						log.Errorf("Undefined backend '%s' for frontend %s", frontend.Backend, frontendName)
-						log.Errorf("Skipping frontend %s...", frontendName)
-						continue frontend
-					}
+// backendHandler is the handler chain of a backend on an entry point, shared by the frontends of the backend.
+// The requests are forwarded to the backend servers with the forwarder of their frontend, passed in the request context.
+type backendHandler struct {
+	handler http.Handler
+	lb      healthcheck.LoadBalancer
+	// middlewares are the names of the middlewares of the chain, in the order the requests go through them.
+	middlewares []string
+}
 
-					lbMethod, err := types.NewLoadBalancerMethod(config.Backends[frontend.Backend].LoadBalancer)
-					if err != nil {
-						log.Errorf("Error loading load balancer method '%+v' for frontend %s: %v", config.Backends[frontend.Backend].LoadBalancer, frontendName, err)
-						log.Errorf("Skipping frontend %s...", frontendName)
-						continue frontend
-					}
+// getBackendHandler returns the handler chain of the backend on the entry point, creating it for the first frontend of the backend.
+func (s *Server) getBackendHandler(backends map[string]*backendHandler, entryPointName string, backendName string, config *types.Configuration,
+	globalConfiguration configuration.GlobalConfiguration, built *builtConfiguration) (*backendHandler, error) {
+	backendKey := backendCacheKey(entryPointName, backendName)
+	if backend, ok := backends[backendKey]; ok {
+		log.Debugf("Reusing backend %s", backendName)
+		return backend, nil
+	}
 
-					var sticky *roundrobin.StickySession
-					var cookieName string
-					if stickiness := config.Backends[frontend.Backend].LoadBalancer.Stickiness; stickiness != nil {
-						cookieName = cookie.GetName(stickiness.CookieName, frontend.Backend)
-						sticky = roundrobin.NewStickySession(cookieName)
-					}
+	log.Debugf("Creating backend %s", backendName)
+	backend, err := s.loadBackendHandler(entryPointName, backendName, config, globalConfiguration, built)
+	if err != nil {
+		return nil, err
+	}
+	backends[backendKey] = backend
+	return backend, nil
+}
 
-					var lb http.Handler
-					switch lbMethod {
-					case types.Drr:
-						log.Debugf("Creating load-balancer drr")
-						rebalancer, _ := roundrobin.NewRebalancer(rr)
-						if sticky != nil {
-							log.Debugf("Sticky session with cookie %v", cookieName)
-							rebalancer, _ = roundrobin.NewRebalancer(rr, roundrobin.RebalancerStickySession(sticky))
-						}
-						lb = rebalancer
-						if err := s.configureLBServers(rebalancer, config, frontend); err != nil {
-							log.Errorf("Skipping frontend %s...", frontendName)
-							continue frontend
-						}
-						hcOpts := parseHealthCheckOptions(rebalancer, frontend.Backend, config.Backends[frontend.Backend].HealthCheck, globalConfiguration.HealthCheck)
-						if hcOpts != nil {
-							log.Debugf("Setting up backend health check %s", *hcOpts)
-							hcOpts.Transport = s.defaultForwardingRoundTripper
-							backendsHealthCheck[entryPointName+frontend.Backend] = healthcheck.NewBackendHealthCheck(*hcOpts, frontend.Backend)
-						}
-						lb = middlewares.NewEmptyBackendHandler(rebalancer, lb)
-					case types.Wrr:
-						log.Debugf("Creating load-balancer wrr")
-						if sticky != nil {
-							log.Debugf("Sticky session with cookie %v", cookieName)
-							if s.accessLoggerMiddleware != nil {
-								rr, _ = roundrobin.New(saveFrontend, roundrobin.EnableStickySession(sticky))
-							} else {
-								rr, _ = roundrobin.New(fwd, roundrobin.EnableStickySession(sticky))
-							}
-						}
-						lb = rr
-						if err := s.configureLBServers(rr, config, frontend); err != nil {
-							log.Errorf("Skipping frontend %s...", frontendName)
-							continue frontend
-						}
-						hcOpts := parseHealthCheckOptions(rr, frontend.Backend, config.Backends[frontend.Backend].HealthCheck, globalConfiguration.HealthCheck)
-						if hcOpts != nil {
-							log.Debugf("Setting up backend health check %s", *hcOpts)
-							hcOpts.Transport = s.defaultForwardingRoundTripper
-							backendsHealthCheck[entryPointName+frontend.Backend] = healthcheck.NewBackendHealthCheck(*hcOpts, frontend.Backend)
-						}
-						lb = middlewares.NewEmptyBackendHandler(rr, lb)
-					}
+// loadBackendHandler creates the handler chain of a backend: the load balancer of the backend servers,
+// with their health check and outlier detection, and the connection limit, buffering and circuit breaker of the backend.
+func (s *Server) loadBackendHandler(entryPointName string, backendName string, config *types.Configuration,
+	globalConfiguration configuration.GlobalConfiguration, built *builtConfiguration) (*backendHandler, error) {
+	backend := config.Backends[backendName]
+	if backend == nil {
+		return nil, fmt.Errorf("undefined backend '%s'", backendName)
+	}
+	backendKey := backendCacheKey(entryPointName, backendName)
+	diags := &built.diagnostics
 
-					if len(frontend.Errors) > 0 {
-						for _, errorPage := range frontend.Errors {
-							if config.Backends[errorPage.Backend] != nil && config.Backends[errorPage.Backend].Servers["error"].URL != "" {
-								errorPageHandler, err := middlewares.NewErrorPagesHandler(errorPage, config.Backends[errorPage.Backend].Servers["error"].URL)
-								if err != nil {
-									log.Errorf("Error creating custom error page middleware, %v", err)
-								} else {
-									n.Use(errorPageHandler)
-								}
-							} else {
-								log.Errorf("Error Page is configured for Frontend %s, but either Backend %s is not set or Backend URL is missing", frontendName, errorPage.Backend)
-							}
-						}
-					}
+	// names are the names of the middlewares added to n, and lbNames the ones wrapping the load balancer,
+	// in the order the requests go through them.
+	var names, lbNames []string
 
-					if frontend.RateLimit != nil && len(frontend.RateLimit.RateSet) > 0 {
-						lb, err = s.buildRateLimiter(lb, frontend.RateLimit)
-						lb = s.wrapHTTPHandlerWithAccessLog(lb, fmt.Sprintf("rate limit for %s", frontendName))
-						if err != nil {
-							log.Errorf("Error creating rate limiter: %v", err)
-							log.Errorf("Skipping frontend %s...", frontendName)
-							continue frontend
-						}
-					}
+	var fwd http.Handler = middlewares.ContextForwarder{}
 
-					maxConns := config.Backends[frontend.Backend].MaxConn
-					if maxConns != nil && maxConns.Amount != 0 {
-						extractFunc, err := utils.NewExtractor(maxConns.ExtractorFunc)
-						if err != nil {
-							log.Errorf("Error creating connlimit: %v", err)
-							log.Errorf("Skipping frontend %s...", frontendName)
-							continue frontend
-						}
-						log.Debugf("Creating load-balancer connlimit")
-						lb, err = connlimit.New(lb, extractFunc, maxConns.Amount)
-						lb = s.wrapHTTPHandlerWithAccessLog(lb, fmt.Sprintf("connection limit for %s", frontendName))
-						if err != nil {
-							log.Errorf("Error creating connlimit: %v", err)
-							log.Errorf("Skipping frontend %s...", frontendName)
-							continue frontend
-						}
-					}
+	var outlierDetector *healthcheck.OutlierDetector
+	if odOpts := parseOutlierDetectionOptions(backendName, backend.OutlierDetection, diags); odOpts != nil {
+		log.Debugf("Setting up backend outlier detection %s", *odOpts)
+		outlierDetector = healthcheck.NewOutlierDetector(*odOpts, backendName, s.metricsRegistry)
+		fwd = middlewares.NewOutlierDetection(fwd, outlierDetector)
+	}
 
-					if globalConfiguration.Retry != nil {
-						countServers := len(config.Backends[frontend.Backend].Servers)
-						lb = s.buildRetryMiddleware(lb, globalConfiguration, countServers, frontend.Backend)
-					}
+	lbMethod, err := types.NewLoadBalancerMethod(backend.LoadBalancer)
+	if err != nil {
+		return nil, fmt.Errorf("error loading load balancer method '%+v': %v", backend.LoadBalancer, err)
+	}
+
+	var sticky *roundrobin.StickySession
+	var cookieName string
+	if stickiness := backend.LoadBalancer.Stickiness; stickiness != nil {
+		cookieName = cookie.GetName(stickiness.CookieName, backendName)
+		sticky = roundrobin.NewStickySession(cookieName)
+	}
+
+	var balancer healthcheck.LoadBalancer
+	var lb http.Handler
+	switch lbMethod {
+	case types.Drr:
+		log.Debugf("Creating load-balancer drr")
+		rr, _ := roundrobin.New(fwd)
+		rebalancer, _ := roundrobin.NewRebalancer(rr)
+		if sticky != nil {
+			log.Debugf("Sticky session with cookie %v", cookieName)
+			rebalancer, _ = roundrobin.NewRebalancer(rr, roundrobin.RebalancerStickySession(sticky))
+		}
+		lb = rebalancer
+		balancer = rebalancer
+	case types.Wrr:
+		log.Debugf("Creating load-balancer wrr")
+		rr, _ := roundrobin.New(fwd)
+		if sticky != nil {
+			log.Debugf("Sticky session with cookie %v", cookieName)
+			rr, _ = roundrobin.New(fwd, roundrobin.EnableStickySession(sticky))
+		}
+		lb = rr
+		balancer = rr
+	case types.LeastConn, types.PowerOfTwoChoices, types.EWMA, types.ConsistentHash:
+		log.Debugf("Creating load-balancer %s", backend.LoadBalancer.Method)
+		if sticky != nil {
+			log.Debugf("Sticky session with cookie %v", cookieName)
+		}
+		var lbBalancer *loadbalancer.Balancer
+		switch lbMethod {
+		case types.LeastConn:
+			lbBalancer = loadbalancer.NewLeastConn(fwd, sticky)
+		case types.PowerOfTwoChoices:
+			lbBalancer = loadbalancer.NewPowerOfTwoChoices(fwd, sticky)
+		case types.ConsistentHash:
+			lbBalancer, err = buildConsistentHash(fwd, sticky, backend.LoadBalancer.ConsistentHash, backendName, diags)
+			if err != nil {
+				return nil, fmt.Errorf("error creating consistent hash load balancer: %v", err)
+			}
+		default:
+			lbBalancer = loadbalancer.NewEWMA(fwd, sticky)
+		}
+		lb = lbBalancer
+		balancer = lbBalancer
+	}
 
-					if s.metricsRegistry.IsEnabled() {
-						n.Use(middlewares.NewBackendMetricsMiddleware(s.metricsRegistry, frontend.Backend))
-					}
+	if err := s.configureLBServers(balancer, config, backendName); err != nil {
+		return nil, err
+	}
+	hcOpts, err := parseHealthCheckOptions(balancer, backendName, backend.HealthCheck, globalConfiguration.HealthCheck, diags)
+	if err != nil {
+		return nil, err
+	}
+	if hcOpts != nil {
+		log.Debugf("Setting up backend health check %s", *hcOpts)
+		hcOpts.Transport = s.defaultForwardingRoundTripper
+		built.backendsHealthCheck[backendKey] = healthcheck.NewBackendHealthCheck(*hcOpts, backendName)
+	}
+	lb = middlewares.NewEmptyBackendHandler(balancer, lb)
 
-					ipWhitelistMiddleware, err := configureIPWhitelistMiddleware(frontend.WhitelistSourceRange)
-					if err != nil {
-						log.Errorf("Error creating IP Whitelister: %s", err)
-					} else if ipWhitelistMiddleware != nil {
-						ipWhitelistMiddleware = s.wrapNegroniHandlerWithAccessLog(ipWhitelistMiddleware, fmt.Sprintf("ipwhitelister for %s", frontendName))
-						n.Use(s.tracingMiddleware.NewNegroniHandlerWrapper("IP whitelist", ipWhitelistMiddleware, false))
-						log.Infof("Configured IP Whitelists: %s", frontend.WhitelistSourceRange)
-					}
+	if outlierDetector != nil {
+		outlierDetector.LB = balancer
+		outlierDetector.HealthCheck = built.backendsHealthCheck[backendKey]
+		built.outlierDetectors[backendKey] = outlierDetector
+	}
 
-					if frontend.Redirect != nil {
-						rewrite, err := s.buildRedirectHandler(entryPointName, frontend.Redirect)
-						if err != nil {
-							log.Errorf("Error creating Frontend Redirect: %v", err)
-						} else {
-							n.Use(s.wrapNegroniHandlerWithAccessLog(rewrite, fmt.Sprintf("frontend redirect for %s", frontendName)))
-							log.Debugf("Frontend %s redirect created", frontendName)
-						}
-					}
+	maxConns := backend.MaxConn
+	if maxConns != nil && maxConns.Amount != 0 {
+		extractFunc, err := utils.NewExtractor(maxConns.ExtractorFunc)
+		if err != nil {
+			return nil, fmt.Errorf("error creating connlimit: %v", err)
+		}
+		log.Debugf("Creating load-balancer connlimit")
+		lb, err = connlimit.New(lb, extractFunc, maxConns.Amount)
+		lb = s.wrapHTTPHandlerWithAccessLog(lb, fmt.Sprintf("connection limit for %s", backendName))
+		if err != nil {
+			return nil, fmt.Errorf("error creating connlimit: %v", err)
+		}
+		lbNames = append([]string{"Connection limit"}, lbNames...)
+	}
 
-					if len(frontend.BasicAuth) > 0 {
-						users := types.Users{}
-						for _, user := range frontend.BasicAuth {
-							users = append(users, user)
-						}
+	if backend.Buffering != nil {
+		bufferedLb, err := s.buildBufferingMiddleware(lb, backend.Buffering)
 
-						auth := &types.Auth{}
-						auth.Basic = &types.Basic{
-							Users: users,
-						}
-						authMiddleware, err := mauth.NewAuthenticator(auth, s.tracingMiddleware)
-						if err != nil {
-							log.Errorf("Error creating Auth: %s", err)
-						} else {
-							n.Use(s.wrapNegroniHandlerWithAccessLog(authMiddleware, fmt.Sprintf("Auth for %s", frontendName)))
-						}
-					}
+		if err != nil {
+			log.Errorf("Error setting up buffering middleware: %s", err)
+			diags.errorf("backends", backendName, "error setting up buffering middleware: %v", err)
+		} else {
+			lb = bufferedLb
+			lbNames = append([]string{"Buffering"}, lbNames...)
+		}
+	}
 
-					if headerMiddleware != nil {
-						log.Debugf("Adding header middleware for frontend %s", frontendName)
-						n.Use(s.tracingMiddleware.NewNegroniHandlerWrapper("Header", headerMiddleware, false))
-					}
+	n := negroni.New()
+	if s.metricsRegistry.IsEnabled() {
+		n.Use(middlewares.NewBackendMetricsMiddleware(s.metricsRegistry, backendName))
+		names = append(names, "Backend metrics")
+	}
 
-					secureMiddleware := middlewares.NewSecure(frontend.Headers)
-					if secureMiddleware != nil {
-						log.Debugf("Adding secure middleware for frontend %s", frontendName)
-						n.UseFunc(secureMiddleware.HandlerFuncWithNext)
-					}
+	if backend.CircuitBreaker != nil {
+		log.Debugf("Creating circuit breaker %s", backend.CircuitBreaker.Expression)
+		expression := backend.CircuitBreaker.Expression
+		circuitBreaker, err := middlewares.NewCircuitBreaker(lb, expression, middlewares.NewCircuitBreakerOptions(expression))
+		if err != nil {
+			return nil, fmt.Errorf("error creating circuit breaker: %v", err)
+		}
+		n.Use(s.tracingMiddleware.NewNegroniHandlerWrapper("Circuit breaker", circuitBreaker, false))
+		names = append(names, "Circuit breaker")
+	} else {
+		n.UseHandler(lb)
+	}
 
-					if config.Backends[frontend.Backend].Buffering != nil {
-						bufferedLb, err := s.buildBufferingMiddleware(lb, config.Backends[frontend.Backend].Buffering)
+	return &backendHandler{handler: n, lb: balancer, middlewares: append(names, lbNames...)}, nil
+}
 
-						if err != nil {
-							log.Errorf("Error setting up buffering middleware: %s", err)
-						} else {
-							lb = bufferedLb
-						}
-					}
+// backendCacheKey identifies the handler chain of a backend, which is shared by the frontends of the backend on an entry point.
+func backendCacheKey(entryPointName, backendName string) string {
+	return entryPointName + "/" + backendName
+}
 
-					if config.Backends[frontend.Backend].CircuitBreaker != nil {
-						log.Debugf("Creating circuit breaker %s", config.Backends[frontend.Backend].CircuitBreaker.Expression)
-						expression := config.Backends[frontend.Backend].CircuitBreaker.Expression
-						circuitBreaker, err := middlewares.NewCircuitBreaker(lb, expression, middlewares.NewCircuitBreakerOptions(expression))
-						if err != nil {
-							log.Errorf("Error creating circuit breaker: %v", err)
-							log.Errorf("Skipping frontend %s...", frontendName)
-							continue frontend
-						}
-						n.Use(s.tracingMiddleware.NewNegroniHandlerWrapper("Circuit breaker", circuitBreaker, false))
-					} else {
-						n.UseHandler(lb)
-					}
-					backends[entryPointName+frontend.Backend] = n
-				} else {
-					log.Debugf("Reusing backend %s", frontend.Backend)
-				}
-				if frontend.Priority > 0 {
-					newServerRoute.route.Priority(frontend.Priority)
-				}
-				s.wireFrontendBackend(newServerRoute, backends[entryPointName+frontend.Backend])
+// buildForwarder creates the forwarder of the requests of the frontend to the servers of its backend.
+func (s *Server) buildForwarder(entryPointName string, frontendName string, frontend *types.Frontend,
+	globalConfiguration configuration.GlobalConfiguration, errorHandler *RecordingErrorHandler) (http.Handler, error) {
+	roundTripper, rewriter, err := s.buildRoundTripperAndRewriter(entryPointName, frontend, globalConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseModifier func(res *http.Response) error
+	if headerMiddleware := middlewares.NewHeaderFromStruct(frontend.Headers); headerMiddleware != nil {
+		responseModifier = headerMiddleware.ModifyResponseHeaders
+	}
+
+	var fwd http.Handler
+
+	fwd, err = forward.New(
+		forward.Stream(true),
+		forward.PassHostHeader(frontend.PassHostHeader),
+		forward.RoundTripper(roundTripper),
+		forward.WebsocketTLSClientConfig(getWebsocketTLSClientConfig(roundTripper)),
+		forward.ErrorHandler(errorHandler),
+		forward.Rewriter(rewriter),
+		forward.ResponseModifier(responseModifier),
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("error creating forwarder: %v", err)
+	}
+
+	if s.tracingMiddleware.IsEnabled() {
+		tm := s.tracingMiddleware.NewForwarderMiddleware(frontendName, frontend.Backend)
+
+		next := fwd
+		fwd = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tm.ServeHTTP(w, r, next.ServeHTTP)
+		})
+	}
+
+	if s.accessLoggerMiddleware != nil {
+		saveBackend := accesslog.NewSaveBackend(fwd, frontend.Backend)
+		fwd = accesslog.NewSaveFrontend(saveBackend, frontendName)
+	}
+	return fwd, nil
+}
+
+// buildRoundTripperAndRewriter creates the round tripper and the request rewriter of the requests forwarded by the frontend.
+func (s *Server) buildRoundTripperAndRewriter(entryPointName string, frontend *types.Frontend,
+	globalConfiguration configuration.GlobalConfiguration) (http.RoundTripper, forward.ReqRewriter, error) {
+	entryPoint := globalConfiguration.EntryPoints[entryPointName]
+
+	roundTripper, err := s.getRoundTripper(entryPointName, globalConfiguration, frontend.PassTLSCert, entryPoint.TLS)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create RoundTripper: %v", err)
+	}
+
+	rewriter, err := NewHeaderRewriter(entryPoint.ForwardedHeaders.TrustedIPs, entryPoint.ForwardedHeaders.Insecure)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating rewriter: %v", err)
+	}
+	return roundTripper, rewriter, nil
+}
+
+// loadFrontendHandler adds to n the middlewares of the frontend, followed by next which forwards the requests to the backends of the frontend,
+// and returns the names of the middlewares, in the order the requests go through them.
+func (s *Server) loadFrontendHandler(n *negroni.Negroni, entryPointName string, frontendName string, frontend *types.Frontend, next http.Handler,
+	countServers int, config *types.Configuration, globalConfiguration configuration.GlobalConfiguration, built *builtConfiguration) ([]string, error) {
+	diags := &built.diagnostics
 
-				err := newServerRoute.route.GetError()
+	// names are the names of the middlewares added to n, and nextNames the ones wrapping next,
+	// in the order the requests go through them.
+	var names, nextNames []string
+
+	// The errors returned to the gRPC clients are converted into gRPC statuses.
+	n.Use(middlewares.NewGRPCErrors())
+	names = append(names, "gRPC errors")
+
+	if s.accessLoggerMiddleware != nil && frontend.AccessLog != nil {
+		saveSettings, err := accesslog.NewSaveSettings(frontend.AccessLog)
+		if err != nil {
+			return nil, fmt.Errorf("error creating access log settings: %v", err)
+		}
+		n.Use(saveSettings)
+		names = append(names, "Access log settings")
+	}
+
+	if len(frontend.Errors) > 0 {
+		for _, errorPage := range frontend.Errors {
+			if config.Backends[errorPage.Backend] != nil && config.Backends[errorPage.Backend].Servers["error"].URL != "" {
+				errorPageHandler, err := middlewares.NewErrorPagesHandler(errorPage, config.Backends[errorPage.Backend].Servers["error"].URL)
 				if err != nil {
-					log.Errorf("Error building route: %s", err)
+					log.Errorf("Error creating custom error page middleware, %v", err)
+					diags.errorf("frontends", frontendName, "error creating error page middleware: %v", err)
+				} else {
+					n.Use(errorPageHandler)
+					names = append(names, "Error pages")
 				}
+			} else {
+				log.Errorf("Error Page is configured for Frontend %s, but either Backend %s is not set or Backend URL is missing", frontendName, errorPage.Backend)
+				diags.errorf("frontends", frontendName, "error page configured, but either backend %s is not set or its URL is missing", errorPage.Backend)
 			}
 		}
 	}
-	healthcheck.GetHealthCheck(s.metricsRegistry).SetBackendsConfiguration(s.routinesPool.Ctx(), backendsHealthCheck)
-	// Get new certificates list sorted per entrypoints
-	// Update certificates
-	entryPointsCertificates, err := s.loadHTTPSConfiguration(configurations, globalConfiguration.DefaultEntryPoints)
-	//sort routes and update certificates
-	for serverEntryPointName, serverEntryPoint := range serverEntryPoints {
-		serverEntryPoint.httpRouter.GetHandler().SortRoutes()
-		_, exists := entryPointsCertificates[serverEntryPointName]
-		if exists {
-			serverEntryPoint.certs.Set(entryPointsCertificates[serverEntryPointName])
+
+	var err error
+	if frontend.RateLimit != nil && len(frontend.RateLimit.RateSet) > 0 {
+		next, err = s.buildRateLimiter(next, frontend.RateLimit, frontendName, diags)
+		next = s.wrapHTTPHandlerWithAccessLog(next, fmt.Sprintf("rate limit for %s", frontendName))
+		if err != nil {
+			return nil, fmt.Errorf("error creating rate limiter: %v", err)
+		}
+		nextNames = append([]string{"Rate limit"}, nextNames...)
+	}
+
+	if globalConfiguration.Retry != nil || frontend.Retry != nil {
+		// The retries of a frontend with weighted backends are counted for all its backends.
+		backendName := frontend.Backend
+		if frontend.WeightedBackends != nil {
+			backendName = strings.Join(frontend.WeightedBackends.Names(), ",")
+		}
+		next, err = s.buildRetryMiddleware(next, globalConfiguration, frontend.Retry, countServers, backendName)
+		if err != nil {
+			return nil, fmt.Errorf("error creating retry middleware: %v", err)
+		}
+		nextNames = append([]string{"Retry"}, nextNames...)
+	}
+
+	if frontend.Mirroring != nil {
+		roundTripper, rewriter, err := s.buildRoundTripperAndRewriter(entryPointName, frontend, globalConfiguration)
+		if err != nil {
+			return nil, err
+		}
+		next, err = s.buildMirroringMiddleware(next, frontend, config, roundTripper, rewriter)
+		if err != nil {
+			return nil, fmt.Errorf("error creating mirroring middleware: %v", err)
+		}
+		nextNames = append([]string{"Mirroring"}, nextNames...)
+	}
+
+	ipWhitelistMiddleware, err := configureIPWhitelistMiddleware(frontend.WhitelistSourceRange)
+	if err != nil {
+		return nil, fmt.Errorf("error creating IP whitelister: %v", err)
+	}
+	if ipWhitelistMiddleware != nil {
+		ipWhitelistMiddleware = s.wrapNegroniHandlerWithAccessLog(ipWhitelistMiddleware, fmt.Sprintf("ipwhitelister for %s", frontendName))
+		n.Use(s.tracingMiddleware.NewNegroniHandlerWrapper("IP whitelist", ipWhitelistMiddleware, false))
+		names = append(names, "IP whitelist")
+		log.Infof("Configured IP Whitelists: %s", frontend.WhitelistSourceRange)
+	}
+
+	if frontend.TLSClientCert != nil {
+		tlsClientCertMiddleware, err := middlewares.NewTLSClientCert(frontend.TLSClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("error creating TLS client certificate middleware: %v", err)
+		}
+		n.Use(s.wrapNegroniHandlerWithAccessLog(tlsClientCertMiddleware, fmt.Sprintf("TLS client certificate for %s", frontendName)))
+		names = append(names, "TLS client certificate")
+		log.Debugf("Adding TLS client certificate middleware for frontend %s", frontendName)
+	}
+
+	if frontend.Redirect != nil {
+		rewrite, err := s.buildRedirectHandler(entryPointName, frontend.Redirect)
+		if err != nil {
+			log.Errorf("Error creating Frontend Redirect: %v", err)
+			diags.errorf("frontends", frontendName, "error creating redirect: %v", err)
+		} else {
+			n.Use(s.wrapNegroniHandlerWithAccessLog(rewrite, fmt.Sprintf("frontend redirect for %s", frontendName)))
+			names = append(names, "Redirect")
+			log.Debugf("Frontend %s redirect created", frontendName)
 		}
 	}
 
-	return serverEntryPoints, err
+	if len(frontend.BasicAuth) > 0 {
+		users := types.Users{}
+		for _, user := range frontend.BasicAuth {
+			users = append(users, user)
+		}
+
+		auth := &types.Auth{}
+		auth.Basic = &types.Basic{
+			Users: users,
+		}
+		authMiddleware, err := mauth.NewAuthenticator(auth, s.tracingMiddleware)
+		if err != nil {
+			return nil, fmt.Errorf("error creating auth: %v", err)
+		}
+		n.Use(s.wrapNegroniHandlerWithAccessLog(authMiddleware, fmt.Sprintf("Auth for %s", frontendName)))
+		names = append(names, "Basic auth")
+	}
+
+	if frontend.Auth != nil {
+		authMiddleware, err := mauth.NewAuthenticator(frontend.Auth, s.tracingMiddleware)
+		if err != nil {
+			return nil, fmt.Errorf("error creating auth: %v", err)
+		}
+		n.Use(s.wrapNegroniHandlerWithAccessLog(authMiddleware, fmt.Sprintf("Auth for %s", frontendName)))
+		names = append(names, "Auth")
+	}
+
+	if headerMiddleware := middlewares.NewHeaderFromStruct(frontend.Headers); headerMiddleware != nil {
+		log.Debugf("Adding header middleware for frontend %s", frontendName)
+		n.Use(s.tracingMiddleware.NewNegroniHandlerWrapper("Header", headerMiddleware, false))
+		names = append(names, "Header")
+	}
+
+	secureMiddleware := middlewares.NewSecure(frontend.Headers)
+	if secureMiddleware != nil {
+		log.Debugf("Adding secure middleware for frontend %s", frontendName)
+		n.UseFunc(secureMiddleware.HandlerFuncWithNext)
+		names = append(names, "Secure")
+	}
+
+	if frontend.Compression != nil {
+		compressMiddleware, err := middlewares.NewCompress(frontend.Compression)
+		if err != nil {
+			log.Errorf("Error creating compression middleware for frontend %s: %v", frontendName, err)
+			diags.errorf("frontends", frontendName, "error creating compression middleware: %v", err)
+		} else {
+			log.Debugf("Adding compression middleware for frontend %s", frontendName)
+			n.Use(s.tracingMiddleware.NewNegroniHandlerWrapper("Compress", compressMiddleware, false))
+			names = append(names, "Compress")
+		}
+	}
+
+	if frontend.Cache != nil {
+		cacheMiddleware, ok := built.caches[frontendName]
+		if !ok {
+			var err error
+			cacheMiddleware, err = s.cacheManager.New(frontendName, frontend.Cache,
+				s.metricsRegistry.CacheHitsCounter().With("frontend", frontendName), s.metricsRegistry.CacheMissesCounter().With("frontend", frontendName))
+			if err != nil {
+				log.Errorf("Error creating cache middleware for frontend %s: %v", frontendName, err)
+				diags.errorf("frontends", frontendName, "error creating cache middleware: %v", err)
+			}
+		}
+		if cacheMiddleware != nil {
+			log.Debugf("Adding cache middleware for frontend %s", frontendName)
+			built.caches[frontendName] = cacheMiddleware
+			n.Use(s.tracingMiddleware.NewNegroniHandlerWrapper("Cache", cacheMiddleware, false))
+			names = append(names, "Cache")
+		}
+	}
+
+	n.UseHandler(next)
+	return append(names, nextNames...), nil
 }
 
-func (s *Server) configureLBServers(lb healthcheck.LoadBalancer, config *types.Configuration, frontend *types.Frontend) error {
-	for name, srv := range config.Backends[frontend.Backend].Servers {
+func (s *Server) buildWeightedBackendsHandler(frontendName string, weightedBackends *types.WeightedBackends,
+	backends map[string]http.Handler, backendLoadBalancers map[string]healthcheck.LoadBalancer) http.Handler {
+	var cookieName string
+	if weightedBackends.Stickiness != nil {
+		cookieName = cookie.GetName(weightedBackends.Stickiness.CookieName, frontendName)
+		log.Debugf("Sticky weighted backends with cookie %v", cookieName)
+	}
+
+	handler := middlewares.NewWeightedBackends(cookieName)
+	for _, backendName := range weightedBackends.Names() {
+		weight := weightedBackends.Backends[backendName]
+		log.Debugf("Adding backend %s with weight %d to frontend %s", backendName, weight, frontendName)
+		handler.AddBackend(backendName, backends[backendName], backendLoadBalancers[backendName], weight)
+	}
+	return handler
+}
+
+func (s *Server) configureLBServers(lb healthcheck.LoadBalancer, config *types.Configuration, backendName string) error {
+	for name, srv := range config.Backends[backendName].Servers {
 		u, err := url.Parse(srv.URL)
 		if err != nil {
 			log.Errorf("Error parsing server URL %s: %v", srv.URL, err)
@@ -1216,7 +1728,7 @@ func (s *Server) configureLBServers(lb healthcheck.LoadBalancer, config *types.C
 			log.Errorf("Error adding server %s to load balancer: %v", srv.URL, err)
 			return err
 		}
-		s.metricsRegistry.BackendServerUpGauge().With("backend", frontend.Backend, "url", srv.URL).Set(1)
+		s.metricsRegistry.BackendServerUpGauge().With("backend", backendName, "url", srv.URL).Set(1)
 	}
 	return nil
 }
@@ -1236,7 +1748,11 @@ func configureIPWhitelistMiddleware(whitelistSourceRanges []string) (negroni.Han
 	return nil, nil
 }
 
-func (s *Server) wireFrontendBackend(serverRoute *serverRoute, handler http.Handler) {
+// wireFrontendBackend sets the handler of the route, wrapped by the path modifiers of the route,
+// and returns the names of the modifiers in the order the requests go through them.
+func (s *Server) wireFrontendBackend(serverRoute *serverRoute, handler http.Handler, frontendName string, diags *diagnostics) []string {
+	var names []string
+
 	// path replace - This needs to always be the very last on the handler chain (first in the order in this function)
 	// -- Replacing Path should happen at the very end of the Modifier chain, after all the Matcher+Modifiers ran
 	if len(serverRoute.replacePath) > 0 {
@@ -1244,14 +1760,17 @@ func (s *Server) wireFrontendBackend(serverRoute *serverRoute, handler http.Hand
 			Path:    serverRoute.replacePath,
 			Handler: handler,
 		}
+		names = append([]string{"Replace path"}, names...)
 	}
 
 	if len(serverRoute.replacePathRegex) > 0 {
 		sp := strings.Split(serverRoute.replacePathRegex, " ")
 		if len(sp) == 2 {
 			handler = middlewares.NewReplacePathRegexHandler(sp[0], sp[1], handler)
+			names = append([]string{"Replace path regex"}, names...)
 		} else {
 			log.Warnf("Invalid syntax for ReplacePathRegex: %s. Separate the regular expression and the replacement by a space.", serverRoute.replacePathRegex)
+			diags.errorf("frontends", frontendName, "invalid syntax for ReplacePathRegex %q, the regular expression and the replacement must be separated by a space", serverRoute.replacePathRegex)
 		}
 	}
 
@@ -1262,6 +1781,7 @@ func (s *Server) wireFrontendBackend(serverRoute *serverRoute, handler http.Hand
 			Prefix:  serverRoute.addPrefix,
 			Handler: handler,
 		}
+		names = append([]string{"Add prefix"}, names...)
 	}
 
 	// strip prefix
@@ -1270,14 +1790,17 @@ func (s *Server) wireFrontendBackend(serverRoute *serverRoute, handler http.Hand
 			Prefixes: serverRoute.stripPrefixes,
 			Handler:  handler,
 		}
+		names = append([]string{"Strip prefix"}, names...)
 	}
 
 	// strip prefix with regex
 	if len(serverRoute.stripPrefixesRegex) > 0 {
 		handler = middlewares.NewStripPrefixRegex(handler, serverRoute.stripPrefixesRegex)
+		names = append([]string{"Strip prefix regex"}, names...)
 	}
 
 	serverRoute.route.Handler(handler)
+	return names
 }
 
 func (s *Server) buildRedirectHandler(srcEntryPointName string, opt *types.Redirect) (negroni.Handler, error) {
@@ -1309,34 +1832,149 @@ func (s *Server) buildDefaultHTTPRouter() *mux.Router {
 	return router
 }
 
-func parseHealthCheckOptions(lb healthcheck.LoadBalancer, backend string, hc *types.HealthCheck, hcConfig *configuration.HealthCheckConfig) *healthcheck.Options {
+func parseHealthCheckOptions(lb healthcheck.LoadBalancer, backend string, hc *types.HealthCheck, hcConfig *configuration.HealthCheckConfig, diags *diagnostics) (*healthcheck.Options, error) {
 	if hc == nil || hc.Path == "" || hcConfig == nil {
+		return nil, nil
+	}
+
+	// the empty mode stands for the default HTTP mode
+	var mode string
+	switch hc.Mode {
+	case "", healthcheck.ModeHTTP:
+	case healthcheck.ModeGRPC:
+		mode = healthcheck.ModeGRPC
+	default:
+		log.Errorf("Illegal healthcheck mode for backend '%s': %q, using %q", backend, hc.Mode, healthcheck.ModeHTTP)
+		diags.errorf("backends", backend, "illegal healthcheck mode %q", hc.Mode)
+	}
+
+	status, err := types.StatusCodes(hc.Status).Ranges()
+	if err != nil {
+		return nil, fmt.Errorf("illegal healthcheck status: %v", err)
+	}
+
+	var body *regexp.Regexp
+	if hc.BodyRegex != "" {
+		body, err = regexp.Compile(hc.BodyRegex)
+		if err != nil {
+			return nil, fmt.Errorf("illegal healthcheck body regex: %v", err)
+		}
+	}
+
+	return &healthcheck.Options{
+		Mode:               mode,
+		Scheme:             hc.Scheme,
+		Path:               hc.Path,
+		Port:               hc.Port,
+		Hostname:           hc.Hostname,
+		Headers:            hc.Headers,
+		Interval:           parseHealthCheckInterval(backend, hc, hcConfig, diags),
+		Timeout:            parseHealthCheckTimeout(backend, hc, diags),
+		Status:             status,
+		Body:               body,
+		HealthyThreshold:   hc.HealthyThreshold,
+		UnhealthyThreshold: hc.UnhealthyThreshold,
+		LB:                 lb,
+	}, nil
+}
+
+// buildConsistentHash creates a consistent hash load balancer, using the client IP and the default load factor by default.
+func buildConsistentHash(next http.Handler, sticky *roundrobin.StickySession, consistentHash *types.ConsistentHashing, backend string, diags *diagnostics) (*loadbalancer.Balancer, error) {
+	extractorFunc := "client.ip"
+	loadFactor := loadbalancer.DefaultLoadFactor
+	if consistentHash != nil {
+		if len(consistentHash.ExtractorFunc) > 0 {
+			extractorFunc = consistentHash.ExtractorFunc
+		}
+		if consistentHash.LoadFactor >= 1 {
+			loadFactor = consistentHash.LoadFactor
+		} else if consistentHash.LoadFactor != 0 {
+			log.Errorf("Invalid load factor %v for consistent hash, must be at least 1. Using default %v", consistentHash.LoadFactor, loadFactor)
+			diags.errorf("backends", backend, "invalid load factor %v for consistent hash, must be at least 1", consistentHash.LoadFactor)
+		}
+	}
+
+	extractor, err := loadbalancer.NewExtractor(extractorFunc)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Consistent hash on %s with load factor %v", extractorFunc, loadFactor)
+	return loadbalancer.NewConsistentHash(next, sticky, extractor, loadFactor), nil
+}
+
+// parseHealthCheckTimeout returns zero, which stands for the default timeout, when the timeout is empty or invalid.
+func parseHealthCheckTimeout(backend string, hc *types.HealthCheck, diags *diagnostics) time.Duration {
+	if hc.Timeout == "" {
+		return 0
+	}
+
+	timeout, err := time.ParseDuration(hc.Timeout)
+	switch {
+	case err != nil:
+		log.Errorf("Illegal healthcheck timeout for backend '%s': %s", backend, err)
+		diags.errorf("backends", backend, "illegal healthcheck timeout: %v", err)
+		return 0
+	case timeout <= 0:
+		log.Errorf("Healthcheck timeout smaller than zero for backend '%s'", backend)
+		diags.errorf("backends", backend, "healthcheck timeout smaller than zero")
+		return 0
+	}
+	return timeout
+}
+
+func parseOutlierDetectionOptions(backend string, od *types.OutlierDetection, diags *diagnostics) *healthcheck.OutlierOptions {
+	if od == nil {
 		return nil
 	}
 
+	return &healthcheck.OutlierOptions{
+		ConsecutiveErrors:  od.ConsecutiveErrors,
+		BaseEjectionTime:   parseOutlierDetectionDuration(backend, "base ejection time", od.BaseEjectionTime, diags),
+		MaxEjectionTime:    parseOutlierDetectionDuration(backend, "max ejection time", od.MaxEjectionTime, diags),
+		MaxEjectionPercent: od.MaxEjectionPercent,
+	}
+}
+
+// parseOutlierDetectionDuration returns zero, which stands for the default value, when the duration is empty or invalid.
+func parseOutlierDetectionDuration(backend string, name string, value string, diags *diagnostics) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(value)
+	switch {
+	case err != nil:
+		log.Errorf("Illegal outlier detection %s for backend '%s': %s", name, backend, err)
+		diags.errorf("backends", backend, "illegal outlier detection %s: %v", name, err)
+		return 0
+	case duration <= 0:
+		log.Errorf("Outlier detection %s smaller than zero for backend '%s'", name, backend)
+		diags.errorf("backends", backend, "outlier detection %s smaller than zero", name)
+		return 0
+	}
+	return duration
+}
+
+func parseHealthCheckInterval(backend string, hc *types.HealthCheck, hcConfig *configuration.HealthCheckConfig, diags *diagnostics) time.Duration {
 	interval := time.Duration(hcConfig.Interval)
 	if hc.Interval != "" {
 		intervalOverride, err := time.ParseDuration(hc.Interval)
 		switch {
 		case err != nil:
 			log.Errorf("Illegal healthcheck interval for backend '%s': %s", backend, err)
+			diags.errorf("backends", backend, "illegal healthcheck interval: %v", err)
 		case intervalOverride <= 0:
 			log.Errorf("Healthcheck interval smaller than zero for backend '%s', backend", backend)
+			diags.errorf("backends", backend, "healthcheck interval smaller than zero")
 		default:
 			interval = intervalOverride
 		}
 	}
-
-	return &healthcheck.Options{
-		Path:     hc.Path,
-		Port:     hc.Port,
-		Interval: interval,
-		LB:       lb,
-	}
+	return interval
 }
 
-func getRoute(serverRoute *serverRoute, route *types.Route) error {
-	rules := Rules{route: serverRoute}
+func getRoute(serverRoute *serverRoute, route *types.Route, forwardedHeaders *configuration.ForwardedHeaders) error {
+	rules := Rules{route: serverRoute, forwardedHeaders: forwardedHeaders}
 	newRoute, err := rules.Parse(route.Rule)
 	if err != nil {
 		return err
@@ -1346,6 +1984,15 @@ func getRoute(serverRoute *serverRoute, route *types.Route) error {
 	return nil
 }
 
+func sortedProviderNames(configurations types.Configurations) []string {
+	var keys []string
+	for key := range configurations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func sortedFrontendNamesForConfig(configuration *types.Configuration) []string {
 	var keys []string
 	for key := range configuration.Frontends {
@@ -1433,11 +2080,22 @@ func stopMetricsClients() {
 	metrics.StopInfluxDB()
 }
 
-func (s *Server) buildRateLimiter(handler http.Handler, rlConfig *types.RateLimit) (http.Handler, error) {
+func (s *Server) buildRateLimiter(handler http.Handler, rlConfig *types.RateLimit, frontendName string, diags *diagnostics) (http.Handler, error) {
 	extractFunc, err := utils.NewExtractor(rlConfig.ExtractorFunc)
 	if err != nil {
 		return nil, err
 	}
+
+	if rlConfig.Distributed {
+		if s.rateLimitCounter != nil {
+			log.Debugf("Creating load-balancer distributed rate limiter")
+			rateLimiter, err := mratelimit.New(handler, extractFunc, rlConfig.RateSet, s.rateLimitCounter, url.PathEscape(frontendName))
+			return s.tracingMiddleware.NewHTTPHandlerWrapper("Rate limit", rateLimiter, false), err
+		}
+		log.Warnf("Distributed rate limiting of frontend %s requires the cluster mode, using a local rate limiter", frontendName)
+		diags.warnf("frontends", frontendName, "distributed rate limiting requires the cluster mode, using a local rate limiter")
+	}
+
 	log.Debugf("Creating load-balancer rate limiter")
 	rateSet := ratelimit.NewRateSet()
 	for _, rate := range rlConfig.RateSet {
@@ -1450,7 +2108,7 @@ func (s *Server) buildRateLimiter(handler http.Handler, rlConfig *types.RateLimi
 
 }
 
-func (s *Server) buildRetryMiddleware(handler http.Handler, globalConfig configuration.GlobalConfiguration, countServers int, backendName string) http.Handler {
+func (s *Server) buildRetryMiddleware(handler http.Handler, globalConfig configuration.GlobalConfiguration, retryConfig *types.Retry, countServers int, backendName string) (http.Handler, error) {
 	retryListeners := middlewares.RetryListeners{}
 	if s.metricsRegistry.IsEnabled() {
 		retryListeners = append(retryListeners, middlewares.NewMetricsRetryListener(s.metricsRegistry, backendName))
@@ -1460,14 +2118,24 @@ func (s *Server) buildRetryMiddleware(handler http.Handler, globalConfig configu
 	}
 
 	retryAttempts := countServers
-	if globalConfig.Retry.Attempts > 0 {
+	if globalConfig.Retry != nil && globalConfig.Retry.Attempts > 0 {
 		retryAttempts = globalConfig.Retry.Attempts
 	}
 
-	log.Debugf("Creating retries max attempts %d", retryAttempts)
+	if retryConfig == nil {
+		log.Debugf("Creating retries max attempts %d", retryAttempts)
+		return s.tracingMiddleware.NewHTTPHandlerWrapper("Retry", middlewares.NewRetry(retryAttempts, handler, retryListeners), false), nil
+	}
+
+	retry, err := middlewares.NewRetryWithPolicy(retryConfig, retryAttempts, handler, retryListeners)
+	if err != nil {
+		return nil, err
+	}
 
-	return s.tracingMiddleware.NewHTTPHandlerWrapper("Retry", middlewares.NewRetry(retryAttempts, handler, retryListeners), false)
+	log.Debugf("Creating retries with policy %+v", *retryConfig)
+	return s.tracingMiddleware.NewHTTPHandlerWrapper("Retry", retry, false), nil
 }
+
 func (s *Server) wrapNegroniHandlerWithAccessLog(handler negroni.Handler, frontendName string) negroni.Handler {
 	if s.accessLoggerMiddleware != nil {
 		saveBackend := accesslog.NewSaveNegroniBackend(handler, "Træfik")
@@ -1486,6 +2154,41 @@ func (s *Server) wrapHTTPHandlerWithAccessLog(handler http.Handler, frontendName
 	return handler
 }
 
+func (s *Server) buildMirroringMiddleware(handler http.Handler, frontend *types.Frontend, config *types.Configuration, roundTripper http.RoundTripper, rewriter forward.ReqRewriter) (http.Handler, error) {
+	mirroring := frontend.Mirroring
+	if config.Backends[mirroring.Backend] == nil {
+		return nil, fmt.Errorf("undefined mirror backend '%s'", mirroring.Backend)
+	}
+	if mirroring.Percent < 0 || mirroring.Percent > 100 {
+		return nil, fmt.Errorf("invalid mirroring percentage %d, must be between 0 and 100", mirroring.Percent)
+	}
+
+	fwd, err := forward.New(
+		forward.Stream(true),
+		forward.PassHostHeader(frontend.PassHostHeader),
+		forward.RoundTripper(roundTripper),
+		forward.WebsocketTLSClientConfig(getWebsocketTLSClientConfig(roundTripper)),
+		forward.Rewriter(rewriter),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rr, err := roundrobin.New(fwd)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.configureLBServers(rr, config, mirroring.Backend); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Mirroring %d%% of the requests to backend %s", mirroring.Percent, mirroring.Backend)
+
+	return middlewares.NewMirroring(handler, middlewares.NewEmptyBackendHandler(rr, rr), mirroring.Backend, mirroring.Percent, mirroring.MaxBodySize,
+		s.metricsRegistry.BackendMirrorSuccessCounter(), s.metricsRegistry.BackendMirrorFailureCounter()), nil
+}
+
 func (s *Server) buildBufferingMiddleware(handler http.Handler, config *types.Buffering) (http.Handler, error) {
 	log.Debugf("Setting up buffering: request limits: %d (mem), %d (max), response limits: %d (mem), %d (max) with retry: '%s'",
 		config.MemRequestBodyBytes, config.MaxRequestBodyBytes, config.MemResponseBodyBytes,
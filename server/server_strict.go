@@ -0,0 +1,58 @@
+package server
+
+import (
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+)
+
+// rejectedConfigurationError is returned by loadConfig in strict reload mode
+// when a frontend of the configurations fails to build.
+type rejectedConfigurationError struct {
+	diagnostics []Diagnostic
+}
+
+func (e *rejectedConfigurationError) Error() string {
+	return strings.Join(rejectionReasons(e.diagnostics), ", ")
+}
+
+// rejectionReasons returns the errors of the diagnostics, as the reasons of the rejection of a configuration.
+func rejectionReasons(diags []Diagnostic) []string {
+	var reasons []string
+	for _, diag := range diags {
+		if diag.Level != DiagnosticError {
+			continue
+		}
+		if len(diag.Name) > 0 {
+			reasons = append(reasons, diag.Section+" "+diag.Name+": "+diag.Message)
+		} else {
+			reasons = append(reasons, diag.Section+": "+diag.Message)
+		}
+	}
+	return reasons
+}
+
+// rejectConfiguration records the configuration of a provider rejected in strict reload mode,
+// the current configuration staying live.
+func (s *Server) rejectConfiguration(configMsg types.ConfigMessage, reasons []string) {
+	now := time.Now()
+
+	s.metricsRegistry.ConfigReloadsFailureCounter().Add(1)
+	s.metricsRegistry.LastConfigReloadFailureGauge().Set(float64(now.Unix()))
+	s.metricsRegistry.ConfigReloadsRejectedCounter().With("provider", configMsg.ProviderName).Add(1)
+	s.metricsRegistry.LastConfigReloadRejectedGauge().With("provider", configMsg.ProviderName).Set(float64(now.Unix()))
+
+	s.lastRejectedConfiguration.Set(&types.RejectedConfiguration{
+		ProviderName:  configMsg.ProviderName,
+		Date:          now,
+		Reasons:       reasons,
+		Configuration: configMsg.Configuration,
+	})
+
+	for _, reason := range reasons {
+		log.Errorf("Invalid configuration from provider %s: %s", configMsg.ProviderName, reason)
+	}
+	log.Errorf("Configuration from provider %s rejected in strict reload mode, keeping the current configuration", configMsg.ProviderName)
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerLoadConfigStrictReload(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		strictReload bool
+		expectErr    bool
+	}{
+		{
+			desc:         "frontend skipped",
+			strictReload: false,
+		},
+		{
+			desc:         "configuration rejected",
+			strictReload: true,
+			expectErr:    true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			globalConfig := configuration.GlobalConfiguration{
+				EntryPoints: configuration.EntryPoints{
+					"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+				},
+				StrictReload: test.strictReload,
+			}
+
+			dynamicConfigs := types.Configurations{
+				"config": buildDynamicConfig(
+					withFrontend("frontend", buildFrontend(withRoute("route", "Path:/"))),
+					withFrontend("invalid", buildFrontend(withRoute("route", "Foo:/"))),
+					withBackend("backend", buildBackend(withServer("server", "http://127.0.0.1:8080"))),
+				),
+			}
+
+			srv := NewServer(globalConfig, nil)
+			entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+			if !test.expectErr {
+				require.NoError(t, err)
+				assert.NotNil(t, entryPoints["http"])
+				return
+			}
+
+			require.IsType(t, &rejectedConfigurationError{}, err)
+			assert.Len(t, err.(*rejectedConfigurationError).diagnostics, 1)
+			assert.Nil(t, entryPoints)
+		})
+	}
+}
+
+func TestServerLoadConfigStrictReloadMiddlewareErrors(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		frontend       func(*types.Frontend)
+		expectedReason string
+	}{
+		{
+			desc: "compression",
+			frontend: func(f *types.Frontend) {
+				f.Compression = &types.Compression{Encodings: []string{"foo"}}
+			},
+			expectedReason: `frontends frontend: error creating compression middleware: unsupported encoding "foo", must be br or gzip`,
+		},
+		{
+			desc: "cache",
+			frontend: func(f *types.Frontend) {
+				f.Cache = &types.Cache{Storage: "foo"}
+			},
+			expectedReason: `frontends frontend: error creating cache middleware: unsupported storage "foo", must be memory or disk`,
+		},
+		{
+			desc: "error page",
+			frontend: func(f *types.Frontend) {
+				f.Errors = map[string]*types.ErrorPage{"page": {Backend: "undefined", Status: []string{"500"}}}
+			},
+			expectedReason: "frontends frontend: error page configured, but either backend undefined is not set or its URL is missing",
+		},
+		{
+			desc: "redirect",
+			frontend: func(f *types.Frontend) {
+				f.Redirect = &types.Redirect{Regex: "(", Replacement: "/"}
+			},
+			expectedReason: "frontends frontend: error creating redirect: error parsing regexp: missing closing ): `(`",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			globalConfig := configuration.GlobalConfiguration{
+				EntryPoints: configuration.EntryPoints{
+					"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+				},
+				StrictReload: true,
+			}
+
+			dynamicConfigs := types.Configurations{
+				"config": buildDynamicConfig(
+					withFrontend("frontend", buildFrontend(withRoute("route", "Path:/"), test.frontend)),
+					withBackend("backend", buildBackend(withServer("server", "http://127.0.0.1:8080"))),
+				),
+			}
+
+			srv := NewServer(globalConfig, nil)
+			entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+			require.IsType(t, &rejectedConfigurationError{}, err)
+			assert.Equal(t, []string{test.expectedReason}, rejectionReasons(err.(*rejectedConfigurationError).diagnostics))
+			assert.Nil(t, entryPoints)
+		})
+	}
+}
+
+func TestServerLoadConfigurationStrictReload(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		strictReload     bool
+		expectedStatus   int
+		expectedRejected bool
+	}{
+		{
+			desc:           "invalid configuration applied",
+			strictReload:   false,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			desc:             "invalid configuration rejected",
+			strictReload:     true,
+			expectedStatus:   http.StatusOK,
+			expectedRejected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			backendServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			}))
+			defer backendServer.Close()
+
+			globalConfig := configuration.GlobalConfiguration{
+				EntryPoints: configuration.EntryPoints{
+					"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+				},
+				StrictReload: test.strictReload,
+			}
+
+			srv := NewServer(globalConfig, nil)
+			srv.serverEntryPoints = srv.buildEntryPoints(globalConfig)
+			srv.serverEntryPoints["http"].httpServer = &http.Server{}
+
+			validConfig := buildDynamicConfig(
+				withFrontend("frontend", buildFrontend(withRoute("route", "Path:/"))),
+				withBackend("backend", buildBackend(withServer("server", backendServer.URL))),
+			)
+			srv.loadConfiguration(types.ConfigMessage{ProviderName: "file", Configuration: validConfig})
+
+			invalidConfig := buildDynamicConfig(
+				withFrontend("frontend", buildFrontend(withRoute("route", "Path:/"), func(f *types.Frontend) {
+					f.Backend = "undefined"
+					f.Auth = &types.Auth{JWT: &types.JWT{Secret: "jwtsecret"}}
+				})),
+				withBackend("backend", buildBackend(withServer("server", backendServer.URL))),
+			)
+			srv.loadConfiguration(types.ConfigMessage{ProviderName: "file", Configuration: invalidConfig})
+
+			recorder := httptest.NewRecorder()
+			srv.serverEntryPoints["http"].httpRouter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar/", nil))
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+
+			currentConfigurations := srv.currentConfigurations.Get().(types.Configurations)
+			rejected, _ := srv.lastRejectedConfiguration.Get().(*types.RejectedConfiguration)
+			if !test.expectedRejected {
+				assert.Equal(t, invalidConfig, currentConfigurations["file"])
+				assert.Nil(t, rejected)
+				return
+			}
+
+			assert.Equal(t, validConfig, currentConfigurations["file"])
+			require.NotNil(t, rejected)
+			assert.Equal(t, "file", rejected.ProviderName)
+			assert.Equal(t, invalidConfig, rejected.Configuration)
+			assert.Equal(t, []string{"frontends frontend: error creating backend undefined: undefined backend 'undefined'"}, rejected.Reasons)
+
+			content, err := json.Marshal(rejected)
+			require.NoError(t, err)
+			assert.NotContains(t, string(content), "jwtsecret", "the secrets should be redacted from the API")
+		})
+	}
+}
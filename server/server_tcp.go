@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/healthcheck"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+	"github.com/containous/traefik/tcp"
+	"github.com/containous/traefik/types"
+)
+
+func (s *Server) setupTCPServerEntryPoint(newServerEntryPointName string, newServerEntryPoint *serverEntryPoint) *serverEntryPoint {
+	entryPoint := s.globalConfiguration.EntryPoints[newServerEntryPointName]
+	log.Infof("Preparing TCP server %s %+v", newServerEntryPointName, entryPoint)
+
+	listener, err := buildListener(entryPoint)
+	if err != nil {
+		log.Fatal("Error preparing server: ", err)
+	}
+	newServerEntryPoint.listener = listener
+	newServerEntryPoint.tcpConns = newTCPConnections()
+
+	return newServerEntryPoint
+}
+
+func (s *Server) startTCPServer(serverEntryPoint *serverEntryPoint) {
+	log.Infof("Starting TCP server on %s", serverEntryPoint.listener.Addr())
+	for {
+		conn, err := serverEntryPoint.listener.Accept()
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				log.Debugf("Temporary error while accepting TCP connection: %s", err)
+				time.Sleep(5 * time.Millisecond)
+				continue
+			}
+			log.Debugf("TCP server on %s stopped: %s", serverEntryPoint.listener.Addr(), err)
+			return
+		}
+
+		if !serverEntryPoint.tcpConns.add(conn) {
+			conn.Close()
+			return
+		}
+		safe.Go(func() {
+			defer serverEntryPoint.tcpConns.remove(conn)
+			serverEntryPoint.tcpRouter.ServeTCP(conn)
+		})
+	}
+}
+
+// tcpConnections tracks the active connections of a TCP entry point, so that they are closed when stopping.
+type tcpConnections struct {
+	lock   sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func newTCPConnections() *tcpConnections {
+	return &tcpConnections{conns: make(map[net.Conn]struct{})}
+}
+
+// add tracks the connection, and returns false if the entry point is stopping.
+func (c *tcpConnections) add(conn net.Conn) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.closed {
+		return false
+	}
+	c.conns[conn] = struct{}{}
+	c.wg.Add(1)
+	return true
+}
+
+func (c *tcpConnections) remove(conn net.Conn) {
+	c.lock.Lock()
+	delete(c.conns, conn)
+	c.lock.Unlock()
+	c.wg.Done()
+}
+
+// shutdown waits for the active connections to end until the context is done, and then closes them.
+func (c *tcpConnections) shutdown(ctx context.Context) error {
+	c.lock.Lock()
+	c.closed = true
+	c.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for conn := range c.conns {
+		conn.Close()
+	}
+	return ctx.Err()
+}
+
+// loadTCPFrontendConfig wires a frontend on a TCP entry point: its HostSNI rules are added
+// to the router and forwarded to a weighted round robin of the backend servers.
+func (s *Server) loadTCPFrontendConfig(router *tcp.Router, entryPointName string, frontendName string, config *types.Configuration,
+	tcpBackends map[string]tcp.Handler, backendsHealthCheck map[string]*healthcheck.BackendHealthCheck, globalConfiguration configuration.GlobalConfiguration, diags *diagnostics) error {
+	frontend := config.Frontends[frontendName]
+
+	var hosts []string
+	for routeName, route := range frontend.Routes {
+		rules := Rules{}
+		routeHosts, err := rules.ParseHostSNI(route.Rule)
+		if err != nil {
+			return err
+		}
+		log.Debugf("Creating TCP route %s %s", routeName, route.Rule)
+		hosts = append(hosts, routeHosts...)
+	}
+	if len(hosts) == 0 {
+		return errors.New("no HostSNI rule defined")
+	}
+
+	backendKey := entryPointName + frontend.Backend
+	if tcpBackends[backendKey] == nil {
+		log.Debugf("Creating TCP backend %s", frontend.Backend)
+
+		backend := config.Backends[frontend.Backend]
+		if backend == nil {
+			return fmt.Errorf("undefined backend '%s'", frontend.Backend)
+		}
+
+		lbMethod, err := types.NewLoadBalancerMethod(backend.LoadBalancer)
+		if err != nil || lbMethod != types.Wrr {
+			log.Warnf("Load balancer method '%+v' is not supported for TCP backend %s, using wrr", backend.LoadBalancer, frontend.Backend)
+			diags.warnf("backends", frontend.Backend, "load balancer method '%+v' is not supported for TCP backends, using wrr", backend.LoadBalancer)
+		}
+
+		dialTimeout := configuration.DefaultDialTimeout
+		if globalConfiguration.ForwardingTimeouts != nil {
+			dialTimeout = time.Duration(globalConfiguration.ForwardingTimeouts.DialTimeout)
+		}
+
+		lb, err := tcp.NewWRRLoadBalancer(dialTimeout)
+		if err != nil {
+			return err
+		}
+		if err := s.configureLBServers(lb, config, frontend.Backend); err != nil {
+			return err
+		}
+
+		if backend.HealthCheck != nil && globalConfiguration.HealthCheck != nil {
+			hcOpts := &healthcheck.Options{
+				Mode:     healthcheck.ModeTCP,
+				Port:     backend.HealthCheck.Port,
+				Interval: parseHealthCheckInterval(frontend.Backend, backend.HealthCheck, globalConfiguration.HealthCheck, diags),
+				Timeout:  parseHealthCheckTimeout(frontend.Backend, backend.HealthCheck, diags),
+				LB:       lb,
+			}
+			log.Debugf("Setting up TCP backend health check %s", *hcOpts)
+			backendsHealthCheck[backendKey] = healthcheck.NewBackendHealthCheck(*hcOpts, frontend.Backend)
+		}
+
+		tcpBackends[backendKey] = lb
+	} else {
+		log.Debugf("Reusing TCP backend %s", frontend.Backend)
+	}
+
+	for _, host := range hosts {
+		if err := router.AddRoute(host, tcpBackends[backendKey]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
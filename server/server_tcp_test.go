@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerLoadConfigTCPFrontend(t *testing.T) {
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer backendListener.Close()
+
+	go func() {
+		for {
+			conn, err := backendListener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("backend"))
+			conn.Close()
+		}
+	}()
+
+	testCases := []struct {
+		desc         string
+		rule         string
+		wantResponse string
+	}{
+		{
+			desc:         "catch-all HostSNI rule",
+			rule:         "HostSNI:*",
+			wantResponse: "backend",
+		},
+		{
+			desc:         "HostSNI rule not matching",
+			rule:         "HostSNI:foo.bar",
+			wantResponse: "",
+		},
+		{
+			desc:         "HTTP rule on TCP entry point",
+			rule:         "Host:foo.bar",
+			wantResponse: "",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			globalConfig := configuration.GlobalConfiguration{
+				EntryPoints: configuration.EntryPoints{
+					"tcp": &configuration.EntryPoint{Address: ":5432", Protocol: configuration.ProtocolTCP},
+				},
+			}
+
+			frontend := buildFrontend(withRoute("route", test.rule))
+			frontend.EntryPoints = []string{"tcp"}
+			dynamicConfigs := types.Configurations{
+				"config": buildDynamicConfig(
+					withFrontend("frontend", frontend),
+					withBackend("backend", buildBackend(withServer("server", "tcp://"+backendListener.Addr().String()))),
+				),
+			}
+
+			srv := NewServer(globalConfig, nil)
+			entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+			require.NoError(t, err)
+			require.NotNil(t, entryPoints["tcp"].tcpRouter)
+			assert.Nil(t, entryPoints["tcp"].httpRouter)
+
+			client, server := net.Pipe()
+			go entryPoints["tcp"].tcpRouter.ServeTCP(server)
+
+			_, err = client.Write([]byte("PING"))
+			require.NoError(t, err)
+
+			response, err := ioutil.ReadAll(client)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantResponse, string(response))
+		})
+	}
+}
+
+func TestTCPConnectionsShutdown(t *testing.T) {
+	conns := newTCPConnections()
+
+	client, server := net.Pipe()
+	defer client.Close()
+	require.True(t, conns.add(server))
+
+	ended, endedServer := net.Pipe()
+	defer ended.Close()
+	require.True(t, conns.add(endedServer))
+	conns.remove(endedServer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, conns.shutdown(ctx))
+
+	// the active connection is closed once the grace period is over
+	_, err := client.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+
+	// no connection is accepted anymore
+	_, other := net.Pipe()
+	assert.False(t, conns.add(other))
+}
+
+func TestTCPConnectionsShutdownWithoutConnections(t *testing.T) {
+	conns := newTCPConnections()
+
+	_, server := net.Pipe()
+	require.True(t, conns.add(server))
+	conns.remove(server)
+
+	assert.NoError(t, conns.shutdown(context.Background()))
+}
@@ -2,10 +2,13 @@ package server
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,6 +18,8 @@ import (
 	"github.com/containous/traefik/healthcheck"
 	"github.com/containous/traefik/metrics"
 	"github.com/containous/traefik/middlewares"
+	"github.com/containous/traefik/middlewares/accesslog"
+	mratelimit "github.com/containous/traefik/middlewares/ratelimit"
 	"github.com/containous/traefik/testhelpers"
 	"github.com/containous/traefik/tls"
 	"github.com/containous/traefik/types"
@@ -416,7 +421,7 @@ func TestServerMultipleFrontendRules(t *testing.T) {
 				if r.URL.String() != test.expectedURL {
 					t.Fatalf("got URL %s, expected %s", r.URL.String(), test.expectedURL)
 				}
-			}))
+			}), "frontend", &diagnostics{})
 			serverRoute.route.GetHandler().ServeHTTP(nil, request)
 		})
 	}
@@ -501,6 +506,7 @@ func TestServerParseHealthCheckOptions(t *testing.T) {
 		desc     string
 		hc       *types.HealthCheck
 		wantOpts *healthcheck.Options
+		wantErr  bool
 	}{
 		{
 			desc:     "nil health check",
@@ -550,6 +556,84 @@ func TestServerParseHealthCheckOptions(t *testing.T) {
 				LB:       lb,
 			},
 		},
+		{
+			desc: "request and response options",
+			hc: &types.HealthCheck{
+				Scheme:             "https",
+				Path:               "/path",
+				Timeout:            "3s",
+				Hostname:           "foo.bar",
+				Headers:            map[string]string{"X-Foo": "bar"},
+				Status:             []string{"200-299", "302"},
+				HealthyThreshold:   2,
+				UnhealthyThreshold: 3,
+			},
+			wantOpts: &healthcheck.Options{
+				Scheme:             "https",
+				Path:               "/path",
+				Hostname:           "foo.bar",
+				Headers:            map[string]string{"X-Foo": "bar"},
+				Interval:           globalInterval,
+				Timeout:            3 * time.Second,
+				Status:             []types.StatusRange{{Low: 200, High: 299}, {Low: 302, High: 302}},
+				HealthyThreshold:   2,
+				UnhealthyThreshold: 3,
+				LB:                 lb,
+			},
+		},
+		{
+			desc: "grpc mode",
+			hc: &types.HealthCheck{
+				Mode: "grpc",
+				Path: "/",
+			},
+			wantOpts: &healthcheck.Options{
+				Mode:     healthcheck.ModeGRPC,
+				Path:     "/",
+				Interval: globalInterval,
+				LB:       lb,
+			},
+		},
+		{
+			desc: "invalid mode",
+			hc: &types.HealthCheck{
+				Mode: "udp",
+				Path: "/path",
+			},
+			wantOpts: &healthcheck.Options{
+				Path:     "/path",
+				Interval: globalInterval,
+				LB:       lb,
+			},
+		},
+		{
+			desc: "invalid timeout",
+			hc: &types.HealthCheck{
+				Path:    "/path",
+				Timeout: "-3s",
+			},
+			wantOpts: &healthcheck.Options{
+				Path:     "/path",
+				Interval: globalInterval,
+				LB:       lb,
+			},
+		},
+		{
+			desc: "invalid status",
+			hc: &types.HealthCheck{
+				Path:   "/path",
+				Status: []string{"2xx"},
+			},
+			wantErr: true,
+		},
+		{
+			desc: "invalid body regex",
+			hc: &types.HealthCheck{
+				Path:      "/path",
+				BodyRegex: "(",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -557,7 +641,12 @@ func TestServerParseHealthCheckOptions(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			gotOpts := parseHealthCheckOptions(lb, "backend", test.hc, &configuration.HealthCheckConfig{Interval: flaeg.Duration(globalInterval)})
+			gotOpts, err := parseHealthCheckOptions(lb, "backend", test.hc, &configuration.HealthCheckConfig{Interval: flaeg.Duration(globalInterval)}, &diagnostics{})
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
 			if !reflect.DeepEqual(gotOpts, test.wantOpts) {
 				t.Errorf("got health check options %+v, want %+v", gotOpts, test.wantOpts)
 			}
@@ -565,6 +654,149 @@ func TestServerParseHealthCheckOptions(t *testing.T) {
 	}
 }
 
+func TestServerParseOutlierDetectionOptions(t *testing.T) {
+	tests := []struct {
+		desc     string
+		od       *types.OutlierDetection
+		wantOpts *healthcheck.OutlierOptions
+	}{
+		{
+			desc:     "nil outlier detection",
+			od:       nil,
+			wantOpts: nil,
+		},
+		{
+			desc:     "default options",
+			od:       &types.OutlierDetection{},
+			wantOpts: &healthcheck.OutlierOptions{},
+		},
+		{
+			desc: "unparseable durations",
+			od: &types.OutlierDetection{
+				ConsecutiveErrors: 3,
+				BaseEjectionTime:  "unparseable",
+				MaxEjectionTime:   "-42s",
+			},
+			wantOpts: &healthcheck.OutlierOptions{
+				ConsecutiveErrors: 3,
+			},
+		},
+		{
+			desc: "parseable options",
+			od: &types.OutlierDetection{
+				ConsecutiveErrors:  3,
+				BaseEjectionTime:   "10s",
+				MaxEjectionTime:    "5m",
+				MaxEjectionPercent: 20,
+			},
+			wantOpts: &healthcheck.OutlierOptions{
+				ConsecutiveErrors:  3,
+				BaseEjectionTime:   10 * time.Second,
+				MaxEjectionTime:    5 * time.Minute,
+				MaxEjectionPercent: 20,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			gotOpts := parseOutlierDetectionOptions("backend", test.od, &diagnostics{})
+			assert.Equal(t, test.wantOpts, gotOpts)
+		})
+	}
+}
+
+func TestServerBuildConsistentHash(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		consistentHash *types.ConsistentHashing
+		expectedErr    bool
+	}{
+		{
+			desc: "default extractor",
+		},
+		{
+			desc:           "cookie extractor",
+			consistentHash: &types.ConsistentHashing{ExtractorFunc: "request.cookie.session", LoadFactor: 2},
+		},
+		{
+			desc:           "invalid load factor",
+			consistentHash: &types.ConsistentHashing{ExtractorFunc: "request.header.X-Tenant", LoadFactor: 0.5},
+		},
+		{
+			desc:           "invalid extractor",
+			consistentHash: &types.ConsistentHashing{ExtractorFunc: "foo"},
+			expectedErr:    true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			lb, err := buildConsistentHash(http.NotFoundHandler(), nil, test.consistentHash, "backend", &diagnostics{})
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, lb)
+		})
+	}
+}
+
+func TestServerBuildDistributedRateLimiter(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		distributed         bool
+		counter             mratelimit.Counter
+		expectedDistributed bool
+	}{
+		{
+			desc:                "distributed rate limit",
+			distributed:         true,
+			counter:             mratelimit.NewMemoryCounter(),
+			expectedDistributed: true,
+		},
+		{
+			desc:        "distributed rate limit without cluster",
+			distributed: true,
+		},
+		{
+			desc:    "local rate limit",
+			counter: mratelimit.NewMemoryCounter(),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			srv := NewServer(configuration.GlobalConfiguration{}, nil)
+			srv.rateLimitCounter = test.counter
+
+			rlConfig := &types.RateLimit{
+				ExtractorFunc: "client.ip",
+				Distributed:   test.distributed,
+				RateSet: map[string]*types.Rate{
+					"rate1": {Period: flaeg.Duration(time.Second), Average: 1, Burst: 1},
+				},
+			}
+
+			handler, err := srv.buildRateLimiter(http.NotFoundHandler(), rlConfig, "frontend", &diagnostics{})
+			require.NoError(t, err)
+
+			_, distributed := handler.(*mratelimit.RateLimiter)
+			assert.Equal(t, test.expectedDistributed, distributed)
+		})
+	}
+}
+
 func TestNewServerWithWhitelistSourceRange(t *testing.T) {
 	cases := []struct {
 		desc                 string
@@ -885,6 +1117,46 @@ func TestServerResponseEmptyBackend(t *testing.T) {
 			},
 			wantStatusCode: http.StatusServiceUnavailable,
 		},
+		{
+			desc: "Empty Backend LB-LeastConn",
+			dynamicConfig: func(testServerURL string) *types.Configuration {
+				return buildDynamicConfig(
+					withFrontend("frontend", buildFrontend(withRoute(requestPath, routeRule))),
+					withBackend("backend", buildBackend(withLoadBalancer("LeastConn", false))),
+				)
+			},
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			desc: "Ok LB-PowerOfTwoChoices Sticky",
+			dynamicConfig: func(testServerURL string) *types.Configuration {
+				return buildDynamicConfig(
+					withFrontend("frontend", buildFrontend(withRoute(requestPath, routeRule))),
+					withBackend("backend", buildBackend(withServer("testServer", testServerURL), withLoadBalancer("PowerOfTwoChoices", true))),
+				)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			desc: "Ok LB-ConsistentHash",
+			dynamicConfig: func(testServerURL string) *types.Configuration {
+				return buildDynamicConfig(
+					withFrontend("frontend", buildFrontend(withRoute(requestPath, routeRule))),
+					withBackend("backend", buildBackend(withServer("testServer", testServerURL), withLoadBalancer("ConsistentHash", false))),
+				)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			desc: "Ok LB-EWMA",
+			dynamicConfig: func(testServerURL string) *types.Configuration {
+				return buildDynamicConfig(
+					withFrontend("frontend", buildFrontend(withRoute(requestPath, routeRule))),
+					withBackend("backend", buildBackend(withServer("testServer", testServerURL), withLoadBalancer("EWMA", false))),
+				)
+			},
+			wantStatusCode: http.StatusOK,
+		},
 	}
 
 	for _, test := range testCases {
@@ -923,6 +1195,377 @@ func TestServerResponseEmptyBackend(t *testing.T) {
 	}
 }
 
+func TestServerLoadConfigMirroring(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		mirrorBackend  string
+		wantStatusCode int
+		wantMirrored   bool
+	}{
+		{
+			desc:           "mirror backend defined",
+			mirrorBackend:  "mirror",
+			wantStatusCode: http.StatusOK,
+			wantMirrored:   true,
+		},
+		{
+			desc:           "undefined mirror backend",
+			mirrorBackend:  "unknown",
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			}))
+			defer testServer.Close()
+
+			mirrored := make(chan string, 1)
+			mirrorServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				mirrored <- req.URL.Path
+				rw.WriteHeader(http.StatusTeapot)
+			}))
+			defer mirrorServer.Close()
+
+			globalConfig := configuration.GlobalConfiguration{
+				EntryPoints: configuration.EntryPoints{
+					"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+				},
+			}
+			dynamicConfigs := types.Configurations{
+				"config": buildDynamicConfig(
+					withFrontend("frontend", buildFrontend(withRoute("/path", "Path:/path"), withMirroring(test.mirrorBackend, 100))),
+					withBackend("backend", buildBackend(withServer("testServer", testServer.URL))),
+					withBackend("mirror", buildBackend(withServer("mirrorServer", mirrorServer.URL))),
+				),
+			}
+
+			srv := NewServer(globalConfig, nil)
+			entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+			require.NoError(t, err)
+
+			responseRecorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, testServer.URL+"/path", nil)
+
+			entryPoints["http"].httpRouter.ServeHTTP(responseRecorder, request)
+			assert.Equal(t, test.wantStatusCode, responseRecorder.Code)
+
+			if test.wantMirrored {
+				select {
+				case path := <-mirrored:
+					assert.Equal(t, "/path", path)
+				case <-time.After(time.Second):
+					t.Error("request was not mirrored")
+				}
+			}
+		})
+	}
+}
+
+func TestServerLoadConfigWeightedBackends(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		weights    map[string]int
+		wantCounts map[int]int
+	}{
+		{
+			desc:       "requests spread on the weighted backends",
+			weights:    map[string]int{"v1": 3, "v2": 1},
+			wantCounts: map[int]int{http.StatusOK: 3, http.StatusAccepted: 1},
+		},
+		{
+			desc:       "undefined weighted backend",
+			weights:    map[string]int{"v1": 1, "unknown": 1},
+			wantCounts: map[int]int{http.StatusNotFound: 4},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			v1Server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			}))
+			defer v1Server.Close()
+
+			v2Server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusAccepted)
+			}))
+			defer v2Server.Close()
+
+			globalConfig := configuration.GlobalConfiguration{
+				EntryPoints: configuration.EntryPoints{
+					"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+				},
+			}
+
+			frontend := buildFrontend(withRoute("/path", "Path:/path"))
+			frontend.Backend = ""
+			frontend.WeightedBackends = &types.WeightedBackends{Backends: test.weights}
+
+			dynamicConfigs := types.Configurations{
+				"config": buildDynamicConfig(
+					withFrontend("frontend", frontend),
+					withBackend("v1", buildBackend(withServer("v1Server", v1Server.URL))),
+					withBackend("v2", buildBackend(withServer("v2Server", v2Server.URL))),
+				),
+			}
+
+			srv := NewServer(globalConfig, nil)
+			entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+			require.NoError(t, err)
+
+			counts := map[int]int{}
+			for i := 0; i < 4; i++ {
+				responseRecorder := httptest.NewRecorder()
+				request := httptest.NewRequest(http.MethodGet, v1Server.URL+"/path", nil)
+
+				entryPoints["http"].httpRouter.ServeHTTP(responseRecorder, request)
+				counts[responseRecorder.Code]++
+			}
+
+			assert.Equal(t, test.wantCounts, counts)
+		})
+	}
+}
+
+func TestServerLoadConfigWeightedBackendsRateLimit(t *testing.T) {
+	backendServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer backendServer.Close()
+
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+		},
+	}
+
+	frontend := buildFrontend(withRoute("/path", "Path:/path"))
+	frontend.Backend = ""
+	frontend.WeightedBackends = &types.WeightedBackends{Backends: map[string]int{"v1": 1, "v2": 1}}
+	frontend.RateLimit = &types.RateLimit{
+		ExtractorFunc: "client.ip",
+		RateSet: map[string]*types.Rate{
+			"rate1": {Period: flaeg.Duration(time.Minute), Average: 1, Burst: 2},
+		},
+	}
+
+	dynamicConfigs := types.Configurations{
+		"config": buildDynamicConfig(
+			withFrontend("frontend", frontend),
+			withBackend("v1", buildBackend(withServer("v1Server", backendServer.URL))),
+			withBackend("v2", buildBackend(withServer("v2Server", backendServer.URL))),
+		),
+	}
+
+	srv := NewServer(globalConfig, nil)
+	entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+	require.NoError(t, err)
+
+	// The rate limit of the frontend is shared by its weighted backends.
+	counts := map[int]int{}
+	for i := 0; i < 4; i++ {
+		responseRecorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, backendServer.URL+"/path", nil)
+
+		entryPoints["http"].httpRouter.ServeHTTP(responseRecorder, request)
+		counts[responseRecorder.Code]++
+	}
+
+	assert.Equal(t, map[int]int{http.StatusOK: 2, http.StatusTooManyRequests: 2}, counts)
+}
+
+func TestServerLoadConfigSharedBackend(t *testing.T) {
+	backendServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer backendServer.Close()
+
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+		},
+	}
+
+	dynamicConfigs := types.Configurations{
+		"config": buildDynamicConfig(
+			withFrontend("a-public", buildFrontend(withRoute("route", "Host:public.local"))),
+			withFrontend("b-private", buildFrontend(withRoute("route", "Host:private.local"), func(f *types.Frontend) {
+				f.Auth = &types.Auth{Basic: &types.Basic{Users: types.Users{"test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"}}}
+			})),
+			withFrontend("c-invalid", buildFrontend(withRoute("route", "Host:invalid.local"), func(f *types.Frontend) {
+				f.Auth = &types.Auth{Basic: &types.Basic{Users: types.Users{"invalid"}}}
+			})),
+			withBackend("backend", buildBackend(withServer("server", backendServer.URL))),
+		),
+	}
+
+	srv := NewServer(globalConfig, nil)
+	entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc           string
+		host           string
+		expectedStatus int
+	}{
+		{
+			desc:           "frontend without auth",
+			host:           "public.local",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "frontend with auth on the same backend",
+			host:           "private.local",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "frontend with invalid auth is skipped",
+			host:           "invalid.local",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "http://"+test.host+"/", nil)
+
+			entryPoints["http"].httpRouter.ServeHTTP(recorder, request)
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestServerLoadConfigSharedBackendAccessLog(t *testing.T) {
+	backendServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer backendServer.Close()
+
+	logFile, err := ioutil.TempFile("", "traefik-access-log")
+	require.NoError(t, err)
+	logFile.Close()
+	defer os.Remove(logFile.Name())
+
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+		},
+		AccessLog: &types.AccessLog{FilePath: logFile.Name(), Format: accesslog.JSONFormat},
+	}
+
+	dynamicConfigs := types.Configurations{
+		"config": buildDynamicConfig(
+			withFrontend("a-filtered", buildFrontend(withRoute("route", "Host:filtered.local"), func(f *types.Frontend) {
+				f.AccessLog = &types.FrontendAccessLog{Filters: &types.AccessLogFilters{StatusCodes: types.StatusCodes{"500-599"}}}
+			})),
+			withFrontend("b-default", buildFrontend(withRoute("route", "Host:default.local"))),
+			withBackend("backend", buildBackend(withServer("server", backendServer.URL))),
+		),
+	}
+
+	srv := NewServer(globalConfig, nil)
+	require.NotNil(t, srv.accessLoggerMiddleware)
+	entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+	require.NoError(t, err)
+
+	for _, host := range []string{"filtered.local", "default.local", "filtered.local"} {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://"+host+"/", nil)
+
+		srv.accessLoggerMiddleware.ServeHTTP(recorder, request, entryPoints["http"].httpRouter.ServeHTTP)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+	require.NoError(t, srv.accessLoggerMiddleware.Close())
+
+	logs, err := ioutil.ReadFile(logFile.Name())
+	require.NoError(t, err)
+
+	// the settings of a frontend only apply to its own requests, not to the other frontends of its backend
+	lines := strings.Split(strings.TrimSpace(string(logs)), "\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"FrontendName":"b-default"`)
+}
+
+func TestServerBuildConfigSharedBackendState(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+	backendServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/block" {
+			received <- struct{}{}
+			<-release
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer backendServer.Close()
+
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"http": &configuration.EntryPoint{ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+		},
+		HealthCheck: &configuration.HealthCheckConfig{Interval: flaeg.Duration(time.Minute)},
+	}
+
+	dynamicConfigs := types.Configurations{
+		"config": buildDynamicConfig(
+			withFrontend("a-frontend", buildFrontend(withRoute("route", "Host:a.local"), func(f *types.Frontend) {
+				f.Headers = &types.Headers{CustomResponseHeaders: map[string]string{"X-Frontend": "a"}}
+			})),
+			withFrontend("b-frontend", buildFrontend(withRoute("route", "Host:b.local"))),
+			withBackend("backend", buildBackend(withServer("server", backendServer.URL), func(b *types.Backend) {
+				b.HealthCheck = &types.HealthCheck{Path: "/health"}
+				b.OutlierDetection = &types.OutlierDetection{ConsecutiveErrors: 5}
+				b.MaxConn = &types.MaxConn{Amount: 1, ExtractorFunc: "client.ip"}
+			})),
+		),
+	}
+
+	srv := NewServer(globalConfig, nil)
+	built := srv.buildConfig(dynamicConfigs, globalConfig)
+	require.Empty(t, built.diagnostics)
+
+	// the frontends of the backend share its health check, outlier detection and connection limit
+	assert.Len(t, built.backendsHealthCheck, 1)
+	assert.Len(t, built.outlierDetectors, 1)
+
+	router := built.serverEntryPoints["http"].httpRouter
+	blocked := make(chan *httptest.ResponseRecorder)
+	go func() {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://a.local/block", nil))
+		blocked <- recorder
+	}()
+	<-received
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://b.local/", nil))
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+	close(release)
+	recorder = <-blocked
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "a", recorder.Header().Get("X-Frontend"))
+
+	// the responses are still modified with the headers of their own frontend
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://b.local/", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("X-Frontend"))
+}
+
 func TestBuildRedirectHandler(t *testing.T) {
 	srv := Server{
 		globalConfiguration: configuration.GlobalConfiguration{
@@ -1074,6 +1717,12 @@ func withServer(name, url string) func(backend *types.Backend) {
 	}
 }
 
+func withMirroring(backend string, percent int) func(*types.Frontend) {
+	return func(f *types.Frontend) {
+		f.Mirroring = &types.Mirroring{Backend: backend, Percent: percent}
+	}
+}
+
 func withLoadBalancer(method string, sticky bool) func(*types.Backend) {
 	return func(be *types.Backend) {
 		if sticky {
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+	"github.com/containous/traefik/udp"
+)
+
+func (s *Server) setupUDPServerEntryPoint(newServerEntryPointName string, newServerEntryPoint *serverEntryPoint) *serverEntryPoint {
+	entryPoint := s.globalConfiguration.EntryPoints[newServerEntryPointName]
+	log.Infof("Preparing UDP server %s %+v", newServerEntryPointName, entryPoint)
+
+	conn, err := net.ListenPacket("udp", entryPoint.Address)
+	if err != nil {
+		log.Fatal("Error preparing server: ", err)
+	}
+
+	sessionTimeout := configuration.DefaultUDPSessionTimeout
+	if entryPoint.UDP != nil && entryPoint.UDP.SessionTimeout > 0 {
+		sessionTimeout = time.Duration(entryPoint.UDP.SessionTimeout)
+	}
+
+	newServerEntryPoint.udpConn = conn
+	newServerEntryPoint.udpProxy = udp.NewProxy(conn, newServerEntryPoint.udpBalancer, sessionTimeout)
+
+	return newServerEntryPoint
+}
+
+func (s *Server) startUDPServer(serverEntryPoint *serverEntryPoint) {
+	log.Infof("Starting UDP server on %s", serverEntryPoint.udpConn.LocalAddr())
+	if err := serverEntryPoint.udpProxy.Serve(); err != nil {
+		log.Debugf("UDP server on %s stopped: %s", serverEntryPoint.udpConn.LocalAddr(), err)
+	}
+}
+
+// loadUDPFrontendConfig wires the frontend of a UDP entry point: new sessions are forwarded
+// to a weighted round robin of the backend servers. The frontend routes are ignored.
+func (s *Server) loadUDPFrontendConfig(balancer *udp.BalancerSwitcher, frontendName string, config *types.Configuration, diags *diagnostics) error {
+	frontend := config.Frontends[frontendName]
+	if len(frontend.Routes) > 0 {
+		log.Debugf("Routes of frontend %s are ignored on UDP entrypoints", frontendName)
+	}
+
+	backend := config.Backends[frontend.Backend]
+	if backend == nil {
+		return fmt.Errorf("undefined backend '%s'", frontend.Backend)
+	}
+
+	lbMethod, err := types.NewLoadBalancerMethod(backend.LoadBalancer)
+	if err != nil || lbMethod != types.Wrr {
+		log.Warnf("Load balancer method '%+v' is not supported for UDP backend %s, using wrr", backend.LoadBalancer, frontend.Backend)
+		diags.warnf("backends", frontend.Backend, "load balancer method '%+v' is not supported for UDP backends, using wrr", backend.LoadBalancer)
+	}
+
+	log.Debugf("Creating UDP backend %s", frontend.Backend)
+	lb, err := udp.NewWRRLoadBalancer()
+	if err != nil {
+		return err
+	}
+	if err := s.configureLBServers(lb, config, frontend.Backend); err != nil {
+		return err
+	}
+
+	balancer.UpdateBalancer(lb)
+	return nil
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/types"
+	"github.com/containous/traefik/udp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerLoadConfigUDPFrontend(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"dns": &configuration.EntryPoint{Address: ":53", Protocol: configuration.ProtocolUDP},
+		},
+	}
+
+	first := buildFrontend(withRoute("route", "Host:foo.bar"))
+	first.EntryPoints = []string{"dns"}
+	first.Backend = "first"
+	second := buildFrontend()
+	second.EntryPoints = []string{"dns"}
+	second.Backend = "second"
+
+	dynamicConfigs := types.Configurations{
+		"config": buildDynamicConfig(
+			withFrontend("a-frontend", first),
+			withFrontend("b-frontend", second),
+			withBackend("first", buildBackend(withServer("server", "udp://10.0.0.1:53"))),
+			withBackend("second", buildBackend(withServer("server", "udp://10.0.0.2:53"))),
+		),
+	}
+
+	srv := NewServer(globalConfig, nil)
+	entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+	require.NoError(t, err)
+	require.NotNil(t, entryPoints["dns"].udpBalancer)
+	assert.Nil(t, entryPoints["dns"].httpRouter)
+
+	balancer, ok := entryPoints["dns"].udpBalancer.GetBalancer().(*udp.WRRLoadBalancer)
+	require.True(t, ok)
+	require.Len(t, balancer.Servers(), 1)
+	assert.Equal(t, "10.0.0.1:53", balancer.Servers()[0].Host)
+}
+
+func TestServerLoadConfigUDPFrontendUndefinedBackend(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"dns": &configuration.EntryPoint{Address: ":53", Protocol: configuration.ProtocolUDP},
+		},
+	}
+
+	frontend := buildFrontend()
+	frontend.EntryPoints = []string{"dns"}
+
+	dynamicConfigs := types.Configurations{
+		"config": buildDynamicConfig(withFrontend("frontend", frontend)),
+	}
+
+	srv := NewServer(globalConfig, nil)
+	entryPoints, err := srv.loadConfig(dynamicConfigs, globalConfig)
+	require.NoError(t, err)
+	assert.Nil(t, entryPoints["dns"].udpBalancer.GetBalancer())
+}
+
+func TestServerLoadConfigUDPFrontendAcrossProviders(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"dns": &configuration.EntryPoint{Address: ":53", Protocol: configuration.ProtocolUDP},
+		},
+	}
+
+	newConfig := func(frontendName, backendName, serverURL string) *types.Configuration {
+		frontend := buildFrontend()
+		frontend.EntryPoints = []string{"dns"}
+		frontend.Backend = backendName
+		return buildDynamicConfig(
+			withFrontend(frontendName, frontend),
+			withBackend(backendName, buildBackend(withServer("server", serverURL))),
+		)
+	}
+
+	dynamicConfigs := types.Configurations{
+		"b-provider": newConfig("second", "second", "udp://10.0.0.2:53"),
+		"a-provider": newConfig("first", "first", "udp://10.0.0.1:53"),
+	}
+
+	// the frontend kept must not depend on the map iteration order
+	for i := 0; i < 10; i++ {
+		srv := NewServer(globalConfig, nil)
+		built := srv.buildConfig(dynamicConfigs, globalConfig)
+
+		balancer, ok := built.serverEntryPoints["dns"].udpBalancer.GetBalancer().(*udp.WRRLoadBalancer)
+		require.True(t, ok)
+		require.Len(t, balancer.Servers(), 1)
+		assert.Equal(t, "10.0.0.1:53", balancer.Servers()[0].Host)
+
+		assert.Equal(t, []string{"frontends second: frontend first is already defined on UDP entrypoint dns"}, rejectionReasons(built.diagnostics))
+	}
+}
@@ -0,0 +1,304 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/metrics"
+	"github.com/containous/traefik/middlewares"
+	"github.com/containous/traefik/middlewares/accesslog"
+	"github.com/containous/traefik/middlewares/auth"
+	"github.com/containous/traefik/middlewares/cache"
+	"github.com/containous/traefik/middlewares/redirect"
+	"github.com/containous/traefik/safe"
+	traefikTls "github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	"github.com/containous/traefik/whitelist"
+)
+
+// Diagnostic levels
+const (
+	DiagnosticError   = "error"
+	DiagnosticWarning = "warning"
+)
+
+// Diagnostic is a problem found in a configuration
+type Diagnostic struct {
+	Level string `json:"level"`
+	// Section is the part of the configuration holding the problem, such as entryPoints, frontends, backends or tls
+	Section string `json:"section"`
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message"`
+}
+
+type diagnostics []Diagnostic
+
+func (d *diagnostics) errorf(section, name, format string, args ...interface{}) {
+	d.add(Diagnostic{Level: DiagnosticError, Section: section, Name: name, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *diagnostics) warnf(section, name, format string, args ...interface{}) {
+	d.add(Diagnostic{Level: DiagnosticWarning, Section: section, Name: name, Message: fmt.Sprintf(format, args...)})
+}
+
+// add appends the diagnostic once, as the problems of a backend are found again for each of its frontends.
+func (d *diagnostics) add(diag Diagnostic) {
+	for _, existing := range *d {
+		if existing == diag {
+			return
+		}
+	}
+	*d = append(*d, diag)
+}
+
+// HasErrors returns true if one of the diagnostics is an error
+func HasErrors(diags []Diagnostic) bool {
+	for _, diag := range diags {
+		if diag.Level == DiagnosticError {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateGlobalConfiguration returns the problems of the static configuration preventing the server to start,
+// the effective configuration being already set.
+func ValidateGlobalConfiguration(globalConfiguration configuration.GlobalConfiguration) []Diagnostic {
+	var diags diagnostics
+
+	for _, entryPointName := range globalConfiguration.DefaultEntryPoints {
+		if _, ok := globalConfiguration.EntryPoints[entryPointName]; !ok {
+			diags.errorf("defaultEntryPoints", entryPointName, "undefined entrypoint '%s'", entryPointName)
+		}
+	}
+
+	for _, entryPointName := range sortedEntryPointNames(globalConfiguration.EntryPoints) {
+		validateEntryPoint(&diags, globalConfiguration, entryPointName)
+	}
+
+	internalEntryPoints := map[string]string{}
+	if globalConfiguration.API != nil {
+		internalEntryPoints["api"] = globalConfiguration.API.EntryPoint
+	}
+	if globalConfiguration.Ping != nil {
+		internalEntryPoints["ping"] = globalConfiguration.Ping.EntryPoint
+	}
+	if globalConfiguration.Metrics != nil && globalConfiguration.Metrics.Prometheus != nil {
+		internalEntryPoints["metrics"] = globalConfiguration.Metrics.Prometheus.EntryPoint
+	}
+	if globalConfiguration.Rest != nil {
+		internalEntryPoints["rest"] = globalConfiguration.Rest.EntryPoint
+	}
+	if globalConfiguration.ACME != nil {
+		internalEntryPoints["acme"] = globalConfiguration.ACME.EntryPoint
+	}
+	for _, section := range []string{"api", "ping", "metrics", "rest", "acme"} {
+		entryPointName, ok := internalEntryPoints[section]
+		if ok && len(entryPointName) > 0 && globalConfiguration.EntryPoints[entryPointName] == nil {
+			diags.errorf(section, "", "undefined entrypoint '%s'", entryPointName)
+		}
+	}
+
+	return diags
+}
+
+func validateEntryPoint(diags *diagnostics, globalConfiguration configuration.GlobalConfiguration, entryPointName string) {
+	entryPoint := globalConfiguration.EntryPoints[entryPointName]
+	const section = "entryPoints"
+
+	if _, _, err := net.SplitHostPort(entryPoint.Address); err != nil {
+		diags.errorf(section, entryPointName, "invalid address %q: %v", entryPoint.Address, err)
+	}
+
+	if entryPoint.TLS != nil {
+		if _, _, err := entryPoint.TLS.Certificates.CreateTLSConfig(entryPointName); err != nil {
+			diags.errorf(section, entryPointName, "error loading certificates: %v", err)
+		}
+		if len(entryPoint.TLS.MinVersion) > 0 {
+			if _, ok := traefikTls.MinVersion[entryPoint.TLS.MinVersion]; !ok {
+				diags.warnf(section, entryPointName, "unknown TLS minimum version %q, ignored", entryPoint.TLS.MinVersion)
+			}
+		}
+		for _, cipher := range entryPoint.TLS.CipherSuites {
+			if _, ok := traefikTls.CipherSuites[cipher]; !ok {
+				diags.errorf(section, entryPointName, "invalid cipher suite %q", cipher)
+			}
+		}
+		caFiles := append([]string{}, entryPoint.TLS.ClientCAFiles...)
+		for _, caFile := range append(caFiles, entryPoint.TLS.ClientCA.Files...) {
+			if _, err := ioutil.ReadFile(caFile); err != nil {
+				diags.errorf(section, entryPointName, "error reading client CA: %v", err)
+			}
+		}
+	}
+
+	if entryPoint.Redirect != nil {
+		if err := validateRedirect(globalConfiguration, entryPoint.Redirect); err != nil {
+			diags.errorf(section, entryPointName, "invalid redirect: %v", err)
+		}
+	}
+
+	if entryPoint.Auth != nil {
+		if _, err := auth.NewAuthenticator(entryPoint.Auth, nil); err != nil {
+			diags.errorf(section, entryPointName, "invalid auth: %v", err)
+		}
+	}
+
+	if len(entryPoint.WhitelistSourceRange) > 0 {
+		if _, err := middlewares.NewIPWhitelister(entryPoint.WhitelistSourceRange); err != nil {
+			diags.errorf(section, entryPointName, "invalid whitelist source range: %v", err)
+		}
+	}
+
+	if entryPoint.ProxyProtocol != nil {
+		if _, err := whitelist.NewIP(entryPoint.ProxyProtocol.TrustedIPs, entryPoint.ProxyProtocol.Insecure); err != nil {
+			diags.errorf(section, entryPointName, "invalid proxy protocol trusted IPs: %v", err)
+		}
+	}
+
+	if entryPoint.ForwardedHeaders != nil {
+		if _, err := NewHeaderRewriter(entryPoint.ForwardedHeaders.TrustedIPs, entryPoint.ForwardedHeaders.Insecure); err != nil {
+			diags.errorf(section, entryPointName, "invalid forwarded headers trusted IPs: %v", err)
+		}
+	}
+}
+
+// ValidateConfiguration returns the problems of a dynamic configuration, found by building it as the server does
+// when loading it, without applying it.
+func ValidateConfiguration(globalConfiguration configuration.GlobalConfiguration, config *types.Configuration) []Diagnostic {
+	s := newValidationServer(globalConfiguration)
+	s.defaultConfigurationValues(config)
+	built := s.buildConfig(types.Configurations{"validation": config}, globalConfiguration)
+
+	diags := built.diagnostics
+	validateCertificates(&diags, globalConfiguration, config, built.certificates)
+
+	return diags
+}
+
+// newValidationServer creates a server only building the configurations: it reports no metric,
+// checks the cache storages instead of creating them, and is never started.
+func newValidationServer(globalConfiguration configuration.GlobalConfiguration) *Server {
+	s := &Server{
+		globalConfiguration:           globalConfiguration,
+		routinesPool:                  safe.NewPool(context.Background()),
+		defaultForwardingRoundTripper: createForwardingRoundTripper(globalConfiguration, createHTTPTransport(globalConfiguration)),
+		metricsRegistry:               metrics.NewVoidRegistry(),
+		cacheManager:                  cache.NewValidationManager(),
+	}
+	if globalConfiguration.AccessLog != nil || globalConfiguration.AccessLogsFile != "" {
+		// the access log middlewares are built without writing any log
+		s.accessLoggerMiddleware = &accesslog.LogHandler{}
+	}
+	return s
+}
+
+func validateRedirect(globalConfiguration configuration.GlobalConfiguration, opt *types.Redirect) error {
+	if len(opt.EntryPoint) > 0 {
+		if globalConfiguration.EntryPoints[opt.EntryPoint] == nil {
+			return fmt.Errorf("unknown target entrypoint %q", opt.EntryPoint)
+		}
+		return nil
+	}
+	_, err := redirect.NewRegexHandler(opt.Regex, opt.Replacement, opt.Permanent)
+	return err
+}
+
+// validateCertificates checks the entrypoints of the certificates of the dynamic configuration,
+// and that the domains of the frontends on TLS entrypoints have a certificate
+func validateCertificates(diags *diagnostics, globalConfiguration configuration.GlobalConfiguration, config *types.Configuration,
+	dynamicCertificates map[string]*traefikTls.DomainsCertificates) {
+	const section = "tls"
+
+	staticCertificates := make(map[string]*traefikTls.DomainsCertificates)
+	for _, entryPointName := range sortedEntryPointNames(globalConfiguration.EntryPoints) {
+		entryPoint := globalConfiguration.EntryPoints[entryPointName]
+		if entryPoint.TLS == nil {
+			continue
+		}
+		if _, domainsCertificates, err := entryPoint.TLS.Certificates.CreateTLSConfig(entryPointName); err == nil && domainsCertificates[entryPointName] != nil {
+			staticCertificates[entryPointName] = domainsCertificates[entryPointName]
+		}
+	}
+
+	for i, tlsConfiguration := range config.TLS {
+		// The certificates given by their content are named by their index
+		name := fmt.Sprintf("%d", i)
+		if tlsConfiguration.Certificate != nil && !strings.Contains(tlsConfiguration.Certificate.CertFile.String(), "-----BEGIN") {
+			name = tlsConfiguration.Certificate.CertFile.String()
+		}
+
+		for _, entryPointName := range tlsConfiguration.EntryPoints {
+			entryPoint, ok := globalConfiguration.EntryPoints[entryPointName]
+			if !ok {
+				diags.warnf(section, name, "undefined entrypoint '%s', ignored", entryPointName)
+			} else if entryPoint.TLS == nil {
+				diags.warnf(section, name, "entrypoint %s is not a TLS entrypoint, ignored", entryPointName)
+			}
+		}
+	}
+
+	for _, frontendName := range sortedFrontendNamesForConfig(config) {
+		frontend := config.Frontends[frontendName]
+
+		for _, entryPointName := range frontend.EntryPoints {
+			entryPoint, ok := globalConfiguration.EntryPoints[entryPointName]
+			if !ok || entryPoint.TLS == nil || entryPoint.IsUDP() {
+				continue
+			}
+			if globalConfiguration.ACME != nil && globalConfiguration.ACME.EntryPoint == entryPointName {
+				continue
+			}
+
+			for _, domain := range frontendDomains(frontend) {
+				if !hasCertificate(staticCertificates[entryPointName], domain) && !hasCertificate(dynamicCertificates[entryPointName], domain) {
+					diags.warnf("frontends", frontendName, "no certificate for domain %s on entrypoint %s, the default certificate is served", domain, entryPointName)
+				}
+			}
+		}
+	}
+}
+
+func frontendDomains(frontend *types.Frontend) []string {
+	var domains []string
+	for _, routeName := range sortedRouteNames(frontend) {
+		rules := &Rules{}
+		routeDomains, err := rules.ParseDomains(frontend.Routes[routeName].Rule)
+		if err != nil {
+			continue
+		}
+		domains = append(domains, routeDomains...)
+	}
+	return domains
+}
+
+// hasCertificate checks the domain against the domains of the certificates, as serverEntryPoint.getCertificate does
+func hasCertificate(certificates *traefikTls.DomainsCertificates, domain string) bool {
+	if certificates == nil {
+		return false
+	}
+	for domains := range *certificates {
+		for _, certificateDomain := range strings.Split(domains, ",") {
+			selector := "^" + strings.Replace(certificateDomain, "*.", "[^\\.]*\\.?", -1) + "$"
+			if match, _ := regexp.MatchString(selector, domain); match {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sortedEntryPointNames(entryPoints configuration.EntryPoints) []string {
+	var names []string
+	for name := range entryPoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,265 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/containous/flaeg"
+	"github.com/containous/traefik/configuration"
+	"github.com/containous/traefik/ping"
+	"github.com/containous/traefik/tls"
+	"github.com/containous/traefik/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateGlobalConfiguration(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   configuration.GlobalConfiguration
+		expected []Diagnostic
+	}{
+		{
+			desc: "valid configuration",
+			config: configuration.GlobalConfiguration{
+				EntryPoints: configuration.EntryPoints{
+					"http":  &configuration.EntryPoint{Address: ":80", Redirect: &types.Redirect{EntryPoint: "https"}},
+					"https": &configuration.EntryPoint{Address: ":443", TLS: &tls.TLS{MinVersion: "VersionTLS12"}},
+				},
+				DefaultEntryPoints: []string{"http", "https"},
+			},
+		},
+		{
+			desc: "undefined default entrypoint",
+			config: configuration.GlobalConfiguration{
+				EntryPoints:        configuration.EntryPoints{"http": &configuration.EntryPoint{Address: ":80"}},
+				DefaultEntryPoints: []string{"http", "https"},
+			},
+			expected: []Diagnostic{
+				{Level: DiagnosticError, Section: "defaultEntryPoints", Name: "https", Message: "undefined entrypoint 'https'"},
+			},
+		},
+		{
+			desc: "invalid entrypoints",
+			config: configuration.GlobalConfiguration{
+				EntryPoints: configuration.EntryPoints{
+					"http": &configuration.EntryPoint{Address: "80", Redirect: &types.Redirect{EntryPoint: "admin"}},
+					"https": &configuration.EntryPoint{
+						Address:              ":443",
+						TLS:                  &tls.TLS{MinVersion: "foo", CipherSuites: []string{"foo"}},
+						WhitelistSourceRange: []string{"foo"},
+					},
+				},
+			},
+			expected: []Diagnostic{
+				{Level: DiagnosticError, Section: "entryPoints", Name: "http", Message: `invalid address "80": address 80: missing port in address`},
+				{Level: DiagnosticError, Section: "entryPoints", Name: "http", Message: `invalid redirect: unknown target entrypoint "admin"`},
+				{Level: DiagnosticWarning, Section: "entryPoints", Name: "https", Message: `unknown TLS minimum version "foo", ignored`},
+				{Level: DiagnosticError, Section: "entryPoints", Name: "https", Message: `invalid cipher suite "foo"`},
+				{Level: DiagnosticError, Section: "entryPoints", Name: "https", Message: `invalid whitelist source range: parsing CIDR whitelist [foo]: parsing CIDR whitelist <nil>: invalid CIDR address: foo`},
+			},
+		},
+		{
+			desc: "undefined ping entrypoint",
+			config: configuration.GlobalConfiguration{
+				EntryPoints: configuration.EntryPoints{"http": &configuration.EntryPoint{Address: ":80"}},
+				Ping:        &ping.Handler{EntryPoint: "traefik"},
+			},
+			expected: []Diagnostic{
+				{Level: DiagnosticError, Section: "ping", Message: "undefined entrypoint 'traefik'"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			diags := ValidateGlobalConfiguration(test.config)
+			assert.Equal(t, test.expected, diags)
+		})
+	}
+}
+
+func TestValidateConfiguration(t *testing.T) {
+	globalConfig := configuration.GlobalConfiguration{
+		EntryPoints: configuration.EntryPoints{
+			"http":  &configuration.EntryPoint{Address: ":80", ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+			"https": &configuration.EntryPoint{Address: ":443", TLS: &tls.TLS{}, ForwardedHeaders: &configuration.ForwardedHeaders{Insecure: true}},
+			"tcp":   &configuration.EntryPoint{Address: ":8443", Protocol: "tcp"},
+		},
+		DefaultEntryPoints: []string{"http"},
+		HealthCheck:        &configuration.HealthCheckConfig{Interval: flaeg.Duration(configuration.DefaultHealthCheckInterval)},
+	}
+
+	testCases := []struct {
+		desc     string
+		config   *types.Configuration
+		expected []Diagnostic
+	}{
+		{
+			desc: "valid configuration",
+			config: buildDynamicConfig(
+				withFrontend("frontend", buildFrontend(withRoute("route", "Host:foo.bar && PathPrefix:/api"))),
+				withBackend("backend", buildBackend(withServer("server", "http://127.0.0.1:8080"))),
+			),
+		},
+		{
+			desc: "undefined backend and entrypoint",
+			config: buildDynamicConfig(
+				withFrontend("frontend", buildFrontend(withRoute("route", "Host:foo.bar"), func(f *types.Frontend) {
+					f.EntryPoints = []string{"http", "admin"}
+					f.Backend = "undefined"
+				})),
+			),
+			expected: []Diagnostic{
+				{Level: DiagnosticError, Section: "frontends", Name: "frontend", Message: "undefined entrypoint 'admin'"},
+				{Level: DiagnosticError, Section: "frontends", Name: "frontend", Message: "error creating backend undefined: undefined backend 'undefined'"},
+			},
+		},
+		{
+			desc: "invalid rule",
+			config: buildDynamicConfig(
+				withFrontend("frontend", buildFrontend(withRoute("route", "Host:foo.bar && Foo:/"))),
+				withBackend("backend", buildBackend(withServer("server", "http://127.0.0.1:8080"))),
+			),
+			expected: []Diagnostic{
+				{Level: DiagnosticError, Section: "frontends", Name: "frontend", Message: `error creating route route: error parsing rule: unknown function Foo at position 17 in "Host:foo.bar && Foo:/"`},
+			},
+		},
+		{
+			desc: "invalid redirect and cache storage",
+			config: buildDynamicConfig(
+				withFrontend("frontend", buildFrontend(withRoute("route", "Host:foo.bar"), func(f *types.Frontend) {
+					f.Redirect = &types.Redirect{Regex: "(", Replacement: "/"}
+					f.Cache = &types.Cache{Storage: "disk"}
+				})),
+				withBackend("backend", buildBackend(withServer("server", "http://127.0.0.1:8080"))),
+			),
+			expected: []Diagnostic{
+				{Level: DiagnosticError, Section: "frontends", Name: "frontend", Message: "error creating redirect: error parsing regexp: missing closing ): `(`"},
+				{Level: DiagnosticError, Section: "frontends", Name: "frontend", Message: "error creating cache middleware: the disk storage requires a path"},
+			},
+		},
+		{
+			desc: "invalid retry policy",
+			config: buildDynamicConfig(
+				withFrontend("frontend", buildFrontend(withRoute("route", "Host:foo.bar"), func(f *types.Frontend) {
+					f.Retry = &types.Retry{Budget: -1}
+				})),
+				withBackend("backend", buildBackend(withServer("server", "http://127.0.0.1:8080"))),
+			),
+			expected: []Diagnostic{
+				{Level: DiagnosticError, Section: "frontends", Name: "frontend", Message: "error creating middlewares: error creating retry middleware: invalid retry budget -1, must be positive"},
+			},
+		},
+		{
+			desc: "invalid whitelist",
+			config: buildDynamicConfig(
+				withFrontend("frontend", buildFrontend(withRoute("route", "Host:foo.bar"), func(f *types.Frontend) {
+					f.WhitelistSourceRange = []string{"foo"}
+				})),
+				withBackend("backend", buildBackend(withServer("server", "http://127.0.0.1:8080"))),
+			),
+			expected: []Diagnostic{
+				{Level: DiagnosticError, Section: "frontends", Name: "frontend", Message: "error creating middlewares: error creating IP whitelister: parsing CIDR whitelist [foo]: parsing CIDR whitelist <nil>: invalid CIDR address: foo"},
+			},
+		},
+		{
+			desc: "undefined weighted backend",
+			config: buildDynamicConfig(
+				withFrontend("frontend", buildFrontend(withRoute("route", "Host:foo.bar"), func(f *types.Frontend) {
+					f.WeightedBackends = &types.WeightedBackends{Backends: map[string]int{"backend": 1, "undefined": 1}}
+				})),
+				withBackend("backend", buildBackend(withServer("server", "http://127.0.0.1:8080"))),
+			),
+			expected: []Diagnostic{
+				{Level: DiagnosticError, Section: "frontends", Name: "frontend", Message: "error creating backend undefined: undefined backend 'undefined'"},
+			},
+		},
+		{
+			desc: "TCP frontend without HostSNI rule",
+			config: buildDynamicConfig(
+				withFrontend("frontend", buildFrontend(withRoute("route", "Host:foo.bar"), func(f *types.Frontend) {
+					f.EntryPoints = []string{"tcp"}
+				})),
+				withBackend("backend", buildBackend(withServer("server", "tcp://127.0.0.1:8080"))),
+			),
+			expected: []Diagnostic{
+				{Level: DiagnosticError, Section: "frontends", Name: "frontend", Message: "error creating TCP frontend on entrypoint tcp: error parsing SNI hosts: rule Host is not supported on TCP entry points"},
+			},
+		},
+		{
+			desc: "invalid backend",
+			config: buildDynamicConfig(
+				withFrontend("frontend", buildFrontend(withRoute("route", "Host:foo.bar"))),
+				withBackend("backend", buildBackend(withServer("server", "http://127.0.0.1:8080"), func(b *types.Backend) {
+					b.CircuitBreaker = &types.CircuitBreaker{Expression: "Foo() > 1"}
+					b.HealthCheck = &types.HealthCheck{Path: "/health", Interval: "-1s"}
+				})),
+			),
+			expected: []Diagnostic{
+				{Level: DiagnosticError, Section: "backends", Name: "backend", Message: "healthcheck interval smaller than zero"},
+				{Level: DiagnosticError, Section: "frontends", Name: "frontend", Message: "error creating backend backend: error creating circuit breaker: unsupported function: Foo"},
+			},
+		},
+		{
+			desc: "invalid consistent hash extractor",
+			config: buildDynamicConfig(
+				withFrontend("frontend", buildFrontend(withRoute("route", "Host:foo.bar"))),
+				withBackend("backend", buildBackend(withServer("server", "http://127.0.0.1:8080"), func(b *types.Backend) {
+					b.LoadBalancer = &types.LoadBalancer{Method: "ConsistentHash", ConsistentHash: &types.ConsistentHashing{ExtractorFunc: "foo"}}
+				})),
+			),
+			expected: []Diagnostic{
+				{Level: DiagnosticError, Section: "frontends", Name: "frontend", Message: "error creating backend backend: error creating consistent hash load balancer: Unsupported limiting variable: 'foo'"},
+			},
+		},
+		{
+			desc: "missing certificate",
+			config: buildDynamicConfig(
+				withFrontend("frontend", buildFrontend(withRoute("route", "Host:foo.bar"), func(f *types.Frontend) {
+					f.EntryPoints = []string{"https"}
+				})),
+				withBackend("backend", buildBackend(withServer("server", "http://127.0.0.1:8080"))),
+				func(config *types.Configuration) {
+					config.TLS = []*tls.Configuration{{
+						EntryPoints: []string{"https"},
+						Certificate: &tls.Certificate{CertFile: "/missing.crt", KeyFile: "/missing.key"},
+					}}
+				},
+			),
+			expected: []Diagnostic{
+				{Level: DiagnosticError, Section: "tls", Message: "error loading certificates: tls: failed to find any PEM data in certificate input"},
+				{Level: DiagnosticWarning, Section: "frontends", Name: "frontend", Message: "no certificate for domain foo.bar on entrypoint https, the default certificate is served"},
+			},
+		},
+		{
+			desc: "certificate",
+			config: buildDynamicConfig(
+				withFrontend("frontend", buildFrontend(withRoute("route", "Host:example.com"), func(f *types.Frontend) {
+					f.EntryPoints = []string{"https"}
+				})),
+				withBackend("backend", buildBackend(withServer("server", "http://127.0.0.1:8080"))),
+				func(config *types.Configuration) {
+					config.TLS = []*tls.Configuration{{
+						EntryPoints: []string{"https"},
+						Certificate: &tls.Certificate{CertFile: localhostCert, KeyFile: localhostKey},
+					}}
+				},
+			),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			diags := ValidateConfiguration(globalConfig, test.config)
+			assert.Equal(t, test.expected, diags)
+			assert.Equal(t, len(test.expected) > 0 && test.expected[0].Level == DiagnosticError, HasErrors(diags))
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package tcp
+
+import (
+	"net"
+)
+
+// Handler is the TCP counterpart of http.Handler: it takes ownership of the connection
+// and is responsible for closing it.
+type Handler interface {
+	ServeTCP(conn net.Conn)
+}
+
+// HandlerFunc is an adapter to allow the use of ordinary functions as TCP handlers.
+type HandlerFunc func(conn net.Conn)
+
+// ServeTCP calls f(conn).
+func (f HandlerFunc) ServeTCP(conn net.Conn) {
+	f(conn)
+}
@@ -0,0 +1,34 @@
+package tcp
+
+import (
+	"net"
+
+	"github.com/containous/traefik/safe"
+)
+
+// HandlerSwitcher allows hot switching of the TCP router of an entry point
+type HandlerSwitcher struct {
+	router *safe.Safe
+}
+
+// NewHandlerSwitcher builds a new instance of HandlerSwitcher
+func NewHandlerSwitcher(newRouter *Router) *HandlerSwitcher {
+	return &HandlerSwitcher{
+		router: safe.New(newRouter),
+	}
+}
+
+// ServeTCP forwards the connection to the current router
+func (hs *HandlerSwitcher) ServeTCP(conn net.Conn) {
+	hs.GetHandler().ServeTCP(conn)
+}
+
+// GetHandler returns the current router
+func (hs *HandlerSwitcher) GetHandler() *Router {
+	return hs.router.Get().(*Router)
+}
+
+// UpdateHandler safely updates the current router with a new one
+func (hs *HandlerSwitcher) UpdateHandler(newRouter *Router) {
+	hs.router.Set(newRouter)
+}
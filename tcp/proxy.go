@@ -0,0 +1,58 @@
+package tcp
+
+import (
+	"io"
+	"net"
+	"time"
+
+	"github.com/containous/traefik/log"
+)
+
+// Proxy forwards a TCP connection to a target address
+type Proxy struct {
+	target      string
+	dialTimeout time.Duration
+}
+
+// NewProxy creates a new Proxy towards the target address (host:port)
+func NewProxy(target string, dialTimeout time.Duration) *Proxy {
+	return &Proxy{target: target, dialTimeout: dialTimeout}
+}
+
+// ServeTCP dials the target and copies bytes in both directions until both sides are done
+func (p *Proxy) ServeTCP(conn net.Conn) {
+	defer conn.Close()
+
+	backendConn, err := net.DialTimeout("tcp", p.target, p.dialTimeout)
+	if err != nil {
+		log.Errorf("Error while connecting to backend %s: %v", p.target, err)
+		return
+	}
+	defer backendConn.Close()
+
+	errChan := make(chan error, 2)
+	go connCopy(conn, backendConn, errChan)
+	go connCopy(backendConn, conn, errChan)
+
+	for i := 0; i < 2; i++ {
+		if err := <-errChan; err != nil {
+			log.Debugf("Error while forwarding TCP connection from %s to %s: %v", conn.RemoteAddr(), p.target, err)
+		}
+	}
+}
+
+type closeWriter interface {
+	CloseWrite() error
+}
+
+func connCopy(dst, src net.Conn, errChan chan<- error) {
+	_, err := io.Copy(dst, src)
+	errChan <- err
+
+	// Propagate the end of stream to the other side, keeping the opposite direction open.
+	if cw, ok := dst.(closeWriter); ok {
+		cw.CloseWrite()
+	} else {
+		dst.Close()
+	}
+}
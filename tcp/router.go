@@ -0,0 +1,181 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+)
+
+// CatchAllSNI is the SNI host matching every connection, including the non-TLS ones.
+const CatchAllSNI = "*"
+
+// clientHelloTimeout is the maximum duration to wait for the TLS ClientHello of a new connection.
+const clientHelloTimeout = 10 * time.Second
+
+const (
+	recordHeaderLen = 5
+	// maxRecordLen is the maximum length of a TLS plaintext record, holding the whole ClientHello.
+	maxRecordLen = 16384
+)
+
+// Router routes TCP connections to handlers according to the Server Name Indication
+// of their TLS ClientHello, without terminating TLS.
+type Router struct {
+	routes   map[string]Handler
+	catchAll Handler
+}
+
+// NewRouter builds a new Router without any route
+func NewRouter() *Router {
+	return &Router{routes: make(map[string]Handler)}
+}
+
+// AddRoute registers the handler for the given SNI host.
+// The host can be an exact domain, a wildcard domain (*.example.com) or the catch-all *.
+func (r *Router) AddRoute(sniHost string, handler Handler) error {
+	host := types.CanonicalDomain(sniHost)
+	if host == CatchAllSNI {
+		if r.catchAll != nil {
+			return errors.New("a catch-all route is already defined")
+		}
+		r.catchAll = handler
+		return nil
+	}
+
+	if _, exists := r.routes[host]; exists {
+		return fmt.Errorf("a route is already defined for SNI host %s", host)
+	}
+	r.routes[host] = handler
+	return nil
+}
+
+// HasRoutes returns true if at least one route is defined
+func (r *Router) HasRoutes() bool {
+	return r.catchAll != nil || len(r.routes) > 0
+}
+
+// ServeTCP reads the TLS ClientHello of the connection and forwards the connection,
+// including the bytes already read, to the handler matching its SNI.
+// Without any SNI route, the connection is forwarded to the catch-all handler right away,
+// so that the protocols where the server speaks first are not delayed.
+func (r *Router) ServeTCP(conn net.Conn) {
+	if len(r.routes) == 0 && r.catchAll != nil {
+		r.catchAll.ServeTCP(conn)
+		return
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(clientHelloTimeout)); err != nil {
+		log.Debugf("Error while setting read deadline: %v", err)
+	}
+
+	br := bufio.NewReaderSize(conn, recordHeaderLen+maxRecordLen)
+	serverName, err := clientHelloServerName(br)
+	if err != nil {
+		log.Debugf("Error while reading TLS ClientHello from %s: %v", conn.RemoteAddr(), err)
+	}
+
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		log.Debugf("Error while resetting read deadline: %v", err)
+	}
+
+	handler := r.match(serverName)
+	if handler == nil {
+		log.Debugf("No TCP route found for SNI %q, closing connection from %s", serverName, conn.RemoteAddr())
+		conn.Close()
+		return
+	}
+
+	handler.ServeTCP(&Conn{Conn: conn, reader: br})
+}
+
+func (r *Router) match(serverName string) Handler {
+	host := types.CanonicalDomain(serverName)
+	if len(host) > 0 {
+		if handler, ok := r.routes[host]; ok {
+			return handler
+		}
+
+		if i := strings.Index(host, "."); i > 0 {
+			if handler, ok := r.routes["*"+host[i:]]; ok {
+				return handler
+			}
+		}
+	}
+	return r.catchAll
+}
+
+// Conn is a net.Conn whose first bytes have already been read (peeked) by the router.
+type Conn struct {
+	net.Conn
+	reader io.Reader
+}
+
+// Read reads the peeked bytes first, then the underlying connection
+func (c *Conn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// CloseWrite shuts down the writing side of the underlying connection, if supported
+func (c *Conn) CloseWrite() error {
+	if cw, ok := c.Conn.(closeWriter); ok {
+		return cw.CloseWrite()
+	}
+	return c.Conn.Close()
+}
+
+// clientHelloServerName peeks the TLS ClientHello record and returns the requested server name.
+// It returns an empty server name if the connection does not start with a TLS handshake.
+func clientHelloServerName(br *bufio.Reader) (string, error) {
+	const recordTypeHandshake = 0x16
+
+	hdr, err := br.Peek(1)
+	if err != nil {
+		return "", err
+	}
+	if hdr[0] != recordTypeHandshake {
+		return "", nil
+	}
+
+	hdr, err = br.Peek(recordHeaderLen)
+	if err != nil {
+		return "", err
+	}
+	recordLen := int(hdr[3])<<8 | int(hdr[4])
+
+	helloBytes, err := br.Peek(recordHeaderLen + recordLen)
+	if err != nil {
+		return "", err
+	}
+
+	var serverName string
+	sniffer := tls.Server(sniffConn{reader: bytes.NewReader(helloBytes)}, &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverName = hello.ServerName
+			return nil, errSniffed
+		},
+	})
+	// The handshake always fails: it is only used to parse the ClientHello.
+	sniffer.Handshake()
+
+	return serverName, nil
+}
+
+var errSniffed = errors.New("client hello sniffed")
+
+// sniffConn is a read-only net.Conn used to feed the peeked ClientHello to the TLS parser.
+type sniffConn struct {
+	net.Conn
+	reader io.Reader
+}
+
+func (c sniffConn) Read(p []byte) (int, error)  { return c.reader.Read(p) }
+func (c sniffConn) Write(p []byte) (int, error) { return 0, io.EOF }
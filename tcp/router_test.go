@@ -0,0 +1,183 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterServeTCP(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		routes     []string
+		serverName string
+		nextProtos []string
+		noTLS      bool
+		expected   string
+	}{
+		{
+			desc:       "exact SNI match",
+			routes:     []string{"foo.bar", "bar.foo"},
+			serverName: "foo.bar",
+			expected:   "foo.bar",
+		},
+		{
+			desc:       "SNI match is case insensitive",
+			routes:     []string{"Foo.Bar"},
+			serverName: "foo.bar",
+			expected:   "Foo.Bar",
+		},
+		{
+			desc:       "ClientHello larger than the default buffer",
+			routes:     []string{"foo.bar", "*"},
+			serverName: "foo.bar",
+			nextProtos: largeNextProtos(),
+			expected:   "foo.bar",
+		},
+		{
+			desc:       "wildcard SNI match",
+			routes:     []string{"*.bar", "*"},
+			serverName: "foo.bar",
+			expected:   "*.bar",
+		},
+		{
+			desc:       "catch-all SNI match",
+			routes:     []string{"foo.bar", "*"},
+			serverName: "other.bar",
+			expected:   "*",
+		},
+		{
+			desc:     "catch-all without TLS",
+			routes:   []string{"foo.bar", "*"},
+			noTLS:    true,
+			expected: "*",
+		},
+		{
+			desc:       "no match",
+			routes:     []string{"foo.bar"},
+			serverName: "other.bar",
+			expected:   "",
+		},
+		{
+			desc:     "no match without TLS",
+			routes:   []string{"foo.bar"},
+			noTLS:    true,
+			expected: "",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			matched := make(chan string, 1)
+			router := NewRouter()
+			for _, route := range test.routes {
+				route := route
+				err := router.AddRoute(route, HandlerFunc(func(conn net.Conn) {
+					defer conn.Close()
+					matched <- route
+				}))
+				require.NoError(t, err)
+			}
+
+			client, server := net.Pipe()
+			go router.ServeTCP(server)
+
+			if test.noTLS {
+				go client.Write([]byte("GET / HTTP/1.1\r\n\r\n"))
+			} else {
+				go tls.Client(client, &tls.Config{ServerName: test.serverName, NextProtos: test.nextProtos, InsecureSkipVerify: true}).Handshake()
+			}
+
+			select {
+			case route := <-matched:
+				assert.Equal(t, test.expected, route)
+			case <-time.After(time.Second):
+				assert.Empty(t, test.expected, "no route matched")
+			}
+			client.Close()
+		})
+	}
+}
+
+func TestRouterAddRouteDuplicate(t *testing.T) {
+	router := NewRouter()
+	handler := HandlerFunc(func(conn net.Conn) {})
+
+	require.NoError(t, router.AddRoute("foo.bar", handler))
+	require.NoError(t, router.AddRoute("*", handler))
+	assert.True(t, router.HasRoutes())
+
+	assert.Error(t, router.AddRoute("FOO.bar", handler))
+	assert.Error(t, router.AddRoute("*", handler))
+}
+
+func TestRouterForwardsPeekedBytes(t *testing.T) {
+	router := NewRouter()
+	received := make(chan string, 1)
+	err := router.AddRoute("*", HandlerFunc(func(conn net.Conn) {
+		defer conn.Close()
+		data, _ := ioutil.ReadAll(conn)
+		received <- string(data)
+	}))
+	require.NoError(t, err)
+
+	client, server := net.Pipe()
+	go router.ServeTCP(server)
+
+	_, err = client.Write([]byte("PING"))
+	require.NoError(t, err)
+	client.Close()
+
+	select {
+	case data := <-received:
+		assert.Equal(t, "PING", data)
+	case <-time.After(time.Second):
+		t.Fatal("connection not forwarded")
+	}
+}
+
+func TestRouterCatchAllServerFirst(t *testing.T) {
+	router := NewRouter()
+	err := router.AddRoute("*", HandlerFunc(func(conn net.Conn) {
+		defer conn.Close()
+		conn.Write([]byte("220 ready\r\n"))
+	}))
+	require.NoError(t, err)
+
+	client, server := net.Pipe()
+	defer client.Close()
+	go router.ServeTCP(server)
+
+	// The client waits for the greeting of the server, without sending anything first.
+	received := make(chan string, 1)
+	go func() {
+		data, _ := ioutil.ReadAll(client)
+		received <- string(data)
+	}()
+
+	select {
+	case data := <-received:
+		assert.Equal(t, "220 ready\r\n", data)
+	case <-time.After(time.Second):
+		t.Fatal("the greeting of the server was not forwarded")
+	}
+}
+
+// largeNextProtos returns ALPN protocols making a ClientHello of about 8KB.
+func largeNextProtos() []string {
+	var protos []string
+	for i := 0; i < 40; i++ {
+		protos = append(protos, fmt.Sprintf("%03d%s", i, strings.Repeat("x", 197)))
+	}
+	return protos
+}
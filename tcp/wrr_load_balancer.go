@@ -0,0 +1,53 @@
+package tcp
+
+import (
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+// WRRLoadBalancer is a weighted round robin load balancer of TCP servers.
+// It implements the healthcheck.LoadBalancer interface, so its servers can be health checked.
+type WRRLoadBalancer struct {
+	rr          *roundrobin.RoundRobin
+	dialTimeout time.Duration
+}
+
+// NewWRRLoadBalancer creates a new WRRLoadBalancer without any server
+func NewWRRLoadBalancer(dialTimeout time.Duration) (*WRRLoadBalancer, error) {
+	// The round robin is only used to pick the next server, it never serves HTTP requests.
+	rr, err := roundrobin.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &WRRLoadBalancer{rr: rr, dialTimeout: dialTimeout}, nil
+}
+
+// ServeTCP forwards the connection to the next server
+func (b *WRRLoadBalancer) ServeTCP(conn net.Conn) {
+	u, err := b.rr.NextServer()
+	if err != nil {
+		log.Errorf("Error getting next TCP server: %v", err)
+		conn.Close()
+		return
+	}
+	NewProxy(u.Host, b.dialTimeout).ServeTCP(conn)
+}
+
+// UpsertServer adds or updates a server
+func (b *WRRLoadBalancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	return b.rr.UpsertServer(u, options...)
+}
+
+// RemoveServer removes a server
+func (b *WRRLoadBalancer) RemoveServer(u *url.URL) error {
+	return b.rr.RemoveServer(u)
+}
+
+// Servers returns the list of servers
+func (b *WRRLoadBalancer) Servers() []*url.URL {
+	return b.rr.Servers()
+}
@@ -0,0 +1,79 @@
+package tcp
+
+import (
+	"io/ioutil"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestWRRLoadBalancer(t *testing.T) {
+	lb, err := NewWRRLoadBalancer(time.Second)
+	require.NoError(t, err)
+
+	first := newEchoServer(t, "first")
+	defer first.Close()
+	second := newEchoServer(t, "second")
+	defer second.Close()
+
+	require.NoError(t, lb.UpsertServer(&url.URL{Scheme: "tcp", Host: first.Addr().String()}, roundrobin.Weight(3)))
+	require.NoError(t, lb.UpsertServer(&url.URL{Scheme: "tcp", Host: second.Addr().String()}, roundrobin.Weight(1)))
+	assert.Len(t, lb.Servers(), 2)
+
+	counts := map[string]int{}
+	for i := 0; i < 8; i++ {
+		counts[roundTrip(t, lb)]++
+	}
+	assert.Equal(t, map[string]int{"first": 6, "second": 2}, counts)
+
+	require.NoError(t, lb.RemoveServer(&url.URL{Scheme: "tcp", Host: first.Addr().String()}))
+	assert.Equal(t, "second", roundTrip(t, lb))
+}
+
+func TestWRRLoadBalancerNoServer(t *testing.T) {
+	lb, err := NewWRRLoadBalancer(time.Second)
+	require.NoError(t, err)
+
+	client, server := net.Pipe()
+	go lb.ServeTCP(server)
+
+	data, err := ioutil.ReadAll(client)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func roundTrip(t *testing.T, handler Handler) string {
+	t.Helper()
+
+	client, server := net.Pipe()
+	go handler.ServeTCP(server)
+
+	data, err := ioutil.ReadAll(client)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// newEchoServer starts a TCP server writing its name to every connection before closing it.
+func newEchoServer(t *testing.T, name string) net.Listener {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte(name))
+			conn.Close()
+		}
+	}()
+	return listener
+}
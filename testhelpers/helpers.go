@@ -3,8 +3,11 @@ package testhelpers
 import (
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+
+	"golang.org/x/net/http2"
 )
 
 // Intp returns a pointer to the given integer value.
@@ -34,3 +37,24 @@ func MustParseURL(rawURL string) *url.URL {
 	}
 	return u
 }
+
+// NewH2CServer starts a server speaking HTTP/2 over cleartext TCP (h2c) with prior knowledge.
+// The server is stopped by closing the returned listener.
+func NewH2CServer(handler http.Handler) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("failed to listen: %s", err))
+	}
+
+	server := &http2.Server{}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.ServeConn(conn, &http2.ServeConnOpts{Handler: handler})
+		}
+	}()
+	return listener
+}
@@ -4,12 +4,15 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/abronan/valkeyrie/store"
 	"github.com/containous/flaeg"
@@ -20,12 +23,13 @@ import (
 
 // Backend holds backend configuration.
 type Backend struct {
-	Servers        map[string]Server `json:"servers,omitempty"`
-	CircuitBreaker *CircuitBreaker   `json:"circuitBreaker,omitempty"`
-	LoadBalancer   *LoadBalancer     `json:"loadBalancer,omitempty"`
-	MaxConn        *MaxConn          `json:"maxConn,omitempty"`
-	HealthCheck    *HealthCheck      `json:"healthCheck,omitempty"`
-	Buffering      *Buffering        `json:"buffering,omitempty"`
+	Servers          map[string]Server `json:"servers,omitempty"`
+	CircuitBreaker   *CircuitBreaker   `json:"circuitBreaker,omitempty"`
+	LoadBalancer     *LoadBalancer     `json:"loadBalancer,omitempty"`
+	MaxConn          *MaxConn          `json:"maxConn,omitempty"`
+	HealthCheck      *HealthCheck      `json:"healthCheck,omitempty"`
+	OutlierDetection *OutlierDetection `json:"outlierDetection,omitempty"`
+	Buffering        *Buffering        `json:"buffering,omitempty"`
 }
 
 // MaxConn holds maximum connection configuration
@@ -36,9 +40,10 @@ type MaxConn struct {
 
 // LoadBalancer holds load balancing configuration.
 type LoadBalancer struct {
-	Method     string      `json:"method,omitempty"`
-	Sticky     bool        `json:"sticky,omitempty"` // Deprecated: use Stickiness instead
-	Stickiness *Stickiness `json:"stickiness,omitempty"`
+	Method         string             `json:"method,omitempty"`
+	Sticky         bool               `json:"sticky,omitempty"` // Deprecated: use Stickiness instead
+	Stickiness     *Stickiness        `json:"stickiness,omitempty"`
+	ConsistentHash *ConsistentHashing `json:"consistentHash,omitempty"`
 }
 
 // Stickiness holds sticky session configuration.
@@ -46,6 +51,12 @@ type Stickiness struct {
 	CookieName string `json:"cookieName,omitempty"`
 }
 
+// ConsistentHashing holds the configuration of the ConsistentHash load balancing method.
+type ConsistentHashing struct {
+	ExtractorFunc string  `json:"extractorFunc,omitempty"`
+	LoadFactor    float64 `json:"loadFactor,omitempty"`
+}
+
 // CircuitBreaker holds circuit breaker configuration.
 type CircuitBreaker struct {
 	Expression string `json:"expression,omitempty"`
@@ -62,9 +73,26 @@ type Buffering struct {
 
 // HealthCheck holds HealthCheck configuration
 type HealthCheck struct {
-	Path     string `json:"path,omitempty"`
-	Port     int    `json:"port,omitempty"`
-	Interval string `json:"interval,omitempty"`
+	Mode               string            `json:"mode,omitempty"`
+	Scheme             string            `json:"scheme,omitempty"`
+	Path               string            `json:"path,omitempty"`
+	Port               int               `json:"port,omitempty"`
+	Interval           string            `json:"interval,omitempty"`
+	Timeout            string            `json:"timeout,omitempty"`
+	Hostname           string            `json:"hostname,omitempty"`
+	Headers            map[string]string `json:"headers,omitempty"`
+	Status             []string          `json:"status,omitempty"`
+	BodyRegex          string            `json:"bodyRegex,omitempty"`
+	HealthyThreshold   int               `json:"healthyThreshold,omitempty"`
+	UnhealthyThreshold int               `json:"unhealthyThreshold,omitempty"`
+}
+
+// OutlierDetection holds passive health check configuration
+type OutlierDetection struct {
+	ConsecutiveErrors  int    `json:"consecutiveErrors,omitempty"`
+	BaseEjectionTime   string `json:"baseEjectionTime,omitempty"`
+	MaxEjectionTime    string `json:"maxEjectionTime,omitempty"`
+	MaxEjectionPercent int    `json:"maxEjectionPercent,omitempty"`
 }
 
 // Server holds server configuration.
@@ -96,6 +124,7 @@ type Rate struct {
 type RateLimit struct {
 	RateSet       map[string]*Rate `json:"rateset,omitempty"`
 	ExtractorFunc string           `json:"extractorFunc,omitempty"`
+	Distributed   bool             `json:"distributed,omitempty"`
 }
 
 // Headers holds the custom header configuration
@@ -164,6 +193,81 @@ type Frontend struct {
 	Errors               map[string]*ErrorPage `json:"errors,omitempty"`
 	RateLimit            *RateLimit            `json:"ratelimit,omitempty"`
 	Redirect             *Redirect             `json:"redirect,omitempty"`
+	Mirroring            *Mirroring            `json:"mirroring,omitempty"`
+	WeightedBackends     *WeightedBackends     `json:"weightedBackends,omitempty"`
+	AccessLog            *FrontendAccessLog    `json:"accessLog,omitempty"`
+	Retry                *Retry                `json:"retry,omitempty"`
+	Compression          *Compression          `json:"compression,omitempty"`
+	Cache                *Cache                `json:"cache,omitempty"`
+	Auth                 *Auth                 `json:"auth,omitempty"`
+	TLSClientCert        *TLSClientCert        `json:"tlsClientCert,omitempty"`
+}
+
+// TLSClientCert forwards the TLS client certificate of the requests to the backend,
+// and restricts the certificates allowed to access a frontend.
+type TLSClientCert struct {
+	PEM             bool     `json:"pem,omitempty"`
+	Fields          []string `json:"fields,omitempty"`
+	AllowedSubjects []string `json:"allowedSubjects,omitempty"`
+	AllowedSANs     []string `json:"allowedSANs,omitempty"`
+}
+
+// WeightedBackends spreads the requests of a frontend on several backends, proportionally to their weights.
+// When set, it replaces the backend of the frontend.
+type WeightedBackends struct {
+	Backends   map[string]int `json:"backends,omitempty"`
+	Stickiness *Stickiness    `json:"stickiness,omitempty"`
+}
+
+// Names returns the sorted names of the weighted backends
+func (w *WeightedBackends) Names() []string {
+	var names []string
+	for name := range w.Backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Mirroring configures the duplication of a percentage of the requests of a frontend to a mirror backend.
+// The responses of the mirror backend are discarded.
+type Mirroring struct {
+	Backend     string `json:"backend,omitempty"`
+	Percent     int    `json:"percent,omitempty"`
+	MaxBodySize int64  `json:"maxBodySize,omitempty"`
+}
+
+// Retry configures the retries of the requests of a frontend, it overrides the global retry configuration.
+// The requests are retried on network errors, and on the responses with a retriable status code.
+// Only the requests with an idempotent method are retried, unless other methods are specified.
+type Retry struct {
+	Attempts        int            `json:"attempts,omitempty"`
+	StatusCodes     StatusCodes    `json:"statusCodes,omitempty"`
+	Methods         []string       `json:"methods,omitempty"`
+	InitialInterval flaeg.Duration `json:"initialInterval,omitempty"`
+	MaxInterval     flaeg.Duration `json:"maxInterval,omitempty"`
+	Budget          float64        `json:"budget,omitempty"`
+}
+
+// Compression configures the compression of the responses, negotiated with the Accept-Encoding header of the requests.
+// The zero values stand for the default settings.
+type Compression struct {
+	Encodings            []string `json:"encodings,omitempty" description:"Enabled encodings by order of preference: br, gzip" export:"true"`
+	MinSize              int      `json:"minSize,omitempty" description:"Minimum size in bytes of the compressed responses" export:"true"`
+	GzipLevel            int      `json:"gzipLevel,omitempty" description:"Gzip compression level, from 1 to 9" export:"true"`
+	BrotliLevel          int      `json:"brotliLevel,omitempty" description:"Brotli compression level, from 1 to 11" export:"true"`
+	IncludedContentTypes []string `json:"includedContentTypes,omitempty" description:"Compress only the responses with these content types, such as text/html or text/*" export:"true"`
+	ExcludedContentTypes []string `json:"excludedContentTypes,omitempty" description:"Do not compress the responses with these content types" export:"true"`
+}
+
+// Cache configures the caching of the responses of a frontend, according to their Cache-Control, Expires and Vary headers.
+// The zero values stand for the default settings.
+type Cache struct {
+	Storage     string         `json:"storage,omitempty" description:"Storage of the cached responses: memory or disk" export:"true"`
+	Path        string         `json:"path,omitempty" description:"Directory of the disk storage"`
+	MaxEntries  int            `json:"maxEntries,omitempty" description:"Maximum number of cached responses" export:"true"`
+	MaxBodySize int64          `json:"maxBodySize,omitempty" description:"Maximum size in bytes of a cached response body" export:"true"`
+	DefaultTTL  flaeg.Duration `json:"defaultTTL,omitempty" description:"Lifetime of the responses without freshness information, not cached when zero" export:"true"`
 }
 
 // Redirect configures a redirection of an entry point to another, or to an URL
@@ -182,11 +286,23 @@ const (
 	Wrr LoadBalancerMethod = iota
 	// Drr = Dynamic Round Robin
 	Drr
+	// LeastConn = Least Connections
+	LeastConn
+	// PowerOfTwoChoices = Power of Two Random Choices
+	PowerOfTwoChoices
+	// EWMA = Exponentially Weighted Moving Average of the latency
+	EWMA
+	// ConsistentHash = Consistent Hashing with Bounded Loads
+	ConsistentHash
 )
 
 var loadBalancerMethodNames = []string{
 	"Wrr",
 	"Drr",
+	"LeastConn",
+	"PowerOfTwoChoices",
+	"EWMA",
+	"ConsistentHash",
 }
 
 // NewLoadBalancerMethod create a new LoadBalancerMethod from a given LoadBalancer.
@@ -219,6 +335,15 @@ type ConfigMessage struct {
 	Configuration *Configuration
 }
 
+// RejectedConfiguration is a configuration of a provider rejected by the strict reload mode,
+// with the reasons of the rejection.
+type RejectedConfiguration struct {
+	ProviderName  string         `json:"provider"`
+	Date          time.Time      `json:"date"`
+	Reasons       []string       `json:"reasons"`
+	Configuration *Configuration `json:"configuration"`
+}
+
 // Constraint hold a parsed constraint expression
 type Constraint struct {
 	Key string `export:"true"`
@@ -346,10 +471,12 @@ type Cluster struct {
 
 // Auth holds authentication configuration (BASIC, DIGEST, users)
 type Auth struct {
-	Basic       *Basic   `export:"true"`
-	Digest      *Digest  `export:"true"`
-	Forward     *Forward `export:"true"`
-	HeaderField string   `export:"true"`
+	Basic       *Basic   `json:"basic,omitempty" export:"true"`
+	Digest      *Digest  `json:"digest,omitempty" export:"true"`
+	Forward     *Forward `json:"forward,omitempty" export:"true"`
+	JWT         *JWT     `json:"jwt,omitempty" export:"true"`
+	OIDC        *OIDC    `json:"oidc,omitempty" export:"true"`
+	HeaderField string   `json:"headerField,omitempty" export:"true"`
 }
 
 // Users authentication users
@@ -369,9 +496,69 @@ type Digest struct {
 
 // Forward authentication
 type Forward struct {
-	Address            string     `description:"Authentication server address"`
-	TLS                *ClientTLS `description:"Enable TLS support" export:"true"`
-	TrustForwardHeader bool       `description:"Trust X-Forwarded-* headers" export:"true"`
+	Address             string        `description:"Authentication server address"`
+	TLS                 *ClientTLS    `description:"Enable TLS support" export:"true"`
+	TrustForwardHeader  bool          `description:"Trust X-Forwarded-* headers" export:"true"`
+	AuthResponseHeaders []string      `description:"Headers copied from the authentication server response to the forwarded request" export:"true"`
+	ForwardBody         bool          `description:"Forward the request body to the authentication server" export:"true"`
+	MaxBodySize         int64         `description:"Maximum size in bytes of the request bodies forwarded to the authentication server" export:"true"`
+	Cache               *ForwardCache `description:"Cache the allow decisions of the authentication server" export:"true"`
+}
+
+// ForwardCache caches the allow decisions of a forward authentication server,
+// the requests with the same key attributes getting the same decision.
+type ForwardCache struct {
+	TTL        flaeg.Duration `description:"Duration of the cached decisions" export:"true"`
+	Key        []string       `description:"Request attributes identifying the cached decisions: method, host, path, clientIP, header:<name> or cookie:<name>" export:"true"`
+	MaxEntries int            `description:"Maximum number of cached decisions" export:"true"`
+}
+
+// JWT authentication, verifying the signature and the claims of the bearer tokens of the requests.
+// The signatures are verified with the secret (HMAC), the public keys (RSA and ECDSA), or the keys of a JSON Web Key Set.
+type JWT struct {
+	Secret              string            `json:"secret,omitempty" description:"Secret verifying the HMAC signatures"`
+	PublicKeys          []string          `json:"publicKeys,omitempty" description:"PEM encoded RSA or ECDSA public keys, or files holding them"`
+	JWKS                string            `json:"jwks,omitempty" description:"URL or file of the JSON Web Key Set" export:"true"`
+	JWKSRefreshInterval flaeg.Duration    `json:"jwksRefreshInterval,omitempty" description:"Interval between two reloads of the JSON Web Key Set" export:"true"`
+	Issuer              string            `json:"issuer,omitempty" description:"Required issuer (iss claim)" export:"true"`
+	Audiences           []string          `json:"audiences,omitempty" description:"Accepted audiences (aud claim)" export:"true"`
+	ClockSkew           flaeg.Duration    `json:"clockSkew,omitempty" description:"Tolerance of the validation of the exp and nbf claims" export:"true"`
+	AllowNoExpiration   bool              `json:"allowNoExpiration,omitempty" description:"Accept the tokens without exp claim" export:"true"`
+	ClaimHeaders        map[string]string `json:"claimHeaders,omitempty" description:"Request headers set with the values of the claims, by header name" export:"true"`
+}
+
+// MarshalJSON encodes the JWT authentication without its secret, the configurations being exposed by the API and in the logs.
+func (j JWT) MarshalJSON() ([]byte, error) {
+	type jwt JWT
+	redacted := jwt(j)
+	redacted.Secret = ""
+	return json.Marshal(redacted)
+}
+
+// OIDC authentication, logging the users in with the authorization code flow of an OpenID Connect provider.
+// The users are authorized by the domain of their email address, or by their groups.
+type OIDC struct {
+	Issuer          string         `json:"issuer,omitempty" description:"URL of the OpenID Connect provider, serving its discovery document" export:"true"`
+	ClientID        string         `json:"clientId,omitempty" description:"Client ID registered at the provider" export:"true"`
+	ClientSecret    string         `json:"clientSecret,omitempty" description:"Client secret registered at the provider"`
+	Scopes          []string       `json:"scopes,omitempty" description:"Requested scopes" export:"true"`
+	CallbackPath    string         `json:"callbackPath,omitempty" description:"Path of the redirect URL registered at the provider" export:"true"`
+	SessionSecret   string         `json:"sessionSecret,omitempty" description:"Secret encrypting the session cookies"`
+	SessionLifetime flaeg.Duration `json:"sessionLifetime,omitempty" description:"Duration after which the users have to log in again" export:"true"`
+	CookieName      string         `json:"cookieName,omitempty" description:"Name of the session cookie" export:"true"`
+	CookieDomain    string         `json:"cookieDomain,omitempty" description:"Domain of the session cookie" export:"true"`
+	AllowedDomains  []string       `json:"allowedDomains,omitempty" description:"Allowed domains of the email addresses" export:"true"`
+	AllowedGroups   []string       `json:"allowedGroups,omitempty" description:"Allowed groups" export:"true"`
+	GroupsClaim     string         `json:"groupsClaim,omitempty" description:"Claim of the ID tokens holding the groups of the users" export:"true"`
+}
+
+// MarshalJSON encodes the OIDC authentication without its secrets, the configurations being exposed by the API and in the logs.
+func (o OIDC) MarshalJSON() ([]byte, error) {
+	type oidc OIDC
+	redacted := oidc(o)
+	redacted.ClientSecret = ""
+	redacted.SessionSecret = ""
+	return json.Marshal(redacted)
 }
 
 // CanonicalDomain returns a lower case domain with trim space
@@ -456,8 +643,168 @@ type TraefikLog struct {
 
 // AccessLog holds the configuration settings for the access logger (middlewares/accesslog).
 type AccessLog struct {
-	FilePath string `json:"file,omitempty" description:"Access log file path. Stdout is used when omitted or empty" export:"true"`
-	Format   string `json:"format,omitempty" description:"Access log format: json | common" export:"true"`
+	FilePath string            `json:"file,omitempty" description:"Access log file path. Stdout is used when omitted or empty, unless the access logs are shipped" export:"true"`
+	Format   string            `json:"format,omitempty" description:"Access log format: json | common" export:"true"`
+	Filters  *AccessLogFilters `json:"filters,omitempty" description:"Access log filters, used to keep only specific access logs" export:"true"`
+	Fields   *AccessLogFields  `json:"fields,omitempty" description:"Access log fields and headers to keep, drop or redact" export:"true"`
+	Buffer   *AccessLogBuffer  `json:"buffer,omitempty" description:"Write the access logs asynchronously through a memory buffer" export:"true"`
+	Syslog   *AccessLogSyslog  `json:"syslog,omitempty" description:"Ship the access logs to a syslog server (RFC 5424)" export:"true"`
+	HTTP     *AccessLogHTTP    `json:"http,omitempty" description:"Ship the access logs to an HTTP collector, as batches of JSON" export:"true"`
+}
+
+// AccessLogSyslog holds the settings of the shipping of the access logs to a syslog server.
+type AccessLogSyslog struct {
+	Network  string `json:"network,omitempty" description:"Network of the syslog server: tcp | udp" export:"true"`
+	Address  string `json:"address,omitempty" description:"Address of the syslog server"`
+	Facility string `json:"facility,omitempty" description:"Syslog facility of the access logs" export:"true"`
+	AppName  string `json:"appName,omitempty" description:"Application name of the access logs" export:"true"`
+	Retries  int    `json:"retries,omitempty" description:"Number of retries of the batches that failed to be sent" export:"true"`
+}
+
+// AccessLogHTTP holds the settings of the shipping of the access logs to an HTTP collector.
+type AccessLogHTTP struct {
+	URL     string         `json:"url,omitempty" description:"URL of the HTTP collector"`
+	Timeout flaeg.Duration `json:"timeout,omitempty" description:"Timeout of the requests to the HTTP collector" export:"true"`
+	Retries int            `json:"retries,omitempty" description:"Number of retries of the batches that failed to be sent" export:"true"`
+}
+
+// AccessLogBuffer holds the settings of the buffer of the asynchronous access log writer.
+type AccessLogBuffer struct {
+	Size          int            `json:"size,omitempty" description:"Number of access logs held in memory before being written" export:"true"`
+	FlushInterval flaeg.Duration `json:"flushInterval,omitempty" description:"Interval between the writes of the buffered access logs" export:"true"`
+	OnFull        string         `json:"onFull,omitempty" description:"Behavior when the buffer is full: block | drop" export:"true"`
+}
+
+// FrontendAccessLog overrides the access log filters and fields for the requests of a frontend.
+type FrontendAccessLog struct {
+	Filters *AccessLogFilters `json:"filters,omitempty"`
+	Fields  *AccessLogFields  `json:"fields,omitempty"`
+}
+
+// AccessLogFilters holds the filters of the access logs. A request is logged when it matches at least one of the filters.
+type AccessLogFilters struct {
+	StatusCodes   StatusCodes    `json:"statusCodes,omitempty" description:"Keep the access logs with a status code in the specified ranges" export:"true"`
+	RetryAttempts bool           `json:"retryAttempts,omitempty" description:"Keep the access logs of the retried requests" export:"true"`
+	MinDuration   flaeg.Duration `json:"minDuration,omitempty" description:"Keep the access logs of the requests taking longer than the specified duration" export:"true"`
+}
+
+// StatusCodes holds status codes or ranges of status codes, such as "200" or "500-599"
+type StatusCodes []string
+
+// Set adds strings elem into the the parser
+// it splits str on "," and ";"
+func (s *StatusCodes) Set(str string) error {
+	fargs := func(c rune) bool {
+		return c == ',' || c == ';'
+	}
+	*s = append(*s, strings.FieldsFunc(str, fargs)...)
+	return nil
+}
+
+// Get []string
+func (s *StatusCodes) Get() interface{} { return *s }
+
+// String return slice in a string
+func (s *StatusCodes) String() string { return fmt.Sprintf("%v", *s) }
+
+// SetValue sets []string into the parser
+func (s *StatusCodes) SetValue(val interface{}) {
+	*s = val.(StatusCodes)
+}
+
+// StatusRange is an inclusive range of HTTP status codes.
+type StatusRange struct {
+	Low  int
+	High int
+}
+
+// Contains returns true if the status code is in the range.
+func (r StatusRange) Contains(code int) bool {
+	return code >= r.Low && code <= r.High
+}
+
+// Ranges parses the status codes or ranges of status codes, such as "200" or "200-299".
+func (s StatusCodes) Ranges() ([]StatusRange, error) {
+	var ranges []StatusRange
+	for _, value := range s {
+		codes := strings.Split(strings.TrimSpace(value), "-")
+		if len(codes) > 2 {
+			return nil, fmt.Errorf("invalid status code range: %q", value)
+		}
+
+		low, err := strconv.Atoi(codes[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %q: %s", value, err)
+		}
+		high := low
+		if len(codes) == 2 {
+			high, err = strconv.Atoi(codes[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid status code %q: %s", value, err)
+			}
+		}
+
+		if low < 100 || high > 599 || low > high {
+			return nil, fmt.Errorf("invalid status code range: %q", value)
+		}
+		ranges = append(ranges, StatusRange{Low: low, High: high})
+	}
+	return ranges, nil
+}
+
+const (
+	// AccessLogKeep is the mode keeping a field or a header in the access logs
+	AccessLogKeep = "keep"
+	// AccessLogDrop is the mode removing a field or a header from the access logs
+	AccessLogDrop = "drop"
+	// AccessLogRedact is the mode replacing the value of a field or a header by "REDACTED" in the access logs
+	AccessLogRedact = "redact"
+)
+
+// AccessLogFields holds the modes of the fields and of the headers of the access logs.
+type AccessLogFields struct {
+	DefaultMode string        `json:"defaultMode,omitempty" description:"Default mode of the fields: keep | drop | redact" export:"true"`
+	Names       FieldNames    `json:"names,omitempty" description:"Override the mode of the fields" export:"true"`
+	Headers     *FieldHeaders `json:"headers,omitempty" description:"Headers to keep, drop or redact" export:"true"`
+}
+
+// FieldHeaders holds the modes of the request and response headers of the access logs.
+type FieldHeaders struct {
+	DefaultMode string     `json:"defaultMode,omitempty" description:"Default mode of the headers: keep | drop | redact" export:"true"`
+	Names       FieldNames `json:"names,omitempty" description:"Override the mode of the headers" export:"true"`
+}
+
+// FieldNames maps the names of access log fields or headers to their modes
+type FieldNames map[string]string
+
+// Set adds strings elem into the the parser
+// it splits str on " ", "," and ";", each element being formatted as name=mode
+func (f *FieldNames) Set(str string) error {
+	fargs := func(c rune) bool {
+		return c == ' ' || c == ',' || c == ';'
+	}
+	if *f == nil {
+		*f = make(FieldNames)
+	}
+	for _, field := range strings.FieldsFunc(str, fargs) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid field mode %q, must be formatted as name=mode", field)
+		}
+		(*f)[parts[0]] = parts[1]
+	}
+	return nil
+}
+
+// Get map[string]string
+func (f *FieldNames) Get() interface{} { return *f }
+
+// String return map in a string
+func (f *FieldNames) String() string { return fmt.Sprintf("%v", *f) }
+
+// SetValue sets map[string]string into the parser
+func (f *FieldNames) SetValue(val interface{}) {
+	*f = val.(FieldNames)
 }
 
 // ClientTLS holds TLS specific configurations as client
@@ -1,9 +1,11 @@
 package types
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHeaders_ShouldReturnFalseWhenNotHasCustomHeadersDefined(t *testing.T) {
@@ -35,3 +37,113 @@ func TestHeaders_ShouldReturnTrueWhenHasSecureHeadersDefined(t *testing.T) {
 
 	assert.True(t, headers.HasSecureHeadersDefined())
 }
+
+func TestStatusCodesSet(t *testing.T) {
+	var statusCodes StatusCodes
+	err := statusCodes.Set("200,300-302;500-599")
+	assert.NoError(t, err)
+	assert.Equal(t, StatusCodes{"200", "300-302", "500-599"}, statusCodes)
+}
+
+func TestFieldNamesSet(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		value         string
+		expected      FieldNames
+		expectedError bool
+	}{
+		{
+			desc:     "separated by spaces, commas and semicolons",
+			value:    "ClientUsername=drop RequestHost=redact,Authorization=keep;User-Agent=drop",
+			expected: FieldNames{"ClientUsername": "drop", "RequestHost": "redact", "Authorization": "keep", "User-Agent": "drop"},
+		},
+		{
+			desc:          "missing mode",
+			value:         "ClientUsername",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var names FieldNames
+			err := names.Set(test.value)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, names)
+		})
+	}
+}
+
+func TestAuthMarshalJSONRedactsSecrets(t *testing.T) {
+	auth := &Auth{
+		JWT:  &JWT{Secret: "jwtsecret", Issuer: "https://auth.example.com"},
+		OIDC: &OIDC{ClientID: "dashboard", ClientSecret: "clientsecret", SessionSecret: "sessionsecret"},
+	}
+
+	content, err := json.Marshal(auth)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"jwt":{"issuer":"https://auth.example.com"},"oidc":{"clientId":"dashboard"}}`, string(content))
+	assert.Equal(t, "jwtsecret", auth.JWT.Secret)
+
+	decoded := &Auth{}
+	require.NoError(t, json.Unmarshal([]byte(`{"jwt":{"secret":"jwtsecret"},"oidc":{"clientSecret":"clientsecret","sessionSecret":"sessionsecret"}}`), decoded))
+	assert.Equal(t, "jwtsecret", decoded.JWT.Secret)
+	assert.Equal(t, "clientsecret", decoded.OIDC.ClientSecret)
+	assert.Equal(t, "sessionsecret", decoded.OIDC.SessionSecret)
+}
+
+func TestStatusCodesRanges(t *testing.T) {
+	tests := []struct {
+		desc      string
+		values    StatusCodes
+		expected  []StatusRange
+		wantError bool
+	}{
+		{
+			desc: "no status",
+		},
+		{
+			desc:     "codes and ranges",
+			values:   StatusCodes{"200-299", " 302 "},
+			expected: []StatusRange{{Low: 200, High: 299}, {Low: 302, High: 302}},
+		},
+		{
+			desc:      "invalid code",
+			values:    StatusCodes{"2xx"},
+			wantError: true,
+		},
+		{
+			desc:      "inverted range",
+			values:    StatusCodes{"299-200"},
+			wantError: true,
+		},
+		{
+			desc:      "out of bounds range",
+			values:    StatusCodes{"200-600"},
+			wantError: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ranges, err := test.values.Ranges()
+			if test.wantError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, ranges)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package udp
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/containous/traefik/safe"
+)
+
+// BalancerSwitcher allows hot switching of the balancer of a UDP entry point
+type BalancerSwitcher struct {
+	balancer *safe.Safe
+}
+
+// NewBalancerSwitcher builds a new instance of BalancerSwitcher, the balancer can be nil
+func NewBalancerSwitcher(newBalancer Balancer) *BalancerSwitcher {
+	return &BalancerSwitcher{
+		balancer: safe.New(newBalancer),
+	}
+}
+
+// NextServer returns the next server of the current balancer
+func (bs *BalancerSwitcher) NextServer() (*url.URL, error) {
+	balancer := bs.GetBalancer()
+	if balancer == nil {
+		return nil, errors.New("no backend defined")
+	}
+	return balancer.NextServer()
+}
+
+// GetBalancer returns the current balancer
+func (bs *BalancerSwitcher) GetBalancer() Balancer {
+	balancer, _ := bs.balancer.Get().(Balancer)
+	return balancer
+}
+
+// UpdateBalancer safely updates the current balancer with a new one
+func (bs *BalancerSwitcher) UpdateBalancer(newBalancer Balancer) {
+	bs.balancer.Set(newBalancer)
+}
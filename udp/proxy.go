@@ -0,0 +1,174 @@
+package udp
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/safe"
+)
+
+// maxDatagramSize is the maximum size of a UDP payload
+const maxDatagramSize = 65535
+
+// Proxy forwards the datagrams received on a UDP entry point to the backend servers.
+// The datagrams sent from the same client address belong to the same session:
+// they are forwarded to the same server until the session stays idle longer than the session timeout.
+type Proxy struct {
+	conn           net.PacketConn
+	balancer       Balancer
+	sessionTimeout time.Duration
+
+	lock     sync.Mutex
+	sessions map[string]*session
+}
+
+// NewProxy creates a new Proxy serving the datagrams of the given connection
+func NewProxy(conn net.PacketConn, balancer Balancer, sessionTimeout time.Duration) *Proxy {
+	return &Proxy{
+		conn:           conn,
+		balancer:       balancer,
+		sessionTimeout: sessionTimeout,
+		sessions:       make(map[string]*session),
+	}
+}
+
+// Serve reads the incoming datagrams until the connection is closed
+func (p *Proxy) Serve() error {
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, clientAddr, err := p.conn.ReadFrom(buf)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				log.Debugf("Temporary error while reading UDP datagram: %s", err)
+				continue
+			}
+			return err
+		}
+
+		sess, err := p.getSession(clientAddr)
+		if err != nil {
+			log.Errorf("Error while creating UDP session for %s: %v", clientAddr, err)
+			continue
+		}
+
+		if err := sess.forward(buf[:n]); err != nil {
+			log.Debugf("Error while forwarding UDP datagram from %s to %s: %v", clientAddr, sess.backendConn.RemoteAddr(), err)
+		}
+	}
+}
+
+// Close closes all the sessions
+func (p *Proxy) Close() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for key, sess := range p.sessions {
+		sess.backendConn.Close()
+		delete(p.sessions, key)
+	}
+}
+
+func (p *Proxy) getSession(clientAddr net.Addr) (*session, error) {
+	key := clientAddr.String()
+
+	p.lock.Lock()
+	sess, ok := p.sessions[key]
+	p.lock.Unlock()
+	if ok {
+		return sess, nil
+	}
+
+	u, err := p.balancer.NextServer()
+	if err != nil {
+		return nil, err
+	}
+
+	// the backend is dialed outside the lock, so that resolving its address does not block the other sessions
+	backendConn, err := net.Dial("udp", u.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	sess = &session{
+		clientAddr:  clientAddr,
+		backendConn: backendConn,
+	}
+
+	p.lock.Lock()
+	if existing, ok := p.sessions[key]; ok {
+		p.lock.Unlock()
+		backendConn.Close()
+		return existing, nil
+	}
+	p.sessions[key] = sess
+	p.lock.Unlock()
+	log.Debugf("New UDP session from %s to %s", clientAddr, u.Host)
+
+	safe.Go(func() {
+		p.replyLoop(key, sess)
+	})
+	return sess, nil
+}
+
+// replyLoop forwards the datagrams received from the backend server to the client,
+// and ends the session once it has been idle longer than the session timeout.
+func (p *Proxy) replyLoop(key string, sess *session) {
+	defer p.closeSession(key, sess)
+
+	buf := make([]byte, maxDatagramSize)
+	for {
+		if err := sess.backendConn.SetReadDeadline(time.Now().Add(p.sessionTimeout)); err != nil {
+			log.Debugf("Error while setting UDP session read deadline: %v", err)
+			return
+		}
+
+		n, err := sess.backendConn.Read(buf)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() && !sess.isIdle(p.sessionTimeout) {
+				continue
+			}
+			return
+		}
+		sess.touch()
+
+		if _, err := p.conn.WriteTo(buf[:n], sess.clientAddr); err != nil {
+			log.Debugf("Error while sending UDP datagram to %s: %v", sess.clientAddr, err)
+		}
+	}
+}
+
+func (p *Proxy) closeSession(key string, sess *session) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.sessions[key] == sess {
+		delete(p.sessions, key)
+	}
+	sess.backendConn.Close()
+	log.Debugf("UDP session from %s closed", sess.clientAddr)
+}
+
+type session struct {
+	// lastActivity is the UnixNano time of the last datagram of the session.
+	// It is accessed atomically, and kept first for 64-bit alignment.
+	lastActivity int64
+	clientAddr   net.Addr
+	backendConn  net.Conn
+}
+
+func (s *session) forward(datagram []byte) error {
+	s.touch()
+	_, err := s.backendConn.Write(datagram)
+	return err
+}
+
+func (s *session) touch() {
+	atomic.StoreInt64(&s.lastActivity, time.Now().UnixNano())
+}
+
+func (s *session) isIdle(timeout time.Duration) bool {
+	return time.Since(time.Unix(0, atomic.LoadInt64(&s.lastActivity))) >= timeout
+}
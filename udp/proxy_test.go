@@ -0,0 +1,155 @@
+package udp
+
+import (
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestProxySessionAffinity(t *testing.T) {
+	first := newEchoServer(t, "first")
+	defer first.Close()
+	second := newEchoServer(t, "second")
+	defer second.Close()
+
+	lb, err := NewWRRLoadBalancer()
+	require.NoError(t, err)
+	require.NoError(t, lb.UpsertServer(&url.URL{Scheme: "udp", Host: first.LocalAddr().String()}, roundrobin.Weight(1)))
+	require.NoError(t, lb.UpsertServer(&url.URL{Scheme: "udp", Host: second.LocalAddr().String()}, roundrobin.Weight(1)))
+
+	proxyAddr, closeProxy := startProxy(t, lb, time.Second)
+	defer closeProxy()
+
+	clientA := dialProxy(t, proxyAddr)
+	defer clientA.Close()
+	clientB := dialProxy(t, proxyAddr)
+	defer clientB.Close()
+
+	responseA := roundTrip(t, clientA, "ping")
+	responseB := roundTrip(t, clientB, "ping")
+	assert.NotEqual(t, responseA, responseB, "sessions should be balanced")
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, responseA, roundTrip(t, clientA, "ping"))
+		assert.Equal(t, responseB, roundTrip(t, clientB, "ping"))
+	}
+}
+
+func TestProxySessionTimeout(t *testing.T) {
+	first := newEchoServer(t, "first")
+	defer first.Close()
+	second := newEchoServer(t, "second")
+	defer second.Close()
+
+	lb, err := NewWRRLoadBalancer()
+	require.NoError(t, err)
+	require.NoError(t, lb.UpsertServer(&url.URL{Scheme: "udp", Host: first.LocalAddr().String()}, roundrobin.Weight(1)))
+	require.NoError(t, lb.UpsertServer(&url.URL{Scheme: "udp", Host: second.LocalAddr().String()}, roundrobin.Weight(1)))
+
+	proxy, proxyAddr, closeProxy := startProxyWithSessions(t, lb, 100*time.Millisecond)
+	defer closeProxy()
+
+	client := dialProxy(t, proxyAddr)
+	defer client.Close()
+
+	response := roundTrip(t, client, "ping")
+	assert.Len(t, sessionKeys(proxy), 1)
+
+	time.Sleep(300 * time.Millisecond)
+	assert.Empty(t, sessionKeys(proxy), "idle session should be closed")
+
+	assert.NotEqual(t, response, roundTrip(t, client, "ping"), "a new session should be balanced to the next server")
+}
+
+func TestProxyNoBackend(t *testing.T) {
+	proxy, proxyAddr, closeProxy := startProxyWithSessions(t, NewBalancerSwitcher(nil), time.Second)
+	defer closeProxy()
+
+	client := dialProxy(t, proxyAddr)
+	defer client.Close()
+
+	_, err := client.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	require.NoError(t, client.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+	_, err = client.Read(make([]byte, 16))
+	assert.Error(t, err)
+	assert.Empty(t, sessionKeys(proxy))
+}
+
+func startProxy(t *testing.T, balancer Balancer, sessionTimeout time.Duration) (string, func()) {
+	_, addr, closeProxy := startProxyWithSessions(t, balancer, sessionTimeout)
+	return addr, closeProxy
+}
+
+func startProxyWithSessions(t *testing.T, balancer Balancer, sessionTimeout time.Duration) (*Proxy, string, func()) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	proxy := NewProxy(conn, balancer, sessionTimeout)
+	go proxy.Serve()
+
+	return proxy, conn.LocalAddr().String(), func() {
+		conn.Close()
+		proxy.Close()
+	}
+}
+
+func sessionKeys(proxy *Proxy) []string {
+	proxy.lock.Lock()
+	defer proxy.lock.Unlock()
+
+	var keys []string
+	for key := range proxy.sessions {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func dialProxy(t *testing.T, addr string) net.Conn {
+	t.Helper()
+
+	conn, err := net.Dial("udp", addr)
+	require.NoError(t, err)
+	return conn
+}
+
+func roundTrip(t *testing.T, conn net.Conn, data string) string {
+	t.Helper()
+
+	_, err := conn.Write([]byte(data))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	return string(buf[:n])
+}
+
+// newEchoServer starts a UDP server answering every datagram with its name followed by the datagram.
+func newEchoServer(t *testing.T, name string) net.PacketConn {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteTo(append([]byte(name+":"), buf[:n]...), addr)
+		}
+	}()
+	return conn
+}
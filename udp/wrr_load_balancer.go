@@ -0,0 +1,48 @@
+package udp
+
+import (
+	"net/url"
+
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+// Balancer selects the backend server of new UDP sessions
+type Balancer interface {
+	NextServer() (*url.URL, error)
+}
+
+// WRRLoadBalancer is a weighted round robin load balancer of UDP servers.
+// It implements the healthcheck.LoadBalancer interface.
+type WRRLoadBalancer struct {
+	rr *roundrobin.RoundRobin
+}
+
+// NewWRRLoadBalancer creates a new WRRLoadBalancer without any server
+func NewWRRLoadBalancer() (*WRRLoadBalancer, error) {
+	// The round robin is only used to pick the next server, it never serves HTTP requests.
+	rr, err := roundrobin.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &WRRLoadBalancer{rr: rr}, nil
+}
+
+// NextServer returns the server of the next session
+func (b *WRRLoadBalancer) NextServer() (*url.URL, error) {
+	return b.rr.NextServer()
+}
+
+// UpsertServer adds or updates a server
+func (b *WRRLoadBalancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	return b.rr.UpsertServer(u, options...)
+}
+
+// RemoveServer removes a server
+func (b *WRRLoadBalancer) RemoveServer(u *url.URL) error {
+	return b.rr.RemoveServer(u)
+}
+
+// Servers returns the list of servers
+func (b *WRRLoadBalancer) Servers() []*url.URL {
+	return b.rr.Servers()
+}